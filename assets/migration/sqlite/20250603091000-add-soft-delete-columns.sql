@@ -0,0 +1,40 @@
+-- +migrate Up
+ALTER TABLE user ADD COLUMN deleted_by TEXT;
+ALTER TABLE user ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE role ADD COLUMN deleted_by TEXT;
+ALTER TABLE role ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE permission ADD COLUMN deleted_by TEXT;
+ALTER TABLE permission ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE resource ADD COLUMN deleted_by TEXT;
+ALTER TABLE resource ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE org ADD COLUMN deleted_by TEXT;
+ALTER TABLE org ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE team ADD COLUMN deleted_by TEXT;
+ALTER TABLE team ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_user_deleted_at ON user(deleted_at);
+CREATE INDEX idx_role_deleted_at ON role(deleted_at);
+CREATE INDEX idx_permission_deleted_at ON permission(deleted_at);
+CREATE INDEX idx_resource_deleted_at ON resource(deleted_at);
+CREATE INDEX idx_org_deleted_at ON org(deleted_at);
+CREATE INDEX idx_team_deleted_at ON team(deleted_at);
+
+-- +migrate Down
+DROP INDEX idx_user_deleted_at;
+DROP INDEX idx_role_deleted_at;
+DROP INDEX idx_permission_deleted_at;
+DROP INDEX idx_resource_deleted_at;
+DROP INDEX idx_org_deleted_at;
+DROP INDEX idx_team_deleted_at;
+ALTER TABLE user DROP COLUMN deleted_at;
+ALTER TABLE user DROP COLUMN deleted_by;
+ALTER TABLE role DROP COLUMN deleted_at;
+ALTER TABLE role DROP COLUMN deleted_by;
+ALTER TABLE permission DROP COLUMN deleted_at;
+ALTER TABLE permission DROP COLUMN deleted_by;
+ALTER TABLE resource DROP COLUMN deleted_at;
+ALTER TABLE resource DROP COLUMN deleted_by;
+ALTER TABLE org DROP COLUMN deleted_at;
+ALTER TABLE org DROP COLUMN deleted_by;
+ALTER TABLE team DROP COLUMN deleted_at;
+ALTER TABLE team DROP COLUMN deleted_by;
@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE user ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE role ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE permission ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE resource ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE org ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE team ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +migrate Down
+ALTER TABLE user DROP COLUMN version;
+ALTER TABLE role DROP COLUMN version;
+ALTER TABLE permission DROP COLUMN version;
+ALTER TABLE resource DROP COLUMN version;
+ALTER TABLE org DROP COLUMN version;
+ALTER TABLE team DROP COLUMN version;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE org_member (
+    id TEXT PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    FOREIGN KEY (org_id) REFERENCES org(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+    UNIQUE (org_id, user_id)
+);
+
+CREATE INDEX idx_org_member_user_id ON org_member(user_id);
+
+-- +migrate Down
+DROP INDEX idx_org_member_user_id;
+DROP TABLE org_member;
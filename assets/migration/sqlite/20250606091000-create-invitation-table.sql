@@ -0,0 +1,28 @@
+-- +migrate Up
+CREATE TABLE invitation (
+    id TEXT PRIMARY KEY,
+    short_id TEXT UNIQUE,
+    email_enc BLOB NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    inviter_id TEXT,
+    org_id TEXT NOT NULL,
+    team_id TEXT,
+    role_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP NOT NULL,
+    user_id TEXT,
+    responded_at TIMESTAMP,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    FOREIGN KEY (org_id) REFERENCES org(id) ON DELETE CASCADE,
+    FOREIGN KEY (team_id) REFERENCES team(id) ON DELETE CASCADE,
+    FOREIGN KEY (role_id) REFERENCES role(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_invitation_org_id ON invitation(org_id);
+CREATE INDEX idx_invitation_team_id ON invitation(team_id);
+
+-- +migrate Down
+DROP TABLE invitation;
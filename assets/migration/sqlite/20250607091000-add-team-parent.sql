@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE team ADD COLUMN parent_id TEXT REFERENCES team(id);
+
+CREATE INDEX idx_team_parent_id ON team(parent_id);
+
+-- +migrate Down
+DROP INDEX idx_team_parent_id;
+ALTER TABLE team DROP COLUMN parent_id;
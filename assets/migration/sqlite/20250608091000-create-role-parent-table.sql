@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE role_parent (
+    role_id TEXT NOT NULL,
+    parent_id TEXT NOT NULL,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (role_id, parent_id),
+    FOREIGN KEY (role_id) REFERENCES role(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES role(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_role_parent_parent_id ON role_parent(parent_id);
+
+-- +migrate Down
+DROP INDEX idx_role_parent_parent_id;
+DROP TABLE role_parent;
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE policy (
+    id TEXT PRIMARY KEY,
+    short_id TEXT UNIQUE,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    effect TEXT NOT NULL,
+    actions TEXT NOT NULL DEFAULT '[]',
+    resources TEXT NOT NULL DEFAULT '[]',
+    conditions TEXT NOT NULL DEFAULT '[]',
+    enabled INTEGER NOT NULL DEFAULT 1,
+    created_by TEXT,
+    updated_by TEXT,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE policy;
@@ -0,0 +1,18 @@
+-- Res: AuditLog
+-- Table: audit_log
+
+-- Create
+INSERT INTO audit_log (id, short_id, actor_id, action, target_type, target_id, before_data, after_data, ip, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- List
+SELECT id, short_id, actor_id, action, target_type, target_id, before_data, after_data, ip, created_at
+FROM audit_log
+WHERE (? = '' OR actor_id = ?)
+  AND (? = '' OR action = ?)
+  AND (? = '' OR target_type = ?)
+  AND (? = '' OR target_id = ?)
+  AND (? IS NULL OR created_at >= ?)
+  AND (? IS NULL OR created_at < ?)
+ORDER BY created_at DESC
+LIMIT ?;
@@ -0,0 +1,35 @@
+-- Res: Invitation
+-- Table: invitation
+
+-- Create
+INSERT INTO invitation (id, short_id, email_enc, token_hash, inviter_id, org_id, team_id, role_id, status, expires_at, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- Get
+SELECT * FROM invitation WHERE id = ?;
+
+-- GetByTokenHash
+SELECT * FROM invitation WHERE token_hash = ?;
+
+-- GetPendingForTeam
+SELECT * FROM invitation
+WHERE team_id = ?
+  AND status = 'pending'
+ORDER BY created_at DESC;
+
+-- GetPendingForOrg
+SELECT * FROM invitation
+WHERE org_id = ?
+  AND team_id IS NULL
+  AND status = 'pending'
+ORDER BY created_at DESC;
+
+-- Respond
+UPDATE invitation SET status = ?, user_id = ?, responded_at = ?, updated_by = ?, updated_at = ?
+WHERE id = ? AND status = 'pending';
+
+-- PurgeByTeam
+DELETE FROM invitation WHERE team_id IN (SELECT id FROM team WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByOrg
+DELETE FROM invitation WHERE org_id IN (SELECT id FROM org WHERE deleted_at IS NOT NULL AND deleted_at < ?);
@@ -6,7 +6,35 @@ INSERT INTO org (id, short_id, name, short_description, description, created_by,
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- GetDefault
-SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at FROM org ORDER BY created_at ASC LIMIT 1;
+SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version FROM org WHERE deleted_at IS NULL ORDER BY created_at ASC LIMIT 1;
 
 -- Delete
-DELETE FROM org WHERE id = ?;
+UPDATE org SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Purge
+DELETE FROM org WHERE deleted_at IS NOT NULL AND deleted_at < ?;
+
+-- GetAll
+SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version FROM org WHERE deleted_at IS NULL ORDER BY created_at ASC;
+
+-- Get
+SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version FROM org WHERE id = ? AND deleted_at IS NULL;
+
+-- GetForUser
+SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version
+FROM org
+WHERE deleted_at IS NULL
+  AND (
+    id IN (SELECT org_id FROM org_member WHERE user_id = ?)
+    OR id IN (SELECT org_id FROM org_owner WHERE user_id = ?)
+  )
+ORDER BY created_at ASC;
+
+-- GetDeleted
+SELECT id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version, deleted_by, deleted_at FROM org WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- Update
+UPDATE org SET short_id = ?, name = ?, short_description = ?, description = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE org SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
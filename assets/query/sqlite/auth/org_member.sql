@@ -0,0 +1,30 @@
+-- Res: OrgMember
+-- Table: org_member
+
+-- Add
+INSERT INTO org_member (id, org_id, user_id, created_at, updated_at)
+VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- Remove
+DELETE FROM org_member WHERE org_id = ? AND user_id = ?;
+
+-- GetOrgMembers
+SELECT u.* FROM user u
+JOIN org_member om ON u.id = om.user_id
+WHERE om.org_id = ?
+  AND u.deleted_at IS NULL
+ORDER BY u.name ASC;
+
+-- GetOrgNonMembers
+SELECT u.* FROM user u
+WHERE u.deleted_at IS NULL
+  AND u.id NOT IN (
+    SELECT user_id FROM org_member WHERE org_id = ?
+)
+ORDER BY u.name ASC;
+
+-- PurgeByUser
+DELETE FROM org_member WHERE user_id IN (SELECT id FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByOrg
+DELETE FROM org_member WHERE org_id IN (SELECT id FROM org WHERE deleted_at IS NOT NULL AND deleted_at < ?);
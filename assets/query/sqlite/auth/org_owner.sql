@@ -10,10 +10,18 @@ DELETE FROM org_owner WHERE org_id = ? AND user_id = ?;
 -- GetOrgOwners
 SELECT u.* FROM "user" u
 INNER JOIN org_owner o ON u.id = o.user_id
-WHERE o.org_id = ?;
+WHERE o.org_id = ?
+  AND u.deleted_at IS NULL;
 
 -- GetOrgUnassignedOwners
 SELECT u.* FROM "user" u
-WHERE u.id NOT IN (
+WHERE u.deleted_at IS NULL
+  AND u.id NOT IN (
     SELECT user_id FROM org_owner WHERE org_id = ?
 );
+
+-- PurgeByUser
+DELETE FROM org_owner WHERE user_id IN (SELECT id FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByOrg
+DELETE FROM org_owner WHERE org_id IN (SELECT id FROM org WHERE deleted_at IS NOT NULL AND deleted_at < ?);
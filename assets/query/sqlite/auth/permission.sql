@@ -2,19 +2,31 @@
 -- Table: permission
 
 -- GetAll
-SELECT id, short_id, name, description, created_by, updated_by, created_at, updated_at FROM permission;
+SELECT id, short_id, name, description, created_by, updated_by, created_at, updated_at, version FROM permission WHERE deleted_at IS NULL;
 
 -- Get
-SELECT id, short_id, name, description, created_by, updated_by, created_at, updated_at
+SELECT id, short_id, name, description, created_by, updated_by, created_at, updated_at, version
 FROM permission
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
+
+-- GetDeleted
+SELECT id, short_id, name, description, created_by, updated_by, created_at, updated_at, version, deleted_by, deleted_at
+FROM permission
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
 
 -- Create
 INSERT INTO permission (id, short_id, name, description, created_by, updated_by, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- Update
-UPDATE permission SET short_id = ?, name = ?, description = ?, updated_by = ?, updated_at = ? WHERE id = ?;
+UPDATE permission SET short_id = ?, name = ?, description = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
 -- Delete
-DELETE FROM permission WHERE id = ?;
+UPDATE permission SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE permission SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- Purge
+DELETE FROM permission WHERE deleted_at IS NOT NULL AND deleted_at < ?;
@@ -0,0 +1,19 @@
+-- Res: Policy
+-- Table: policy
+
+-- GetAll
+SELECT * FROM policy ORDER BY name;
+
+-- Get
+SELECT * FROM policy WHERE id = ?;
+
+-- Create
+INSERT INTO policy (id, short_id, name, description, effect, actions, resources, conditions, enabled, created_by, updated_by, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- Update
+UPDATE policy SET name = ?, description = ?, effect = ?, actions = ?, resources = ?, conditions = ?, enabled = ?, updated_by = ?, updated_at = ?
+WHERE id = ?;
+
+-- Delete
+DELETE FROM policy WHERE id = ?;
@@ -2,28 +2,40 @@
 -- Table: resource
 
 -- GetAll
-SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at FROM resource;
+SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at, version FROM resource WHERE deleted_at IS NULL;
 
 -- Get
-SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at
+SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at, version
 FROM resource
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- GetPreload
 SELECT DISTINCT
-    r.id, r.name, r.description, r.short_id, r.created_by, r.updated_by, r.created_at, r.updated_at,
+    r.id, r.name, r.description, r.short_id, r.created_by, r.updated_by, r.created_at, r.updated_at, r.version,
     p.id AS permission_id, p.name AS permission_name, p.short_id AS permission_short_id
 FROM resource r
     LEFT JOIN resource_permission rp ON r.id = rp.resource_id
-    LEFT JOIN permission p ON rp.permission_id = p.id
-WHERE r.id = ?;
+    LEFT JOIN permission p ON rp.permission_id = p.id AND p.deleted_at IS NULL
+WHERE r.id = ? AND r.deleted_at IS NULL;
+
+-- GetDeleted
+SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at, version, deleted_by, deleted_at
+FROM resource
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
 
 -- Create
 INSERT INTO resource (id, name, description, short_id, created_by, updated_by, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- Update
-UPDATE resource SET name = ?, description = ?, short_id = ?, updated_by = ?, updated_at = ? WHERE id = ?;
+UPDATE resource SET name = ?, description = ?, short_id = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
 -- Delete
-DELETE FROM resource WHERE id = ?;
+UPDATE resource SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE resource SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- Purge
+DELETE FROM resource WHERE deleted_at IS NOT NULL AND deleted_at < ?;
@@ -11,13 +11,21 @@ DELETE FROM resource_permission WHERE resource_id = ? AND permission_id = ?;
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
 JOIN resource_permission rp ON p.id = rp.permission_id
-WHERE rp.resource_id = ?;
+WHERE rp.resource_id = ?
+  AND p.deleted_at IS NULL;
 
 -- GetResourceUnassignedPermissions
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id NOT IN (
+WHERE p.deleted_at IS NULL
+  AND p.id NOT IN (
     SELECT rp.permission_id
     FROM resource_permission rp
     WHERE rp.resource_id = ?
-);
\ No newline at end of file
+);
+
+-- PurgeByResource
+DELETE FROM resource_permission WHERE resource_id IN (SELECT id FROM resource WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByPermission
+DELETE FROM resource_permission WHERE permission_id IN (SELECT id FROM permission WHERE deleted_at IS NOT NULL AND deleted_at < ?);
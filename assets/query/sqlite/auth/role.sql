@@ -2,28 +2,40 @@
 -- Table: role
 
 -- GetAll
-SELECT id, name, description, short_id FROM role;
+SELECT id, name, description, short_id, version FROM role WHERE deleted_at IS NULL;
 
 -- Get
-SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at
+SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at, version
 FROM role
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- GetPreload
 SELECT DISTINCT
-    r.id, r.name, r.description, r.short_id, r.created_by, r.updated_by, r.created_at, r.updated_at,
+    r.id, r.name, r.description, r.short_id, r.created_by, r.updated_by, r.created_at, r.updated_at, r.version,
     p.id AS permission_id, p.name AS permission_name, p.short_id AS permission_short_id
 FROM role r
     LEFT JOIN role_permission rp ON r.id = rp.role_id
-    LEFT JOIN permission p ON rp.permission_id = p.id
-WHERE r.id = ?;
+    LEFT JOIN permission p ON rp.permission_id = p.id AND p.deleted_at IS NULL
+WHERE r.id = ? AND r.deleted_at IS NULL;
+
+-- GetDeleted
+SELECT id, name, description, short_id, created_by, updated_by, created_at, updated_at, version, deleted_by, deleted_at
+FROM role
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
 
 -- Create
 INSERT INTO role (id, name, description, short_id, created_by, updated_by, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- Update
-UPDATE role SET name = ?, description = ?, short_id = ?, updated_by = ?, updated_at = ? WHERE id = ?;
+UPDATE role SET name = ?, description = ?, short_id = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
 -- Delete
-DELETE FROM role WHERE id = ?;
+UPDATE role SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE role SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- Purge
+DELETE FROM role WHERE deleted_at IS NOT NULL AND deleted_at < ?;
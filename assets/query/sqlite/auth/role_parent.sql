@@ -0,0 +1,41 @@
+-- Res: RoleParent
+-- Table: role_parent
+
+-- Add
+INSERT INTO role_parent (role_id, parent_id, created_at, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- Remove
+DELETE FROM role_parent WHERE role_id = ? AND parent_id = ?;
+
+-- GetParents
+SELECT r.id, r.name, r.description, r.short_id, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
+FROM role r
+JOIN role_parent rp ON r.id = rp.parent_id
+WHERE rp.role_id = ?
+  AND r.deleted_at IS NULL
+ORDER BY r.name;
+
+-- GetAncestors
+WITH RECURSIVE ancestors(id, depth) AS (
+    SELECT rp.parent_id, 1
+    FROM role_parent rp
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+    WHERE rp.role_id = ?
+    UNION
+    SELECT rp.parent_id, a.depth + 1
+    FROM role_parent rp
+             JOIN ancestors a ON rp.role_id = a.id
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+    WHERE a.depth < (SELECT COUNT(*) FROM role)
+)
+SELECT r.id, r.name, r.description, r.short_id, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
+FROM role r
+         JOIN (SELECT id, MIN(depth) AS depth FROM ancestors GROUP BY id) a ON a.id = r.id
+WHERE r.id != ?
+ORDER BY a.depth, r.name;
+
+-- PurgeByRole
+DELETE FROM role_parent WHERE role_id IN (SELECT id FROM role WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByParent
+DELETE FROM role_parent WHERE parent_id IN (SELECT id FROM role WHERE deleted_at IS NOT NULL AND deleted_at < ?);
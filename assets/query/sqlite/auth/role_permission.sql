@@ -10,13 +10,29 @@ DELETE FROM role_permission WHERE role_id = ? AND permission_id = ?;
 -- GetRolePermissions
 SELECT p.id, p.short_id, p.name, p.description, p.created_by, p.updated_by, p.created_at, p.updated_at  FROM permission p
 INNER JOIN role_permission rp ON p.id = rp.permission_id
-WHERE rp.role_id = ?;
+WHERE rp.role_id = ?
+  AND p.deleted_at IS NULL;
 
 -- GetRoleUnassignedPermissions
+WITH RECURSIVE lineage(id) AS (
+    SELECT ?
+    UNION
+    SELECT rp.parent_id
+    FROM role_parent rp
+             JOIN lineage l ON rp.role_id = l.id
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+)
 SELECT p.id, p.short_id, p.name, p.description, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id NOT IN (
+WHERE p.deleted_at IS NULL
+  AND p.id NOT IN (
     SELECT rp.permission_id
     FROM role_permission rp
-    WHERE rp.role_id = ?
-);
\ No newline at end of file
+             JOIN lineage l ON rp.role_id = l.id
+);
+
+-- PurgeByRole
+DELETE FROM role_permission WHERE role_id IN (SELECT id FROM role WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByPermission
+DELETE FROM role_permission WHERE permission_id IN (SELECT id FROM permission WHERE deleted_at IS NOT NULL AND deleted_at < ?);
@@ -2,16 +2,34 @@
 -- Table: team
 
 -- Create
-INSERT INTO team (id, org_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO team (id, org_id, parent_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- GetAll
-SELECT id, org_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at FROM team WHERE org_id = ?;
+SELECT id, org_id, parent_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version FROM team WHERE org_id = ? AND deleted_at IS NULL;
 
 -- Get
-SELECT id, org_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at FROM team WHERE id = ?;
+SELECT id, org_id, parent_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version FROM team WHERE id = ? AND deleted_at IS NULL;
+
+-- GetUserTeams
+SELECT t.id, t.org_id, t.parent_id, t.short_id, t.name, t.short_description, t.description, t.created_by, t.updated_by, t.created_at, t.updated_at, t.version
+FROM team t
+JOIN team_member tm ON t.id = tm.team_id
+WHERE tm.user_id = ? AND t.deleted_at IS NULL;
+
+-- GetDeleted
+SELECT id, org_id, parent_id, short_id, name, short_description, description, created_by, updated_by, created_at, updated_at, version, deleted_by, deleted_at FROM team WHERE org_id = ? AND deleted_at IS NOT NULL ORDER BY deleted_at DESC;
 
 -- Update
-UPDATE team SET short_id = ?, name = ?, short_description = ?, description = ?, updated_by = ?, updated_at = ? WHERE id = ?;
+UPDATE team SET parent_id = ?, short_id = ?, name = ?, short_description = ?, description = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
 -- Delete
-DELETE FROM team WHERE id = ?;
+UPDATE team SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE team SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- DetachPurgedChildren
+UPDATE team SET parent_id = NULL WHERE parent_id IN (SELECT id FROM team WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- Purge
+DELETE FROM team WHERE deleted_at IS NOT NULL AND deleted_at < ?;
@@ -10,12 +10,14 @@ DELETE FROM team_member WHERE team_id = ? AND user_id = ?;
 SELECT u.*
 FROM user u
 JOIN team_member tm ON u.id = tm.user_id
-WHERE tm.team_id = ?;
+WHERE tm.team_id = ?
+  AND u.deleted_at IS NULL;
 
 -- ListUsersNotInTeam
 SELECT u.*
 FROM user u
-WHERE u.id NOT IN (
+WHERE u.deleted_at IS NULL
+  AND u.id NOT IN (
     SELECT user_id FROM team_member WHERE team_id = ?
 );
 
@@ -23,12 +25,14 @@ WHERE u.id NOT IN (
 SELECT t.*
 FROM team t
 JOIN team_member tm ON t.id = tm.team_id
-WHERE tm.user_id = ?;
+WHERE tm.user_id = ?
+  AND t.deleted_at IS NULL;
 
 -- ListTeamsUserNotMember
 SELECT t.*
 FROM team t
-WHERE t.id NOT IN (
+WHERE t.deleted_at IS NULL
+  AND t.id NOT IN (
     SELECT team_id FROM team_member WHERE user_id = ?
 );
 
@@ -39,12 +43,14 @@ JOIN user_role ur ON r.id = ur.role_id
 WHERE ur.user_id = ?
   AND ur.context_type = 'team'
   AND ur.context_id = ?
-  AND r.contextual = true;
+  AND r.contextual = true
+  AND r.deleted_at IS NULL;
 
 -- ListTeamUnassignedRoles
 SELECT r.id, r.name, r.description, r.short_id, r.contextual, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
 FROM role r
 WHERE r.contextual = true
+  AND r.deleted_at IS NULL
   AND r.id NOT IN (
     SELECT role_id
     FROM user_role
@@ -52,3 +58,9 @@ WHERE r.contextual = true
       AND context_type = 'team'
       AND context_id = ?
 );
+
+-- PurgeByUser
+DELETE FROM team_member WHERE user_id IN (SELECT id FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByTeam
+DELETE FROM team_member WHERE team_id IN (SELECT id FROM team WHERE deleted_at IS NOT NULL AND deleted_at < ?);
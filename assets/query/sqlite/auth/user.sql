@@ -2,36 +2,51 @@
 -- Table: user
 
 -- GetAll
-SELECT id, username, email_enc, password_enc, name, short_id, created_by, updated_by, created_at, updated_at, last_login_at, last_login_ip, is_active FROM user;
+SELECT id, username, email_enc, password_enc, name, short_id, created_by, updated_by, created_at, updated_at, version, last_login_at, last_login_ip, is_active FROM user WHERE deleted_at IS NULL;
 
 -- Get
-SELECT id, name, username, email_enc, password_enc, short_id, created_by, updated_by, created_at, updated_at, last_login_at, last_login_ip, is_active
+SELECT id, name, username, email_enc, password_enc, short_id, created_by, updated_by, created_at, updated_at, version, last_login_at, last_login_ip, is_active
 FROM user
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
 
 -- GetPreload
 SELECT DISTINCT
-    u.id, u.name, u.username, u.email_enc, u.password_enc, u.short_id, u.created_by, u.updated_by, u.created_at, u.updated_at, u.last_login_at, u.last_login_ip, u.is_active,
+    u.id, u.name, u.username, u.email_enc, u.password_enc, u.short_id, u.created_by, u.updated_by, u.created_at, u.updated_at, u.version, u.last_login_at, u.last_login_ip, u.is_active,
     r.id AS role_id, r.name AS role_name,
     p.id AS permission_id, p.name AS permission_name
 FROM user u
        LEFT JOIN user_role ur ON u.id = ur.user_id
-       LEFT JOIN role r ON ur.role_id = r.id
+       LEFT JOIN role r ON ur.role_id = r.id AND r.deleted_at IS NULL
        LEFT JOIN role_permission rp ON r.id = rp.role_id
-       LEFT JOIN permission p ON rp.permission_id = p.id
-WHERE u.id = ?;
+       LEFT JOIN permission p ON rp.permission_id = p.id AND p.deleted_at IS NULL
+WHERE u.id = ? AND u.deleted_at IS NULL;
+
+-- GetDeleted
+SELECT id, username, email_enc, password_enc, name, short_id, created_by, updated_by, created_at, updated_at, version, last_login_at, last_login_ip, is_active, deleted_by, deleted_at
+FROM user
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
 
 -- Create
 INSERT INTO user (id, username, email_enc, name, password_enc, short_id, created_by, updated_by, created_at, updated_at)
 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- Update
-UPDATE user SET username = ?, email_enc = ?, name = ?, short_id = ?, updated_by = ?, updated_at = ? WHERE id = ?;
+UPDATE user SET username = ?, email_enc = ?, name = ?, short_id = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL;
 
 -- Delete
-DELETE FROM user WHERE id = ?;
+UPDATE user SET deleted_by = ?, deleted_at = ? WHERE id = ? AND deleted_at IS NULL;
+
+-- Restore
+UPDATE user SET deleted_by = NULL, deleted_at = NULL, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- Purge
+DELETE FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?;
+
+-- SetActive
+UPDATE user SET is_active = ?, updated_by = ?, updated_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL;
 
 -- UpdatePassword
 UPDATE user
-SET password_enc = ?, updated_by = ?, updated_at = ?
-WHERE id = ?;
+SET password_enc = ?, updated_by = ?, updated_at = ?, version = version + 1
+WHERE id = ? AND deleted_at IS NULL;
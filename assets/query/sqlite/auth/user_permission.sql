@@ -2,13 +2,24 @@
 -- Table: user_permission
 
 -- GetUserAssignedPermissions
+WITH RECURSIVE user_roles(id) AS (
+    SELECT ur.role_id
+    FROM user_role ur
+             JOIN role r ON r.id = ur.role_id AND r.deleted_at IS NULL
+    WHERE ur.user_id = ?
+    UNION
+    SELECT rp.parent_id
+    FROM role_parent rp
+             JOIN user_roles ur ON rp.role_id = ur.id
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+)
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id IN (
+WHERE p.deleted_at IS NULL
+  AND p.id IN (
     SELECT rp.permission_id
     FROM role_permission rp
-             JOIN user_role ur ON rp.role_id = ur.role_id
-    WHERE ur.user_id = ?
+             JOIN user_roles ur ON rp.role_id = ur.id
     UNION
     SELECT up.permission_id
     FROM user_permission up
@@ -16,32 +27,55 @@ WHERE p.id IN (
 );
 
 -- GetUserIndirectPermissions
+WITH RECURSIVE user_roles(id) AS (
+    SELECT ur.role_id
+    FROM user_role ur
+             JOIN role r ON r.id = ur.role_id AND r.deleted_at IS NULL
+    WHERE ur.user_id = ?
+    UNION
+    SELECT rp.parent_id
+    FROM role_parent rp
+             JOIN user_roles ur ON rp.role_id = ur.id
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+)
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id IN (
+WHERE p.deleted_at IS NULL
+  AND p.id IN (
     SELECT rp.permission_id
     FROM role_permission rp
-             JOIN user_role ur ON rp.role_id = ur.role_id
-    WHERE ur.user_id = ?
+             JOIN user_roles ur ON rp.role_id = ur.id
 );
 
 -- GetUserDirectPermissions
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id IN (
+WHERE p.deleted_at IS NULL
+  AND p.id IN (
     SELECT up.permission_id
     FROM user_permission up
     WHERE up.user_id = ?
 );
 
 -- GetUserUnassignedPermissions
+WITH RECURSIVE user_roles(id) AS (
+    SELECT ur.role_id
+    FROM user_role ur
+             JOIN role r ON r.id = ur.role_id AND r.deleted_at IS NULL
+    WHERE ur.user_id = ?
+    UNION
+    SELECT rp.parent_id
+    FROM role_parent rp
+             JOIN user_roles ur ON rp.role_id = ur.id
+             JOIN role r ON r.id = rp.parent_id AND r.deleted_at IS NULL
+)
 SELECT p.id, p.name, p.description, p.short_id, p.created_by, p.updated_by, p.created_at, p.updated_at
 FROM permission p
-WHERE p.id NOT IN (
+WHERE p.deleted_at IS NULL
+  AND p.id NOT IN (
     SELECT rp.permission_id
     FROM role_permission rp
-             JOIN user_role ur ON rp.role_id = ur.role_id
-    WHERE ur.user_id = ?
+             JOIN user_roles ur ON rp.role_id = ur.id
     UNION
     SELECT up.permission_id
     FROM user_permission up
@@ -53,3 +87,9 @@ INSERT INTO user_permission (user_id, permission_id) VALUES (?, ?);
 
 -- RemovePermissionFromUser
 DELETE FROM user_permission WHERE user_id = ? AND permission_id = ?;
+
+-- PurgeByUser
+DELETE FROM user_permission WHERE user_id IN (SELECT id FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByPermission
+DELETE FROM user_permission WHERE permission_id IN (SELECT id FROM permission WHERE deleted_at IS NOT NULL AND deleted_at < ?);
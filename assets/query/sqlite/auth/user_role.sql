@@ -5,7 +5,7 @@
 INSERT INTO user_role (user_id, role_id, context_type, context_id)
 SELECT ?, ?, ?, ?
 WHERE EXISTS (
-    SELECT 1 FROM role WHERE id = ?
+    SELECT 1 FROM role WHERE id = ? AND deleted_at IS NULL
 );
 
 -- RemoveRole
@@ -21,12 +21,28 @@ FROM role r
 JOIN user_role ur ON r.id = ur.role_id
 WHERE ur.user_id = ?
   AND ur.context_type = ?
-  AND ur.context_id = ?;
+  AND ur.context_id = ?
+  AND r.deleted_at IS NULL;
+
+-- GetUserAllRoles
+SELECT DISTINCT r.id, r.name, r.description, r.short_id, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
+FROM role r
+JOIN user_role ur ON r.id = ur.role_id
+WHERE ur.user_id = ?
+  AND r.deleted_at IS NULL;
+
+-- GetUserRoleAssignments
+SELECT ur.role_id, ur.context_type, ur.context_id
+FROM user_role ur
+JOIN role r ON r.id = ur.role_id
+WHERE ur.user_id = ?
+  AND r.deleted_at IS NULL;
 
 -- GetUserUnassignedRoles
 SELECT r.id, r.name, r.description, r.short_id, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
 FROM role r
-WHERE r.id NOT IN (
+WHERE r.deleted_at IS NULL
+  AND r.id NOT IN (
     SELECT role_id
     FROM user_role
     WHERE user_id = ?
@@ -41,16 +57,33 @@ JOIN user_role ur ON r.id = ur.role_id
 WHERE ur.user_id = ?
   AND ur.context_type = ?
   AND ur.context_id = ?
-  AND r.contextual = true;
+  AND r.contextual = true
+  AND r.deleted_at IS NULL;
 
 -- GetContextualUnassignedRoles
 SELECT r.id, r.name, r.description, r.short_id, r.status, r.created_by, r.updated_by, r.created_at, r.updated_at
 FROM role r
 WHERE r.contextual = true
+  AND r.deleted_at IS NULL
   AND r.id NOT IN (
     SELECT role_id
     FROM user_role
     WHERE user_id = ?
       AND context_type = ?
       AND context_id = ?
-);
\ No newline at end of file
+);
+
+-- PurgeByUser
+DELETE FROM user_role WHERE user_id IN (SELECT id FROM user WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByRole
+DELETE FROM user_role WHERE role_id IN (SELECT id FROM role WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- PurgeByTeam
+DELETE FROM user_role WHERE context_type = 'team' AND context_id IN (SELECT id FROM team WHERE deleted_at IS NOT NULL AND deleted_at < ?);
+
+-- RemoveContext
+DELETE FROM user_role WHERE user_id = ? AND context_type = ? AND context_id = ?;
+
+-- PurgeByOrg
+DELETE FROM user_role WHERE context_type = 'org' AND context_id IN (SELECT id FROM org WHERE deleted_at IS NOT NULL AND deleted_at < ?);
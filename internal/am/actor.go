@@ -0,0 +1,75 @@
+package am
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ActorHeader is the header used to carry the acting user ID.
+// There are no sessions yet, so it is set by an authenticating proxy and only honored when
+// the request comes from one of the trusted proxies of the configuration.
+const ActorHeader = "X-Actor-ID"
+
+// SystemActorID is the actor of the work the application does on its own, such as purges and reminders.
+// No user has it and no request can claim it, it only gets into a context through WithSystemActor.
+var SystemActorID = uuid.Max
+
+type actorContextKey struct{}
+
+// WithActor returns a new context with the acting user ID stored.
+func WithActor(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, userID)
+}
+
+// ActorFromContext returns the acting user ID stored in the context, if any.
+func ActorFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(actorContextKey{}).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// WithSystemActor returns a new context where the application acts on its own behalf.
+func WithSystemActor(ctx context.Context) context.Context {
+	return WithActor(ctx, SystemActorID)
+}
+
+// IsSystemActor reports whether the context acts on behalf of the application.
+func IsSystemActor(ctx context.Context) bool {
+	userID, ok := ActorFromContext(ctx)
+	return ok && userID == SystemActorID
+}
+
+// ActorName returns how an actor is shown in audit information, the system actor is shown as "system"
+// and records written before actors were tracked have no actor to show.
+func ActorName(userID uuid.UUID) string {
+	switch userID {
+	case SystemActorID:
+		return "system"
+	case uuid.Nil:
+		return "unknown"
+	}
+	return userID.String()
+}
+
+// ActorMw returns a middleware that stores the acting user ID in the request context.
+// The header of a request that does not come from a trusted proxy is ignored, otherwise anyone could act as anyone.
+// Invalid or missing values are ignored and the request continues without an actor.
+func ActorMw(cfg *Config) func(http.Handler) http.Handler {
+	proxies := TrustedProxiesFromCfg(cfg)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			if proxies.FromProxy(r) {
+				if userID, err := uuid.Parse(strings.TrimSpace(r.Header.Get(ActorHeader))); err == nil && userID != SystemActorID {
+					ctx = WithActor(ctx, userID)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package am
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// BeginTxFunc begins a transaction and returns a context carrying it, see WithTx.
+type BeginTxFunc func(ctx context.Context) (context.Context, Tx, error)
+
+// BatchResult is the outcome of a bulk command on one of its targets.
+type BatchResult struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name,omitempty"`
+	OK    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Batch is the outcome of a bulk command. Its changes are applied only when every target succeeded.
+type Batch struct {
+	Command string        `json:"command"`
+	Applied bool          `json:"applied"`
+	Results []BatchResult `json:"results"`
+}
+
+// Failed returns the results of the targets the command could not be applied to.
+func (b Batch) Failed() []BatchResult {
+	var failed []BatchResult
+	for _, r := range b.Results {
+		if !r.OK {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// RunBatch runs fn on each target inside a single transaction. Every target is attempted, even after
+// a failure, so that each one gets its result; the transaction is committed only when all of them
+// succeeded and rolled back otherwise. fn returns the name the target is reported with.
+// Repeated targets are run once.
+func RunBatch(ctx context.Context, begin BeginTxFunc, command string, ids []uuid.UUID,
+	fn func(ctx context.Context, id uuid.UUID) (string, error)) (Batch, error) {
+	batch := Batch{Command: command, Results: []BatchResult{}}
+
+	ctx, tx, err := begin(ctx)
+	if err != nil {
+		return batch, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	failed := false
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		name, err := fn(ctx, id)
+		result := BatchResult{ID: id, Name: name, OK: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+			failed = true
+		}
+		batch.Results = append(batch.Results, result)
+	}
+
+	if failed {
+		return batch, tx.Rollback()
+	}
+	err = tx.Commit()
+	if err != nil {
+		return batch, err
+	}
+	batch.Applied = true
+	return batch, nil
+}
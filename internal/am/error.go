@@ -1,15 +1,18 @@
 package am
 
 const (
-	ErrInvalidID            = "Invalid ID"
-	ErrCannotGetResources   = "Cannot get resources"
-	ErrCannotCreateResource = "Cannot create resource"
-	ErrResourceNotFound     = "Resource not found"
-	ErrCannotUpdateResource = "Cannot update resource"
-	ErrCannotDeleteResource = "Cannot delete resource"
-	ErrTemplateNotFound     = "Template not found"
-	ErrCannotRenderTemplate = "Cannot render template"
-	ErrCannotWriteResponse  = "Cannot write response"
-	ErrInvalidFormData      = "Invalid form data"
-	ErrValidationFailed     = "Validation failed"
+	ErrInvalidID             = "Invalid ID"
+	ErrCannotGetResources    = "Cannot get resources"
+	ErrCannotCreateResource  = "Cannot create resource"
+	ErrResourceNotFound      = "Resource not found"
+	ErrCannotUpdateResource  = "Cannot update resource"
+	ErrCannotDeleteResource  = "Cannot delete resource"
+	ErrCannotRestoreResource = "Cannot restore resource"
+	ErrTemplateNotFound      = "Template not found"
+	ErrCannotRenderTemplate  = "Cannot render template"
+	ErrCannotWriteResponse   = "Cannot write response"
+	ErrInvalidFormData       = "Invalid form data"
+	ErrValidationFailed      = "Validation failed"
+	ErrVersionConflict       = "Resource was modified by someone else"
+	ErrPreconditionFailed    = "Precondition failed"
 )
@@ -12,11 +12,12 @@ type Keys struct {
 
 	DBSQLiteDSN string
 
-	SecCSRFKey       string
-	SecCSRFRedirect  string
-	SecEncryptionKey string
-	SecHashKey       string
-	SecBlockKey      string
+	SecCSRFKey        string
+	SecCSRFRedirect   string
+	SecEncryptionKey  string
+	SecHashKey        string
+	SecBlockKey       string
+	SecTrustedProxies string
 
 	ButtonStyleGray   string
 	ButtonStyleBlue   string
@@ -32,6 +33,26 @@ type Keys struct {
 
 	RenderWebErrors string
 	RenderAPIErrors string
+
+	PurgeRetention string
+	PurgeInterval  string
+
+	ReminderInterval   string
+	ReminderNotifier   string
+	ReminderWebhookURL string
+
+	AssignmentNotifier   string
+	AssignmentWebhookURL string
+
+	AttachmentMaxSize     string
+	AttachmentTypes       string
+	AttachmentStore       string
+	AttachmentFSPath      string
+	AttachmentS3Endpoint  string
+	AttachmentS3Bucket    string
+	AttachmentS3Region    string
+	AttachmentS3AccessKey string
+	AttachmentS3SecretKey string
 }
 
 var Key = Keys{
@@ -46,11 +67,12 @@ var Key = Keys{
 
 	DBSQLiteDSN: "db.sqlite.dsn",
 
-	SecCSRFKey:       "sec.csrf.key",
-	SecCSRFRedirect:  "sec.csrf.redirect",
-	SecEncryptionKey: "sec.encryption.key",
-	SecHashKey:       "sec.hash.key",
-	SecBlockKey:      "sec.block.key",
+	SecCSRFKey:        "sec.csrf.key",
+	SecCSRFRedirect:   "sec.csrf.redirect",
+	SecEncryptionKey:  "sec.encryption.key",
+	SecHashKey:        "sec.hash.key",
+	SecBlockKey:       "sec.block.key",
+	SecTrustedProxies: "sec.trusted.proxies",
 
 	ButtonStyleGray:   "button.style.gray",
 	ButtonStyleBlue:   "button.style.blue",
@@ -66,4 +88,24 @@ var Key = Keys{
 
 	RenderWebErrors: "render.web.errors",
 	RenderAPIErrors: "render.api.errors",
+
+	PurgeRetention: "purge.retention",
+	PurgeInterval:  "purge.interval",
+
+	ReminderInterval:   "reminder.interval",
+	ReminderNotifier:   "reminder.notifier",
+	ReminderWebhookURL: "reminder.webhook.url",
+
+	AssignmentNotifier:   "assignment.notifier",
+	AssignmentWebhookURL: "assignment.webhook.url",
+
+	AttachmentMaxSize:     "attachment.max.size",
+	AttachmentTypes:       "attachment.types",
+	AttachmentStore:       "attachment.store",
+	AttachmentFSPath:      "attachment.fs.path",
+	AttachmentS3Endpoint:  "attachment.s3.endpoint",
+	AttachmentS3Bucket:    "attachment.s3.bucket",
+	AttachmentS3Region:    "attachment.s3.region",
+	AttachmentS3AccessKey: "attachment.s3.access.key",
+	AttachmentS3SecretKey: "attachment.s3.secret.key",
 }
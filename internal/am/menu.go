@@ -124,6 +124,24 @@ func (m *Menu) AddListItem(resource Resource, text ...string) {
 	})
 }
 
+// AddTrashItem adds a new MenuItem for listing the soft deleted resources.
+func (m *Menu) AddTrashItem(resourceType string, text ...string) {
+	// TODO: Use a pluralization library to get the plural form of the resource type.
+	action := fmt.Sprintf("list-deleted-%ss", resourceType)
+	btnText := "Trash"
+	if len(text) > 0 {
+		btnText = text[0]
+	}
+	m.Items = append(m.Items, MenuItem{
+		Feat: Feat{
+			Path:   m.Path,
+			Action: action,
+		},
+		Text:  btnText,
+		Style: BtnSecondaryStyle,
+	})
+}
+
 // AddNewItem adds a new MenuItem for creating a new resource.
 func (m *Menu) AddNewItem(resourceType string, text ...string) {
 	action := fmt.Sprintf("new-%s", resourceType)
@@ -36,6 +36,22 @@ func (m *Menu) AddResNewItem(resourceType string, text ...string) {
 	})
 }
 
+// AddResTrashItem adds a new MenuItem for listing soft deleted resources in a RESTful way.
+func (m *Menu) AddResTrashItem(text ...string) {
+	btnText := "Trash"
+	if len(text) > 0 {
+		btnText = text[0]
+	}
+	m.Items = append(m.Items, MenuItem{
+		Feat: Feat{
+			Path:   m.Path,
+			Action: "trash",
+		},
+		Text:  btnText,
+		Style: BtnSecondaryStyle,
+	})
+}
+
 // AddResShowItem adds a new MenuItem for showing a resource in a RESTful way.
 func (m *Menu) AddResShowItem(resource Resource, text ...string) {
 	btnText := "Show"
@@ -101,3 +117,20 @@ func (m *Menu) AddResGenericItem(action, id string, text ...string) {
 		Style: BtnGenericStyle,
 	})
 }
+
+// AddResQueryItem adds a new MenuItem for a RESTful action along with query params, e.g. a saved search.
+func (m *Menu) AddResQueryItem(action string, params map[string]string, text ...string) {
+	btnText := "Query"
+	if len(text) > 0 {
+		btnText = text[0]
+	}
+	m.Items = append(m.Items, MenuItem{
+		Feat: Feat{
+			Path:   m.Path,
+			Action: action,
+		},
+		Text:        btnText,
+		Style:       BtnInfoStyle,
+		QueryParams: params,
+	})
+}
@@ -12,6 +12,8 @@ import (
 type Model interface {
 	Identifiable
 	Auditable
+	Deletable
+	Versioned
 	Stampable
 	Seedable
 }
@@ -50,9 +52,28 @@ type Auditable interface {
 	UpdatedAt() time.Time
 }
 
+// Deletable interface represents an entity that can be soft deleted.
+type Deletable interface {
+	// DeletedBy returns the UUID of the user who deleted the entity.
+	DeletedBy() uuid.UUID
+	// DeletedAt returns the deletion time of the entity, zero if not deleted.
+	DeletedAt() time.Time
+	// IsDeleted returns true if the entity has been soft deleted.
+	IsDeleted() bool
+}
+
+// Versioned interface represents an entity whose updates are guarded by a row version.
+type Versioned interface {
+	// Version returns the row version the entity was read at.
+	Version() int
+	// SetVersion sets the row version expected by the next update.
+	SetVersion(version int)
+}
+
 type Stampable interface {
 	GenCreateValues(userID ...uuid.UUID) // Modified
 	GenUpdateValues(userID ...uuid.UUID) // Modified
+	GenDeleteValues(userID ...uuid.UUID)
 }
 
 // Seedable is an interface for entities that need special logic before being inserted into the database during seeding.
@@ -71,6 +92,9 @@ type BaseModel struct {
 	updatedBy uuid.UUID
 	createdAt time.Time
 	updatedAt time.Time
+	deletedBy uuid.UUID
+	deletedAt time.Time
+	version   int
 	RefValue  string `json:"ref"`
 }
 
@@ -126,6 +150,27 @@ func WithUpdatedAt(updatedAt time.Time) ModelOption {
 	}
 }
 
+// WithDeletedBy sets the deletedBy field of the BaseModel.
+func WithDeletedBy(deletedBy uuid.UUID) ModelOption {
+	return func(m *BaseModel) {
+		m.deletedBy = deletedBy
+	}
+}
+
+// WithDeletedAt sets the deletedAt field of the BaseModel.
+func WithDeletedAt(deletedAt time.Time) ModelOption {
+	return func(m *BaseModel) {
+		m.deletedAt = deletedAt
+	}
+}
+
+// WithVersion sets the version of the BaseModel.
+func WithVersion(version int) ModelOption {
+	return func(m *BaseModel) {
+		m.version = version
+	}
+}
+
 // NewModel creates a new BaseModel with the provided options.
 func NewModel(options ...ModelOption) *BaseModel {
 	m := &BaseModel{}
@@ -215,6 +260,9 @@ func (m *BaseModel) GenCreateValues(userID ...uuid.UUID) { // Modified
 	m.GenShortID()
 	m.createdAt = time.Now()
 	m.updatedAt = m.createdAt
+	if m.version == 0 {
+		m.version = 1
+	}
 	if len(userID) > 0 {
 		m.createdBy = userID[0]
 		m.updatedBy = userID[0]
@@ -230,6 +278,15 @@ func (m *BaseModel) GenUpdateValues(userID ...uuid.UUID) { // Modified
 	}
 }
 
+// GenDeleteValues sets the values for a soft delete.
+// If a userID is provided, it sets the DeletedBy field.
+func (m *BaseModel) GenDeleteValues(userID ...uuid.UUID) {
+	m.deletedAt = time.Now()
+	if len(userID) > 0 {
+		m.deletedBy = userID[0]
+	}
+}
+
 // CreatedBy returns the UUID of the user who created the entity.
 func (m *BaseModel) CreatedBy() uuid.UUID {
 	return m.createdBy
@@ -250,6 +307,31 @@ func (m *BaseModel) UpdatedAt() time.Time {
 	return m.updatedAt
 }
 
+// DeletedBy returns the UUID of the user who deleted the entity.
+func (m *BaseModel) DeletedBy() uuid.UUID {
+	return m.deletedBy
+}
+
+// DeletedAt returns the deletion time of the entity, zero if not deleted.
+func (m *BaseModel) DeletedAt() time.Time {
+	return m.deletedAt
+}
+
+// IsDeleted returns true if the entity has been soft deleted.
+func (m *BaseModel) IsDeleted() bool {
+	return !m.deletedAt.IsZero()
+}
+
+// Version returns the row version the entity was read at.
+func (m *BaseModel) Version() int {
+	return m.version
+}
+
+// SetVersion sets the row version expected by the next update.
+func (m *BaseModel) SetVersion(version int) {
+	m.version = version
+}
+
 func (m *BaseModel) Ref() string {
 	return m.RefValue
 }
@@ -1,6 +1,9 @@
 package am
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 func NewNullString(s string) sql.NullString {
 	if s == "" {
@@ -11,3 +14,14 @@ func NewNullString(s string) sql.NullString {
 		Valid:  true,
 	}
 }
+
+// NewNullTime converts a time to a sql.NullTime, the zero time is stored as NULL.
+func NewNullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{
+		Time:  t,
+		Valid: true,
+	}
+}
@@ -0,0 +1,65 @@
+package am
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OrgHeader is the header API clients use to select the organization a request applies to.
+const OrgHeader = "X-Org-ID"
+
+// OrgCookie is the cookie that remembers the organization chosen with the org switcher.
+const OrgCookie = "aquamarine.org"
+
+// NoOrgID scopes a request to no organization at all, when the actor is unknown or belongs to none.
+// Org filters match nothing with it, unlike a context without an org, which is not scoped.
+var NoOrgID = uuid.Max
+
+type orgContextKey struct{}
+
+// WithOrg returns a new context with the current organization ID stored.
+func WithOrg(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// WithNoOrg returns a new context scoped to no organization, see NoOrgID.
+func WithNoOrg(ctx context.Context) context.Context {
+	return WithOrg(ctx, NoOrgID)
+}
+
+// OrgFromContext returns the current organization ID stored in the context, if any.
+func OrgFromContext(ctx context.Context) (uuid.UUID, bool) {
+	orgID, ok := ctx.Value(orgContextKey{}).(uuid.UUID)
+	if !ok || orgID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return orgID, true
+}
+
+// RequestedOrg returns the organization the client asked for.
+// The header takes precedence over the cookie so that API clients do not depend on browser state.
+func RequestedOrg(r *http.Request) (uuid.UUID, bool) {
+	if orgID, err := uuid.Parse(strings.TrimSpace(r.Header.Get(OrgHeader))); err == nil {
+		return orgID, true
+	}
+	if c, err := r.Cookie(OrgCookie); err == nil {
+		if orgID, err := uuid.Parse(c.Value); err == nil {
+			return orgID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// SetOrgCookie remembers the selected organization for subsequent requests.
+func SetOrgCookie(w http.ResponseWriter, orgID uuid.UUID) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OrgCookie,
+		Value:    orgID.String(),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
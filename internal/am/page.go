@@ -10,11 +10,12 @@ import (
 
 // Page struct represents a web page with data, flash messages, form, menu, and feature information.
 type Page struct {
-	Data  interface{}
-	Flash Flash
-	Form  Form
-	Menu  *Menu
-	Feat  Feat
+	Data     interface{}
+	Flash    Flash
+	Form     Form
+	Menu     *Menu
+	Feat     Feat
+	Switcher *Switcher
 }
 
 // Form struct represents a form with action, method, CSRF token, and a button.
@@ -56,6 +57,7 @@ func NewPage(r *http.Request, data interface{}) *Page {
 		Menu: &Menu{
 			Items: []MenuItem{},
 		},
+		Switcher: SwitcherFromContext(r.Context()),
 	}
 }
 
@@ -0,0 +1,102 @@
+package am
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPurgeRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+// Purgeable is implemented by services that keep soft deleted entities around
+// and are able to hard delete them once they are old enough.
+type Purgeable interface {
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Purger periodically hard deletes soft deleted entities older than the configured retention period.
+type Purger struct {
+	Core
+	targets []Purgeable
+	done    chan struct{}
+	stop    sync.Once
+}
+
+func NewPurger(targets []Purgeable, opts ...Option) *Purger {
+	core := NewCore("purger", opts...)
+	return &Purger{
+		Core:    core,
+		targets: targets,
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs a first purge right away and then one on every interval.
+func (p *Purger) Start(ctx context.Context) error {
+	interval := p.duration(Key.PurgeInterval, defaultPurgeInterval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			p.Purge(ctx)
+
+			select {
+			case <-ticker.C:
+			case <-p.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends the purge loop, it can be called more than once.
+func (p *Purger) Stop(ctx context.Context) error {
+	p.stop.Do(func() {
+		close(p.done)
+	})
+	return nil
+}
+
+// Purge hard deletes everything that was soft deleted before the retention period.
+// Targets see the system actor in the context, the purges are recorded as its doing.
+func (p *Purger) Purge(ctx context.Context) {
+	ctx = WithSystemActor(ctx)
+	retention := p.duration(Key.PurgeRetention, defaultPurgeRetention)
+	before := time.Now().Add(-retention)
+
+	for _, target := range p.targets {
+		purged, err := target.PurgeDeleted(ctx, before)
+		if err != nil {
+			p.Log().Errorf("cannot purge deleted items: %v", err)
+			continue
+		}
+
+		if purged > 0 {
+			p.Log().Infof("Purged %d deleted items older than %s", purged, before.Format(time.RFC3339))
+		}
+	}
+}
+
+func (p *Purger) duration(key string, defVal time.Duration) time.Duration {
+	val, ok := p.Cfg().StrVal(key)
+	if !ok {
+		return defVal
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		p.Log().Errorf("invalid duration for %s: %s", key, val)
+		return defVal
+	}
+
+	return d
+}
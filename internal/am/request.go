@@ -0,0 +1,128 @@
+package am
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type clientIPContextKey struct{}
+
+// WithClientIP returns a new context with the client IP stored.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey{}, ip)
+}
+
+// ClientIP returns the client IP stored in the context, if any.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey{}).(string)
+	return ip
+}
+
+// TrustedProxies are the addresses of the proxies allowed to speak for the client through request headers.
+type TrustedProxies []*net.IPNet
+
+// NewTrustedProxies reads a comma separated list of IPs and CIDRs such as "127.0.0.1,10.0.0.0/8".
+// Entries that cannot be read are skipped, an empty list trusts no one.
+func NewTrustedProxies(list string) TrustedProxies {
+	var proxies TrustedProxies
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * len(ip.To16())
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), 32
+				}
+				proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			}
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			proxies = append(proxies, ipNet)
+		}
+	}
+	return proxies
+}
+
+// TrustedProxiesFromCfg returns the trusted proxies of the configuration, none when cfg is nil.
+func TrustedProxiesFromCfg(cfg *Config) TrustedProxies {
+	if cfg == nil {
+		return nil
+	}
+	return NewTrustedProxies(cfg.StrValOrDef(Key.SecTrustedProxies, ""))
+}
+
+// Contains reports whether the IP belongs to a trusted proxy.
+func (p TrustedProxies) Contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range p {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// FromProxy reports whether the request was sent by a trusted proxy.
+func (p TrustedProxies) FromProxy(r *http.Request) bool {
+	return p.Contains(remoteIP(r))
+}
+
+// ClientIPMw returns a middleware that stores the client IP in the request context.
+// X-Forwarded-For and X-Real-IP are only honored when the request comes from a trusted proxy,
+// otherwise anyone could choose the IP recorded for them.
+func ClientIPMw(cfg *Config) func(http.Handler) http.Handler {
+	proxies := TrustedProxiesFromCfg(cfg)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithClientIP(r.Context(), requestIP(r, proxies))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestIP returns the IP of the client, the closest address in X-Forwarded-For that is not a trusted proxy
+// when the request comes through one.
+func requestIP(r *http.Request, proxies TrustedProxies) string {
+	ip := remoteIP(r)
+	if !proxies.Contains(ip) {
+		return ip
+	}
+
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		hops := strings.Split(fwd, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			ip = hop
+			if !proxies.Contains(hop) {
+				break
+			}
+		}
+		return ip
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return ip
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -14,6 +14,8 @@ const (
 	ErrorCodeInternalError = "INTERNAL_ERROR"
 	ErrorCodeBadRequest    = "BAD_REQUEST"
 	ErrorCodeNotFound      = "NOT_FOUND"
+	ErrorCodeConflict      = "CONFLICT"
+	ErrorCodePrecondition  = "PRECONDITION_FAILED"
 )
 
 type Response struct {
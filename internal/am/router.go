@@ -38,6 +38,8 @@ func NewWebRouter(name string, opts ...Option) *Router {
 	csrf := CSRFMw(cfg)
 
 	r.Use(MethodOverrideMw)
+	r.Use(ClientIPMw(cfg))
+	r.Use(ActorMw(cfg))
 	r.Use(csrf)
 
 	//flash := NewFlashMiddleware(hashKey)
@@ -55,10 +57,22 @@ func NewAPIRouter(name string, opts ...Option) *Router {
 	}
 
 	r.Use(MethodOverrideMw)
+	r.Use(ClientIPMw(core.Cfg()))
+	r.Use(ActorMw(core.Cfg()))
 
 	return r
 }
 
+// WithMiddleware adds middlewares to a router created with NewRouter.
+// They are registered before any route is defined, as chi requires.
+func WithMiddleware(mws ...func(http.Handler) http.Handler) Option {
+	return func(c Core) {
+		if router, ok := c.(*Router); ok {
+			router.Use(mws...)
+		}
+	}
+}
+
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -1,5 +1,9 @@
 package am
 
+import (
+	"context"
+)
+
 type Service struct {
 	Core
 }
@@ -10,3 +14,30 @@ func NewService(name string, opts ...Option) *Service {
 		Core: core,
 	}
 }
+
+// StampCreate sets the creation values of the model using the actor in the context, if any.
+func (svc *Service) StampCreate(ctx context.Context, m Stampable) {
+	if userID, ok := ActorFromContext(ctx); ok {
+		m.GenCreateValues(userID)
+		return
+	}
+	m.GenCreateValues()
+}
+
+// StampUpdate sets the update values of the model using the actor in the context, if any.
+func (svc *Service) StampUpdate(ctx context.Context, m Stampable) {
+	if userID, ok := ActorFromContext(ctx); ok {
+		m.GenUpdateValues(userID)
+		return
+	}
+	m.GenUpdateValues()
+}
+
+// StampDelete sets the soft delete values of the model using the actor in the context, if any.
+func (svc *Service) StampDelete(ctx context.Context, m Stampable) {
+	if userID, ok := ActorFromContext(ctx); ok {
+		m.GenDeleteValues(userID)
+		return
+	}
+	m.GenDeleteValues()
+}
@@ -0,0 +1,33 @@
+package am
+
+import "context"
+
+// Switcher is a selector rendered by the layout to change the context pages are scoped to,
+// e.g. the current organization.
+type Switcher struct {
+	Label    string
+	Action   string
+	Field    string
+	Selected string
+	Return   string
+	Options  []SwitcherOption
+}
+
+// SwitcherOption is one of the values a Switcher can select.
+type SwitcherOption struct {
+	Value string
+	Label string
+}
+
+type switcherContextKey struct{}
+
+// WithSwitcher returns a new context with the switcher stored so that pages can render it.
+func WithSwitcher(ctx context.Context, s *Switcher) context.Context {
+	return context.WithValue(ctx, switcherContextKey{}, s)
+}
+
+// SwitcherFromContext returns the switcher stored in the context, if any.
+func SwitcherFromContext(ctx context.Context) *Switcher {
+	s, _ := ctx.Value(switcherContextKey{}).(*Switcher)
+	return s
+}
@@ -14,11 +14,17 @@ import (
 const (
 	layoutPath    = "assets/template/layout"
 	handlerPath   = "assets/template/handler"
+	sharedPath    = "assets/template/partial"
 	partialDir    = "partial"
 	defaultLayout = "layout.tmpl"
 	mainTemplate  = "page"
 )
 
+// templateFuncs are the functions every template can use.
+var templateFuncs = template.FuncMap{
+	"actorName": ActorName,
+}
+
 type TemplateManager struct {
 	Core
 	assetsFS  embed.FS
@@ -83,7 +89,18 @@ func (tm *TemplateManager) loadTemplate(key, path, handler string) {
 		return
 	}
 
+	shared, err := tm.assetsFS.ReadDir(sharedPath)
+	if err != nil {
+		tm.Log().Error("Failed to read shared partials directory: ", err)
+		return
+	}
+
 	partialPaths := []string{}
+	for _, partial := range shared {
+		partialPath := filepath.Join(sharedPath, partial.Name())
+		partialPaths = append(partialPaths, partialPath)
+		tm.Log().Debugf("Found shared partial: %s", partialPath)
+	}
 	for _, partial := range partials {
 		partialPath := filepath.Join(handlerPath, handler, partialDir, partial.Name())
 		partialPaths = append(partialPaths, partialPath)
@@ -96,8 +113,7 @@ func (tm *TemplateManager) loadTemplate(key, path, handler string) {
 	allPaths := append([]string{layoutPath, path}, partialPaths...)
 	tm.Log().Debugf("All template paths: %v", allPaths)
 
-	tmpl := template.New(mainTemplate)
-	//RegisterTemplateFuncs(tmpl)
+	tmpl := template.New(mainTemplate).Funcs(templateFuncs)
 
 	tmpl, err = tmpl.ParseFS(tm.assetsFS, allPaths...)
 	if err != nil {
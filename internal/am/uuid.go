@@ -17,3 +17,8 @@ func ParseUUID(s sql.NullString) uuid.UUID {
 	}
 	return uuid.Nil
 }
+
+// NullUUID converts a UUID to a sql.NullString, uuid.Nil is stored as NULL.
+func NullUUID(id uuid.UUID) sql.NullString {
+	return sql.NullString{String: id.String(), Valid: id != uuid.Nil}
+}
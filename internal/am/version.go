@@ -0,0 +1,79 @@
+package am
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VersionField  = "version"
+	ETagHeader    = "ETag"
+	IfMatchHeader = "If-Match"
+)
+
+// ConflictError is returned when an update was made against a stale version of an entity.
+type ConflictError struct {
+	Type    string
+	ID      uuid.UUID
+	Version int
+}
+
+// NewConflictError creates a conflict error for the entity and the version the caller expected.
+func NewConflictError(typ string, id uuid.UUID, version int) *ConflictError {
+	return &ConflictError{Type: typ, ID: id, Version: version}
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified or deleted since version %d", e.Type, e.ID, e.Version)
+}
+
+// IsConflict returns true if err is or wraps a ConflictError.
+func IsConflict(err error) bool {
+	var ce *ConflictError
+	return errors.As(err, &ce)
+}
+
+// FormVersion reads the version hidden field from a submitted form.
+// It returns false if the field is missing or malformed.
+func FormVersion(r *http.Request) (int, bool) {
+	v, err := strconv.Atoi(r.FormValue(VersionField))
+	if err != nil || v < 1 {
+		return 0, false
+	}
+	return v, true
+}
+
+// ETag returns the entity tag for a version.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// SetETag writes the entity tag of a versioned entity to the response headers.
+func SetETag(w http.ResponseWriter, v Versioned) {
+	w.Header().Set(ETagHeader, ETag(v.Version()))
+}
+
+// IfMatchVersion parses the If-Match request header into a version.
+// The first return value is false if the header is absent or set to "*".
+// An error is returned if the header is present but is not a version tag.
+func IfMatchVersion(r *http.Request) (int, bool, error) {
+	h := strings.TrimSpace(r.Header.Get(IfMatchHeader))
+	if h == "" || h == "*" {
+		return 0, false, nil
+	}
+	h = strings.TrimPrefix(h, "W/")
+	tag, err := strconv.Unquote(h)
+	if err != nil {
+		tag = h
+	}
+	v, err := strconv.Atoi(tag)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s header: %s", IfMatchHeader, r.Header.Get(IfMatchHeader))
+	}
+	return v, true, nil
+}
@@ -2,6 +2,8 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/aquamarinepk/todo/internal/am"
@@ -50,6 +52,7 @@ func (h *APIHandler) ShowUser(w http.ResponseWriter, r *http.Request) {
 		am.Respond(w, http.StatusNotFound, res)
 		return
 	}
+	am.SetETag(w, user)
 	res = am.NewSuccessResponse("User retrieved successfully", user)
 	am.Respond(w, http.StatusOK, res)
 }
@@ -61,7 +64,6 @@ func (h *APIHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		am.Respond(w, http.StatusBadRequest, res)
 		return
 	}
-	user.GenCreateValues()
 	if err := h.service.CreateUser(r.Context(), user); err != nil {
 		res := am.NewErrorResponse("Failed to create user", am.ErrorCodeInternalError, err.Error())
 		am.Respond(w, http.StatusInternalServerError, res)
@@ -88,11 +90,19 @@ func (h *APIHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	user.Name = payload.Name
+	if !h.checkIfMatch(w, r, user) {
+		return
+	}
 	if err := h.service.UpdateUser(r.Context(), user); err != nil {
+		if am.IsConflict(err) {
+			h.respondConflict(w, r, err)
+			return
+		}
 		res := am.NewErrorResponse("Failed to update user", am.ErrorCodeInternalError, err.Error())
 		am.Respond(w, http.StatusInternalServerError, res)
 		return
 	}
+	am.SetETag(w, user)
 	res := am.NewSuccessResponse("User updated successfully", user)
 	am.Respond(w, http.StatusOK, res)
 }
@@ -115,6 +125,41 @@ func (h *APIHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	am.Respond(w, http.StatusNoContent, res)
 }
 
+// BulkUsers runs a bulk command on several users, e.g. {"command": "add-role", "user_ids": [...], "role_id": "<id>"}.
+// The response holds the result of each user; nothing is applied, and 409 is returned, if any of them failed.
+func (h *APIHandler) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	var cmd UserBulkCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		res := am.NewErrorResponse("Invalid request payload", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	}
+	batch, err := h.service.BulkUsers(r.Context(), cmd)
+	switch {
+	case errors.Is(err, ErrInvalidBulk):
+		res := am.NewErrorResponse("Invalid bulk command", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	case errors.Is(err, ErrRoleNotFound):
+		res := am.NewErrorResponse("Role not found", am.ErrorCodeNotFound, err.Error())
+		am.Respond(w, http.StatusNotFound, res)
+		return
+	case err != nil:
+		res := am.NewErrorResponse("Failed to run bulk command", am.ErrorCodeInternalError, err.Error())
+		am.Respond(w, http.StatusInternalServerError, res)
+		return
+	}
+	if !batch.Applied {
+		details := fmt.Sprintf("%d of %d users failed", len(batch.Failed()), len(batch.Results))
+		res := am.NewErrorResponse("Bulk command not applied", am.ErrorCodeConflict, details)
+		res.Data = batch
+		am.Respond(w, http.StatusConflict, res)
+		return
+	}
+	res := am.NewSuccessResponse("Bulk command applied", batch)
+	am.Respond(w, http.StatusOK, res)
+}
+
 func (h *APIHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 	var role Role
 	if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
@@ -122,7 +167,6 @@ func (h *APIHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 		am.Respond(w, http.StatusBadRequest, res)
 		return
 	}
-	role.GenCreateValues()
 	if err := h.service.CreateRole(r.Context(), role); err != nil {
 		res := am.NewErrorResponse("Failed to create role", am.ErrorCodeInternalError, err.Error())
 		am.Respond(w, http.StatusInternalServerError, res)
@@ -152,11 +196,19 @@ func (h *APIHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 	}
 	role.Name = payload.Name
 	role.Description = payload.Description
+	if !h.checkIfMatch(w, r, role) {
+		return
+	}
 	if err := h.service.UpdateRole(r.Context(), role); err != nil {
+		if am.IsConflict(err) {
+			h.respondConflict(w, r, err)
+			return
+		}
 		res := am.NewErrorResponse("Failed to update role", am.ErrorCodeInternalError, err.Error())
 		am.Respond(w, http.StatusInternalServerError, res)
 		return
 	}
+	am.SetETag(w, role)
 	res := am.NewSuccessResponse("Role updated successfully", role)
 	am.Respond(w, http.StatusOK, res)
 }
@@ -217,3 +269,123 @@ func (h *APIHandler) RemoveRole(w http.ResponseWriter, r *http.Request) {
 	res := am.NewSuccessResponse("Role removed from user successfully", nil)
 	am.Respond(w, http.StatusOK, res)
 }
+
+func (h *APIHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	filter, err := FormToAuditFilter(NewAuditFilterForm(r.URL.Query()))
+	if err != nil {
+		res := am.NewErrorResponse("Invalid audit filter", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	}
+	entries, err := h.service.GetAuditEntries(r.Context(), filter)
+	if err != nil {
+		res := am.NewErrorResponse("Failed to list audit entries", am.ErrorCodeInternalError, err.Error())
+		am.Respond(w, http.StatusInternalServerError, res)
+		return
+	}
+	res := am.NewSuccessResponse("Audit entries listed successfully", entries)
+	am.Respond(w, http.StatusOK, res)
+}
+
+// ExplainPermission returns every path through which a user holds a permission.
+func (h *APIHandler) ExplainPermission(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+	if err != nil {
+		res := am.NewErrorResponse("Invalid user ID", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	}
+
+	q, err := explainQuery(r, userID)
+	if err != nil || q.PermissionID == uuid.Nil {
+		if err == nil {
+			err = fmt.Errorf("missing permission_id")
+		}
+		res := am.NewErrorResponse("Invalid explain query", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	}
+
+	exp, err := h.service.ExplainPermission(r.Context(), q)
+	if err != nil {
+		res := am.NewErrorResponse("Cannot explain permission", am.ErrorCodeNotFound, err.Error())
+		am.Respond(w, http.StatusNotFound, res)
+		return
+	}
+
+	msg := "Permission granted"
+	if !exp.Granted {
+		msg = "No path grants the permission"
+	}
+	res := am.NewSuccessResponse(msg, exp)
+	am.Respond(w, http.StatusOK, res)
+}
+
+func (h *APIHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.service.GetPolicies(r.Context())
+	if err != nil {
+		res := am.NewErrorResponse("Failed to list policies", am.ErrorCodeInternalError, err.Error())
+		am.Respond(w, http.StatusInternalServerError, res)
+		return
+	}
+	res := am.NewSuccessResponse("Policies listed successfully", policies)
+	am.Respond(w, http.StatusOK, res)
+}
+
+// Authorize decides whether a user can perform an action, combining RBAC with the policies.
+// Subject and env attributes are resolved on the server, the ones in the payload are ignored.
+func (h *APIHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		UserID uuid.UUID `json:"user_id"`
+		AccessRequest
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		res := am.NewErrorResponse("Invalid request payload", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return
+	}
+
+	decision, err := h.service.Authorize(r.Context(), payload.UserID, payload.AccessRequest)
+	if err != nil {
+		res := am.NewErrorResponse("Cannot authorize request", am.ErrorCodeNotFound, err.Error())
+		am.Respond(w, http.StatusNotFound, res)
+		return
+	}
+
+	msg := "Access allowed"
+	if !decision.Allowed {
+		msg = "Access denied"
+	}
+	res := am.NewSuccessResponse(msg, decision)
+	am.Respond(w, http.StatusOK, res)
+}
+
+// checkIfMatch compares the If-Match header, when present, with the current version of the entity.
+// It writes a 412 response and returns false if the client holds a stale version.
+func (h *APIHandler) checkIfMatch(w http.ResponseWriter, r *http.Request, v am.Versioned) bool {
+	version, ok, err := am.IfMatchVersion(r)
+	if err != nil {
+		res := am.NewErrorResponse("Invalid If-Match header", am.ErrorCodeBadRequest, err.Error())
+		am.Respond(w, http.StatusBadRequest, res)
+		return false
+	}
+	if ok && version != v.Version() {
+		am.SetETag(w, v)
+		res := am.NewErrorResponse(am.ErrPreconditionFailed, am.ErrorCodePrecondition, am.ErrVersionConflict)
+		am.Respond(w, http.StatusPreconditionFailed, res)
+		return false
+	}
+	return true
+}
+
+// respondConflict reports an update that lost a race with a concurrent writer.
+// Requests that sent If-Match get a 412, the rest a 409.
+func (h *APIHandler) respondConflict(w http.ResponseWriter, r *http.Request, err error) {
+	if r.Header.Get(am.IfMatchHeader) != "" {
+		res := am.NewErrorResponse(am.ErrPreconditionFailed, am.ErrorCodePrecondition, err.Error())
+		am.Respond(w, http.StatusPreconditionFailed, res)
+		return
+	}
+	res := am.NewErrorResponse(am.ErrVersionConflict, am.ErrorCodeConflict, err.Error())
+	am.Respond(w, http.StatusConflict, res)
+}
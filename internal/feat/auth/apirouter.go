@@ -10,10 +10,15 @@ func NewAPIRouter(handler *APIHandler, opts ...am.Option) *am.Router {
 	r := am.NewRouter("api-router", opts...)
 
 	r.Get("/", handler.ListUsers)
-	r.Get("/{slug}", handler.ShowUser)
+	r.Get("/audit-entries", handler.ListAuditEntries)
+	r.Get("/explain-permission", handler.ExplainPermission)
+	r.Get("/policies", handler.ListPolicies)
+	r.Post("/authorize", handler.Authorize)
+	r.Get("/{id}", handler.ShowUser)
 	r.Post("/create-user", handler.CreateUser)
 	r.Post("/update-user", handler.UpdateUser)
 	r.Post("/delete-user", handler.DeleteUser)
+	r.Post("/bulk-users", handler.BulkUsers)
 	r.Post("/roles", handler.CreateRole)
 	r.Put("/roles", handler.UpdateRole)
 	r.Delete("/roles", handler.DeleteRole)
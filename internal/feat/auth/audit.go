@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	auditEntryType = "audit-entry"
+	// authPurgeType is the target type of the purges of auth entities, they target no single entity.
+	authPurgeType = "auth"
+)
+
+// Audit actions, they match the names of the commands that trigger them.
+const (
+	ActionCreateUser                   = "create-user"
+	ActionUpdateUser                   = "update-user"
+	ActionUpdateUserPassword           = "update-user-password"
+	ActionDeleteUser                   = "delete-user"
+	ActionRestoreUser                  = "restore-user"
+	ActionActivateUser                 = "activate-user"
+	ActionDeactivateUser               = "deactivate-user"
+	ActionAddRoleToUser                = "add-role-to-user"
+	ActionRemoveRoleFromUser           = "remove-role-from-user"
+	ActionAddPermissionToUser          = "add-permission-to-user"
+	ActionRemovePermissionFromUser     = "remove-permission-from-user"
+	ActionAddContextualRole            = "add-contextual-role"
+	ActionRemoveContextualRole         = "remove-contextual-role"
+	ActionCreateRole                   = "create-role"
+	ActionUpdateRole                   = "update-role"
+	ActionDeleteRole                   = "delete-role"
+	ActionRestoreRole                  = "restore-role"
+	ActionAddPermissionToRole          = "add-permission-to-role"
+	ActionRemovePermissionFromRole     = "remove-permission-from-role"
+	ActionAddRoleParent                = "add-role-parent"
+	ActionRemoveRoleParent             = "remove-role-parent"
+	ActionCreatePermission             = "create-permission"
+	ActionUpdatePermission             = "update-permission"
+	ActionDeletePermission             = "delete-permission"
+	ActionRestorePermission            = "restore-permission"
+	ActionCreateResource               = "create-resource"
+	ActionUpdateResource               = "update-resource"
+	ActionDeleteResource               = "delete-resource"
+	ActionRestoreResource              = "restore-resource"
+	ActionAddPermissionToResource      = "add-permission-to-resource"
+	ActionRemovePermissionFromResource = "remove-permission-from-resource"
+	ActionCreateOrg                    = "create-org"
+	ActionUpdateOrg                    = "update-org"
+	ActionDeleteOrg                    = "delete-org"
+	ActionRestoreOrg                   = "restore-org"
+	ActionAddUserToOrg                 = "add-user-to-org"
+	ActionRemoveUserFromOrg            = "remove-user-from-org"
+	ActionAddOrgOwner                  = "add-org-owner"
+	ActionRemoveOrgOwner               = "remove-org-owner"
+	ActionCreateTeam                   = "create-team"
+	ActionUpdateTeam                   = "update-team"
+	ActionDeleteTeam                   = "delete-team"
+	ActionRestoreTeam                  = "restore-team"
+	ActionAssignUserToTeam             = "assign-user-to-team"
+	ActionRemoveUserFromTeam           = "remove-user-from-team"
+	ActionCreateInvitation             = "create-invitation"
+	ActionAcceptInvitation             = "accept-invitation"
+	ActionDeclineInvitation            = "decline-invitation"
+	ActionRevokeInvitation             = "revoke-invitation"
+	ActionCreatePolicy                 = "create-policy"
+	ActionUpdatePolicy                 = "update-policy"
+	ActionDeletePolicy                 = "delete-policy"
+	ActionPurgeDeleted                 = "purge-deleted"
+)
+
+// PurgeSummary is the after snapshot of a purge: the deletion cutoff and how many entities were hard deleted.
+type PurgeSummary struct {
+	Before time.Time `json:"before"`
+	Purged int64     `json:"purged"`
+}
+
+// AuditEntry is an append-only record of an administrative change.
+type AuditEntry struct {
+	*am.BaseModel
+	ActorID    uuid.UUID `json:"actor_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   uuid.UUID `json:"target_id"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	IP         string    `json:"ip"`
+}
+
+// NewAuditEntry creates an audit entry, before and after are stored as JSON snapshots.
+func NewAuditEntry(action, targetType string, targetID uuid.UUID, before, after any) AuditEntry {
+	return AuditEntry{
+		BaseModel:  am.NewModel(am.WithType(auditEntryType)),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     toAuditJSON(before),
+		After:      toAuditJSON(after),
+	}
+}
+
+// BySystem reports whether the change was made by the application on its own, such as a purge.
+func (e AuditEntry) BySystem() bool {
+	return e.ActorID == am.SystemActorID
+}
+
+// MarshalJSON includes the identity and timestamp of the entry, both are part of the record.
+func (e AuditEntry) MarshalJSON() ([]byte, error) {
+	type Alias AuditEntry
+	return json.Marshal(struct {
+		ID        uuid.UUID `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+		Alias
+	}{
+		ID:        e.ID(),
+		CreatedAt: e.CreatedAt(),
+		Alias:     Alias(e),
+	})
+}
+
+// AuditFilter holds the optional criteria used to list audit entries.
+// Zero values are ignored.
+type AuditFilter struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+const defaultAuditLimit = 200
+
+// auditRef builds a snapshot for relationship changes (i.e. role to user).
+type auditRef map[string]string
+
+func toAuditJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
+
+// userSnapshot returns the non sensitive user fields that are recorded in the audit log.
+func userSnapshot(user User) map[string]any {
+	return map[string]any{
+		"username":  user.Username,
+		"name":      user.Name,
+		"is_active": user.IsActive,
+	}
+}
+
+// invitationSnapshot returns the invitation fields that are recorded in the audit log, the email is left out.
+func invitationSnapshot(inv Invitation) map[string]any {
+	return map[string]any{
+		"org_id":     inv.OrgID,
+		"team_id":    inv.TeamID,
+		"role_id":    inv.RoleID,
+		"status":     inv.Status,
+		"expires_at": inv.ExpiresAt,
+		"user_id":    inv.UserID,
+	}
+}
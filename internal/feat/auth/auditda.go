@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntryDA represents the data access layer for the AuditEntry model.
+type AuditEntryDA struct {
+	ID         uuid.UUID      `db:"id"`
+	ShortID    sql.NullString `db:"short_id"`
+	ActorID    sql.NullString `db:"actor_id"`
+	Action     sql.NullString `db:"action"`
+	TargetType sql.NullString `db:"target_type"`
+	TargetID   sql.NullString `db:"target_id"`
+	Before     sql.NullString `db:"before_data"`
+	After      sql.NullString `db:"after_data"`
+	IP         sql.NullString `db:"ip"`
+	CreatedAt  sql.NullTime   `db:"created_at"`
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Bulk user commands.
+const (
+	BulkActivate   = "activate"
+	BulkDeactivate = "deactivate"
+	BulkDelete     = "delete"
+	BulkAddRole    = "add-role"
+	BulkRemoveRole = "remove-role"
+)
+
+// ErrInvalidBulk is returned when a bulk command is unknown or misses one of its arguments.
+var ErrInvalidBulk = errors.New("invalid bulk command")
+
+// UserBulkCommand is a command applied to several users at once, RoleID is the role of add-role and remove-role.
+type UserBulkCommand struct {
+	Command string      `json:"command"`
+	UserIDs []uuid.UUID `json:"user_ids"`
+	RoleID  uuid.UUID   `json:"role_id,omitempty"`
+}
+
+// Validate checks the command is known, has users to run on and has a role when it needs one.
+func (c UserBulkCommand) Validate() error {
+	switch c.Command {
+	case BulkActivate, BulkDeactivate, BulkDelete:
+	case BulkAddRole, BulkRemoveRole:
+		if c.RoleID == uuid.Nil {
+			return fmt.Errorf("%w: %s needs a role", ErrInvalidBulk, c.Command)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidBulk, c.Command)
+	}
+	if len(c.UserIDs) == 0 {
+		return fmt.Errorf("%w: no users selected", ErrInvalidBulk)
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ func ToUserDA(user User) UserDA {
 		UpdatedBy:     sql.NullString{String: user.UpdatedBy().String(), Valid: user.UpdatedBy() != uuid.Nil},
 		CreatedAt:     sql.NullTime{Time: user.CreatedAt(), Valid: !user.CreatedAt().IsZero()},
 		UpdatedAt:     sql.NullTime{Time: user.UpdatedAt(), Valid: !user.UpdatedAt().IsZero()},
+		Version:       user.Version(),
+		DeletedBy:     sql.NullString{String: user.DeletedBy().String(), Valid: user.DeletedBy() != uuid.Nil},
+		DeletedAt:     sql.NullTime{Time: user.DeletedAt(), Valid: user.IsDeleted()},
 		LastLoginAt:   sql.NullTime{Time: derefTime(user.LastLoginAt), Valid: user.LastLoginAt != nil},
 		LastLoginIP:   sql.NullString{String: user.LastLoginIP, Valid: user.LastLoginIP != ""},
 		IsActive:      sql.NullBool{Bool: user.IsActive, Valid: true},
@@ -46,6 +49,9 @@ func ToUser(da UserDA) User {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+			am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+			am.WithDeletedAt(da.DeletedAt.Time),
 		),
 		Name:        da.Name.String,
 		Username:    da.Username.String,
@@ -79,6 +85,7 @@ func ToUserExt(da UserExtDA) User {
 		UpdatedBy:   da.UpdatedBy,
 		CreatedAt:   da.CreatedAt,
 		UpdatedAt:   da.UpdatedAt,
+		Version:     da.Version,
 		LastLoginAt: da.LastLoginAt,
 		LastLoginIP: da.LastLoginIP,
 		IsActive:    da.IsActive,
@@ -124,6 +131,9 @@ func ToRoleDA(role Role) RoleDA {
 		UpdatedBy:   sql.NullString{String: role.UpdatedBy().String(), Valid: role.UpdatedBy() != uuid.Nil},
 		CreatedAt:   sql.NullTime{Time: role.CreatedAt(), Valid: !role.CreatedAt().IsZero()},
 		UpdatedAt:   sql.NullTime{Time: role.UpdatedAt(), Valid: !role.UpdatedAt().IsZero()},
+		Version:     role.Version(),
+		DeletedBy:   sql.NullString{String: role.DeletedBy().String(), Valid: role.DeletedBy() != uuid.Nil},
+		DeletedAt:   sql.NullTime{Time: role.DeletedAt(), Valid: role.IsDeleted()},
 	}
 }
 
@@ -138,6 +148,9 @@ func ToRole(da RoleDA) Role {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+			am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+			am.WithDeletedAt(da.DeletedAt.Time),
 		),
 		Name:          da.Name.String,
 		Description:   da.Description.String,
@@ -174,6 +187,7 @@ func ToRoleExt(da RoleExtDA) Role {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
 		),
 		Name:        da.Name.String,
 		Description: da.Description.String,
@@ -193,6 +207,9 @@ func ToPermissionDA(permission Permission) PermissionDA {
 		UpdatedBy:   sql.NullString{String: permission.UpdatedBy().String(), Valid: permission.UpdatedBy() != uuid.Nil},
 		CreatedAt:   sql.NullTime{Time: permission.CreatedAt(), Valid: !permission.CreatedAt().IsZero()},
 		UpdatedAt:   sql.NullTime{Time: permission.UpdatedAt(), Valid: !permission.UpdatedAt().IsZero()},
+		Version:     permission.Version(),
+		DeletedBy:   sql.NullString{String: permission.DeletedBy().String(), Valid: permission.DeletedBy() != uuid.Nil},
+		DeletedAt:   sql.NullTime{Time: permission.DeletedAt(), Valid: permission.IsDeleted()},
 	}
 }
 
@@ -207,6 +224,9 @@ func ToPermission(da PermissionDA) Permission {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+			am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+			am.WithDeletedAt(da.DeletedAt.Time),
 		),
 		Name:        da.Name.String,
 		Description: da.Description.String,
@@ -237,6 +257,9 @@ func ToResourceDA(resource Resource) ResourceDA {
 		UpdatedBy:   sql.NullString{String: resource.UpdatedBy().String(), Valid: resource.UpdatedBy() != uuid.Nil},
 		CreatedAt:   sql.NullTime{Time: resource.CreatedAt(), Valid: !resource.CreatedAt().IsZero()},
 		UpdatedAt:   sql.NullTime{Time: resource.UpdatedAt(), Valid: !resource.UpdatedAt().IsZero()},
+		Version:     resource.Version(),
+		DeletedBy:   sql.NullString{String: resource.DeletedBy().String(), Valid: resource.DeletedBy() != uuid.Nil},
+		DeletedAt:   sql.NullTime{Time: resource.DeletedAt(), Valid: resource.IsDeleted()},
 	}
 }
 
@@ -251,6 +274,9 @@ func ToResource(da ResourceDA) Resource {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+			am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+			am.WithDeletedAt(da.DeletedAt.Time),
 		),
 		Name:          da.Name.String,
 		Description:   da.Description.String,
@@ -289,6 +315,7 @@ func ToResourceExt(da ResourceExtDA) Resource {
 			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
 		),
 		Name:          da.Name.String,
 		Description:   da.Description.String,
@@ -309,6 +336,9 @@ type OrgDA struct {
 	UpdatedBy        sql.NullString `db:"updated_by"`
 	CreatedAt        time.Time      `db:"created_at"`
 	UpdatedAt        time.Time      `db:"updated_at"`
+	Version          int            `db:"version"`
+	DeletedBy        sql.NullString `db:"deleted_by"`
+	DeletedAt        sql.NullTime   `db:"deleted_at"`
 }
 
 // ToOrg converts OrgDA to Org domain model.
@@ -320,6 +350,9 @@ func ToOrg(da OrgDA) Org {
 		am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 		am.WithCreatedAt(da.CreatedAt),
 		am.WithUpdatedAt(da.UpdatedAt),
+		am.WithVersion(da.Version),
+		am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+		am.WithDeletedAt(da.DeletedAt.Time),
 		am.WithType(orgEntityType),
 	)
 	return Org{
@@ -342,6 +375,9 @@ func ToOrgDA(org Org) OrgDA {
 		UpdatedBy:        sql.NullString{String: org.UpdatedBy().String(), Valid: org.UpdatedBy() != uuid.Nil},
 		CreatedAt:        org.CreatedAt(),
 		UpdatedAt:        org.UpdatedAt(),
+		Version:          org.Version(),
+		DeletedBy:        sql.NullString{String: org.DeletedBy().String(), Valid: org.DeletedBy() != uuid.Nil},
+		DeletedAt:        sql.NullTime{Time: org.DeletedAt(), Valid: org.IsDeleted()},
 	}
 }
 
@@ -353,11 +389,15 @@ func ToTeam(da TeamDA) Team {
 		am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 		am.WithCreatedAt(da.CreatedAt),
 		am.WithUpdatedAt(da.UpdatedAt),
+		am.WithVersion(da.Version),
+		am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+		am.WithDeletedAt(da.DeletedAt.Time),
 		am.WithType(teamEntityType),
 	)
 	return Team{
 		BaseModel:        model,
 		OrgID:            am.ParseUUID(da.OrgID),
+		ParentID:         am.ParseUUID(da.ParentID),
 		Name:             da.Name,
 		ShortDescription: da.ShortDescription,
 		Description:      da.Description,
@@ -386,3 +426,49 @@ func derefTime(t *time.Time) time.Time {
 	}
 	return *t
 }
+
+// ToAuditEntryDA converts an AuditEntry business object to an AuditEntryDA data access object
+func ToAuditEntryDA(entry AuditEntry) AuditEntryDA {
+	return AuditEntryDA{
+		ID:         entry.ID(),
+		ShortID:    sql.NullString{String: entry.ShortID(), Valid: entry.ShortID() != ""},
+		ActorID:    sql.NullString{String: entry.ActorID.String(), Valid: entry.ActorID != uuid.Nil},
+		Action:     sql.NullString{String: entry.Action, Valid: entry.Action != ""},
+		TargetType: sql.NullString{String: entry.TargetType, Valid: entry.TargetType != ""},
+		TargetID:   sql.NullString{String: entry.TargetID.String(), Valid: entry.TargetID != uuid.Nil},
+		Before:     sql.NullString{String: entry.Before, Valid: entry.Before != ""},
+		After:      sql.NullString{String: entry.After, Valid: entry.After != ""},
+		IP:         sql.NullString{String: entry.IP, Valid: entry.IP != ""},
+		CreatedAt:  sql.NullTime{Time: entry.CreatedAt(), Valid: !entry.CreatedAt().IsZero()},
+	}
+}
+
+// ToAuditEntry converts an AuditEntryDA data access object to an AuditEntry business object
+func ToAuditEntry(da AuditEntryDA) AuditEntry {
+	return AuditEntry{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithShortID(da.ShortID.String),
+			am.WithType(auditEntryType),
+			am.WithCreatedBy(am.ParseUUID(da.ActorID)),
+			am.WithCreatedAt(da.CreatedAt.Time),
+			am.WithUpdatedAt(da.CreatedAt.Time),
+		),
+		ActorID:    am.ParseUUID(da.ActorID),
+		Action:     da.Action.String,
+		TargetType: da.TargetType.String,
+		TargetID:   am.ParseUUID(da.TargetID),
+		Before:     da.Before.String,
+		After:      da.After.String,
+		IP:         da.IP.String,
+	}
+}
+
+// ToAuditEntries converts a slice of AuditEntryDA to a slice of AuditEntry business objects
+func ToAuditEntries(das []AuditEntryDA) []AuditEntry {
+	entries := make([]AuditEntry, len(das))
+	for i, da := range das {
+		entries[i] = ToAuditEntry(da)
+	}
+	return entries
+}
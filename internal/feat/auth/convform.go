@@ -1,7 +1,14 @@
 package auth
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 // FormToUser converts a UserForm to a User entity.
@@ -29,3 +36,165 @@ func FormToPermission(form PermissionForm) Permission {
 func FormToResource(form ResourceForm) Resource {
 	return NewResource(form.Name, form.Description, form.Type)
 }
+
+// NewAuditFilterForm reads an AuditFilterForm from the request query values.
+func NewAuditFilterForm(values url.Values) AuditFilterForm {
+	return AuditFilterForm{
+		ActorID:    values.Get("actor_id"),
+		Action:     values.Get("action"),
+		TargetType: values.Get("target_type"),
+		TargetID:   values.Get("target_id"),
+		From:       values.Get("from"),
+		To:         values.Get("to"),
+	}
+}
+
+// FormToAuditFilter converts an AuditFilterForm to an AuditFilter.
+// Dates are expected in YYYY-MM-DD format, the To date is inclusive.
+func FormToAuditFilter(form AuditFilterForm) (AuditFilter, error) {
+	filter := AuditFilter{
+		Action:     form.Action,
+		TargetType: form.TargetType,
+	}
+
+	var err error
+	if form.ActorID != "" {
+		filter.ActorID, err = uuid.Parse(form.ActorID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid actor_id: %w", err)
+		}
+	}
+
+	if form.TargetID != "" {
+		filter.TargetID, err = uuid.Parse(form.TargetID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid target_id: %w", err)
+		}
+	}
+
+	if form.From != "" {
+		filter.From, err = time.ParseInLocation(time.DateOnly, form.From, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from date: %w", err)
+		}
+	}
+
+	if form.To != "" {
+		to, err := time.ParseInLocation(time.DateOnly, form.To, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to date: %w", err)
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+// NewPolicyForm reads a PolicyForm from the submitted form values.
+func NewPolicyForm(values url.Values) PolicyForm {
+	return PolicyForm{
+		Name:        values.Get("name"),
+		Description: values.Get("description"),
+		Effect:      values.Get("effect"),
+		Actions:     values.Get("actions"),
+		Resources:   values.Get("resources"),
+		Conditions:  values.Get("conditions"),
+		Enabled:     values.Get("enabled") != "",
+	}
+}
+
+// PolicyToForm converts a Policy to a PolicyForm, conditions are indented to ease editing.
+func PolicyToForm(p Policy) PolicyForm {
+	conditions := "[]"
+	if len(p.Conditions) > 0 {
+		b, err := json.MarshalIndent(p.Conditions, "", "  ")
+		if err == nil {
+			conditions = string(b)
+		}
+	}
+
+	return PolicyForm{
+		Name:        p.Name,
+		Description: p.Description,
+		Effect:      p.Effect,
+		Actions:     strings.Join(p.Actions, ", "),
+		Resources:   strings.Join(p.Resources, ", "),
+		Conditions:  conditions,
+		Enabled:     p.Enabled,
+	}
+}
+
+// FormToPolicy converts a PolicyForm to a Policy entity.
+func FormToPolicy(form PolicyForm) (Policy, error) {
+	p := NewPolicy(strings.TrimSpace(form.Name), form.Description, form.Effect)
+	p.Actions = splitList(form.Actions)
+	p.Resources = splitList(form.Resources)
+	p.Enabled = form.Enabled
+
+	if strings.TrimSpace(form.Conditions) != "" {
+		err := json.Unmarshal([]byte(form.Conditions), &p.Conditions)
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid conditions: %w", err)
+		}
+	}
+
+	return p, p.Validate()
+}
+
+// NewPolicyTestForm reads a PolicyTestForm from the request query values.
+func NewPolicyTestForm(values url.Values) PolicyTestForm {
+	return PolicyTestForm{
+		UserID:       values.Get("user_id"),
+		Action:       values.Get("action"),
+		ResourceType: values.Get("resource_type"),
+		Resource:     values.Get("resource"),
+		Env:          values.Get("env"),
+	}
+}
+
+// FormToAccessRequest converts a PolicyTestForm to the user and the request to authorize.
+func FormToAccessRequest(form PolicyTestForm) (uuid.UUID, AccessRequest, error) {
+	userID, err := uuid.Parse(form.UserID)
+	if err != nil {
+		return uuid.Nil, AccessRequest{}, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	req := AccessRequest{
+		Action:       strings.TrimSpace(form.Action),
+		ResourceType: strings.TrimSpace(form.ResourceType),
+		Resource:     parseAttrs(form.Resource),
+		Env:          parseAttrs(form.Env),
+	}
+	return userID, req, nil
+}
+
+// parseAttrs reads key=value lines, values with commas become lists.
+func parseAttrs(text string) map[string]any {
+	attrs := make(map[string]any)
+	for _, line := range strings.Split(text, "\n") {
+		k, v, ok := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if strings.Contains(v, ",") {
+			attrs[k] = splitList(v)
+			continue
+		}
+		attrs[k] = v
+	}
+	return attrs
+}
+
+// splitList splits a comma separated value, dropping empty items.
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -53,3 +53,27 @@ func TestHashAndCheckPassword(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateAndHashToken(t *testing.T) {
+	token, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("token generation failed: %v", err)
+	}
+
+	other, err := GenerateToken()
+	if err != nil {
+		t.Fatalf("token generation failed: %v", err)
+	}
+
+	if token == other {
+		t.Errorf("expected different tokens, got %q twice", token)
+	}
+
+	if HashToken(token) != HashToken(token) {
+		t.Errorf("expected hash of %q to be stable", token)
+	}
+
+	if HashToken(token) == HashToken(other) {
+		t.Errorf("expected different hashes for different tokens")
+	}
+}
@@ -3,7 +3,17 @@ package auth
 import "errors"
 
 var (
+	ErrUserNotFound       = errors.New("user not found")
 	ErrRoleNotFound       = errors.New("role not found")
 	ErrPermissionNotFound = errors.New("permission not found")
 	ErrResourceNotFound   = errors.New("resource not found")
+	ErrNotDeleted         = errors.New("item is not in the trash")
+	ErrNotInOrg           = errors.New("item does not belong to the current org")
+	ErrRoleCycle          = errors.New("role cannot extend itself or a role that extends it")
+	ErrTeamCycle          = errors.New("team cannot be nested under itself or one of its descendants")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationClosed   = errors.New("invitation is no longer pending")
+	ErrInvitationExpired  = errors.New("invitation has expired")
+	ErrPolicyNotFound     = errors.New("policy not found")
+	ErrPolicyReadOnly     = errors.New("policy is defined in a file and cannot be changed")
 )
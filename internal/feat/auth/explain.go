@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"github.com/google/uuid"
+)
+
+// Kinds of paths through which a user can hold a permission.
+const (
+	GrantDirect         = "direct"
+	GrantRole           = "role"
+	GrantContextualRole = "contextual-role"
+	GrantResource       = "resource"
+)
+
+// ExplainQuery asks why a user holds a permission.
+// Resource and team are optional, when a team is given only the contextual roles that apply to it are considered.
+type ExplainQuery struct {
+	UserID       uuid.UUID `json:"user_id"`
+	PermissionID uuid.UUID `json:"permission_id"`
+	ResourceID   uuid.UUID `json:"resource_id"`
+	TeamID       uuid.UUID `json:"team_id"`
+}
+
+// GrantPath is one of the ways a user holds a permission.
+type GrantPath struct {
+	Kind    string `json:"kind"`
+	Summary string `json:"summary"`
+	// Role is the role assigned to the user, Via lists the roles it extends up to the one holding the permission.
+	Role        *Role  `json:"role,omitempty"`
+	Via         []Role `json:"via,omitempty"`
+	ContextType string `json:"context_type,omitempty"`
+	ContextID   string `json:"context_id,omitempty"`
+	ContextName string `json:"context_name,omitempty"`
+}
+
+// PermissionExplanation lists every path that grants a permission to a user.
+// When a resource is given the permission also has to be bound to it for the user to be granted it.
+type PermissionExplanation struct {
+	User          User        `json:"user"`
+	Permission    Permission  `json:"permission"`
+	Resource      *Resource   `json:"resource,omitempty"`
+	Team          *Team       `json:"team,omitempty"`
+	Paths         []GrantPath `json:"paths"`
+	ResourceBound bool        `json:"resource_bound"`
+	Granted       bool        `json:"granted"`
+}
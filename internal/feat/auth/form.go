@@ -29,3 +29,35 @@ type ResourceForm struct {
 	Type        string `form:"type" required:"true"`
 	URI         string `form:"uri"`
 }
+
+// AuditFilterForm represents the query parameters used to filter the audit log
+type AuditFilterForm struct {
+	ActorID    string `form:"actor_id"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+}
+
+// PolicyForm represents the form data for creating/updating a policy
+// Actions and resources are comma separated, conditions are a JSON list.
+type PolicyForm struct {
+	Name        string `form:"name" required:"true"`
+	Description string `form:"description"`
+	Effect      string `form:"effect" required:"true"`
+	Actions     string `form:"actions" required:"true"`
+	Resources   string `form:"resources"`
+	Conditions  string `form:"conditions"`
+	Enabled     bool   `form:"enabled"`
+}
+
+// PolicyTestForm represents the query parameters of the policy test page
+// Attributes are written one per line as key=value, comma separated values are read as lists.
+type PolicyTestForm struct {
+	UserID       string `form:"user_id"`
+	Action       string `form:"action"`
+	ResourceType string `form:"resource_type"`
+	Resource     string `form:"resource"`
+	Env          string `form:"env"`
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	invitationEntityType = "invitation"
+
+	// DefaultInvitationTTL is how long an invitation can be accepted when no expiry is given.
+	DefaultInvitationTTL = 7 * 24 * time.Hour
+)
+
+// Invitation statuses.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// Invitation asks someone, identified by email, to join an org and optionally one of its teams with a role.
+// Only the hash of the token sent to the invitee is stored.
+type Invitation struct {
+	*am.BaseModel
+	Email       string    `json:"email"`
+	EmailEnc    []byte    `json:"-"`
+	TokenHash   string    `json:"-"`
+	InviterID   uuid.UUID `json:"inviter_id"`
+	OrgID       uuid.UUID `json:"org_id"`
+	TeamID      uuid.UUID `json:"team_id"`
+	RoleID      uuid.UUID `json:"role_id"`
+	Status      string    `json:"status"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	UserID      uuid.UUID `json:"user_id"`
+	RespondedAt time.Time `json:"responded_at"`
+}
+
+// NewInvitation creates a pending invitation.
+func NewInvitation(email string, orgID, teamID, roleID uuid.UUID, expiresAt time.Time) Invitation {
+	model := am.NewModel(am.WithType(invitationEntityType))
+	model.GenCreateValues()
+	return Invitation{
+		BaseModel: model,
+		Email:     email,
+		OrgID:     orgID,
+		TeamID:    teamID,
+		RoleID:    roleID,
+		Status:    InvitationPending,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// IsPending reports whether the invitation can still be accepted or declined.
+func (i Invitation) IsPending(now time.Time) bool {
+	return i.Status == InvitationPending && now.Before(i.ExpiresAt)
+}
+
+// IsExpired reports whether a pending invitation is past its expiry.
+func (i Invitation) IsExpired(now time.Time) bool {
+	return i.Status == InvitationPending && !now.Before(i.ExpiresAt)
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (i *Invitation) UnmarshalJSON(data []byte) error {
+	type Alias Invitation
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*i = Invitation(*temp)
+	if i.BaseModel == nil {
+		i.BaseModel = am.NewModel(am.WithType(invitationEntityType))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+// InvitationDA represents the data access layer for the Invitation model.
+type InvitationDA struct {
+	ID          sql.NullString `db:"id"`
+	ShortID     sql.NullString `db:"short_id"`
+	EmailEnc    []byte         `db:"email_enc"`
+	TokenHash   string         `db:"token_hash"`
+	InviterID   sql.NullString `db:"inviter_id"`
+	OrgID       sql.NullString `db:"org_id"`
+	TeamID      sql.NullString `db:"team_id"`
+	RoleID      sql.NullString `db:"role_id"`
+	Status      string         `db:"status"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+	UserID      sql.NullString `db:"user_id"`
+	RespondedAt sql.NullTime   `db:"responded_at"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+// ToInvitation converts InvitationDA to the Invitation domain model, the email stays encrypted.
+func ToInvitation(da InvitationDA) Invitation {
+	return Invitation{
+		BaseModel: am.NewModel(
+			am.WithID(am.ParseUUID(da.ID)),
+			am.WithShortID(da.ShortID.String),
+			am.WithType(invitationEntityType),
+			am.WithCreatedBy(am.ParseUUID(da.CreatedBy)),
+			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
+			am.WithCreatedAt(da.CreatedAt),
+			am.WithUpdatedAt(da.UpdatedAt),
+		),
+		EmailEnc:    da.EmailEnc,
+		TokenHash:   da.TokenHash,
+		InviterID:   am.ParseUUID(da.InviterID),
+		OrgID:       am.ParseUUID(da.OrgID),
+		TeamID:      am.ParseUUID(da.TeamID),
+		RoleID:      am.ParseUUID(da.RoleID),
+		Status:      da.Status,
+		ExpiresAt:   da.ExpiresAt,
+		UserID:      am.ParseUUID(da.UserID),
+		RespondedAt: da.RespondedAt.Time,
+	}
+}
+
+// ToInvitationDA converts the Invitation domain model to InvitationDA for DB operations.
+func ToInvitationDA(inv Invitation) InvitationDA {
+	return InvitationDA{
+		ID:          am.NullUUID(inv.ID()),
+		ShortID:     sql.NullString{String: inv.ShortID(), Valid: inv.ShortID() != ""},
+		EmailEnc:    inv.EmailEnc,
+		TokenHash:   inv.TokenHash,
+		InviterID:   am.NullUUID(inv.InviterID),
+		OrgID:       am.NullUUID(inv.OrgID),
+		TeamID:      am.NullUUID(inv.TeamID),
+		RoleID:      am.NullUUID(inv.RoleID),
+		Status:      inv.Status,
+		ExpiresAt:   inv.ExpiresAt,
+		UserID:      am.NullUUID(inv.UserID),
+		RespondedAt: sql.NullTime{Time: inv.RespondedAt, Valid: !inv.RespondedAt.IsZero()},
+		CreatedBy:   am.NullUUID(inv.CreatedBy()),
+		UpdatedBy:   am.NullUUID(inv.UpdatedBy()),
+		CreatedAt:   inv.CreatedAt(),
+		UpdatedAt:   inv.UpdatedAt(),
+	}
+}
+
+// ToInvitations converts a slice of InvitationDA to Invitation domain models.
+func ToInvitations(das []InvitationDA) []Invitation {
+	invitations := make([]Invitation, len(das))
+	for i, da := range das {
+		invitations[i] = ToInvitation(da)
+	}
+	return invitations
+}
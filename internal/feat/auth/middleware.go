@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+const (
+	switchOrgPath = "/auth/switch-org"
+	orgIDField    = "org_id"
+)
+
+// OrgMw scopes each request to an org and makes the org switcher available to the layout.
+// The requested org (header or cookie) is used when it is one of the orgs available to the actor,
+// otherwise the first available org is selected. An org explicitly requested through the header
+// that is not available is rejected. Requests without an actor, or from an actor that belongs to
+// no org, are scoped to no org at all and requests whose orgs cannot be resolved are rejected.
+func OrgMw(service Service) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			orgs, err := availableOrgs(ctx, service)
+			if err != nil {
+				http.Error(w, "Cannot resolve organizations", http.StatusInternalServerError)
+				return
+			}
+
+			explicit := strings.TrimSpace(r.Header.Get(am.OrgHeader)) != ""
+			if len(orgs) == 0 {
+				if explicit {
+					http.Error(w, "Organization not available", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(am.WithNoOrg(ctx)))
+				return
+			}
+
+			selected := orgs[0]
+			if orgID, ok := am.RequestedOrg(r); ok {
+				found := false
+				for _, org := range orgs {
+					if org.ID() == orgID {
+						selected = org
+						found = true
+						break
+					}
+				}
+
+				if !found && explicit {
+					http.Error(w, "Organization not available", http.StatusForbidden)
+					return
+				}
+			}
+
+			options := make([]am.SwitcherOption, len(orgs))
+			for i, org := range orgs {
+				options[i] = am.SwitcherOption{Value: org.ID().String(), Label: org.Name}
+			}
+
+			ctx = am.WithOrg(ctx, selected.ID())
+			ctx = am.WithSwitcher(ctx, &am.Switcher{
+				Label:    "Org",
+				Action:   switchOrgPath,
+				Field:    orgIDField,
+				Selected: selected.ID().String(),
+				Return:   r.URL.RequestURI(),
+				Options:  options,
+			})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// availableOrgs returns the orgs the actor belongs to, none for requests without an actor.
+// Orgs come oldest first so that the default org is selected when nothing was requested.
+func availableOrgs(ctx context.Context, service Service) ([]Org, error) {
+	actorID, ok := am.ActorFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+	return service.GetUserOrgs(ctx, actorID)
+}
@@ -9,6 +9,8 @@ import (
 
 const (
 	orgEntityType = "org"
+	// ContextTypeOrg is the context type of the roles a user has in an org.
+	ContextTypeOrg = "org"
 )
 
 type Org struct {
@@ -16,4 +16,7 @@ type PermissionDA struct {
 	UpdatedBy   sql.NullString `db:"updated_by"`
 	CreatedAt   sql.NullTime   `db:"created_at"`
 	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	Version     int            `db:"version"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
 }
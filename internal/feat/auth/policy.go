@@ -0,0 +1,393 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+const (
+	policyEntityType = "policy"
+)
+
+// Policy effects.
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Policy sources, file policies are read from assets/policy and cannot be edited.
+const (
+	PolicySourceDB   = "db"
+	PolicySourceFile = "file"
+)
+
+// Condition operators.
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpIn       = "in"
+	OpContains = "contains"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpBetween  = "between"
+	OpExists   = "exists"
+)
+
+// Attribute namespaces a condition can refer to.
+const (
+	AttrSubject  = "subject"
+	AttrResource = "resource"
+	AttrEnv      = "env"
+)
+
+// Wildcard matches any action or resource type.
+const Wildcard = "*"
+
+// Policy is a declarative rule evaluated on top of RBAC.
+// It applies to a request when the action and resource type match and all its conditions hold.
+type Policy struct {
+	*am.BaseModel
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Effect      string      `json:"effect"`
+	Actions     []string    `json:"actions"`
+	Resources   []string    `json:"resources"`
+	Conditions  []Condition `json:"conditions"`
+	Enabled     bool        `json:"enabled"`
+	Source      string      `json:"source"`
+}
+
+// Condition compares an attribute, e.g. "subject.team_ids" or "env.hour", with a value.
+// A string value starting with "$" refers to another attribute, e.g. "$resource.owner_team_id".
+type Condition struct {
+	Attr  string `json:"attr"`
+	Op    string `json:"op"`
+	Value any    `json:"value,omitempty"`
+	Not   bool   `json:"not,omitempty"`
+}
+
+// NewPolicy creates an enabled policy stored in the database.
+func NewPolicy(name, description, effect string) Policy {
+	return Policy{
+		BaseModel:   am.NewModel(am.WithType(policyEntityType)),
+		Name:        name,
+		Description: description,
+		Effect:      effect,
+		Enabled:     true,
+		Source:      PolicySourceDB,
+	}
+}
+
+// IsEditable reports whether the policy can be changed through the app.
+func (p Policy) IsEditable() bool {
+	return p.Source != PolicySourceFile
+}
+
+// IsDeny reports whether the policy denies access when it applies.
+func (p Policy) IsDeny() bool {
+	return p.Effect == EffectDeny
+}
+
+// Validate checks the effect and the condition operators.
+func (p Policy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	if p.Effect != EffectAllow && p.Effect != EffectDeny {
+		return fmt.Errorf("invalid policy effect %q", p.Effect)
+	}
+	if len(p.Actions) == 0 {
+		return fmt.Errorf("policy needs at least one action")
+	}
+	for _, c := range p.Conditions {
+		if !validOp(c.Op) {
+			return fmt.Errorf("invalid operator %q in condition on %s", c.Op, c.Attr)
+		}
+		ns, _, _ := strings.Cut(c.Attr, ".")
+		if ns != AttrSubject && ns != AttrResource && ns != AttrEnv {
+			return fmt.Errorf("invalid attribute %q, it must start with subject., resource. or env.", c.Attr)
+		}
+	}
+	return nil
+}
+
+// String returns a readable form of the condition, e.g. "env.hour not between [9 18]".
+func (c Condition) String() string {
+	op := c.Op
+	if c.Not {
+		op = "not " + op
+	}
+	if c.Op == OpExists {
+		return c.Attr + " " + op
+	}
+	return fmt.Sprintf("%s %s %v", c.Attr, op, c.Value)
+}
+
+// UnmarshalJSON ensures Model is always initialized after unmarshal.
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	type Alias Policy
+	temp := &Alias{}
+	if err := json.Unmarshal(data, temp); err != nil {
+		return err
+	}
+	*p = Policy(*temp)
+	if p.BaseModel == nil {
+		p.BaseModel = am.NewModel(am.WithType(policyEntityType))
+	}
+	return nil
+}
+
+// AccessRequest asks whether a subject can perform an action on a resource.
+// Attributes are plain values or lists of values, keyed without their namespace.
+type AccessRequest struct {
+	Action       string         `json:"action"`
+	ResourceType string         `json:"resource_type"`
+	Subject      map[string]any `json:"subject"`
+	Resource     map[string]any `json:"resource"`
+	Env          map[string]any `json:"env"`
+}
+
+// Attr returns the value of a namespaced attribute, e.g. "subject.id".
+func (r AccessRequest) Attr(path string) (any, bool) {
+	ns, name, _ := strings.Cut(path, ".")
+	var attrs map[string]any
+	switch ns {
+	case AttrSubject:
+		attrs = r.Subject
+	case AttrResource:
+		attrs = r.Resource
+	case AttrEnv:
+		attrs = r.Env
+	}
+	v, ok := attrs[name]
+	return v, ok
+}
+
+// ConditionResult records how a condition was evaluated.
+type ConditionResult struct {
+	Condition Condition `json:"condition"`
+	Actual    any       `json:"actual"`
+	Holds     bool      `json:"holds"`
+}
+
+// PolicyResult records how a policy was evaluated against a request.
+type PolicyResult struct {
+	Policy     Policy            `json:"policy"`
+	Matches    bool              `json:"matches"`
+	Applies    bool              `json:"applies"`
+	Conditions []ConditionResult `json:"conditions"`
+}
+
+// Decision is the outcome of combining RBAC with the policies.
+// A deny policy always wins, otherwise access is allowed by RBAC or by an allow policy.
+// Simulated decisions were taken with caller supplied subject or env attributes.
+type Decision struct {
+	Request   AccessRequest  `json:"request"`
+	Allowed   bool           `json:"allowed"`
+	RBAC      bool           `json:"rbac"`
+	Reason    string         `json:"reason"`
+	Results   []PolicyResult `json:"results"`
+	Simulated bool           `json:"simulated"`
+}
+
+// Enforcer decides requests of one subject with attributes and policies resolved up front.
+type Enforcer struct {
+	subject  map[string]any
+	env      map[string]any
+	policies []Policy
+}
+
+// NewEnforcer creates an enforcer for the subject and env attributes and the policies.
+func NewEnforcer(subject, env map[string]any, policies []Policy) *Enforcer {
+	return &Enforcer{subject: subject, env: env, policies: policies}
+}
+
+// Enforce decides the request with the caller's own grant standing in for RBAC.
+// Deny policies still win over the grant and allow policies can grant what it does not.
+func (e *Enforcer) Enforce(req AccessRequest, granted bool) Decision {
+	req.Subject, req.Env = e.subject, e.env
+	return Decide(e.policies, req, granted)
+}
+
+// Decide evaluates the policies for the request and combines them with the RBAC outcome.
+func Decide(policies []Policy, req AccessRequest, rbac bool) Decision {
+	d := Decision{Request: req, RBAC: rbac}
+
+	var allow, deny string
+	for _, p := range policies {
+		res := EvaluatePolicy(p, req)
+		d.Results = append(d.Results, res)
+		if !res.Applies {
+			continue
+		}
+		if p.IsDeny() && deny == "" {
+			deny = p.Name
+		}
+		if !p.IsDeny() && allow == "" {
+			allow = p.Name
+		}
+	}
+
+	switch {
+	case deny != "":
+		d.Reason = fmt.Sprintf("denied by policy %q", deny)
+	case rbac:
+		d.Allowed = true
+		d.Reason = "granted by a role or permission"
+	case allow != "":
+		d.Allowed = true
+		d.Reason = fmt.Sprintf("allowed by policy %q", allow)
+	default:
+		d.Reason = "no permission or policy grants access"
+	}
+
+	return d
+}
+
+// EvaluatePolicy checks whether a policy applies to the request.
+// Conditions are only evaluated when the policy is enabled and its action and resource type match.
+func EvaluatePolicy(p Policy, req AccessRequest) PolicyResult {
+	res := PolicyResult{Policy: p}
+	res.Matches = p.Enabled && matchesAny(p.Actions, req.Action) && matchesAny(p.Resources, req.ResourceType)
+	if !res.Matches {
+		return res
+	}
+
+	res.Applies = true
+	for _, c := range p.Conditions {
+		cr := evalCondition(c, req)
+		res.Conditions = append(res.Conditions, cr)
+		if !cr.Holds {
+			res.Applies = false
+		}
+	}
+	return res
+}
+
+// matchesAny reports whether value is in the patterns, an empty list or a wildcard matches anything.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if p == Wildcard || strings.EqualFold(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func evalCondition(c Condition, req AccessRequest) ConditionResult {
+	actual, found := req.Attr(c.Attr)
+	cr := ConditionResult{Condition: c, Actual: actual}
+
+	value := c.Value
+	if ref, ok := value.(string); ok && strings.HasPrefix(ref, "$") {
+		value, _ = req.Attr(strings.TrimPrefix(ref, "$"))
+	}
+
+	var holds bool
+	switch c.Op {
+	case OpExists:
+		holds = found
+	case OpEq:
+		holds = found && equalValues(actual, value)
+	case OpNe:
+		holds = found && !equalValues(actual, value)
+	case OpIn:
+		holds = found && containsValue(toList(value), actual)
+	case OpContains:
+		holds = found && containsValue(toList(actual), value)
+	case OpGt, OpGte, OpLt, OpLte:
+		holds = found && compareNumbers(c.Op, actual, value)
+	case OpBetween:
+		bounds := toList(value)
+		holds = found && len(bounds) == 2 &&
+			compareNumbers(OpGte, actual, bounds[0]) && compareNumbers(OpLt, actual, bounds[1])
+	}
+
+	cr.Holds = holds != c.Not
+	return cr
+}
+
+func validOp(op string) bool {
+	switch op {
+	case OpEq, OpNe, OpIn, OpContains, OpGt, OpGte, OpLt, OpLte, OpBetween, OpExists:
+		return true
+	}
+	return false
+}
+
+func equalValues(a, b any) bool {
+	fa, okA := toNumber(a)
+	fb, okB := toNumber(b)
+	if okA && okB {
+		return fa == fb
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func containsValue(list []any, v any) bool {
+	for _, item := range list {
+		if equalValues(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareNumbers(op string, a, b any) bool {
+	fa, okA := toNumber(a)
+	fb, okB := toNumber(b)
+	if !okA || !okB {
+		return false
+	}
+	switch op {
+	case OpGt:
+		return fa > fb
+	case OpGte:
+		return fa >= fb
+	case OpLt:
+		return fa < fb
+	case OpLte:
+		return fa <= fb
+	}
+	return false
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func toList(v any) []any {
+	switch l := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return l
+	case []string:
+		list := make([]any, len(l))
+		for i, s := range l {
+			list[i] = s
+		}
+		return list
+	}
+	return []any{v}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestDecide(t *testing.T) {
+	teamEdit := NewPolicy("team-edit", "", EffectAllow)
+	teamEdit.Actions = []string{"write"}
+	teamEdit.Resources = []string{"list"}
+	teamEdit.Conditions = []Condition{
+		{Attr: "subject.team_ids", Op: OpContains, Value: "$resource.owner_team_id"},
+	}
+
+	businessHours := NewPolicy("business-hours", "", EffectDeny)
+	businessHours.Actions = []string{"write"}
+	businessHours.Resources = []string{"list"}
+	businessHours.Conditions = []Condition{
+		{Attr: "env.hour", Op: OpBetween, Value: []any{9.0, 18.0}, Not: true},
+	}
+
+	policies := []Policy{teamEdit, businessHours}
+
+	request := func(teamID string, hour int) AccessRequest {
+		return AccessRequest{
+			Action:       "write",
+			ResourceType: "list",
+			Subject:      map[string]any{"team_ids": []string{"t1", "t2"}},
+			Resource:     map[string]any{"owner_team_id": teamID},
+			Env:          map[string]any{"hour": hour},
+		}
+	}
+
+	cases := []struct {
+		name    string
+		req     AccessRequest
+		rbac    bool
+		allowed bool
+	}{
+		{"member in business hours", request("t2", 10), false, true},
+		{"non member in business hours", request("t3", 10), false, false},
+		{"non member with permission", request("t3", 10), true, true},
+		{"member after hours", request("t1", 18), false, false},
+		{"permission after hours", request("t3", 7), true, false},
+	}
+
+	for _, c := range cases {
+		d := Decide(policies, c.req, c.rbac)
+		if d.Allowed != c.allowed {
+			t.Errorf("%s: expected allowed %v, got %v (%s)", c.name, c.allowed, d.Allowed, d.Reason)
+		}
+	}
+}
+
+func TestEvaluatePolicySkipsDisabled(t *testing.T) {
+	p := NewPolicy("deny-all", "", EffectDeny)
+	p.Actions = []string{Wildcard}
+	p.Enabled = false
+
+	res := EvaluatePolicy(p, AccessRequest{Action: "read"})
+	if res.Matches || res.Applies {
+		t.Errorf("expected a disabled policy not to apply")
+	}
+}
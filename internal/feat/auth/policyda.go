@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+// PolicyDA represents the data access layer for the Policy model.
+// Actions, resources and conditions are stored as JSON.
+type PolicyDA struct {
+	ID          sql.NullString `db:"id"`
+	ShortID     sql.NullString `db:"short_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Effect      string         `db:"effect"`
+	Actions     string         `db:"actions"`
+	Resources   string         `db:"resources"`
+	Conditions  string         `db:"conditions"`
+	Enabled     bool           `db:"enabled"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+// ToPolicy converts PolicyDA to the Policy domain model.
+func ToPolicy(da PolicyDA) (Policy, error) {
+	p := Policy{
+		BaseModel: am.NewModel(
+			am.WithID(am.ParseUUID(da.ID)),
+			am.WithShortID(da.ShortID.String),
+			am.WithType(policyEntityType),
+			am.WithCreatedBy(am.ParseUUID(da.CreatedBy)),
+			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
+			am.WithCreatedAt(da.CreatedAt),
+			am.WithUpdatedAt(da.UpdatedAt),
+		),
+		Name:        da.Name,
+		Description: da.Description.String,
+		Effect:      da.Effect,
+		Enabled:     da.Enabled,
+		Source:      PolicySourceDB,
+	}
+
+	err := json.Unmarshal([]byte(da.Actions), &p.Actions)
+	if err != nil {
+		return Policy{}, err
+	}
+	err = json.Unmarshal([]byte(da.Resources), &p.Resources)
+	if err != nil {
+		return Policy{}, err
+	}
+	err = json.Unmarshal([]byte(da.Conditions), &p.Conditions)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return p, nil
+}
+
+// ToPolicyDA converts the Policy domain model to PolicyDA for DB operations.
+func ToPolicyDA(p Policy) (PolicyDA, error) {
+	actions, err := marshalList(p.Actions)
+	if err != nil {
+		return PolicyDA{}, err
+	}
+	resources, err := marshalList(p.Resources)
+	if err != nil {
+		return PolicyDA{}, err
+	}
+	conditions, err := marshalList(p.Conditions)
+	if err != nil {
+		return PolicyDA{}, err
+	}
+
+	return PolicyDA{
+		ID:          am.NullUUID(p.ID()),
+		ShortID:     sql.NullString{String: p.ShortID(), Valid: p.ShortID() != ""},
+		Name:        p.Name,
+		Description: sql.NullString{String: p.Description, Valid: p.Description != ""},
+		Effect:      p.Effect,
+		Actions:     actions,
+		Resources:   resources,
+		Conditions:  conditions,
+		Enabled:     p.Enabled,
+		CreatedBy:   am.NullUUID(p.CreatedBy()),
+		UpdatedBy:   am.NullUUID(p.UpdatedBy()),
+		CreatedAt:   p.CreatedAt(),
+		UpdatedAt:   p.UpdatedAt(),
+	}, nil
+}
+
+// ToPolicies converts a slice of PolicyDA to Policy domain models.
+func ToPolicies(das []PolicyDA) ([]Policy, error) {
+	policies := make([]Policy, len(das))
+	for i, da := range das {
+		p, err := ToPolicy(da)
+		if err != nil {
+			return nil, err
+		}
+		policies[i] = p
+	}
+	return policies, nil
+}
+
+// marshalList stores nil slices as an empty JSON array.
+func marshalList[T any](list []T) (string, error) {
+	if list == nil {
+		list = []T{}
+	}
+	b, err := json.Marshal(list)
+	return string(b), err
+}
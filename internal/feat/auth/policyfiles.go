@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	policyFilesPath = "assets/policy"
+)
+
+// PolicyFiles holds the policies declared in the JSON files under assets/policy.
+// Each file contains a list of policies, they are loaded once on setup and cannot be edited from the app.
+type PolicyFiles struct {
+	am.Core
+	assetsFS embed.FS
+	mu       sync.RWMutex
+	policies []Policy
+}
+
+// NewPolicyFiles creates a PolicyFiles that reads from the embedded assets.
+func NewPolicyFiles(assetsFS embed.FS, opts ...am.Option) *PolicyFiles {
+	return &PolicyFiles{
+		Core:     am.NewCore("policy-files", opts...),
+		assetsFS: assetsFS,
+	}
+}
+
+// Setup loads the policy files.
+func (pf *PolicyFiles) Setup(ctx context.Context) error {
+	policies, err := pf.load()
+	if err != nil {
+		return err
+	}
+
+	pf.mu.Lock()
+	pf.policies = policies
+	pf.mu.Unlock()
+
+	pf.Log().Infof("Loaded %d file policies", len(policies))
+	return nil
+}
+
+// Policies returns the loaded file policies.
+func (pf *PolicyFiles) Policies() []Policy {
+	pf.mu.RLock()
+	defer pf.mu.RUnlock()
+	return append([]Policy(nil), pf.policies...)
+}
+
+func (pf *PolicyFiles) load() ([]Policy, error) {
+	var policies []Policy
+	err := fs.WalkDir(pf.assetsFS, policyFilesPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		content, err := pf.assetsFS.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("cannot read policy file %s: %w", path, err)
+		}
+
+		var list []Policy
+		err = json.Unmarshal(content, &list)
+		if err != nil {
+			return fmt.Errorf("cannot parse policy file %s: %w", path, err)
+		}
+
+		for _, p := range list {
+			err = p.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid policy in %s: %w", path, err)
+			}
+			// File policies get a stable ID so that they can be referenced across restarts.
+			p.SetID(uuid.NewSHA1(uuid.NameSpaceURL, []byte(path+"#"+p.Name)))
+			p.Source = PolicySourceFile
+			policies = append(policies, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
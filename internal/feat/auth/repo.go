@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
 
@@ -17,8 +18,11 @@ type Repo interface {
 	GetUser(ctx context.Context, id uuid.UUID, preload ...bool) (User, error)
 	CreateUser(ctx context.Context, user User) error
 	UpdateUser(ctx context.Context, user User) error
-	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteUser(ctx context.Context, user User) error
+	GetDeletedUsers(ctx context.Context) ([]User, error)
+	RestoreUser(ctx context.Context, user User) error
 	UpdatePassword(ctx context.Context, user User) error
+	SetUserActive(ctx context.Context, user User) error
 	GetUserAssignedRoles(ctx context.Context, userID uuid.UUID, contextType, contextID string) ([]Role, error)
 	GetUserUnassignedRoles(ctx context.Context, userID uuid.UUID, contextType, contextID string) ([]Role, error)
 	AddRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, contextType, contextID string) error
@@ -37,8 +41,16 @@ type Repo interface {
 	GetRole(ctx context.Context, roleID uuid.UUID, preload ...bool) (Role, error)
 	CreateRole(ctx context.Context, role Role) error
 	UpdateRole(ctx context.Context, role Role) error
-	DeleteRole(ctx context.Context, roleID uuid.UUID) error
+	DeleteRole(ctx context.Context, role Role) error
+	GetDeletedRoles(ctx context.Context) ([]Role, error)
+	RestoreRole(ctx context.Context, role Role) error
 	GetRolePermissions(ctx context.Context, roleID uuid.UUID) ([]Permission, error)
+	GetRoleParents(ctx context.Context, roleID uuid.UUID) ([]Role, error)
+	GetRoleAncestors(ctx context.Context, roleID uuid.UUID) ([]Role, error)
+	AddRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error
+	RemoveRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error
+	GetUserAllRoles(ctx context.Context, userID uuid.UUID) ([]Role, error)
+	GetUserRoleAssignments(ctx context.Context, userID uuid.UUID) ([]RoleAssignment, error)
 	GetRoleUnassignedPermissions(ctx context.Context, roleID uuid.UUID) ([]Permission, error)
 	AddPermissionToRole(ctx context.Context, roleID uuid.UUID, permission Permission) error
 	RemovePermissionFromRole(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error
@@ -49,7 +61,9 @@ type Repo interface {
 	GetPermission(ctx context.Context, id uuid.UUID) (Permission, error)
 	CreatePermission(ctx context.Context, permission Permission) error
 	UpdatePermission(ctx context.Context, permission Permission) error
-	DeletePermission(ctx context.Context, id uuid.UUID) error
+	DeletePermission(ctx context.Context, permission Permission) error
+	GetDeletedPermissions(ctx context.Context) ([]Permission, error)
+	RestorePermission(ctx context.Context, permission Permission) error
 
 	// SECTION: Resource-related methods
 
@@ -57,30 +71,70 @@ type Repo interface {
 	GetResource(ctx context.Context, id uuid.UUID, preload ...bool) (Resource, error)
 	CreateResource(ctx context.Context, resource Resource) error
 	UpdateResource(ctx context.Context, resource Resource) error
-	DeleteResource(ctx context.Context, id uuid.UUID) error
+	DeleteResource(ctx context.Context, resource Resource) error
+	GetDeletedResources(ctx context.Context) ([]Resource, error)
+	RestoreResource(ctx context.Context, resource Resource) error
 	GetResourcePermissions(ctx context.Context, resourceID uuid.UUID) ([]Permission, error)
 	GetResourceUnassignedPermissions(ctx context.Context, resourceID uuid.UUID) ([]Permission, error)
 	AddPermissionToResource(ctx context.Context, resourceID uuid.UUID, permission Permission) error
 	RemovePermissionFromResource(ctx context.Context, resourceID uuid.UUID, permissionID uuid.UUID) error
 
 	// SECTION: Organization-related methods
+	GetAllOrgs(ctx context.Context) ([]Org, error)
+	GetUserOrgs(ctx context.Context, userID uuid.UUID) ([]Org, error)
+	GetOrg(ctx context.Context, id uuid.UUID) (Org, error)
 	CreateOrg(ctx context.Context, org Org) error
+	UpdateOrg(ctx context.Context, org Org) error
+	DeleteOrg(ctx context.Context, org Org) error
+	GetDeletedOrgs(ctx context.Context) ([]Org, error)
+	RestoreOrg(ctx context.Context, org Org) error
 	AddOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
 	RemoveOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
 	GetDefaultOrg(ctx context.Context) (Org, error)
 	GetOrgOwners(ctx context.Context, orgID uuid.UUID) ([]User, error)
 	GetOrgUnassignedOwners(ctx context.Context, orgID uuid.UUID) ([]User, error)
+	GetOrgMembers(ctx context.Context, orgID uuid.UUID) ([]User, error)
+	GetOrgNonMembers(ctx context.Context, orgID uuid.UUID) ([]User, error)
+	AddUserToOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
+	RemoveUserFromOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
 	GetAllTeams(ctx context.Context, orgID uuid.UUID) ([]Team, error)
 	GetTeam(ctx context.Context, id uuid.UUID) (Team, error)
 	CreateTeam(ctx context.Context, team Team) error
 	UpdateTeam(ctx context.Context, team Team) error
-	DeleteTeam(ctx context.Context, id uuid.UUID) error
+	DeleteTeam(ctx context.Context, team Team) error
+	GetDeletedTeams(ctx context.Context, orgID uuid.UUID) ([]Team, error)
+	RestoreTeam(ctx context.Context, team Team) error
 	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]User, error)
+	GetUserTeams(ctx context.Context, userID uuid.UUID) ([]Team, error)
 	GetTeamUnassignedUsers(ctx context.Context, teamID uuid.UUID) ([]User, error)
 	AddUserToTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, relationType string) error
 	RemoveUserFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error
 
-	// Team member roles methods
-	GetUserContextualRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error)
-	GetUserContextualUnassignedRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error)
+	// Contextual roles methods
+	GetUserContextualRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error)
+	GetUserContextualUnassignedRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error)
+
+	// Invitation methods
+	CreateInvitation(ctx context.Context, inv Invitation) error
+	GetInvitation(ctx context.Context, id uuid.UUID) (Invitation, error)
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error)
+	GetTeamInvitations(ctx context.Context, teamID uuid.UUID) ([]Invitation, error)
+	GetOrgInvitations(ctx context.Context, orgID uuid.UUID) ([]Invitation, error)
+	RespondInvitation(ctx context.Context, inv Invitation) error
+
+	// Policy methods
+	GetPolicies(ctx context.Context) ([]Policy, error)
+	GetPolicy(ctx context.Context, id uuid.UUID) (Policy, error)
+	CreatePolicy(ctx context.Context, policy Policy) error
+	UpdatePolicy(ctx context.Context, policy Policy) error
+	DeletePolicy(ctx context.Context, id uuid.UUID) error
+
+	// SECTION: Trash-related methods
+
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+
+	// SECTION: Audit-related methods
+
+	CreateAuditEntry(ctx context.Context, entry AuditEntry) error
+	GetAuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
 }
@@ -21,6 +21,9 @@ type ResourceDA struct {
 	UpdatedBy   sql.NullString `db:"updated_by"`
 	CreatedAt   sql.NullTime   `db:"created_at"`
 	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	Version     int            `db:"version"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
 }
 
 // Convert ResourceDA to Resource
@@ -73,4 +76,5 @@ type ResourceExtDA struct {
 	UpdatedBy      sql.NullString `db:"updated_by"`
 	CreatedAt      sql.NullTime   `db:"created_at"`
 	UpdatedAt      sql.NullTime   `db:"updated_at"`
+	Version        int            `db:"version"`
 }
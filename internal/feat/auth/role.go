@@ -51,3 +51,21 @@ func (r *Role) UnmarshalJSON(data []byte) error {
 func (r *Role) Slug() string {
 	return am.Normalize(r.Name) + "-" + r.ShortID()
 }
+
+// InheritedPermission is a permission a role gets from one of the roles it extends.
+type InheritedPermission struct {
+	Permission
+	Source Role `json:"source"`
+}
+
+// RoleAssignment is a role given to a user, globally when the context is empty or within an org or team.
+type RoleAssignment struct {
+	RoleID      uuid.UUID `json:"role_id"`
+	ContextType string    `json:"context_type"`
+	ContextID   string    `json:"context_id"`
+}
+
+// IsContextual reports whether the role was given within an org or team.
+func (a RoleAssignment) IsContextual() bool {
+	return a.ContextType != ""
+}
@@ -19,6 +19,16 @@ type RoleDA struct {
 	UpdatedBy   sql.NullString `db:"updated_by"`
 	CreatedAt   sql.NullTime   `db:"created_at"`
 	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	Version     int            `db:"version"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
+}
+
+// RoleAssignmentDA represents a row of user_role.
+type RoleAssignmentDA struct {
+	RoleID      string         `db:"role_id"`
+	ContextType sql.NullString `db:"context_type"`
+	ContextID   sql.NullString `db:"context_id"`
 }
 
 // Convert RoleDA to Role
@@ -68,4 +78,19 @@ type RoleExtDA struct {
 	UpdatedBy      sql.NullString `db:"updated_by"`
 	CreatedAt      sql.NullTime   `db:"created_at"`
 	UpdatedAt      sql.NullTime   `db:"updated_at"`
+	Version        int            `db:"version"`
+}
+
+// ToRoleAssignments converts user_role rows to role assignments.
+func ToRoleAssignments(das []RoleAssignmentDA) []RoleAssignment {
+	assignments := make([]RoleAssignment, len(das))
+	for i, da := range das {
+		roleID, _ := uuid.Parse(da.RoleID)
+		assignments[i] = RoleAssignment{
+			RoleID:      roleID,
+			ContextType: da.ContextType.String,
+			ContextID:   da.ContextID.String,
+		}
+	}
+	return assignments
 }
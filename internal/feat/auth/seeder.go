@@ -27,6 +27,7 @@ type SeedData struct {
 	UserPermissions     []map[string]string `json:"user_permissions"`
 	ResourcePermissions []map[string]string `json:"resource_permissions"`
 	OrgOwners           []map[string]string `json:"org_owners"`
+	OrgMembers          []map[string]string `json:"org_members"`
 }
 
 func NewSeeder(assetsFS embed.FS, engine string, repo Repo) *Seeder {
@@ -101,6 +102,10 @@ func (s *Seeder) seedData(ctx context.Context, data *SeedData) error {
 	if err != nil {
 		return err
 	}
+	err = s.seedOrgMembers(ctx, data, orgRefMap, userRefMap)
+	if err != nil {
+		return err
+	}
 	err = s.seedTeams(ctx, data, teamRefMap, orgRefMap)
 	if err != nil {
 		return err
@@ -401,3 +406,25 @@ func (s *Seeder) seedOrgOwners(ctx context.Context, data *SeedData, orgRefMap, u
 	}
 	return tx.Commit()
 }
+
+func (s *Seeder) seedOrgMembers(ctx context.Context, data *SeedData, orgRefMap, userRefMap map[string]uuid.UUID) error {
+	ctx, tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("error at beginning tx for seedOrgMembers: %w", err)
+	}
+	defer tx.Rollback()
+	s.Log().Debug("Seeding org members: start")
+	defer s.Log().Debug("Seeding org members: end")
+	for _, om := range data.OrgMembers {
+		orgID, ok1 := orgRefMap[om["org_ref"]]
+		userID, ok2 := userRefMap[om["user_ref"]]
+		if !ok1 || !ok2 {
+			return fmt.Errorf("error finding org or user ref for org_member")
+		}
+		err := s.repo.AddUserToOrg(ctx, orgID, userID)
+		if err != nil {
+			return fmt.Errorf("error adding org member: %w", err)
+		}
+	}
+	return tx.Commit()
+}
@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
 	"github.com/google/uuid"
@@ -17,11 +18,17 @@ type Service interface {
 	UpdateUser(ctx context.Context, user User) error
 	UpdateUserPassword(ctx context.Context, user User) error
 	DeleteUser(ctx context.Context, id uuid.UUID) error
+	GetDeletedUsers(ctx context.Context) ([]User, error)
+	RestoreUser(ctx context.Context, id uuid.UUID) error
+	ActivateUser(ctx context.Context, id uuid.UUID) error
+	DeactivateUser(ctx context.Context, id uuid.UUID) error
+	BulkUsers(ctx context.Context, cmd UserBulkCommand) (am.Batch, error)
 	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]Role, error)
 	GetUserUnassignedRoles(ctx context.Context, userID uuid.UUID) ([]Role, error)
 	GetUserAssignedPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error)
 	GetUserIndirectPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error)
 	GetUserDirectPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error)
+	ExplainPermission(ctx context.Context, q ExplainQuery) (PermissionExplanation, error)
 	GetUserUnassignedPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error)
 	AddRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
 	RemoveRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error
@@ -35,10 +42,18 @@ type Service interface {
 	CreateRole(ctx context.Context, role Role) error
 	UpdateRole(ctx context.Context, role Role) error
 	DeleteRole(ctx context.Context, roleID uuid.UUID) error
+	GetDeletedRoles(ctx context.Context) ([]Role, error)
+	RestoreRole(ctx context.Context, roleID uuid.UUID) error
 	GetRolePermissions(ctx context.Context, roleID uuid.UUID) ([]Permission, error)
 	GetRoleUnassignedPermissions(ctx context.Context, roleID uuid.UUID) ([]Permission, error)
 	AddPermissionToRole(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error
 	RemovePermissionFromRole(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error
+	GetRoleParents(ctx context.Context, roleID uuid.UUID) ([]Role, error)
+	GetRoleParentCandidates(ctx context.Context, roleID uuid.UUID) ([]Role, error)
+	GetRoleAncestors(ctx context.Context, roleID uuid.UUID) ([]Role, error)
+	GetRoleInheritedPermissions(ctx context.Context, roleID uuid.UUID) ([]InheritedPermission, error)
+	AddRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error
+	RemoveRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error
 
 	// SECTION: Permission-related methods
 
@@ -47,6 +62,8 @@ type Service interface {
 	CreatePermission(ctx context.Context, permission Permission) error
 	UpdatePermission(ctx context.Context, permission Permission) error
 	DeletePermission(ctx context.Context, id uuid.UUID) error
+	GetDeletedPermissions(ctx context.Context) ([]Permission, error)
+	RestorePermission(ctx context.Context, id uuid.UUID) error
 
 	// SECTION: Resource-related methods
 
@@ -55,6 +72,8 @@ type Service interface {
 	CreateResource(ctx context.Context, resource Resource) error
 	UpdateResource(ctx context.Context, resource Resource) error
 	DeleteResource(ctx context.Context, id uuid.UUID) error
+	GetDeletedResources(ctx context.Context) ([]Resource, error)
+	RestoreResource(ctx context.Context, id uuid.UUID) error
 	GetResourcePermissions(ctx context.Context, resourceID uuid.UUID) ([]Permission, error)
 	GetResourceUnassignedPermissions(ctx context.Context, resourceID uuid.UUID) ([]Permission, error)
 	AddPermissionToResource(ctx context.Context, resourceID uuid.UUID, permission Permission) error
@@ -62,6 +81,18 @@ type Service interface {
 
 	// Org methods
 	GetDefaultOrg(ctx context.Context) (Org, error)
+	GetAllOrgs(ctx context.Context) ([]Org, error)
+	GetUserOrgs(ctx context.Context, userID uuid.UUID) ([]Org, error)
+	GetOrg(ctx context.Context, id uuid.UUID) (Org, error)
+	CreateOrg(ctx context.Context, org Org) error
+	UpdateOrg(ctx context.Context, org Org) error
+	DeleteOrg(ctx context.Context, id uuid.UUID) error
+	GetDeletedOrgs(ctx context.Context) ([]Org, error)
+	RestoreOrg(ctx context.Context, id uuid.UUID) error
+	GetOrgMembers(ctx context.Context, orgID uuid.UUID) ([]User, error)
+	GetOrgNonMembers(ctx context.Context, orgID uuid.UUID) ([]User, error)
+	AddUserToOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
+	RemoveUserFromOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
 	GetOrgOwners(ctx context.Context, orgID uuid.UUID) ([]User, error)
 	GetOrgUnassignedOwners(ctx context.Context, orgID uuid.UUID) ([]User, error)
 	AddOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
@@ -73,16 +104,49 @@ type Service interface {
 	CreateTeam(ctx context.Context, team Team) error
 	UpdateTeam(ctx context.Context, team Team) error
 	DeleteTeam(ctx context.Context, id uuid.UUID) error
-	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]User, error)
+	GetDeletedTeams(ctx context.Context, orgID uuid.UUID) ([]Team, error)
+	RestoreTeam(ctx context.Context, id uuid.UUID) error
+	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]TeamMember, error)
+	GetTeamAncestors(ctx context.Context, teamID uuid.UUID) ([]Team, error)
+	GetTeamTree(ctx context.Context, teamID uuid.UUID) (TeamNode, error)
+	GetOrgTeamTree(ctx context.Context, orgID uuid.UUID) ([]TeamNode, error)
 	GetTeamUnassignedUsers(ctx context.Context, teamID uuid.UUID) ([]User, error)
+	GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 	AddUserToTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, relationType string) error
 	RemoveUserFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error
 
 	// Contextual role methods
-	GetUserContextualRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error)
-	GetUserContextualUnassignedRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error)
+	GetUserContextualRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error)
+	GetUserContextualUnassignedRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error)
+	GetUserInheritedTeamRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]InheritedRole, error)
 	AddContextualRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, contextType string, contextID string) error
 	RemoveContextualRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, contextType string, contextID string) error
+
+	// Invitation methods
+	GetTeamInvitations(ctx context.Context, teamID uuid.UUID) ([]Invitation, error)
+	GetOrgInvitations(ctx context.Context, orgID uuid.UUID) ([]Invitation, error)
+	GetInvitationByToken(ctx context.Context, token string) (Invitation, error)
+	CreateInvitation(ctx context.Context, inv Invitation) (string, error)
+	AcceptInvitation(ctx context.Context, token string, user User) (User, error)
+	DeclineInvitation(ctx context.Context, token string) error
+	RevokeInvitation(ctx context.Context, id uuid.UUID) error
+
+	// Policy methods
+	GetPolicies(ctx context.Context) ([]Policy, error)
+	GetPolicy(ctx context.Context, id uuid.UUID) (Policy, error)
+	CreatePolicy(ctx context.Context, policy Policy) error
+	UpdatePolicy(ctx context.Context, policy Policy) error
+	DeletePolicy(ctx context.Context, id uuid.UUID) error
+	Authorize(ctx context.Context, userID uuid.UUID, req AccessRequest) (Decision, error)
+	SimulateAuthorize(ctx context.Context, userID uuid.UUID, req AccessRequest) (Decision, error)
+	Enforcer(ctx context.Context, userID uuid.UUID) (*Enforcer, error)
+
+	// Trash methods
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+
+	// Audit methods
+	GetAuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+	RecordAudit(ctx context.Context, entry AuditEntry) error
 }
 
 var (
@@ -91,13 +155,16 @@ var (
 
 type BaseService struct {
 	*am.Service
-	repo Repo
+	repo     Repo
+	policies *PolicyFiles
 }
 
-func NewService(repo Repo) *BaseService {
+// NewService creates the auth service, policies are the rules read from assets/policy and can be nil.
+func NewService(repo Repo, policies *PolicyFiles) *BaseService {
 	return &BaseService{
-		Service: am.NewService("auth-service"),
-		repo:    repo,
+		Service:  am.NewService("auth-service"),
+		repo:     repo,
+		policies: policies,
 	}
 }
 
@@ -146,22 +213,35 @@ func (svc *BaseService) withEncryptionKey(ctx context.Context) context.Context {
 }
 
 func (svc *BaseService) CreateUser(ctx context.Context, user User) error {
-	user.GenCreateValues()
+	svc.StampCreate(ctx, user)
 	ctx = svc.withEncryptionKey(ctx)
 	err := user.PrePersist(ctx)
 	if err != nil {
 		return fmt.Errorf("error preparing user for insert: %w", err)
 	}
-	return svc.repo.CreateUser(ctx, user)
+	entry := NewAuditEntry(ActionCreateUser, userType, user.ID(), nil, userSnapshot(user))
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreateUser(ctx, user)
+	})
 }
 
 func (svc *BaseService) UpdateUser(ctx context.Context, user User) error {
+	svc.StampUpdate(ctx, user)
 	ctx = svc.withEncryptionKey(ctx)
 	err := user.PrePersist(ctx)
 	if err != nil {
 		return fmt.Errorf("error preparing user for update: %w", err)
 	}
-	return svc.repo.UpdateUser(ctx, user)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetUser(ctx, user.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdateUser, userType, user.ID(), userSnapshot(before), userSnapshot(user))
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdateUser(ctx, user)
+		})
+	})
 }
 
 func (svc *BaseService) UpdateUserPassword(ctx context.Context, user User) error {
@@ -172,12 +252,69 @@ func (svc *BaseService) UpdateUserPassword(ctx context.Context, user User) error
 	}
 
 	user.PasswordEnc = hashedPassword
+	svc.StampUpdate(ctx, user)
 
-	return svc.repo.UpdatePassword(ctx, user)
+	entry := NewAuditEntry(ActionUpdateUserPassword, userType, user.ID(), nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.UpdatePassword(ctx, user)
+	})
 }
 
 func (svc *BaseService) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	return svc.repo.DeleteUser(ctx, id)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		user, err := svc.repo.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeleteUser, userType, id, userSnapshot(user), nil)
+		svc.StampDelete(ctx, user)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeleteUser(ctx, user)
+		})
+	})
+}
+
+func (svc *BaseService) GetDeletedUsers(ctx context.Context) ([]User, error) {
+	return svc.repo.GetDeletedUsers(ctx)
+}
+
+func (svc *BaseService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	user := User{BaseModel: am.NewModel(am.WithID(id), am.WithType(userType))}
+	svc.StampUpdate(ctx, user)
+	entry := NewAuditEntry(ActionRestoreUser, userType, id, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestoreUser(ctx, user)
+	})
+}
+
+// ActivateUser flags a deactivated user as active again.
+func (svc *BaseService) ActivateUser(ctx context.Context, id uuid.UUID) error {
+	return svc.setUserActive(ctx, id, true)
+}
+
+// DeactivateUser keeps the user and its assignments but flags it as inactive.
+func (svc *BaseService) DeactivateUser(ctx context.Context, id uuid.UUID) error {
+	return svc.setUserActive(ctx, id, false)
+}
+
+func (svc *BaseService) setUserActive(ctx context.Context, id uuid.UUID, active bool) error {
+	action := ActionDeactivateUser
+	if active {
+		action = ActionActivateUser
+	}
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		user, err := svc.repo.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		before := userSnapshot(user)
+		user.IsActive = active
+		entry := NewAuditEntry(action, userType, id, before, userSnapshot(user))
+		svc.StampUpdate(ctx, user)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.SetUserActive(ctx, user)
+		})
+	})
 }
 
 func (svc *BaseService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]Role, error) {
@@ -189,7 +326,11 @@ func (svc *BaseService) GetUserUnassignedRoles(ctx context.Context, userID uuid.
 }
 
 func (svc *BaseService) CreateRole(ctx context.Context, role Role) error {
-	return svc.repo.CreateRole(ctx, role)
+	svc.StampCreate(ctx, role)
+	entry := NewAuditEntry(ActionCreateRole, roleType, role.ID(), nil, role)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreateRole(ctx, role)
+	})
 }
 
 func (svc *BaseService) GetRole(ctx context.Context, roleID uuid.UUID) (Role, error) {
@@ -197,11 +338,44 @@ func (svc *BaseService) GetRole(ctx context.Context, roleID uuid.UUID) (Role, er
 }
 
 func (svc *BaseService) UpdateRole(ctx context.Context, role Role) error {
-	return svc.repo.UpdateRole(ctx, role)
+	svc.StampUpdate(ctx, role)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetRole(ctx, role.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdateRole, roleType, role.ID(), before, role)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdateRole(ctx, role)
+		})
+	})
 }
 
 func (svc *BaseService) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
-	return svc.repo.DeleteRole(ctx, roleID)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		role, err := svc.repo.GetRole(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeleteRole, roleType, roleID, role, nil)
+		svc.StampDelete(ctx, role)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeleteRole(ctx, role)
+		})
+	})
+}
+
+func (svc *BaseService) GetDeletedRoles(ctx context.Context) ([]Role, error) {
+	return svc.repo.GetDeletedRoles(ctx)
+}
+
+func (svc *BaseService) RestoreRole(ctx context.Context, roleID uuid.UUID) error {
+	role := Role{BaseModel: am.NewModel(am.WithID(roleID), am.WithType(roleType))}
+	svc.StampUpdate(ctx, role)
+	entry := NewAuditEntry(ActionRestoreRole, roleType, roleID, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestoreRole(ctx, role)
+	})
 }
 
 func (svc *BaseService) GetAllRoles(ctx context.Context) ([]Role, error) {
@@ -217,19 +391,35 @@ func (svc *BaseService) GetRoleUnassignedPermissions(ctx context.Context, roleID
 }
 
 func (svc *BaseService) AddRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
-	return svc.repo.AddRole(ctx, userID, roleID, "", "")
+	ref := auditRef{"role_id": roleID.String()}
+	entry := NewAuditEntry(ActionAddRoleToUser, userType, userID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddRole(ctx, userID, roleID, "", "")
+	})
 }
 
 func (svc *BaseService) RemoveRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID) error {
-	return svc.repo.RemoveRole(ctx, userID, roleID, "", "")
+	ref := auditRef{"role_id": roleID.String()}
+	entry := NewAuditEntry(ActionRemoveRoleFromUser, userType, userID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveRole(ctx, userID, roleID, "", "")
+	})
 }
 
 func (svc *BaseService) AddContextualRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, contextType string, contextID string) error {
-	return svc.repo.AddRole(ctx, userID, roleID, contextType, contextID)
+	ref := auditRef{"role_id": roleID.String(), "context_type": contextType, "context_id": contextID}
+	entry := NewAuditEntry(ActionAddContextualRole, userType, userID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddRole(ctx, userID, roleID, contextType, contextID)
+	})
 }
 
 func (svc *BaseService) RemoveContextualRole(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, contextType string, contextID string) error {
-	return svc.repo.RemoveRole(ctx, userID, roleID, contextType, contextID)
+	ref := auditRef{"role_id": roleID.String(), "context_type": contextType, "context_id": contextID}
+	entry := NewAuditEntry(ActionRemoveContextualRole, userType, userID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveRole(ctx, userID, roleID, contextType, contextID)
+	})
 }
 
 func (svc *BaseService) GetAllPermissions(ctx context.Context) ([]Permission, error) {
@@ -237,7 +427,11 @@ func (svc *BaseService) GetAllPermissions(ctx context.Context) ([]Permission, er
 }
 
 func (svc *BaseService) CreatePermission(ctx context.Context, permission Permission) error {
-	return svc.repo.CreatePermission(ctx, permission)
+	svc.StampCreate(ctx, permission)
+	entry := NewAuditEntry(ActionCreatePermission, permissionType, permission.ID(), nil, permission)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreatePermission(ctx, permission)
+	})
 }
 
 func (svc *BaseService) GetPermission(ctx context.Context, id uuid.UUID) (Permission, error) {
@@ -245,19 +439,48 @@ func (svc *BaseService) GetPermission(ctx context.Context, id uuid.UUID) (Permis
 }
 
 func (svc *BaseService) UpdatePermission(ctx context.Context, permission Permission) error {
-	return svc.repo.UpdatePermission(ctx, permission)
+	svc.StampUpdate(ctx, permission)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetPermission(ctx, permission.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdatePermission, permissionType, permission.ID(), before, permission)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdatePermission(ctx, permission)
+		})
+	})
 }
 
 func (svc *BaseService) DeletePermission(ctx context.Context, id uuid.UUID) error {
-	return svc.repo.DeletePermission(ctx, id)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		permission, err := svc.repo.GetPermission(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeletePermission, permissionType, id, permission, nil)
+		svc.StampDelete(ctx, permission)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeletePermission(ctx, permission)
+		})
+	})
 }
 
-func (svc *BaseService) GetUserAssignedPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error) {
-	return svc.repo.GetUserAssignedPermissions(ctx, userID)
+func (svc *BaseService) GetDeletedPermissions(ctx context.Context) ([]Permission, error) {
+	return svc.repo.GetDeletedPermissions(ctx)
+}
+
+func (svc *BaseService) RestorePermission(ctx context.Context, id uuid.UUID) error {
+	permission := Permission{BaseModel: am.NewModel(am.WithID(id), am.WithType(permissionType))}
+	svc.StampUpdate(ctx, permission)
+	entry := NewAuditEntry(ActionRestorePermission, permissionType, id, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestorePermission(ctx, permission)
+	})
 }
 
-func (svc *BaseService) GetUserIndirectPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error) {
-	return svc.repo.GetUserIndirectPermissions(ctx, userID)
+func (svc *BaseService) GetUserAssignedPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error) {
+	return svc.repo.GetUserAssignedPermissions(ctx, userID)
 }
 
 func (svc *BaseService) GetUserDirectPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error) {
@@ -269,11 +492,19 @@ func (svc *BaseService) GetUserUnassignedPermissions(ctx context.Context, userID
 }
 
 func (svc *BaseService) AddPermissionToUser(ctx context.Context, userID uuid.UUID, permission Permission) error {
-	return svc.repo.AddPermissionToUser(ctx, userID, permission)
+	ref := auditRef{"permission_id": permission.ID().String()}
+	entry := NewAuditEntry(ActionAddPermissionToUser, userType, userID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddPermissionToUser(ctx, userID, permission)
+	})
 }
 
 func (svc *BaseService) RemovePermissionFromUser(ctx context.Context, userID uuid.UUID, permissionID uuid.UUID) error {
-	return svc.repo.RemovePermissionFromUser(ctx, userID, permissionID)
+	ref := auditRef{"permission_id": permissionID.String()}
+	entry := NewAuditEntry(ActionRemovePermissionFromUser, userType, userID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemovePermissionFromUser(ctx, userID, permissionID)
+	})
 }
 
 func (svc *BaseService) AddPermissionToRole(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
@@ -281,11 +512,19 @@ func (svc *BaseService) AddPermissionToRole(ctx context.Context, roleID uuid.UUI
 	if err != nil {
 		return err
 	}
-	return svc.repo.AddPermissionToRole(ctx, roleID, permission)
+	ref := auditRef{"permission_id": permissionID.String()}
+	entry := NewAuditEntry(ActionAddPermissionToRole, roleType, roleID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddPermissionToRole(ctx, roleID, permission)
+	})
 }
 
 func (svc *BaseService) RemovePermissionFromRole(ctx context.Context, roleID uuid.UUID, permissionID uuid.UUID) error {
-	return svc.repo.RemovePermissionFromRole(ctx, roleID, permissionID)
+	ref := auditRef{"permission_id": permissionID.String()}
+	entry := NewAuditEntry(ActionRemovePermissionFromRole, roleType, roleID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemovePermissionFromRole(ctx, roleID, permissionID)
+	})
 }
 
 func (svc *BaseService) GetAllResources(ctx context.Context) ([]Resource, error) {
@@ -297,15 +536,52 @@ func (svc *BaseService) GetResource(ctx context.Context, id uuid.UUID) (Resource
 }
 
 func (svc *BaseService) CreateResource(ctx context.Context, resource Resource) error {
-	return svc.repo.CreateResource(ctx, resource)
+	svc.StampCreate(ctx, resource)
+	entry := NewAuditEntry(ActionCreateResource, resourceEntityType, resource.ID(), nil, resource)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreateResource(ctx, resource)
+	})
 }
 
 func (svc *BaseService) UpdateResource(ctx context.Context, resource Resource) error {
-	return svc.repo.UpdateResource(ctx, resource)
+	svc.StampUpdate(ctx, resource)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetResource(ctx, resource.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdateResource, resourceEntityType, resource.ID(), before, resource)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdateResource(ctx, resource)
+		})
+	})
 }
 
 func (svc *BaseService) DeleteResource(ctx context.Context, id uuid.UUID) error {
-	return svc.repo.DeleteResource(ctx, id)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		resource, err := svc.repo.GetResource(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeleteResource, resourceEntityType, id, resource, nil)
+		svc.StampDelete(ctx, resource)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeleteResource(ctx, resource)
+		})
+	})
+}
+
+func (svc *BaseService) GetDeletedResources(ctx context.Context) ([]Resource, error) {
+	return svc.repo.GetDeletedResources(ctx)
+}
+
+func (svc *BaseService) RestoreResource(ctx context.Context, id uuid.UUID) error {
+	resource := Resource{BaseModel: am.NewModel(am.WithID(id), am.WithType(resourceEntityType))}
+	svc.StampUpdate(ctx, resource)
+	entry := NewAuditEntry(ActionRestoreResource, resourceEntityType, id, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestoreResource(ctx, resource)
+	})
 }
 
 func (svc *BaseService) GetResourcePermissions(ctx context.Context, resourceID uuid.UUID) ([]Permission, error) {
@@ -317,17 +593,125 @@ func (svc *BaseService) GetResourceUnassignedPermissions(ctx context.Context, re
 }
 
 func (svc *BaseService) AddPermissionToResource(ctx context.Context, resourceID uuid.UUID, permission Permission) error {
-	return svc.repo.AddPermissionToResource(ctx, resourceID, permission)
+	ref := auditRef{"permission_id": permission.ID().String()}
+	entry := NewAuditEntry(ActionAddPermissionToResource, resourceEntityType, resourceID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddPermissionToResource(ctx, resourceID, permission)
+	})
 }
 
 func (svc *BaseService) RemovePermissionFromResource(ctx context.Context, resourceID uuid.UUID, permissionID uuid.UUID) error {
-	return svc.repo.RemovePermissionFromResource(ctx, resourceID, permissionID)
+	ref := auditRef{"permission_id": permissionID.String()}
+	entry := NewAuditEntry(ActionRemovePermissionFromResource, resourceEntityType, resourceID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemovePermissionFromResource(ctx, resourceID, permissionID)
+	})
 }
 
 func (svc *BaseService) GetDefaultOrg(ctx context.Context) (Org, error) {
 	return svc.repo.GetDefaultOrg(ctx)
 }
 
+func (svc *BaseService) GetAllOrgs(ctx context.Context) ([]Org, error) {
+	return svc.repo.GetAllOrgs(ctx)
+}
+
+func (svc *BaseService) GetUserOrgs(ctx context.Context, userID uuid.UUID) ([]Org, error) {
+	return svc.repo.GetUserOrgs(ctx, userID)
+}
+
+func (svc *BaseService) GetOrg(ctx context.Context, id uuid.UUID) (Org, error) {
+	return svc.repo.GetOrg(ctx, id)
+}
+
+// CreateOrg creates the org, the actor creating it becomes its first owner and member.
+func (svc *BaseService) CreateOrg(ctx context.Context, org Org) error {
+	svc.StampCreate(ctx, org)
+	entry := NewAuditEntry(ActionCreateOrg, orgEntityType, org.ID(), nil, org)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		err := svc.repo.CreateOrg(ctx, org)
+		if err != nil {
+			return err
+		}
+
+		actorID, ok := am.ActorFromContext(ctx)
+		if !ok {
+			return nil
+		}
+
+		err = svc.repo.AddOrgOwner(ctx, org.ID(), actorID)
+		if err != nil {
+			return err
+		}
+		return svc.repo.AddUserToOrg(ctx, org.ID(), actorID)
+	})
+}
+
+func (svc *BaseService) UpdateOrg(ctx context.Context, org Org) error {
+	svc.StampUpdate(ctx, org)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetOrg(ctx, org.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdateOrg, orgEntityType, org.ID(), before, org)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdateOrg(ctx, org)
+		})
+	})
+}
+
+func (svc *BaseService) DeleteOrg(ctx context.Context, id uuid.UUID) error {
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		org, err := svc.repo.GetOrg(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeleteOrg, orgEntityType, id, org, nil)
+		svc.StampDelete(ctx, org)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeleteOrg(ctx, org)
+		})
+	})
+}
+
+func (svc *BaseService) GetDeletedOrgs(ctx context.Context) ([]Org, error) {
+	return svc.repo.GetDeletedOrgs(ctx)
+}
+
+func (svc *BaseService) RestoreOrg(ctx context.Context, id uuid.UUID) error {
+	org := Org{BaseModel: am.NewModel(am.WithID(id), am.WithType(orgEntityType))}
+	svc.StampUpdate(ctx, org)
+	entry := NewAuditEntry(ActionRestoreOrg, orgEntityType, id, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestoreOrg(ctx, org)
+	})
+}
+
+func (svc *BaseService) GetOrgMembers(ctx context.Context, orgID uuid.UUID) ([]User, error) {
+	return svc.repo.GetOrgMembers(ctx, orgID)
+}
+
+func (svc *BaseService) GetOrgNonMembers(ctx context.Context, orgID uuid.UUID) ([]User, error) {
+	return svc.repo.GetOrgNonMembers(ctx, orgID)
+}
+
+func (svc *BaseService) AddUserToOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	ref := auditRef{"user_id": userID.String()}
+	entry := NewAuditEntry(ActionAddUserToOrg, orgEntityType, orgID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddUserToOrg(ctx, orgID, userID)
+	})
+}
+
+func (svc *BaseService) RemoveUserFromOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	ref := auditRef{"user_id": userID.String()}
+	entry := NewAuditEntry(ActionRemoveUserFromOrg, orgEntityType, orgID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveUserFromOrg(ctx, orgID, userID)
+	})
+}
+
 func (svc *BaseService) GetOrgOwners(ctx context.Context, orgID uuid.UUID) ([]User, error) {
 	return svc.repo.GetOrgOwners(ctx, orgID)
 }
@@ -337,11 +721,19 @@ func (svc *BaseService) GetOrgUnassignedOwners(ctx context.Context, orgID uuid.U
 }
 
 func (svc *BaseService) AddOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
-    return svc.repo.AddOrgOwner(ctx, orgID, userID)
+	ref := auditRef{"user_id": userID.String()}
+	entry := NewAuditEntry(ActionAddOrgOwner, orgEntityType, orgID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddOrgOwner(ctx, orgID, userID)
+	})
 }
 
 func (svc *BaseService) RemoveOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
-    return svc.repo.RemoveOrgOwner(ctx, orgID, userID)
+	ref := auditRef{"user_id": userID.String()}
+	entry := NewAuditEntry(ActionRemoveOrgOwner, orgEntityType, orgID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveOrgOwner(ctx, orgID, userID)
+	})
 }
 
 func (svc *BaseService) GetAllTeams(ctx context.Context, orgID uuid.UUID) ([]Team, error) {
@@ -353,19 +745,62 @@ func (svc *BaseService) GetTeam(ctx context.Context, id uuid.UUID) (Team, error)
 }
 
 func (svc *BaseService) CreateTeam(ctx context.Context, team Team) error {
-	return svc.repo.CreateTeam(ctx, team)
+	err := svc.checkTeamParent(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	svc.StampCreate(ctx, team)
+	entry := NewAuditEntry(ActionCreateTeam, teamEntityType, team.ID(), nil, team)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreateTeam(ctx, team)
+	})
 }
 
 func (svc *BaseService) UpdateTeam(ctx context.Context, team Team) error {
-	return svc.repo.UpdateTeam(ctx, team)
+	err := svc.checkTeamParent(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	svc.StampUpdate(ctx, team)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		before, err := svc.repo.GetTeam(ctx, team.ID())
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionUpdateTeam, teamEntityType, team.ID(), before, team)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.UpdateTeam(ctx, team)
+		})
+	})
 }
 
 func (svc *BaseService) DeleteTeam(ctx context.Context, id uuid.UUID) error {
-	return svc.repo.DeleteTeam(ctx, id)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		team, err := svc.repo.GetTeam(ctx, id)
+		if err != nil {
+			return err
+		}
+		entry := NewAuditEntry(ActionDeleteTeam, teamEntityType, id, team, nil)
+		svc.StampDelete(ctx, team)
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return svc.repo.DeleteTeam(ctx, team)
+		})
+	})
 }
 
-func (svc *BaseService) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]User, error) {
-	return svc.repo.GetTeamMembers(ctx, teamID)
+func (svc *BaseService) GetDeletedTeams(ctx context.Context, orgID uuid.UUID) ([]Team, error) {
+	return svc.repo.GetDeletedTeams(ctx, orgID)
+}
+
+func (svc *BaseService) RestoreTeam(ctx context.Context, id uuid.UUID) error {
+	team := Team{BaseModel: am.NewModel(am.WithID(id), am.WithType(teamEntityType))}
+	svc.StampUpdate(ctx, team)
+	entry := NewAuditEntry(ActionRestoreTeam, teamEntityType, id, nil, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RestoreTeam(ctx, team)
+	})
 }
 
 func (svc *BaseService) GetTeamUnassignedUsers(ctx context.Context, teamID uuid.UUID) ([]User, error) {
@@ -373,17 +808,102 @@ func (svc *BaseService) GetTeamUnassignedUsers(ctx context.Context, teamID uuid.
 }
 
 func (svc *BaseService) AddUserToTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, relationType string) error {
-	return svc.repo.AddUserToTeam(ctx, teamID, userID, relationType)
+	ref := auditRef{"user_id": userID.String(), "relation_type": relationType}
+	entry := NewAuditEntry(ActionAssignUserToTeam, teamEntityType, teamID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddUserToTeam(ctx, teamID, userID, relationType)
+	})
 }
 
 func (svc *BaseService) RemoveUserFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
-	return svc.repo.RemoveUserFromTeam(ctx, teamID, userID)
+	ref := auditRef{"user_id": userID.String()}
+	entry := NewAuditEntry(ActionRemoveUserFromTeam, teamEntityType, teamID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveUserFromTeam(ctx, teamID, userID)
+	})
+}
+
+func (svc *BaseService) GetUserContextualRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error) {
+	return svc.repo.GetUserContextualRoles(ctx, contextType, contextID, userID)
+}
+
+func (svc *BaseService) GetUserContextualUnassignedRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]Role, error) {
+	return svc.repo.GetUserContextualUnassignedRoles(ctx, contextType, contextID, userID)
+}
+
+func (svc *BaseService) GetAuditEntries(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	return svc.repo.GetAuditEntries(ctx, filter)
+}
+
+// RecordAudit records an entry for a change made outside of this service, such as a purge of another feature.
+func (svc *BaseService) RecordAudit(ctx context.Context, entry AuditEntry) error {
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return nil
+	})
+}
+
+// PurgeDeleted hard deletes everything that was soft deleted before the given time.
+// A purge that deletes something is recorded in the audit log in the same transaction.
+func (svc *BaseService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := svc.withTx(ctx, func(ctx context.Context) error {
+		var err error
+		purged, err = svc.repo.PurgeDeleted(ctx, before)
+		if err != nil || purged == 0 {
+			return err
+		}
+		entry := NewAuditEntry(ActionPurgeDeleted, authPurgeType, uuid.Nil, nil, PurgeSummary{Before: before, Purged: purged})
+		return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
 }
 
-func (svc *BaseService) GetUserContextualRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error) {
-	return svc.repo.GetUserContextualRoles(ctx, teamID, userID)
+// withAudit runs fn inside a transaction and records the audit entry in that same transaction,
+// so a change is never persisted without its trace and vice versa.
+func (svc *BaseService) withAudit(ctx context.Context, entry AuditEntry, fn func(ctx context.Context) error) error {
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		svc.StampCreate(ctx, entry)
+		entry.ActorID, _ = am.ActorFromContext(ctx)
+		entry.IP = am.ClientIP(ctx)
+
+		err = svc.repo.CreateAuditEntry(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("cannot record audit entry: %w", err)
+		}
+		return nil
+	})
 }
 
-func (svc *BaseService) GetUserContextualUnassignedRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]Role, error) {
-	return svc.repo.GetUserContextualUnassignedRoles(ctx, teamID, userID)
+// withTx runs fn inside a transaction. When the context already carries one, as in a bulk command,
+// fn joins it and committing or rolling back is left to whoever began it.
+func (svc *BaseService) withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := am.TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	ctx, tx, err := svc.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	err = fn(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
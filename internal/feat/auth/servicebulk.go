@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// BulkUsers runs the command on each of its users in a single transaction, see am.RunBatch.
+// Each change is audited as if it was made on its own. Adding a role a user already has,
+// or removing one it does not have, succeeds without changing anything.
+func (svc *BaseService) BulkUsers(ctx context.Context, cmd UserBulkCommand) (am.Batch, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return am.Batch{}, err
+	}
+	if cmd.Command == BulkAddRole || cmd.Command == BulkRemoveRole {
+		_, err = svc.repo.GetRole(ctx, cmd.RoleID)
+		if err != nil {
+			return am.Batch{}, ErrRoleNotFound
+		}
+	}
+
+	return am.RunBatch(ctx, svc.repo.BeginTx, cmd.Command, cmd.UserIDs, func(ctx context.Context, id uuid.UUID) (string, error) {
+		user, err := svc.repo.GetUser(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return user.Username, svc.bulkUser(ctx, cmd, user)
+	})
+}
+
+func (svc *BaseService) bulkUser(ctx context.Context, cmd UserBulkCommand, user User) error {
+	switch cmd.Command {
+	case BulkActivate:
+		return svc.ActivateUser(ctx, user.ID())
+	case BulkDeactivate:
+		return svc.DeactivateUser(ctx, user.ID())
+	case BulkDelete:
+		return svc.DeleteUser(ctx, user.ID())
+	}
+
+	roles, err := svc.GetUserRoles(ctx, user.ID())
+	if err != nil {
+		return err
+	}
+	has := false
+	for _, role := range roles {
+		if role.ID() == cmd.RoleID {
+			has = true
+			break
+		}
+	}
+	switch {
+	case cmd.Command == BulkAddRole && !has:
+		return svc.AddRole(ctx, user.ID(), cmd.RoleID)
+	case cmd.Command == BulkRemoveRole && has:
+		return svc.RemoveRole(ctx, user.ID(), cmd.RoleID)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ExplainPermission returns every path through which the user holds the permission.
+// Paths come from direct grants, global roles and contextual roles, following the roles they extend.
+// Team roles also apply to the teams nested under the team, and org roles to every team in the org.
+func (svc *BaseService) ExplainPermission(ctx context.Context, q ExplainQuery) (PermissionExplanation, error) {
+	user, err := svc.GetUser(ctx, q.UserID)
+	if err != nil {
+		return PermissionExplanation{}, err
+	}
+
+	perm, err := svc.repo.GetPermission(ctx, q.PermissionID)
+	if err != nil {
+		return PermissionExplanation{}, err
+	}
+
+	exp := PermissionExplanation{User: user, Permission: perm}
+
+	var scope []Team
+	if q.TeamID != uuid.Nil {
+		team, err := svc.repo.GetTeam(ctx, q.TeamID)
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+		exp.Team = &team
+
+		ancestors, err := svc.GetTeamAncestors(ctx, team.ID())
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+		scope = append([]Team{team}, ancestors...)
+	}
+
+	direct, err := svc.repo.GetUserDirectPermissions(ctx, user.ID())
+	if err != nil {
+		return PermissionExplanation{}, err
+	}
+
+	if containsPermission(direct, perm.ID()) {
+		exp.Paths = append(exp.Paths, GrantPath{Kind: GrantDirect, Summary: "Granted directly to the user"})
+	}
+
+	assignments, err := svc.repo.GetUserRoleAssignments(ctx, user.ID())
+	if err != nil {
+		return PermissionExplanation{}, err
+	}
+
+	holds := make(map[uuid.UUID]bool)
+	for _, a := range assignments {
+		contextName, applies, err := svc.assignmentScope(ctx, a, exp.Team, scope)
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+
+		if !applies {
+			continue
+		}
+
+		paths, err := svc.rolePaths(ctx, a.RoleID, perm.ID(), holds)
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+
+		for _, path := range paths {
+			path.Kind = GrantRole
+			path.Summary = "Role " + roleChain(path)
+			if a.IsContextual() {
+				path.Kind = GrantContextualRole
+				path.ContextType = a.ContextType
+				path.ContextID = a.ContextID
+				path.ContextName = contextName
+				path.Summary = fmt.Sprintf("Contextual role %s on %s %s", roleChain(path), a.ContextType, contextName)
+			}
+			exp.Paths = append(exp.Paths, path)
+		}
+	}
+
+	userGranted := len(exp.Paths) > 0
+
+	if q.ResourceID != uuid.Nil {
+		resource, err := svc.repo.GetResource(ctx, q.ResourceID)
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+		exp.Resource = &resource
+
+		bound, err := svc.repo.GetResourcePermissions(ctx, resource.ID())
+		if err != nil {
+			return PermissionExplanation{}, err
+		}
+
+		if containsPermission(bound, perm.ID()) {
+			exp.ResourceBound = true
+			exp.Paths = append(exp.Paths, GrantPath{
+				Kind:    GrantResource,
+				Summary: fmt.Sprintf("Permission bound to resource %s", resource.Name),
+			})
+		}
+	}
+
+	exp.Granted = userGranted && (exp.Resource == nil || exp.ResourceBound)
+	return exp, nil
+}
+
+// assignmentScope tells whether a role assignment applies to the requested team and names its context.
+// Without a team every assignment applies.
+func (svc *BaseService) assignmentScope(ctx context.Context, a RoleAssignment, team *Team, scope []Team) (string, bool, error) {
+	if !a.IsContextual() {
+		return "", true, nil
+	}
+
+	contextID, err := uuid.Parse(a.ContextID)
+	if err != nil {
+		return a.ContextID, team == nil, nil
+	}
+
+	switch a.ContextType {
+	case ContextTypeTeam:
+		for _, t := range scope {
+			if t.ID() == contextID {
+				return t.Name, true, nil
+			}
+		}
+		if team != nil {
+			return "", false, nil
+		}
+		t, err := svc.repo.GetTeam(ctx, contextID)
+		if err != nil {
+			return a.ContextID, true, nil
+		}
+		return t.Name, true, nil
+
+	case ContextTypeOrg:
+		if team != nil && team.OrgID != contextID {
+			return "", false, nil
+		}
+		org, err := svc.repo.GetOrg(ctx, contextID)
+		if err != nil {
+			return a.ContextID, true, nil
+		}
+		return org.Name, true, nil
+	}
+
+	return a.ContextID, team == nil, nil
+}
+
+// rolePaths walks the role and the roles it extends and returns a path for each one holding the permission.
+// holds caches whether a role holds the permission so shared ancestors are only queried once.
+func (svc *BaseService) rolePaths(ctx context.Context, roleID, permID uuid.UUID, holds map[uuid.UUID]bool) ([]GrantPath, error) {
+	role, err := svc.repo.GetRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	type step struct {
+		role Role
+		via  []Role
+	}
+
+	var paths []GrantPath
+	visited := map[uuid.UUID]bool{role.ID(): true}
+	queue := []step{{role: role}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		held, ok := holds[current.role.ID()]
+		if !ok {
+			perms, err := svc.repo.GetRolePermissions(ctx, current.role.ID())
+			if err != nil {
+				return nil, err
+			}
+			held = containsPermission(perms, permID)
+			holds[current.role.ID()] = held
+		}
+
+		if held {
+			assigned := role
+			paths = append(paths, GrantPath{Role: &assigned, Via: current.via})
+		}
+
+		parents, err := svc.repo.GetRoleParents(ctx, current.role.ID())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parent := range parents {
+			if visited[parent.ID()] {
+				continue
+			}
+			visited[parent.ID()] = true
+			via := append(append([]Role{}, current.via...), parent)
+			queue = append(queue, step{role: parent, via: via})
+		}
+	}
+
+	return paths, nil
+}
+
+// roleChain renders the assigned role followed by the roles it extends on the way to the permission.
+func roleChain(path GrantPath) string {
+	names := []string{path.Role.Name}
+	for _, r := range path.Via {
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, " → ")
+}
+
+func containsPermission(perms []Permission, permID uuid.UUID) bool {
+	for _, perm := range perms {
+		if perm.ID() == permID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// GetTeamInvitations returns the pending invitations to a team.
+func (svc *BaseService) GetTeamInvitations(ctx context.Context, teamID uuid.UUID) ([]Invitation, error) {
+	invitations, err := svc.repo.GetTeamInvitations(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.decryptInvitations(invitations)
+}
+
+// GetOrgInvitations returns the pending invitations to an org that do not target a team.
+func (svc *BaseService) GetOrgInvitations(ctx context.Context, orgID uuid.UUID) ([]Invitation, error) {
+	invitations, err := svc.repo.GetOrgInvitations(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.decryptInvitations(invitations)
+}
+
+// GetInvitationByToken returns the invitation the token was issued for.
+func (svc *BaseService) GetInvitationByToken(ctx context.Context, token string) (Invitation, error) {
+	inv, err := svc.repo.GetInvitationByTokenHash(ctx, HashToken(token))
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	err = svc.decryptInvitation(&inv)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	return inv, nil
+}
+
+// CreateInvitation stores the invitation and returns the token to send to the invitee.
+// The token is not stored, it cannot be recovered later.
+func (svc *BaseService) CreateInvitation(ctx context.Context, inv Invitation) (string, error) {
+	inv.Email = strings.TrimSpace(inv.Email)
+	if inv.Email == "" {
+		return "", fmt.Errorf("email is required")
+	}
+
+	token, err := GenerateToken()
+	if err != nil {
+		return "", fmt.Errorf("cannot generate invitation token: %w", err)
+	}
+
+	encKey := svc.Cfg().ByteSliceVal(key.SecEncryptionKey)
+	inv.EmailEnc, err = EncryptEmail(inv.Email, encKey)
+	if err != nil {
+		return "", fmt.Errorf("cannot encrypt invitation email: %w", err)
+	}
+
+	inv.TokenHash = HashToken(token)
+	inv.Status = InvitationPending
+	inv.InviterID, _ = am.ActorFromContext(ctx)
+	if inv.ExpiresAt.IsZero() {
+		inv.ExpiresAt = time.Now().Add(DefaultInvitationTTL)
+	}
+
+	svc.StampCreate(ctx, inv)
+	entry := NewAuditEntry(ActionCreateInvitation, invitationEntityType, inv.ID(), nil, invitationSnapshot(inv))
+	err = svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreateInvitation(ctx, inv)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// AcceptInvitation links the invitee to the org, team and role of the invitation.
+// The user registered with the invitation email is used if there is one, otherwise the given user is created.
+func (svc *BaseService) AcceptInvitation(ctx context.Context, token string, user User) (User, error) {
+	inv, err := svc.pendingInvitation(ctx, token)
+	if err != nil {
+		return User{}, err
+	}
+
+	existing, found, err := svc.findUserByEmail(ctx, inv.Email)
+	if err != nil {
+		return User{}, err
+	}
+
+	if found {
+		user = existing
+	} else {
+		user.Email = inv.Email
+		user.SetType(userType)
+		svc.StampCreate(ctx, user)
+		ctx = svc.withEncryptionKey(ctx)
+		err = user.PrePersist(ctx)
+		if err != nil {
+			return User{}, fmt.Errorf("error preparing user for insert: %w", err)
+		}
+	}
+
+	joins, err := svc.pendingJoins(ctx, inv, user.ID(), found)
+	if err != nil {
+		return User{}, err
+	}
+
+	inv.Status = InvitationAccepted
+	inv.UserID = user.ID()
+	inv.RespondedAt = time.Now()
+	svc.StampUpdate(ctx, inv)
+
+	entry := NewAuditEntry(ActionAcceptInvitation, invitationEntityType, inv.ID(), nil, invitationSnapshot(inv))
+	err = svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		if !found {
+			err := svc.repo.CreateUser(ctx, user)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := svc.joinInvitationTargets(ctx, inv, user.ID(), joins)
+		if err != nil {
+			return err
+		}
+
+		return svc.repo.RespondInvitation(ctx, inv)
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+// DeclineInvitation closes the invitation without linking anyone.
+func (svc *BaseService) DeclineInvitation(ctx context.Context, token string) error {
+	inv, err := svc.pendingInvitation(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return svc.closeInvitation(ctx, inv, InvitationDeclined, ActionDeclineInvitation)
+}
+
+// RevokeInvitation closes a pending invitation so that its token cannot be used anymore.
+func (svc *BaseService) RevokeInvitation(ctx context.Context, id uuid.UUID) error {
+	inv, err := svc.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if inv.Status != InvitationPending {
+		return ErrInvitationClosed
+	}
+
+	return svc.closeInvitation(ctx, inv, InvitationRevoked, ActionRevokeInvitation)
+}
+
+func (svc *BaseService) closeInvitation(ctx context.Context, inv Invitation, status, action string) error {
+	before := invitationSnapshot(inv)
+	inv.Status = status
+	inv.RespondedAt = time.Now()
+	svc.StampUpdate(ctx, inv)
+
+	entry := NewAuditEntry(action, invitationEntityType, inv.ID(), before, invitationSnapshot(inv))
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RespondInvitation(ctx, inv)
+	})
+}
+
+// pendingInvitation returns the invitation for the token if it can still be answered.
+func (svc *BaseService) pendingInvitation(ctx context.Context, token string) (Invitation, error) {
+	inv, err := svc.GetInvitationByToken(ctx, token)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	now := time.Now()
+	if inv.IsExpired(now) {
+		return Invitation{}, ErrInvitationExpired
+	}
+
+	if !inv.IsPending(now) {
+		return Invitation{}, ErrInvitationClosed
+	}
+
+	return inv, nil
+}
+
+// invitationJoins lists the memberships and role the invitation grants that the user does not have yet.
+type invitationJoins struct {
+	org  bool
+	team bool
+	role bool
+}
+
+// pendingJoins works out which memberships and role the user still needs.
+// A user that is being created by the invitation has none of them.
+func (svc *BaseService) pendingJoins(ctx context.Context, inv Invitation, userID uuid.UUID, existing bool) (invitationJoins, error) {
+	joins := invitationJoins{org: true, team: inv.TeamID != uuid.Nil, role: inv.RoleID != uuid.Nil}
+	if !existing {
+		return joins, nil
+	}
+
+	members, err := svc.repo.GetOrgMembers(ctx, inv.OrgID)
+	if err != nil {
+		return joins, err
+	}
+	joins.org = !containsUser(members, userID)
+
+	if joins.team {
+		members, err := svc.repo.GetTeamMembers(ctx, inv.TeamID)
+		if err != nil {
+			return joins, err
+		}
+		joins.team = !containsUser(members, userID)
+	}
+
+	if joins.role {
+		contextType, contextID := invitationContext(inv)
+		roles, err := svc.repo.GetUserContextualRoles(ctx, contextType, contextID, userID)
+		if err != nil {
+			return joins, err
+		}
+		for _, role := range roles {
+			if role.ID() == inv.RoleID {
+				joins.role = false
+				break
+			}
+		}
+	}
+
+	return joins, nil
+}
+
+// joinInvitationTargets adds the user to the org and team of the invitation and grants its role.
+func (svc *BaseService) joinInvitationTargets(ctx context.Context, inv Invitation, userID uuid.UUID, joins invitationJoins) error {
+	if joins.org {
+		err := svc.repo.AddUserToOrg(ctx, inv.OrgID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if joins.team {
+		err := svc.repo.AddUserToTeam(ctx, inv.TeamID, userID, RelationDirect)
+		if err != nil {
+			return err
+		}
+	}
+
+	if joins.role {
+		contextType, contextID := invitationContext(inv)
+		return svc.repo.AddRole(ctx, userID, inv.RoleID, contextType, contextID.String())
+	}
+
+	return nil
+}
+
+// invitationContext returns the context the invitation role is granted in, the team if there is one, otherwise the org.
+func invitationContext(inv Invitation) (string, uuid.UUID) {
+	if inv.TeamID != uuid.Nil {
+		return ContextTypeTeam, inv.TeamID
+	}
+	return ContextTypeOrg, inv.OrgID
+}
+
+// findUserByEmail looks up a user by email.
+// Emails are stored encrypted so the comparison is done on the decrypted values.
+func (svc *BaseService) findUserByEmail(ctx context.Context, email string) (User, bool, error) {
+	users, err := svc.GetUsers(ctx)
+	if err != nil {
+		return User{}, false, err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return user, true, nil
+		}
+	}
+
+	return User{}, false, nil
+}
+
+func (svc *BaseService) decryptInvitations(invitations []Invitation) ([]Invitation, error) {
+	for i := range invitations {
+		err := svc.decryptInvitation(&invitations[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return invitations, nil
+}
+
+func (svc *BaseService) decryptInvitation(inv *Invitation) error {
+	if len(inv.EmailEnc) == 0 {
+		return nil
+	}
+
+	encKey := svc.Cfg().ByteSliceVal(key.SecEncryptionKey)
+	email, err := DecryptEmail(inv.EmailEnc, encKey)
+	if err != nil {
+		return fmt.Errorf("error decrypting email for invitation %s: %w", inv.ID(), err)
+	}
+	inv.Email = email
+	return nil
+}
+
+func containsUser(users []User, userID uuid.UUID) bool {
+	for _, user := range users {
+		if user.ID() == userID {
+			return true
+		}
+	}
+	return false
+}
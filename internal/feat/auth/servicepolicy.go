@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetPolicies returns the file policies followed by the ones stored in the database.
+func (svc *BaseService) GetPolicies(ctx context.Context) ([]Policy, error) {
+	stored, err := svc.repo.GetPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(svc.filePolicies(), stored...), nil
+}
+
+// GetPolicy returns a file or database policy.
+func (svc *BaseService) GetPolicy(ctx context.Context, id uuid.UUID) (Policy, error) {
+	if p, ok := svc.filePolicy(id); ok {
+		return p, nil
+	}
+	return svc.repo.GetPolicy(ctx, id)
+}
+
+func (svc *BaseService) CreatePolicy(ctx context.Context, policy Policy) error {
+	policy.Source = PolicySourceDB
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	svc.StampCreate(ctx, policy)
+	entry := NewAuditEntry(ActionCreatePolicy, policyEntityType, policy.ID(), nil, policy)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.CreatePolicy(ctx, policy)
+	})
+}
+
+func (svc *BaseService) UpdatePolicy(ctx context.Context, policy Policy) error {
+	if _, ok := svc.filePolicy(policy.ID()); ok {
+		return ErrPolicyReadOnly
+	}
+
+	policy.Source = PolicySourceDB
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	before, err := svc.repo.GetPolicy(ctx, policy.ID())
+	if err != nil {
+		return err
+	}
+
+	svc.StampUpdate(ctx, policy)
+	entry := NewAuditEntry(ActionUpdatePolicy, policyEntityType, policy.ID(), before, policy)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.UpdatePolicy(ctx, policy)
+	})
+}
+
+func (svc *BaseService) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	if _, ok := svc.filePolicy(id); ok {
+		return ErrPolicyReadOnly
+	}
+
+	policy, err := svc.repo.GetPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	entry := NewAuditEntry(ActionDeletePolicy, policyEntityType, id, policy, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.DeletePolicy(ctx, id)
+	})
+}
+
+// Authorize decides whether the user can perform the requested action.
+// RBAC grants the action when the user holds a permission with the same name, directly or through a role.
+// Subject and env attributes are always resolved on the server, any sent in the request are discarded.
+func (svc *BaseService) Authorize(ctx context.Context, userID uuid.UUID, req AccessRequest) (Decision, error) {
+	return svc.authorize(ctx, userID, req, false)
+}
+
+// SimulateAuthorize answers what-if questions for the policy test page.
+// Subject and env attributes set in the request replace the resolved ones and the decision is marked as simulated,
+// it must never be used to grant access.
+func (svc *BaseService) SimulateAuthorize(ctx context.Context, userID uuid.UUID, req AccessRequest) (Decision, error) {
+	return svc.authorize(ctx, userID, req, true)
+}
+
+// Enforcer returns an enforcer for the policies on resources whose access is managed by the caller, e.g. shared todo lists.
+// The subject and env attributes of the user and the policies are resolved once, on the server, so one enforcer can decide
+// on many resources of a request.
+func (svc *BaseService) Enforcer(ctx context.Context, userID uuid.UUID) (*Enforcer, error) {
+	req, _, err := svc.resolveRequest(ctx, userID, AccessRequest{}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := svc.GetPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEnforcer(req.Subject, req.Env, policies), nil
+}
+
+func (svc *BaseService) authorize(ctx context.Context, userID uuid.UUID, req AccessRequest, simulate bool) (Decision, error) {
+	req, perms, err := svc.resolveRequest(ctx, userID, req, simulate)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	rbac := false
+	for _, p := range perms {
+		if strings.EqualFold(p.Name, req.Action) {
+			rbac = true
+			break
+		}
+	}
+
+	return svc.decide(ctx, req, rbac, simulate)
+}
+
+// resolveRequest fills the subject and env attributes of the request along with the permissions the user holds.
+// Attributes sent in the request are only kept when simulating.
+func (svc *BaseService) resolveRequest(ctx context.Context, userID uuid.UUID, req AccessRequest, simulate bool) (AccessRequest, []Permission, error) {
+	subject, perms, err := svc.subjectAttrs(ctx, userID)
+	if err != nil {
+		return AccessRequest{}, nil, err
+	}
+	env := envAttrs(time.Now())
+	if simulate {
+		subject = mergeAttrs(subject, req.Subject)
+		env = mergeAttrs(env, req.Env)
+	}
+	req.Subject, req.Env = subject, env
+	return req, perms, nil
+}
+
+func (svc *BaseService) decide(ctx context.Context, req AccessRequest, rbac, simulate bool) (Decision, error) {
+	policies, err := svc.GetPolicies(ctx)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	d := Decide(policies, req, rbac)
+	d.Simulated = simulate
+	return d, nil
+}
+
+// subjectAttrs returns the attributes of the user along with the permissions it holds.
+func (svc *BaseService) subjectAttrs(ctx context.Context, userID uuid.UUID) (map[string]any, []Permission, error) {
+	user, err := svc.repo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	roles, err := svc.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	direct, err := svc.repo.GetUserDirectPermissions(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indirect, err := svc.GetUserIndirectPermissions(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgs, err := svc.repo.GetUserOrgs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teams, err := svc.GetUserTeamIDs(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	perms := append(direct, indirect...)
+	roleNames := make([]string, len(roles))
+	for i, r := range roles {
+		roleNames[i] = r.Name
+	}
+	permNames := make([]string, len(perms))
+	for i, p := range perms {
+		permNames[i] = p.Name
+	}
+	orgIDs := make([]string, len(orgs))
+	for i, o := range orgs {
+		orgIDs[i] = o.ID().String()
+	}
+	teamIDs := make([]string, len(teams))
+	for i, id := range teams {
+		teamIDs[i] = id.String()
+	}
+
+	attrs := map[string]any{
+		"id":          userID.String(),
+		"username":    user.Username,
+		"roles":       roleNames,
+		"permissions": permNames,
+		"org_ids":     orgIDs,
+		"team_ids":    teamIDs,
+	}
+	return attrs, perms, nil
+}
+
+func (svc *BaseService) filePolicies() []Policy {
+	if svc.policies == nil {
+		return nil
+	}
+	return svc.policies.Policies()
+}
+
+func (svc *BaseService) filePolicy(id uuid.UUID) (Policy, bool) {
+	for _, p := range svc.filePolicies() {
+		if p.ID() == id {
+			return p, true
+		}
+	}
+	return Policy{}, false
+}
+
+// envAttrs returns the environment attributes for the given time.
+// Weekday goes from 0 (Sunday) to 6 (Saturday).
+func envAttrs(now time.Time) map[string]any {
+	return map[string]any{
+		"hour":    now.Hour(),
+		"weekday": int(now.Weekday()),
+		"date":    now.Format("2006-01-02"),
+	}
+}
+
+// mergeAttrs returns base with the values in override replacing the ones with the same key.
+func mergeAttrs(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetRoleParents returns the roles the given role extends directly.
+func (svc *BaseService) GetRoleParents(ctx context.Context, roleID uuid.UUID) ([]Role, error) {
+	return svc.repo.GetRoleParents(ctx, roleID)
+}
+
+// GetRoleParentCandidates returns the roles the given role can be made to extend.
+// The role itself, its current parents and the roles that already extend it are left out.
+func (svc *BaseService) GetRoleParentCandidates(ctx context.Context, roleID uuid.UUID) ([]Role, error) {
+	roles, err := svc.repo.GetAllRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	parents, err := svc.repo.GetRoleParents(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := map[uuid.UUID]bool{roleID: true}
+	for _, parent := range parents {
+		excluded[parent.ID()] = true
+	}
+
+	var candidates []Role
+	for _, role := range roles {
+		if excluded[role.ID()] {
+			continue
+		}
+
+		extends, err := svc.roleExtends(ctx, role.ID(), roleID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !extends {
+			candidates = append(candidates, role)
+		}
+	}
+
+	return candidates, nil
+}
+
+// GetRoleAncestors returns every role the given role extends, directly or through other roles, closest first.
+func (svc *BaseService) GetRoleAncestors(ctx context.Context, roleID uuid.UUID) ([]Role, error) {
+	return svc.repo.GetRoleAncestors(ctx, roleID)
+}
+
+// GetRoleInheritedPermissions returns the permissions the role gets from the roles it extends.
+// Permissions the role already has are not repeated, and each inherited one is attributed to the closest role granting it.
+func (svc *BaseService) GetRoleInheritedPermissions(ctx context.Context, roleID uuid.UUID) ([]InheritedPermission, error) {
+	own, err := svc.repo.GetRolePermissions(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, perm := range own {
+		seen[perm.ID()] = true
+	}
+
+	ancestors, err := svc.GetRoleAncestors(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inherited []InheritedPermission
+	for _, ancestor := range ancestors {
+		perms, err := svc.repo.GetRolePermissions(ctx, ancestor.ID())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, perm := range perms {
+			if seen[perm.ID()] {
+				continue
+			}
+			seen[perm.ID()] = true
+			inherited = append(inherited, InheritedPermission{Permission: perm, Source: ancestor})
+		}
+	}
+
+	return inherited, nil
+}
+
+// AddRoleParent makes the role extend the parent, rejecting links that would close a cycle.
+func (svc *BaseService) AddRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error {
+	if roleID == parentID {
+		return ErrRoleCycle
+	}
+
+	extends, err := svc.roleExtends(ctx, parentID, roleID)
+	if err != nil {
+		return err
+	}
+
+	if extends {
+		return ErrRoleCycle
+	}
+
+	ref := auditRef{"parent_id": parentID.String()}
+	entry := NewAuditEntry(ActionAddRoleParent, roleType, roleID, nil, ref)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.AddRoleParent(ctx, roleID, parentID)
+	})
+}
+
+func (svc *BaseService) RemoveRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error {
+	ref := auditRef{"parent_id": parentID.String()}
+	entry := NewAuditEntry(ActionRemoveRoleParent, roleType, roleID, ref, nil)
+	return svc.withAudit(ctx, entry, func(ctx context.Context) error {
+		return svc.repo.RemoveRoleParent(ctx, roleID, parentID)
+	})
+}
+
+// GetUserIndirectPermissions returns the permissions the user gets through roles, including the ones
+// those roles inherit from the roles they extend.
+func (svc *BaseService) GetUserIndirectPermissions(ctx context.Context, userID uuid.UUID) ([]Permission, error) {
+	return svc.repo.GetUserIndirectPermissions(ctx, userID)
+}
+
+// roleExtends reports whether the role extends the other one, directly or transitively.
+func (svc *BaseService) roleExtends(ctx context.Context, roleID, otherID uuid.UUID) (bool, error) {
+	ancestors, err := svc.GetRoleAncestors(ctx, roleID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, ancestor := range ancestors {
+		if ancestor.ID() == otherID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
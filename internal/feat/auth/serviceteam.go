@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetTeamMembers returns the direct members of the team followed by the members inherited from its ancestors.
+// A user that belongs to several teams in the chain is listed once, with the closest relation.
+func (svc *BaseService) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]TeamMember, error) {
+	team, err := svc.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := svc.GetTeamAncestors(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []TeamMember
+	seen := make(map[uuid.UUID]bool)
+	for i, source := range append([]Team{team}, ancestors...) {
+		relation := RelationInherited
+		if i == 0 {
+			relation = RelationDirect
+		}
+
+		users, err := svc.repo.GetTeamMembers(ctx, source.ID())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if seen[user.ID()] {
+				continue
+			}
+			seen[user.ID()] = true
+			members = append(members, TeamMember{User: user, Relation: relation, Source: source})
+		}
+	}
+
+	return members, nil
+}
+
+// GetTeamAncestors returns the teams the given team is nested under, closest first.
+// Deleted ancestors end the chain.
+func (svc *BaseService) GetTeamAncestors(ctx context.Context, teamID uuid.UUID) ([]Team, error) {
+	team, err := svc.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := svc.orgTeams(ctx, team.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ancestorsOf(team, teams), nil
+}
+
+// GetTeamTree returns the given team with all the teams nested under it.
+func (svc *BaseService) GetTeamTree(ctx context.Context, teamID uuid.UUID) (TeamNode, error) {
+	team, err := svc.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return TeamNode{}, err
+	}
+
+	all, err := svc.repo.GetAllTeams(ctx, team.OrgID)
+	if err != nil {
+		return TeamNode{}, err
+	}
+
+	return buildTeamNode(team, childrenByParent(all), map[uuid.UUID]bool{}), nil
+}
+
+// GetOrgTeamTree returns the teams of an org arranged as a forest.
+// Teams whose parent is not available, for instance because it is in the trash, are shown as roots.
+func (svc *BaseService) GetOrgTeamTree(ctx context.Context, orgID uuid.UUID) ([]TeamNode, error) {
+	all, err := svc.repo.GetAllTeams(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uuid.UUID]bool, len(all))
+	for _, team := range all {
+		ids[team.ID()] = true
+	}
+
+	children := childrenByParent(all)
+	visited := make(map[uuid.UUID]bool)
+	var roots []TeamNode
+	for _, team := range all {
+		if team.HasParent() && ids[team.ParentID] {
+			continue
+		}
+		roots = append(roots, buildTeamNode(team, children, visited))
+	}
+
+	return roots, nil
+}
+
+// GetUserInheritedTeamRoles returns the contextual roles the user has in the ancestors of the team.
+// Roles held in a team apply to every team nested under it.
+func (svc *BaseService) GetUserInheritedTeamRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]InheritedRole, error) {
+	ancestors, err := svc.GetTeamAncestors(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var inherited []InheritedRole
+	for _, ancestor := range ancestors {
+		roles, err := svc.repo.GetUserContextualRoles(ctx, ContextTypeTeam, ancestor.ID(), userID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range roles {
+			inherited = append(inherited, InheritedRole{Role: role, Source: ancestor})
+		}
+	}
+
+	return inherited, nil
+}
+
+// GetUserTeamIDs returns the teams the user is a member of, including the teams nested under them
+// since membership is inherited.
+func (svc *BaseService) GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	teams, err := svc.repo.GetUserTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, team := range teams {
+		if seen[team.ID()] {
+			continue
+		}
+		tree, err := svc.GetTeamTree(ctx, team.ID())
+		if err != nil {
+			return nil, err
+		}
+		ids = appendTeamNodeIDs(ids, tree, seen)
+	}
+	return ids, nil
+}
+
+// checkTeamParent rejects parents from another org and moves that would create a cycle.
+func (svc *BaseService) checkTeamParent(ctx context.Context, team Team) error {
+	if !team.HasParent() {
+		return nil
+	}
+
+	if team.ParentID == team.ID() {
+		return ErrTeamCycle
+	}
+
+	parent, err := svc.repo.GetTeam(ctx, team.ParentID)
+	if err != nil {
+		return err
+	}
+
+	if parent.OrgID != team.OrgID {
+		return ErrNotInOrg
+	}
+
+	teams, err := svc.orgTeams(ctx, team.OrgID)
+	if err != nil {
+		return err
+	}
+
+	for _, ancestor := range append([]Team{parent}, ancestorsOf(parent, teams)...) {
+		if ancestor.ID() == team.ID() {
+			return ErrTeamCycle
+		}
+	}
+
+	return nil
+}
+
+// orgTeams returns the active teams of an org indexed by id.
+func (svc *BaseService) orgTeams(ctx context.Context, orgID uuid.UUID) (map[uuid.UUID]Team, error) {
+	all, err := svc.repo.GetAllTeams(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make(map[uuid.UUID]Team, len(all))
+	for _, team := range all {
+		teams[team.ID()] = team
+	}
+	return teams, nil
+}
+
+// ancestorsOf walks up the parent chain of the team.
+// The walk stops at a missing parent or at a team already visited, so stored cycles cannot loop forever.
+func ancestorsOf(team Team, teams map[uuid.UUID]Team) []Team {
+	var ancestors []Team
+	visited := map[uuid.UUID]bool{team.ID(): true}
+	for team.HasParent() {
+		parent, ok := teams[team.ParentID]
+		if !ok || visited[parent.ID()] {
+			break
+		}
+		visited[parent.ID()] = true
+		ancestors = append(ancestors, parent)
+		team = parent
+	}
+	return ancestors
+}
+
+func childrenByParent(teams []Team) map[uuid.UUID][]Team {
+	children := make(map[uuid.UUID][]Team)
+	for _, team := range teams {
+		if team.HasParent() {
+			children[team.ParentID] = append(children[team.ParentID], team)
+		}
+	}
+	return children
+}
+
+func buildTeamNode(team Team, children map[uuid.UUID][]Team, visited map[uuid.UUID]bool) TeamNode {
+	node := TeamNode{Team: team}
+	visited[team.ID()] = true
+	for _, child := range children[team.ID()] {
+		if visited[child.ID()] {
+			continue
+		}
+		node.Children = append(node.Children, buildTeamNode(child, children, visited))
+	}
+	return node
+}
+
+func appendTeamNodeIDs(ids []uuid.UUID, node TeamNode, seen map[uuid.UUID]bool) []uuid.UUID {
+	if !seen[node.ID()] {
+		seen[node.ID()] = true
+		ids = append(ids, node.ID())
+	}
+	for _, child := range node.Children {
+		ids = appendTeamNodeIDs(ids, child, seen)
+	}
+	return ids
+}
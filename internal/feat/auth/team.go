@@ -9,11 +9,19 @@ import (
 
 const (
 	teamEntityType = "team"
+	// ContextTypeTeam is the context type of the roles a user has in a team.
+	ContextTypeTeam = "team"
+
+	// RelationDirect marks users that were added to the team itself.
+	RelationDirect = "direct"
+	// RelationInherited marks users that are members of one of the team ancestors.
+	RelationInherited = "inherited"
 )
 
 type Team struct {
 	*am.BaseModel
 	OrgID            uuid.UUID `json:"org_id"`
+	ParentID         uuid.UUID `json:"parent_id"`
 	OrgRef           string    `json:"org_ref"`
 	Name             string    `json:"name"`
 	ShortDescription string    `json:"short_description"`
@@ -49,3 +57,33 @@ func (t *Team) UnmarshalJSON(data []byte) error {
 func (t *Team) Slug() string {
 	return am.Normalize(t.Name) + "-" + t.ShortID()
 }
+
+// HasParent reports whether the team is nested under another team.
+func (t *Team) HasParent() bool {
+	return t.ParentID != uuid.Nil
+}
+
+// TeamMember is a user that belongs to a team, either directly or through an ancestor team.
+type TeamMember struct {
+	User
+	Relation string `json:"relation"`
+	// Source is the team the membership comes from, the team itself for direct members.
+	Source Team `json:"source"`
+}
+
+// IsInherited reports whether the membership comes from an ancestor team.
+func (m TeamMember) IsInherited() bool {
+	return m.Relation == RelationInherited
+}
+
+// TeamNode is a team along with the teams nested under it.
+type TeamNode struct {
+	Team
+	Children []TeamNode `json:"children,omitempty"`
+}
+
+// InheritedRole is a contextual role a user has in an ancestor team and that applies to its descendants.
+type InheritedRole struct {
+	Role
+	Source Team `json:"source"`
+}
@@ -8,6 +8,7 @@ import (
 type TeamDA struct {
 	ID               sql.NullString `db:"id"`
 	OrgID            sql.NullString `db:"org_id"`
+	ParentID         sql.NullString `db:"parent_id"`
 	ShortID          string         `db:"short_id"`
 	Name             string         `db:"name"`
 	ShortDescription string         `db:"short_description"`
@@ -16,4 +17,7 @@ type TeamDA struct {
 	UpdatedBy        sql.NullString `db:"updated_by"`
 	CreatedAt        time.Time      `db:"created_at"`
 	UpdatedAt        time.Time      `db:"updated_at"`
+	Version          int            `db:"version"`
+	DeletedBy        sql.NullString `db:"deleted_by"`
+	DeletedAt        sql.NullTime   `db:"deleted_at"`
 }
@@ -20,6 +20,9 @@ type UserDA struct {
 	UpdatedBy     sql.NullString `db:"updated_by"`
 	CreatedAt     sql.NullTime   `db:"created_at"`
 	UpdatedAt     sql.NullTime   `db:"updated_at"`
+	Version       int            `db:"version"`
+	DeletedBy     sql.NullString `db:"deleted_by"`
+	DeletedAt     sql.NullTime   `db:"deleted_at"`
 	LastLoginAt   sql.NullTime   `db:"last_login_at"`
 	LastLoginIP   sql.NullString `db:"last_login_ip"`
 	IsActive      sql.NullBool   `db:"is_active"`
@@ -41,6 +44,7 @@ type UserExtDA struct {
 	UpdatedBy      sql.NullString `db:"updated_by"`
 	CreatedAt      sql.NullTime   `db:"created_at"`
 	UpdatedAt      sql.NullTime   `db:"updated_at"`
+	Version        int            `db:"version"`
 	LastLoginAt    sql.NullTime   `db:"last_login_at"`
 	LastLoginIP    sql.NullString `db:"last_login_ip"`
 	IsActive       sql.NullBool   `db:"is_active"`
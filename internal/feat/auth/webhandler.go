@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"bytes"
 	"net/http"
 
 	"github.com/aquamarinepk/todo/internal/am"
@@ -15,6 +16,7 @@ const (
 	resourcePath   = "resource"
 	orgPath        = "org"
 	teamPath       = "team"
+	policyPath     = "policy"
 
 	// Role related paths
 	listRolePermissionsPath     = "list-role-permissions"
@@ -23,7 +25,16 @@ const (
 	contextualRolePath          = "contextual-role"
 	listResourcePermissionsPath = "list-resource-permissions"
 	listOrgOwnersPath           = "list-org-owners"
+	listOrgsPath                = "list-orgs"
+	listOrgMembersPath          = "list-org-members"
+	listUserOrgRolesPath        = "list-user-org-roles"
 	listTeamsPath               = "list-teams"
+	listAuditEntriesPath        = "list-audit-entries"
+	listTeamInvitationsPath     = "list-team-invitations"
+	listOrgInvitationsPath      = "list-org-invitations"
+	acceptInvitationPath        = "accept-invitation"
+	listPoliciesPath            = "list-policies"
+	testPolicyPath              = "test-policy"
 
 	userPathFmt = "%s/%s-user%s"
 )
@@ -32,9 +43,18 @@ const (
 	ActionListUserRoles       = "list-user-roles"
 	ActionListUserPermissions = "list-user-permissions"
 	ActionListTeamMembers     = "list-team-members"
+	ActionListOrgMembers      = "list-org-members"
+	ActionListOrgOwners       = "list-org-owners"
+	ActionListTeamInvitations = "list-team-invitations"
+	ActionListOrgInvitations  = "list-org-invitations"
+	ActionExplainPermission   = "explain-permission"
 	TextRoles                 = "Roles"
 	TextPermissions           = "Permissions"
 	TextMembers               = "Members"
+	TextOwners                = "Owners"
+	TextInvitations           = "Invitations"
+	TextExplain               = "Explain"
+	TextTest                  = "Test"
 )
 
 type WebHandler struct {
@@ -152,3 +172,24 @@ func (h *WebHandler) GetResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// renderPage renders the named auth template with the page.
+func (h *WebHandler) renderPage(w http.ResponseWriter, name string, page *am.Page) {
+	tmpl, err := h.tm.Get("auth", name)
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
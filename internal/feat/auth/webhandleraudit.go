@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+// Audit handlers
+func (h *WebHandler) ListAuditEntries(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List audit entries")
+	ctx := r.Context()
+
+	form := NewAuditFilterForm(r.URL.Query())
+	filter, err := FormToAuditFilter(form)
+	if err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.service.GetAuditEntries(ctx, filter)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Filter  AuditFilterForm
+		Entries []AuditEntry
+	}{
+		Filter:  form,
+		Entries: entries,
+	})
+	page.SetFormAction(authPath + "/" + listAuditEntriesPath)
+
+	page.NewMenu(authPath)
+
+	tmpl, err := h.tm.Get("auth", "list-audit-entries")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
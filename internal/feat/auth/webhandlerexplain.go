@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// ExplainPermission shows every path through which a user holds a permission.
+// The form is shown on its own until a permission is picked.
+func (h *WebHandler) ExplainPermission(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("Explain permission", "user_id", id)
+	ctx := r.Context()
+
+	q, err := explainQuery(r, id)
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.service.GetUser(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	permissions, err := h.service.GetAllPermissions(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	resources, err := h.service.GetAllResources(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	var teams []Team
+	if org, err := h.currentOrg(ctx); err == nil {
+		teams, err = h.service.GetAllTeams(ctx, org.ID())
+		if err != nil {
+			h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	var explanation *PermissionExplanation
+	if q.PermissionID != uuid.Nil {
+		exp, err := h.service.ExplainPermission(ctx, q)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+		explanation = &exp
+	}
+
+	page := am.NewPage(r, struct {
+		User        User
+		Query       ExplainQuery
+		Permissions []Permission
+		Resources   []Resource
+		Teams       []Team
+		Explanation *PermissionExplanation
+	}{
+		User:        user,
+		Query:       q,
+		Permissions: permissions,
+		Resources:   resources,
+		Teams:       teams,
+		Explanation: explanation,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddGenericItem(ActionListUserPermissions, user.ID().String(), "Back")
+
+	h.renderPage(w, "explain-permission", page)
+}
+
+// explainQuery reads the optional permission, resource and team of an explain request.
+func explainQuery(r *http.Request, userID uuid.UUID) (ExplainQuery, error) {
+	values := r.URL.Query()
+	q := ExplainQuery{UserID: userID}
+
+	var err error
+	q.PermissionID, err = optionalUUID(values.Get("permission_id"))
+	if err != nil {
+		return q, err
+	}
+
+	q.ResourceID, err = optionalUUID(values.Get("resource_id"))
+	if err != nil {
+		return q, err
+	}
+
+	q.TeamID, err = optionalUUID(values.Get("team_id"))
+	if err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
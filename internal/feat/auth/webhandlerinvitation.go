@@ -0,0 +1,312 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// invitationsPage is the data of the pending invitations list, shared by teams and orgs.
+type invitationsPage struct {
+	Title       string
+	OrgID       uuid.UUID
+	TeamID      uuid.UUID
+	Invitations []Invitation
+	Roles       []Role
+}
+
+// Invitation handlers
+func (h *WebHandler) ListTeamInvitations(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("List team invitations", "id", id)
+	ctx := r.Context()
+
+	team, err := h.orgTeam(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	invitations, err := h.service.GetTeamInvitations(ctx, team.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	roles, err := h.service.GetAllRoles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, invitationsPage{
+		Title:       team.Name,
+		OrgID:       team.OrgID,
+		TeamID:      team.ID(),
+		Invitations: invitations,
+		Roles:       roles,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddShowItem(team, "Back")
+
+	h.renderPage(w, "list-invitations", page)
+}
+
+func (h *WebHandler) ListOrgInvitations(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("List org invitations", "id", id)
+	ctx := r.Context()
+
+	org, err := h.service.GetOrg(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	invitations, err := h.service.GetOrgInvitations(ctx, org.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	roles, err := h.service.GetAllRoles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, invitationsPage{
+		Title:       org.Name,
+		OrgID:       org.ID(),
+		Invitations: invitations,
+		Roles:       roles,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddShowItem(org, "Back")
+
+	h.renderPage(w, "list-invitations", page)
+}
+
+// CreateInvitation stores the invitation and shows the accept link once, the token is not kept.
+func (h *WebHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create invitation")
+	if err := r.ParseForm(); err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(r.Form.Get("org_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	teamID, err := optionalUUID(r.Form.Get("team_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	roleID, err := optionalUUID(r.Form.Get("role_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	email := strings.TrimSpace(r.Form.Get("email"))
+	if email == "" {
+		h.Err(w, nil, "Email is required", http.StatusBadRequest)
+		return
+	}
+
+	var expiresAt time.Time
+	if days := strings.TrimSpace(r.Form.Get("expires_in_days")); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			h.Err(w, err, "Expiry must be a positive number of days", http.StatusBadRequest)
+			return
+		}
+		expiresAt = time.Now().Add(time.Duration(n) * 24 * time.Hour)
+	}
+
+	inv := NewInvitation(email, orgID, teamID, roleID, expiresAt)
+	token, err := h.service.CreateInvitation(ctx, inv)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	backPath := authPath + "/" + listOrgInvitationsPath + "?id=" + orgID.String()
+	if teamID != uuid.Nil {
+		backPath = authPath + "/" + listTeamInvitationsPath + "?id=" + teamID.String()
+	}
+
+	page := am.NewPage(r, struct {
+		Email    string
+		Link     string
+		BackPath string
+	}{
+		Email:    email,
+		Link:     authPath + "/" + acceptInvitationPath + "?token=" + token,
+		BackPath: backPath,
+	})
+
+	h.renderPage(w, "show-invitation-link", page)
+}
+
+func (h *WebHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Revoke invitation", "id", id)
+
+	err = h.service.RevokeInvitation(ctx, id)
+	if err != nil {
+		h.invitationErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	path := authPath + "/" + listOrgInvitationsPath + "?id=" + r.FormValue("org_id")
+	if teamID := r.FormValue("team_id"); teamID != "" {
+		path = authPath + "/" + listTeamInvitationsPath + "?id=" + teamID
+	}
+	h.Redir(w, r, path)
+}
+
+// ShowInvitation is the page the invitee lands on from the invitation link.
+func (h *WebHandler) ShowInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	token := r.URL.Query().Get("token")
+
+	inv, err := h.service.GetInvitationByToken(ctx, token)
+	if err != nil {
+		h.invitationErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	now := time.Now()
+	if inv.IsExpired(now) {
+		h.invitationErr(w, ErrInvitationExpired, am.ErrResourceNotFound)
+		return
+	}
+
+	if !inv.IsPending(now) {
+		h.invitationErr(w, ErrInvitationClosed, am.ErrResourceNotFound)
+		return
+	}
+
+	org, err := h.service.GetOrg(ctx, inv.OrgID)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	var team Team
+	if inv.TeamID != uuid.Nil {
+		team, err = h.service.GetTeam(ctx, inv.TeamID)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+	}
+
+	var role Role
+	if inv.RoleID != uuid.Nil {
+		role, err = h.service.GetRole(ctx, inv.RoleID)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+	}
+
+	page := am.NewPage(r, struct {
+		Token      string
+		Invitation Invitation
+		OrgName    string
+		TeamName   string
+		RoleName   string
+	}{
+		Token:      token,
+		Invitation: inv,
+		OrgName:    org.Name,
+		TeamName:   team.Name,
+		RoleName:   role.Name,
+	})
+
+	h.renderPage(w, "accept-invitation", page)
+}
+
+// AcceptInvitation links the invitee, creating the user from the form if the email is not registered yet.
+func (h *WebHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	user := NewUser(strings.TrimSpace(r.Form.Get("username")), strings.TrimSpace(r.Form.Get("name")))
+	user.Password = r.Form.Get("password")
+
+	user, err := h.service.AcceptInvitation(ctx, r.Form.Get("token"), user)
+	if err != nil {
+		h.invitationErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/show-user?id="+user.ID().String())
+}
+
+func (h *WebHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	err := h.service.DeclineInvitation(ctx, r.FormValue("token"))
+	if err != nil {
+		h.invitationErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	h.Redir(w, r, "/")
+}
+
+// invitationErr maps invitation errors to the matching status code.
+func (h *WebHandler) invitationErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvitationNotFound):
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrInvitationClosed), errors.Is(err, ErrInvitationExpired):
+		h.Err(w, err, err.Error(), http.StatusGone)
+	default:
+		h.Err(w, err, msg, http.StatusInternalServerError)
+	}
+}
+
+// optionalUUID parses a form value that may be left empty.
+func optionalUUID(value string) (uuid.UUID, error) {
+	if strings.TrimSpace(value) == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(value)
+}
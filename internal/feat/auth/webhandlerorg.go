@@ -1,72 +1,286 @@
 package auth
 
 import (
-	"bytes"
+	"context"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/aquamarinepk/todo/internal/am"
 	"github.com/google/uuid"
 )
 
 // Org handlers
+func (h *WebHandler) ListOrgs(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List orgs")
+	ctx := r.Context()
+
+	orgs, err := h.service.GetAllOrgs(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	currentID, _ := am.OrgFromContext(ctx)
+
+	page := am.NewPage(r, struct {
+		Orgs      []Org
+		CurrentID uuid.UUID
+	}{
+		Orgs:      orgs,
+		CurrentID: currentID,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddNewItem(orgPath)
+	menu.AddTrashItem(orgPath)
+
+	h.renderPage(w, "list-orgs", page)
+}
+
+func (h *WebHandler) NewOrg(w http.ResponseWriter, r *http.Request) {
+	org := NewOrg("", "", "", uuid.Nil)
+
+	page := am.NewPage(r, org)
+	page.SetFormAction(am.CreatePath(authPath, orgPath))
+	page.SetFormButtonText("Create")
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(org)
+
+	h.renderPage(w, "new-org", page)
+}
+
+func (h *WebHandler) CreateOrg(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	actorID, _ := am.ActorFromContext(ctx)
+
+	org := NewOrg(r.Form.Get("name"), r.Form.Get("short_description"), r.Form.Get("description"), actorID)
+	if strings.TrimSpace(org.Name) == "" {
+		h.Err(w, nil, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.CreateOrg(ctx, org)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/"+listOrgsPath)
+}
+
+// ShowOrg shows the requested org, or the current one when no id is given.
 func (h *WebHandler) ShowOrg(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("Show org")
 	ctx := r.Context()
 
-	org, err := h.service.GetDefaultOrg(ctx)
+	var org Org
+	var err error
+	if r.URL.Query().Get("id") != "" {
+		id, err := h.ID(w, r)
+		if err != nil {
+			return
+		}
+		org, err = h.service.GetOrg(ctx, id)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+	} else {
+		org, err = h.currentOrg(ctx)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+	}
+
+	owners, err := h.service.GetOrgOwners(ctx, org.ID())
 	if err != nil {
-		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
 		return
 	}
 
-	owners, err := h.service.GetOrgOwners(ctx, org.ID())
+	members, err := h.service.GetOrgMembers(ctx, org.ID())
 	if err != nil {
 		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
 		return
 	}
 
 	page := am.NewPage(r, struct {
-		Org    Org
-		Owners []User
+		Org     Org
+		Owners  []User
+		Members []User
 	}{
-		Org:    org,
-		Owners: owners,
+		Org:     org,
+		Owners:  owners,
+		Members: members,
 	})
 
 	menu := page.NewMenu(authPath)
-	menu.AddGenericItem("list-org-owners", org.ID().String(), "Owners")
+	menu.AddListItem(org)
+	menu.AddEditItem(org)
+	menu.AddGenericItem(ActionListOrgOwners, org.ID().String(), TextOwners)
+	menu.AddGenericItem(ActionListOrgMembers, org.ID().String(), TextMembers)
+	menu.AddGenericItem(ActionListOrgInvitations, org.ID().String(), TextInvitations)
+
+	h.renderPage(w, "show-org", page)
+}
+
+func (h *WebHandler) EditOrg(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+	ctx := r.Context()
 
-	tmpl, err := h.tm.Get("auth", "show-org")
+	org, err := h.service.GetOrg(ctx, id)
 	if err != nil {
-		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	page := am.NewPage(r, org)
+	page.SetFormAction(am.UpdatePath(authPath, orgPath))
+	page.SetFormButtonText("Update")
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(org)
+
+	h.renderPage(w, "edit-org", page)
+}
+
+func (h *WebHandler) UpdateOrg(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
 		return
 	}
 
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, page)
+	id, err := uuid.Parse(r.Form.Get("id"))
 	if err != nil {
-		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
-	_, err = buf.WriteTo(w)
+	h.Log().Info("Update org ", id)
+	ctx := r.Context()
+
+	org, err := h.service.GetOrg(ctx, id)
 	if err != nil {
-		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	org.Name = r.Form.Get("name")
+	org.ShortDescription = r.Form.Get("short_description")
+	org.Description = r.Form.Get("description")
+
+	if version, ok := am.FormVersion(r); ok {
+		org.SetVersion(version)
 	}
+
+	err = h.service.UpdateOrg(ctx, org)
+	if err != nil {
+		if am.IsConflict(err) {
+			h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+			return
+		}
+		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/show-org?id="+org.ID().String())
+}
+
+func (h *WebHandler) DeleteOrg(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Delete org ", id)
+	ctx := r.Context()
+
+	err = h.service.DeleteOrg(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/"+listOrgsPath)
+}
+
+// SwitchOrg remembers the org selected in the layout switcher and goes back to the page it was used from.
+// Only the orgs the actor belongs to can be selected.
+func (h *WebHandler) SwitchOrg(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(r.FormValue("org_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	orgs, err := availableOrgs(ctx, h.service)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	member := false
+	for _, org := range orgs {
+		if org.ID() == orgID {
+			member = true
+			break
+		}
+	}
+	if !member {
+		http.Error(w, "Organization not available", http.StatusForbidden)
+		return
+	}
+
+	am.SetOrgCookie(w, orgID)
+	h.Redir(w, r, localPath(r.FormValue("return"), authPath+"/"+listOrgsPath))
+}
+
+// localPath returns ret when it is a path of this app, def otherwise.
+// Anything a browser could read as another host, e.g. "//evil.com" or "/\evil.com", is rejected.
+func localPath(ret, def string) string {
+	if !strings.HasPrefix(ret, "/") || strings.HasPrefix(ret, "//") || strings.Contains(ret, "\\") {
+		return def
+	}
+	u, err := url.Parse(ret)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return def
+	}
+	return ret
+}
+
+// currentOrg returns the org selected for the request.
+// It falls back to the default org when no org middleware is in place.
+func (h *WebHandler) currentOrg(ctx context.Context) (Org, error) {
+	if orgID, ok := am.OrgFromContext(ctx); ok {
+		return h.service.GetOrg(ctx, orgID)
+	}
+	return h.service.GetDefaultOrg(ctx)
 }
 
 // Organization relationships
 func (h *WebHandler) ListOrgOwners(w http.ResponseWriter, r *http.Request) {
 	id, err := h.ID(w, r)
 	if err != nil {
-		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
 		return
 	}
 
 	h.Log().Info("List org owners", "id", id)
 	ctx := r.Context()
 
-	org, err := h.service.GetDefaultOrg(ctx)
+	org, err := h.service.GetOrg(ctx, id)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
@@ -97,23 +311,7 @@ func (h *WebHandler) ListOrgOwners(w http.ResponseWriter, r *http.Request) {
 	menu := page.NewMenu(authPath)
 	menu.AddShowItem(org, "Back")
 
-	tmpl, err := h.tm.Get("auth", "list-org-owners")
-	if err != nil {
-		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
-		return
-	}
-
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, page)
-	if err != nil {
-		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
-		return
-	}
-
-	_, err = buf.WriteTo(w)
-	if err != nil {
-		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
-	}
+	h.renderPage(w, "list-org-owners", page)
 }
 
 func (h *WebHandler) AddOrgOwner(w http.ResponseWriter, r *http.Request) {
@@ -139,7 +337,7 @@ func (h *WebHandler) AddOrgOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	path := am.ListPath(authPath, listOrgOwnersPath) + "?id=" + orgID.String()
+	path := authPath + "/" + listOrgOwnersPath + "?id=" + orgID.String()
 	http.Redirect(w, r, path, http.StatusSeeOther)
 }
 
@@ -166,6 +364,210 @@ func (h *WebHandler) RemoveOrgOwner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	path := am.ListPath(authPath, listOrgOwnersPath) + "?id=" + orgID.String()
+	path := authPath + "/" + listOrgOwnersPath + "?id=" + orgID.String()
 	http.Redirect(w, r, path, http.StatusSeeOther)
 }
+
+func (h *WebHandler) ListOrgMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("List org members", "id", id)
+	ctx := r.Context()
+
+	org, err := h.service.GetOrg(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	members, err := h.service.GetOrgMembers(ctx, org.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	nonMembers, err := h.service.GetOrgNonMembers(ctx, org.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Org        Org
+		Members    []User
+		NonMembers []User
+	}{
+		Org:        org,
+		Members:    members,
+		NonMembers: nonMembers,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddShowItem(org, "Back")
+
+	h.renderPage(w, "list-org-members", page)
+}
+
+func (h *WebHandler) AddUserToOrg(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(r.FormValue("org_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(r.FormValue("user_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.AddUserToOrg(ctx, orgID, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/"+listOrgMembersPath+"?id="+orgID.String())
+}
+
+func (h *WebHandler) RemoveUserFromOrg(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := uuid.Parse(r.FormValue("org_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(r.FormValue("user_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.RemoveUserFromOrg(ctx, orgID, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/"+listOrgMembersPath+"?id="+orgID.String())
+}
+
+// ListUserOrgRoles lists the contextual roles a member has in an org.
+func (h *WebHandler) ListUserOrgRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, err := h.ParseUUIDFromQuery(r, "org_id")
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := h.ParseUUIDFromQuery(r, "user_id")
+	if err != nil {
+		h.Err(w, err, ErrInvalidUserID, http.StatusBadRequest)
+		return
+	}
+
+	org, err := h.service.GetOrg(ctx, orgID)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	user, err := h.service.GetUser(ctx, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	assignedRoles, err := h.service.GetUserContextualRoles(ctx, ContextTypeOrg, orgID, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	unassignedRoles, err := h.service.GetUserContextualUnassignedRoles(ctx, ContextTypeOrg, orgID, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Org             Org
+		User            User
+		AssignedRoles   []Role
+		UnassignedRoles []Role
+	}{
+		Org:             org,
+		User:            user,
+		AssignedRoles:   assignedRoles,
+		UnassignedRoles: unassignedRoles,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddGenericItem(ActionListOrgMembers, org.ID().String(), "Back")
+
+	h.renderPage(w, "list-user-org-roles", page)
+}
+
+func (h *WebHandler) AddOrgRole(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Add org role to user")
+	ctx := r.Context()
+
+	orgID, userID, roleID, err := parseOrgRoleForm(r)
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.AddContextualRole(ctx, userID, roleID, ContextTypeOrg, orgID.String())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, userOrgRolesPath(orgID, userID))
+}
+
+func (h *WebHandler) RemoveOrgRole(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Remove org role from user")
+	ctx := r.Context()
+
+	orgID, userID, roleID, err := parseOrgRoleForm(r)
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.RemoveContextualRole(ctx, userID, roleID, ContextTypeOrg, orgID.String())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, userOrgRolesPath(orgID, userID))
+}
+
+func parseOrgRoleForm(r *http.Request) (orgID, userID, roleID uuid.UUID, err error) {
+	orgID, err = uuid.Parse(r.FormValue("org_id"))
+	if err != nil {
+		return
+	}
+	userID, err = uuid.Parse(r.FormValue("user_id"))
+	if err != nil {
+		return
+	}
+	roleID, err = uuid.Parse(r.FormValue("role_id"))
+	return
+}
+
+func userOrgRolesPath(orgID, userID uuid.UUID) string {
+	return authPath + "/" + listUserOrgRolesPath + "?org_id=" + orgID.String() + "&user_id=" + userID.String()
+}
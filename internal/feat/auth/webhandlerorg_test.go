@@ -0,0 +1,25 @@
+package auth
+
+import "testing"
+
+func TestLocalPath(t *testing.T) {
+	const def = "/auth/list-orgs"
+	tests := []struct {
+		ret  string
+		want string
+	}{
+		{"/res/todo?filter=owned", "/res/todo?filter=owned"},
+		{"", def},
+		{"res/todo", def},
+		{"//evil.com", def},
+		{"/\\evil.com", def},
+		{"/\\/evil.com", def},
+		{"https://evil.com", def},
+		{"/%", def},
+	}
+	for _, tt := range tests {
+		if got := localPath(tt.ret, def); got != tt.want {
+			t.Errorf("localPath(%q): expected %q, got %q", tt.ret, tt.want, got)
+		}
+	}
+}
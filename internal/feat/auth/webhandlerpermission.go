@@ -25,6 +25,7 @@ func (h *WebHandler) ListPermissions(w http.ResponseWriter, r *http.Request) {
 
 	menu := page.NewMenu(authPath)
 	menu.AddNewItem("permission")
+	menu.AddTrashItem("permission")
 
 	tmpl, err := h.tm.Get("auth", "list-permissions")
 	if err != nil {
@@ -83,7 +84,6 @@ func (h *WebHandler) CreatePermission(w http.ResponseWriter, r *http.Request) {
 	name := r.FormValue("name")
 	description := r.FormValue("description")
 	permission := NewPermission(name, description)
-	permission.GenCreateValues()
 
 	err := h.service.CreatePermission(ctx, permission)
 	if err != nil {
@@ -208,11 +208,20 @@ func (h *WebHandler) UpdatePermission(w http.ResponseWriter, r *http.Request) {
 		am.WithCreatedBy(permission.CreatedBy()),
 		am.WithUpdatedBy(uuid.New()),
 		am.WithCreatedAt(permission.CreatedAt()),
+		am.WithVersion(permission.Version()),
 		am.WithUpdatedAt(time.Now()),
 	)
 
+	if version, ok := am.FormVersion(r); ok {
+		permission.SetVersion(version)
+	}
+
 	err = h.service.UpdatePermission(ctx, permission)
 	if err != nil {
+		if am.IsConflict(err) {
+			h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+			return
+		}
 		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// policyFormPage is the data of the new and edit policy pages.
+type policyFormPage struct {
+	ID     uuid.UUID
+	Policy PolicyForm
+}
+
+// Policy handlers
+func (h *WebHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List policies")
+	ctx := r.Context()
+
+	policies, err := h.service.GetPolicies(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, policies)
+
+	menu := page.NewMenu(authPath)
+	menu.AddNewItem(policyPath)
+	menu.AddGenericItem(testPolicyPath, "", TextTest)
+
+	h.renderPage(w, "list-policies", page)
+}
+
+func (h *WebHandler) NewPolicy(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("New policy form")
+
+	page := am.NewPage(r, policyFormPage{
+		Policy: PolicyForm{Effect: EffectAllow, Conditions: "[]", Enabled: true},
+	})
+	page.SetFormAction(am.CreatePath(authPath, policyPath))
+	page.SetFormButtonText("Create")
+
+	menu := page.NewMenu(authPath)
+	menu.AddGenericItem(listPoliciesPath, "", "Back")
+
+	h.renderPage(w, "new-policy", page)
+}
+
+func (h *WebHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create policy")
+	ctx := r.Context()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	policy, err := FormToPolicy(NewPolicyForm(r.PostForm))
+	if err != nil {
+		h.Err(w, err, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.CreatePolicy(ctx, policy)
+	if err != nil {
+		h.policyErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, authPath+"/show-policy?id="+policy.ID().String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ShowPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("Show policy", "id", id)
+	ctx := r.Context()
+
+	policy, err := h.service.GetPolicy(ctx, id)
+	if err != nil {
+		h.policyErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	page := am.NewPage(r, policy)
+
+	menu := page.NewMenu(authPath)
+	menu.AddGenericItem(listPoliciesPath, "", "Back")
+	if policy.IsEditable() {
+		menu.AddEditItem(policy)
+		menu.AddDeleteItem(policy)
+	}
+
+	h.renderPage(w, "show-policy", page)
+}
+
+func (h *WebHandler) EditPolicy(w http.ResponseWriter, r *http.Request) {
+	id, err := h.ID(w, r)
+	if err != nil {
+		return
+	}
+
+	h.Log().Info("Edit policy", "id", id)
+	ctx := r.Context()
+
+	policy, err := h.service.GetPolicy(ctx, id)
+	if err != nil {
+		h.policyErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	if !policy.IsEditable() {
+		h.policyErr(w, ErrPolicyReadOnly, am.ErrCannotUpdateResource)
+		return
+	}
+
+	page := am.NewPage(r, policyFormPage{ID: policy.ID(), Policy: PolicyToForm(policy)})
+	page.SetFormAction(am.UpdatePath(authPath, policyPath))
+	page.SetFormButtonText("Update")
+
+	menu := page.NewMenu(authPath)
+	menu.AddShowItem(policy, "Back")
+
+	h.renderPage(w, "edit-policy", page)
+}
+
+func (h *WebHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Update policy")
+	ctx := r.Context()
+
+	err := r.ParseForm()
+	if err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	id, err := uuid.Parse(r.PostForm.Get("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.service.GetPolicy(ctx, id)
+	if err != nil {
+		h.policyErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	policy, err := FormToPolicy(NewPolicyForm(r.PostForm))
+	if err != nil {
+		h.Err(w, err, err.Error(), http.StatusBadRequest)
+		return
+	}
+	policy.BaseModel = existing.BaseModel
+
+	err = h.service.UpdatePolicy(ctx, policy)
+	if err != nil {
+		h.policyErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, authPath+"/show-policy?id="+id.String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Delete policy")
+	ctx := r.Context()
+
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeletePolicy(ctx, id)
+	if err != nil {
+		h.policyErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, authPath+"/"+listPoliciesPath, http.StatusSeeOther)
+}
+
+// TestPolicy simulates an access request and shows how RBAC and every policy contributed to the decision.
+// The form is shown on its own until a user is picked.
+func (h *WebHandler) TestPolicy(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Test policy")
+	ctx := r.Context()
+
+	form := NewPolicyTestForm(r.URL.Query())
+
+	users, err := h.service.GetUsers(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	var decision *Decision
+	if form.UserID != "" {
+		userID, req, err := FormToAccessRequest(form)
+		if err != nil {
+			h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+
+		d, err := h.service.SimulateAuthorize(ctx, userID, req)
+		if err != nil {
+			h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+		decision = &d
+	}
+
+	page := am.NewPage(r, struct {
+		Query    PolicyTestForm
+		Users    []User
+		Decision *Decision
+	}{
+		Query:    form,
+		Users:    users,
+		Decision: decision,
+	})
+	page.SetFormAction(authPath + "/" + testPolicyPath)
+
+	menu := page.NewMenu(authPath)
+	menu.AddGenericItem(listPoliciesPath, "", "Back")
+
+	h.renderPage(w, "test-policy", page)
+}
+
+func (h *WebHandler) policyErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrPolicyNotFound):
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrPolicyReadOnly):
+		h.Err(w, err, err.Error(), http.StatusForbidden)
+	default:
+		h.Err(w, err, msg, http.StatusInternalServerError)
+	}
+}
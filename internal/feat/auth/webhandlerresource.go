@@ -25,6 +25,7 @@ func (h *WebHandler) ListResources(w http.ResponseWriter, r *http.Request) {
 
 	menu := page.NewMenu(authPath)
 	menu.AddNewItem("resource")
+	menu.AddTrashItem("resource")
 
 	tmpl, err := h.tm.Get("auth", "list-resources")
 	if err != nil {
@@ -213,10 +214,19 @@ func (h *WebHandler) UpdateResource(w http.ResponseWriter, r *http.Request) {
 		am.WithCreatedBy(resource.CreatedBy()),
 		am.WithUpdatedBy(uuid.New()),
 		am.WithCreatedAt(resource.CreatedAt()),
+		am.WithVersion(resource.Version()),
 		am.WithUpdatedAt(time.Now()),
 	)
 
+	if version, ok := am.FormVersion(r); ok {
+		resource.SetVersion(version)
+	}
+
 	if err := h.service.UpdateResource(r.Context(), resource); err != nil {
+		if am.IsConflict(err) {
+			h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+			return
+		}
 		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
 		return
 	}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -25,6 +26,7 @@ func (h *WebHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
 
 	menu := page.NewMenu(authPath)
 	menu.AddNewItem(rolePath)
+	menu.AddTrashItem(rolePath)
 
 	tmpl, err := h.tm.Get("auth", "list-roles")
 	if err != nil {
@@ -93,7 +95,6 @@ func (h *WebHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 	}
 
 	role := NewRole(name, description, status)
-	role.GenCreateValues()
 
 	err := h.service.CreateRole(ctx, role)
 	if err != nil {
@@ -120,7 +121,35 @@ func (h *WebHandler) ShowRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page := am.NewPage(r, role)
+	parents, err := h.service.GetRoleParents(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	candidates, err := h.service.GetRoleParentCandidates(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	inherited, err := h.service.GetRoleInheritedPermissions(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, struct {
+		Role
+		Parents              []Role
+		ParentCandidates     []Role
+		InheritedPermissions []InheritedPermission
+	}{
+		Role:                 role,
+		Parents:              parents,
+		ParentCandidates:     candidates,
+		InheritedPermissions: inherited,
+	})
 
 	menu := page.NewMenu(authPath)
 	menu.AddListItem(role)
@@ -213,8 +242,16 @@ func (h *WebHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 	role.Name = r.Form.Get("name")
 	role.Description = r.Form.Get("description")
 
+	if version, ok := am.FormVersion(r); ok {
+		role.SetVersion(version)
+	}
+
 	err = h.service.UpdateRole(ctx, role)
 	if err != nil {
+		if am.IsConflict(err) {
+			h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+			return
+		}
 		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
 		return
 	}
@@ -374,6 +411,62 @@ func (h *WebHandler) RemovePermissionFromRole(w http.ResponseWriter, r *http.Req
 	http.Redirect(w, r, path, http.StatusSeeOther)
 }
 
+func (h *WebHandler) AddRoleParent(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Add parent to role")
+	ctx := r.Context()
+
+	roleID, parentID, err := parseRoleParentForm(r)
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.AddRoleParent(ctx, roleID, parentID)
+	if err != nil {
+		if errors.Is(err, ErrRoleCycle) {
+			h.Err(w, err, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/show-role?id="+roleID.String())
+}
+
+func (h *WebHandler) RemoveRoleParent(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Remove parent from role")
+	ctx := r.Context()
+
+	roleID, parentID, err := parseRoleParentForm(r)
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.RemoveRoleParent(ctx, roleID, parentID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/show-role?id="+roleID.String())
+}
+
+func parseRoleParentForm(r *http.Request) (roleID, parentID uuid.UUID, err error) {
+	roleID, err = uuid.Parse(r.FormValue("role_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	parentID, err = uuid.Parse(r.FormValue("parent_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return roleID, parentID, nil
+}
+
 // Handler methods for contextual roles
 func (h *WebHandler) ListUserContextualRoles(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -398,19 +491,25 @@ func (h *WebHandler) ListUserContextualRoles(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	team, err := h.service.GetTeam(ctx, teamID)
+	team, err := h.orgTeam(ctx, teamID)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
 	}
 
-	assignedRoles, err := h.service.GetUserContextualRoles(ctx, teamID, userID)
+	assignedRoles, err := h.service.GetUserContextualRoles(ctx, ContextTypeTeam, teamID, userID)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	unassignedRoles, err := h.service.GetUserContextualUnassignedRoles(ctx, ContextTypeTeam, teamID, userID)
 	if err != nil {
 		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
 		return
 	}
 
-	unassignedRoles, err := h.service.GetUserContextualUnassignedRoles(ctx, teamID, userID)
+	inheritedRoles, err := h.service.GetUserInheritedTeamRoles(ctx, teamID, userID)
 	if err != nil {
 		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
 		return
@@ -420,11 +519,13 @@ func (h *WebHandler) ListUserContextualRoles(w http.ResponseWriter, r *http.Requ
 		User            User
 		Team            Team
 		AssignedRoles   []Role
+		InheritedRoles  []InheritedRole
 		UnassignedRoles []Role
 	}{
 		User:            user,
 		Team:            team,
 		AssignedRoles:   assignedRoles,
+		InheritedRoles:  inheritedRoles,
 		UnassignedRoles: unassignedRoles,
 	})
 
@@ -475,7 +576,7 @@ func (h *WebHandler) AddContextualRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.AddContextualRole(ctx, userID, roleID, "team", teamID.String())
+	err = h.service.AddContextualRole(ctx, userID, roleID, ContextTypeTeam, teamID.String())
 	if err != nil {
 		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
 		return
@@ -508,7 +609,7 @@ func (h *WebHandler) RemoveContextualRole(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = h.service.RemoveContextualRole(ctx, userID, roleID, "team", teamID.String())
+	err = h.service.RemoveContextualRole(ctx, userID, roleID, ContextTypeTeam, teamID.String())
 	if err != nil {
 		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
 		return
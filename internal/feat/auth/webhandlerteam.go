@@ -2,6 +2,8 @@ package auth
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -13,7 +15,7 @@ import (
 // Team handlers
 func (h *WebHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	org, err := h.service.GetDefaultOrg(ctx)
+	org, err := h.currentOrg(ctx)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
@@ -35,6 +37,7 @@ func (h *WebHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
 
 	menu := page.NewMenu(authPath)
 	menu.AddNewItem("team")
+	menu.AddTrashItem("team")
 
 	tmpl, err := h.tm.Get("auth", "list-teams")
 	if err != nil {
@@ -57,14 +60,20 @@ func (h *WebHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
 
 func (h *WebHandler) NewTeam(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	org, err := h.service.GetDefaultOrg(ctx)
+	org, err := h.currentOrg(ctx)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
 	}
 
+	parents, err := h.service.GetAllTeams(ctx, org.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
 	team := NewTeam(org.ID(), "", "", "")
-	page := am.NewPage(r, team)
+	page := am.NewPage(r, teamPage{Team: team, Parents: parents})
 	page.SetFormAction(am.CreatePath(authPath, teamPath))
 	page.SetFormButtonText("Create")
 
@@ -97,7 +106,7 @@ func (h *WebHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	org, err := h.service.GetDefaultOrg(ctx)
+	org, err := h.currentOrg(ctx)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
@@ -107,7 +116,14 @@ func (h *WebHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	shortDescription := r.Form.Get("short_description")
 	description := r.Form.Get("description")
 
+	parentID, err := optionalUUID(r.Form.Get("parent_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
 	team := NewTeam(org.ID(), name, shortDescription, description)
+	team.ParentID = parentID
 	team.BaseModel = am.NewModel(
 		am.WithID(team.ID()),
 		am.WithCreatedBy(team.CreatedBy()),
@@ -118,7 +134,7 @@ func (h *WebHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.CreateTeam(ctx, team)
 	if err != nil {
-		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		h.teamErr(w, err, am.ErrCannotCreateResource)
 		return
 	}
 
@@ -135,18 +151,31 @@ func (h *WebHandler) ShowTeam(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// You will need to implement GetTeam in the service/repo layer
-	team, err := h.service.GetTeam(ctx, id)
+	team, err := h.orgTeam(ctx, id)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
 	}
 
-	page := am.NewPage(r, team)
+	ancestors, err := h.service.GetTeamAncestors(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	tree, err := h.service.GetTeamTree(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, teamPage{Team: team, Ancestors: ancestors, Tree: tree})
 
 	menu := page.NewMenu(authPath)
 	menu.AddListItem(team)
 	menu.AddEditItem(team)
 	menu.AddGenericItem(ActionListTeamMembers, team.ID().String(), TextMembers)
+	menu.AddGenericItem(ActionListTeamInvitations, team.ID().String(), TextInvitations)
 
 	tmpl, err := h.tm.Get("auth", "show-team")
 	if err != nil {
@@ -175,13 +204,19 @@ func (h *WebHandler) EditTeam(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx := r.Context()
 
-	team, err := h.service.GetTeam(ctx, id)
+	team, err := h.orgTeam(ctx, id)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
 	}
 
-	page := am.NewPage(r, team)
+	parents, err := h.parentOptions(ctx, team)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, teamPage{Team: team, Parents: parents})
 	page.SetFormAction(am.UpdatePath(authPath, teamPath))
 	page.SetFormButtonText("Update")
 
@@ -223,12 +258,19 @@ func (h *WebHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("Update team ", id)
 	ctx := r.Context()
 
-	team, err := h.service.GetTeam(ctx, id)
+	team, err := h.orgTeam(ctx, id)
 	if err != nil {
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
 		return
 	}
 
+	parentID, err := optionalUUID(r.Form.Get("parent_id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	team.ParentID = parentID
 	team.Name = r.Form.Get("name")
 	team.ShortDescription = r.Form.Get("short_description")
 	team.Description = r.Form.Get("description")
@@ -237,12 +279,17 @@ func (h *WebHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
 		am.WithCreatedBy(team.CreatedBy()),
 		am.WithUpdatedBy(uuid.New()),
 		am.WithCreatedAt(team.CreatedAt()),
+		am.WithVersion(team.Version()),
 		am.WithUpdatedAt(time.Now()),
 	)
 
+	if version, ok := am.FormVersion(r); ok {
+		team.SetVersion(version)
+	}
+
 	err = h.service.UpdateTeam(ctx, team)
 	if err != nil {
-		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		h.teamErr(w, err, am.ErrCannotUpdateResource)
 		return
 	}
 
@@ -259,6 +306,11 @@ func (h *WebHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	if _, err := h.orgTeam(ctx, id); err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
 	if err := h.service.DeleteTeam(ctx, id); err != nil {
 		h.Err(w, err, am.ErrCannotDeleteResource, http.StatusInternalServerError)
 		return
@@ -277,7 +329,7 @@ func (h *WebHandler) ListTeamMembers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	team, err := h.service.GetTeam(ctx, id)
+	team, err := h.orgTeam(ctx, id)
 	if err != nil {
 		h.Log().Error("Failed to get team", "id", id, "error", err)
 		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
@@ -300,7 +352,7 @@ func (h *WebHandler) ListTeamMembers(w http.ResponseWriter, r *http.Request) {
 
 	page := am.NewPage(r, struct {
 		Team       Team
-		Members    []User
+		Members    []TeamMember
 		Unassigned []User
 	}{
 		Team:       team,
@@ -347,8 +399,7 @@ func (h *WebHandler) AssignUserToTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// For now we'll use "member" as the default relation type
-	err = h.service.AddUserToTeam(ctx, teamID, userID, "member")
+	err = h.service.AddUserToTeam(ctx, teamID, userID, RelationDirect)
 	if err != nil {
 		h.Log().Error("Failed to assign user to team", "team_id", teamID, "user_id", userID, "error", err)
 		h.Err(w, err, am.ErrCannotCreateResource, http.StatusInternalServerError)
@@ -398,3 +449,73 @@ func (h *WebHandler) RemoveUserFromTeam(w http.ResponseWriter, r *http.Request)
 	// Redirect back to team members page
 	http.Redirect(w, r, fmt.Sprintf("/auth/list-team-members?id=%s", teamID), http.StatusSeeOther)
 }
+
+// orgTeam returns the team only if it belongs to the current org.
+func (h *WebHandler) orgTeam(ctx context.Context, id uuid.UUID) (Team, error) {
+	team, err := h.service.GetTeam(ctx, id)
+	if err != nil {
+		return Team{}, err
+	}
+
+	org, err := h.currentOrg(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+
+	if team.OrgID != org.ID() {
+		return Team{}, ErrNotInOrg
+	}
+
+	return team, nil
+}
+
+// teamPage is the data of the team pages, the team along with its place in the hierarchy.
+type teamPage struct {
+	Team
+	Parents   []Team
+	Ancestors []Team
+	Tree      TeamNode
+}
+
+// parentOptions returns the teams the given team can be moved under, that is, all but itself and its descendants.
+func (h *WebHandler) parentOptions(ctx context.Context, team Team) ([]Team, error) {
+	teams, err := h.service.GetAllTeams(ctx, team.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := h.service.GetTeamTree(ctx, team.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]bool)
+	var exclude func(node TeamNode)
+	exclude = func(node TeamNode) {
+		excluded[node.ID()] = true
+		for _, child := range node.Children {
+			exclude(child)
+		}
+	}
+	exclude(tree)
+
+	var options []Team
+	for _, t := range teams {
+		if !excluded[t.ID()] {
+			options = append(options, t)
+		}
+	}
+	return options, nil
+}
+
+// teamErr maps team errors to the matching status code.
+func (h *WebHandler) teamErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case am.IsConflict(err):
+		h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+	case errors.Is(err, ErrTeamCycle), errors.Is(err, ErrNotInOrg):
+		h.Err(w, err, err.Error(), http.StatusBadRequest)
+	default:
+		h.Err(w, err, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,341 @@
+package auth
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// TrashItem is the view of a soft deleted entity in the trash lists.
+type TrashItem struct {
+	ID        uuid.UUID
+	Name      string
+	DeletedBy uuid.UUID
+	DeletedAt time.Time
+}
+
+// Trash is the data rendered by the list-deleted-items template.
+type Trash struct {
+	Title         string
+	RestoreAction string
+	Items         []TrashItem
+}
+
+type trashable interface {
+	ID() uuid.UUID
+	am.Deletable
+}
+
+func newTrashItem(model trashable, name string) TrashItem {
+	return TrashItem{
+		ID:        model.ID(),
+		Name:      name,
+		DeletedBy: model.DeletedBy(),
+		DeletedAt: model.DeletedAt(),
+	}
+}
+
+func (h *WebHandler) ListDeletedUsers(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted users")
+	ctx := r.Context()
+
+	users, err := h.service.GetDeletedUsers(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(users))
+	for i, user := range users {
+		items[i] = newTrashItem(user, user.Username)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Users",
+		RestoreAction: "restore-user",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewUser("", ""))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore user ", id)
+	ctx := r.Context()
+
+	err = h.service.RestoreUser(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-users")
+}
+
+func (h *WebHandler) ListDeletedRoles(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted roles")
+	ctx := r.Context()
+
+	roles, err := h.service.GetDeletedRoles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(roles))
+	for i, role := range roles {
+		items[i] = newTrashItem(role, role.Name)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Roles",
+		RestoreAction: "restore-role",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewRole("", "", ""))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestoreRole(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore role ", id)
+	ctx := r.Context()
+
+	err = h.service.RestoreRole(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-roles")
+}
+
+func (h *WebHandler) ListDeletedPermissions(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted permissions")
+	ctx := r.Context()
+
+	permissions, err := h.service.GetDeletedPermissions(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(permissions))
+	for i, permission := range permissions {
+		items[i] = newTrashItem(permission, permission.Name)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Permissions",
+		RestoreAction: "restore-permission",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewPermission("", ""))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestorePermission(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore permission ", id)
+	ctx := r.Context()
+
+	err = h.service.RestorePermission(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-permissions")
+}
+
+func (h *WebHandler) ListDeletedResources(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted resources")
+	ctx := r.Context()
+
+	resources, err := h.service.GetDeletedResources(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(resources))
+	for i, resource := range resources {
+		items[i] = newTrashItem(resource, resource.Name)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Resources",
+		RestoreAction: "restore-resource",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewResource("", "", ""))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestoreResource(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore resource ", id)
+	ctx := r.Context()
+
+	err = h.service.RestoreResource(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-resources")
+}
+
+func (h *WebHandler) ListDeletedOrgs(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted orgs")
+	ctx := r.Context()
+
+	orgs, err := h.service.GetDeletedOrgs(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(orgs))
+	for i, org := range orgs {
+		items[i] = newTrashItem(org, org.Name)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Orgs",
+		RestoreAction: "restore-org",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewOrg("", "", "", uuid.Nil))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestoreOrg(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore org ", id)
+	ctx := r.Context()
+
+	err = h.service.RestoreOrg(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-orgs")
+}
+
+func (h *WebHandler) ListDeletedTeams(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted teams")
+	ctx := r.Context()
+
+	org, err := h.currentOrg(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrResourceNotFound, http.StatusNotFound)
+		return
+	}
+
+	teams, err := h.service.GetDeletedTeams(ctx, org.ID())
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]TrashItem, len(teams))
+	for i, team := range teams {
+		items[i] = newTrashItem(team, team.Name)
+	}
+
+	page := am.NewPage(r, Trash{
+		Title:         "Deleted Teams",
+		RestoreAction: "restore-team",
+		Items:         items,
+	})
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewTeam(uuid.Nil, "", "", ""))
+
+	h.renderTrash(w, page)
+}
+
+func (h *WebHandler) RestoreTeam(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.FormValue("id"))
+	if err != nil {
+		h.Err(w, err, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.Log().Info("Restore team ", id)
+	ctx := r.Context()
+
+	err = h.service.RestoreTeam(ctx, id)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRestoreResource, http.StatusInternalServerError)
+		return
+	}
+
+	h.Redir(w, r, authPath+"/list-deleted-teams")
+}
+
+func (h *WebHandler) renderTrash(w http.ResponseWriter, page *am.Page) {
+	tmpl, err := h.tm.Get("auth", "list-deleted-items")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
@@ -2,12 +2,19 @@ package auth
 
 import (
 	"bytes"
+	"errors"
 	"net/http"
 
 	"github.com/aquamarinepk/todo/internal/am"
 	"github.com/google/uuid"
 )
 
+// userListPage is the data of the user list, roles are the ones the bulk commands can add or remove.
+type userListPage struct {
+	Users []User
+	Roles []Role
+}
+
 func (h *WebHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("List of users")
 	ctx := r.Context()
@@ -18,11 +25,18 @@ func (h *WebHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	page := am.NewPage(r, users)
+	roles, err := h.service.GetAllRoles(ctx)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, userListPage{Users: users, Roles: roles})
 	page.SetFormAction(authPath)
 
 	menu := page.NewMenu(authPath)
 	menu.AddNewItem(userType)
+	menu.AddTrashItem(userType)
 
 	tmpl, err := h.tm.Get("auth", "list-users")
 	if err != nil {
@@ -244,8 +258,16 @@ func (h *WebHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		user.PasswordEnc = passwordEnc
 	}
 
+	if version, ok := am.FormVersion(r); ok {
+		user.SetVersion(version)
+	}
+
 	err = h.service.UpdateUser(ctx, user)
 	if err != nil {
+		if am.IsConflict(err) {
+			h.Err(w, err, am.ErrVersionConflict, http.StatusConflict)
+			return
+		}
 		h.Err(w, err, am.ErrCannotUpdateResource, http.StatusInternalServerError)
 		return
 	}
@@ -275,6 +297,69 @@ func (h *WebHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	h.Redir(w, r, path)
 }
 
+// BulkUsers runs the bulk command of the form on the selected users and shows the result of each one.
+func (h *WebHandler) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	cmd := UserBulkCommand{Command: r.FormValue("command")}
+	for _, v := range r.Form["user_ids"] {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			h.Err(w, err, ErrInvalidUserID, http.StatusBadRequest)
+			return
+		}
+		cmd.UserIDs = append(cmd.UserIDs, id)
+	}
+	if v := r.FormValue("role_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			h.Err(w, err, ErrInvalidRoleID, http.StatusBadRequest)
+			return
+		}
+		cmd.RoleID = id
+	}
+
+	h.Log().Info("Bulk ", cmd.Command, " of ", len(cmd.UserIDs), " users")
+	batch, err := h.service.BulkUsers(r.Context(), cmd)
+	switch {
+	case errors.Is(err, ErrInvalidBulk):
+		h.Err(w, err, ErrInvalidFormData, http.StatusBadRequest)
+		return
+	case errors.Is(err, ErrRoleNotFound):
+		h.Err(w, err, ErrInvalidRoleID, http.StatusNotFound)
+		return
+	case err != nil:
+		h.Err(w, err, ErrCannotUpdateUser, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, batch)
+
+	menu := page.NewMenu(authPath)
+	menu.AddListItem(NewUser("", ""))
+
+	tmpl, err := h.tm.Get("auth", "bulk-users")
+	if err != nil {
+		h.Err(w, err, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		h.Err(w, err, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
+
 func (h *WebHandler) ListUserRoles(w http.ResponseWriter, r *http.Request) {
 	var err error
 	var userID uuid.UUID
@@ -386,6 +471,7 @@ func (h *WebHandler) ListUserPermissions(w http.ResponseWriter, r *http.Request)
 
 	menu := page.NewMenu(authPath)
 	menu.AddShowItem(user, "Back")
+	menu.AddGenericItem(ActionExplainPermission, user.ID().String(), TextExplain)
 
 	tmpl, err := h.tm.Get("auth", "list-user-permissions")
 	if err != nil {
@@ -16,11 +16,15 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 	core.Get("/edit-user", handler.EditUser)
 	core.Post("/update-user", handler.UpdateUser)
 	core.Post("/delete-user", handler.DeleteUser)
+	core.Get("/list-deleted-users", handler.ListDeletedUsers)
+	core.Post("/restore-user", handler.RestoreUser)
+	core.Post("/bulk-users", handler.BulkUsers)
 	// User relationships
 	core.Get("/list-user-roles", handler.ListUserRoles)
 	core.Get("/list-user-permissions", handler.ListUserPermissions)
 	core.Post("/add-role-to-user", handler.AddRoleToUser)
 	core.Post("/remove-role-from-user", handler.RemoveRoleFromUser)
+	core.Get("/explain-permission", handler.ExplainPermission)
 	core.Post("/add-permission-to-user", handler.AddPermissionToUser)
 	core.Post("/remove-permission-from-user", handler.RemovePermissionFromUser)
 	core.Get("/list-user-contextual-roles", handler.ListUserContextualRoles)
@@ -35,10 +39,14 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 	core.Get("/edit-role", handler.EditRole)
 	core.Post("/update-role", handler.UpdateRole)
 	core.Post("/delete-role", handler.DeleteRole)
+	core.Get("/list-deleted-roles", handler.ListDeletedRoles)
+	core.Post("/restore-role", handler.RestoreRole)
 	// Role relationships
 	core.Get("/list-role-permissions", handler.ListRolePermissions)
 	core.Post("/add-permission-to-role", handler.AddPermissionToRole)
 	core.Post("/remove-permission-from-role", handler.RemovePermissionFromRole)
+	core.Post("/add-role-parent", handler.AddRoleParent)
+	core.Post("/remove-role-parent", handler.RemoveRoleParent)
 
 	// Permission routes
 	core.Get("/list-permissions", handler.ListPermissions)
@@ -48,6 +56,8 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 	core.Get("/edit-permission", handler.EditPermission)
 	core.Post("/update-permission", handler.UpdatePermission)
 	core.Post("/delete-permission", handler.DeletePermission)
+	core.Get("/list-deleted-permissions", handler.ListDeletedPermissions)
+	core.Post("/restore-permission", handler.RestorePermission)
 
 	// Resource routes
 	core.Get("/list-resources", handler.ListResources)
@@ -57,16 +67,34 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 	core.Get("/edit-resource", handler.EditResource)
 	core.Post("/update-resource", handler.UpdateResource)
 	core.Post("/delete-resource", handler.DeleteResource)
+	core.Get("/list-deleted-resources", handler.ListDeletedResources)
+	core.Post("/restore-resource", handler.RestoreResource)
 	// Resource relationships
 	core.Get("/list-resource-permissions", handler.ListResourcePermissions)
 	core.Post("/add-permission-to-resource", handler.AddPermissionToResource)
 	core.Post("/remove-permission-from-resource", handler.RemovePermissionFromResource)
 
 	// Organization routes
+	core.Get("/list-orgs", handler.ListOrgs)
+	core.Get("/new-org", handler.NewOrg)
+	core.Post("/create-org", handler.CreateOrg)
 	core.Get("/show-org", handler.ShowOrg)
+	core.Get("/edit-org", handler.EditOrg)
+	core.Post("/update-org", handler.UpdateOrg)
+	core.Post("/delete-org", handler.DeleteOrg)
+	core.Get("/list-deleted-orgs", handler.ListDeletedOrgs)
+	core.Post("/restore-org", handler.RestoreOrg)
+	core.Post("/switch-org", handler.SwitchOrg)
+	// Organization relationships
 	core.Get("/list-org-owners", handler.ListOrgOwners)
 	core.Post("/add-org-owner", handler.AddOrgOwner)
 	core.Post("/remove-org-owner", handler.RemoveOrgOwner)
+	core.Get("/list-org-members", handler.ListOrgMembers)
+	core.Post("/add-user-to-org", handler.AddUserToOrg)
+	core.Post("/remove-user-from-org", handler.RemoveUserFromOrg)
+	core.Get("/list-user-org-roles", handler.ListUserOrgRoles)
+	core.Post("/add-org-role", handler.AddOrgRole)
+	core.Post("/remove-org-role", handler.RemoveOrgRole)
 
 	// Team routes
 	core.Get("/list-teams", handler.ListTeams)
@@ -76,10 +104,34 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 	core.Get("/edit-team", handler.EditTeam)
 	core.Post("/update-team", handler.UpdateTeam)
 	core.Post("/delete-team", handler.DeleteTeam)
+	core.Get("/list-deleted-teams", handler.ListDeletedTeams)
+	core.Post("/restore-team", handler.RestoreTeam)
 	// Team relationships
 	core.Get("/list-team-members", handler.ListTeamMembers)
 	core.Post("/assign-user-to-team", handler.AssignUserToTeam)
 	core.Post("/remove-user-from-team", handler.RemoveUserFromTeam)
 
+	// Invitation routes
+	core.Get("/list-team-invitations", handler.ListTeamInvitations)
+	core.Get("/list-org-invitations", handler.ListOrgInvitations)
+	core.Post("/create-invitation", handler.CreateInvitation)
+	core.Post("/revoke-invitation", handler.RevokeInvitation)
+	core.Get("/accept-invitation", handler.ShowInvitation)
+	core.Post("/accept-invitation", handler.AcceptInvitation)
+	core.Post("/decline-invitation", handler.DeclineInvitation)
+
+	// Policy routes
+	core.Get("/list-policies", handler.ListPolicies)
+	core.Get("/new-policy", handler.NewPolicy)
+	core.Post("/create-policy", handler.CreatePolicy)
+	core.Get("/show-policy", handler.ShowPolicy)
+	core.Get("/edit-policy", handler.EditPolicy)
+	core.Post("/update-policy", handler.UpdatePolicy)
+	core.Post("/delete-policy", handler.DeletePolicy)
+	core.Get("/test-policy", handler.TestPolicy)
+
+	// Audit routes
+	core.Get("/list-audit-entries", handler.ListAuditEntries)
+
 	return core
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
 	"github.com/aquamarinepk/todo/internal/feat/auth"
@@ -23,11 +24,16 @@ var (
 	resUserRole   = "user_role"
 	resUserPerm   = "user_permission"
 	resRolePerm   = "role_permission"
+	resRoleParent = "role_parent"
 	resResPerm    = "resource_permission"
 	resOrg        = "org"
 	resOrgOwner   = "org_owner"
+	resOrgMember  = "org_member"
 	resTeamMember = "team_member"
 	resTeam       = "team"
+	resAuditLog   = "audit_log"
+	resInvitation = "invitation"
+	resPolicy     = "policy"
 )
 
 type AuthRepo struct {
@@ -74,7 +80,8 @@ func (repo *AuthRepo) DB() *sqlx.DB {
 	return repo.db
 }
 
-// getExec returns the correct Execer (Tx or DB) from context.
+// getExec returns the transaction of the context, or the DB when there is none.
+// Reads go through it too: with a shared cache, tables written by an open transaction are locked for other connections.
 func (repo *AuthRepo) getExec(ctx context.Context) sqlx.ExtContext {
 	tx, ok := am.TxFromContext(ctx)
 	if ok {
@@ -101,7 +108,7 @@ func (repo *AuthRepo) GetUsers(ctx context.Context) ([]auth.User, error) {
 	}
 
 	var users []auth.UserDA
-	err = repo.db.SelectContext(ctx, &users, query)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &users, query)
 	if err != nil {
 		return nil, err
 	}
@@ -127,8 +134,11 @@ func (repo *AuthRepo) getUser(ctx context.Context, id uuid.UUID) (auth.User, err
 	}
 
 	var user auth.UserDA
-	err = repo.db.GetContext(ctx, &user, query, id)
+	err = sqlx.GetContext(ctx, repo.getExec(ctx), &user, query, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.User{}, auth.ErrUserNotFound
+		}
 		return auth.User{}, err
 	}
 
@@ -141,7 +151,7 @@ func (repo *AuthRepo) getUserPreload(ctx context.Context, id uuid.UUID) (auth.Us
 		return auth.User{}, err
 	}
 
-	rows, err := repo.db.QueryxContext(ctx, query, id)
+	rows, err := repo.getExec(ctx).QueryxContext(ctx, query, id)
 	if err != nil {
 		return auth.User{}, err
 	}
@@ -214,22 +224,55 @@ func (repo *AuthRepo) UpdateUser(ctx context.Context, user auth.User) error {
 
 	userDA := auth.ToUserDA(user)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, userDA.Username, userDA.EmailEnc, userDA.Name,
-		userDA.ShortID, userDA.UpdatedBy, userDA.UpdatedAt, userDA.ID)
-	return err
+	result, err := exec.ExecContext(ctx, query, userDA.Username, userDA.EmailEnc, userDA.Name,
+		userDA.ShortID, userDA.UpdatedBy, userDA.UpdatedAt, userDA.ID, userDA.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, user)
 }
 
-func (repo *AuthRepo) DeleteUser(ctx context.Context, id uuid.UUID) error {
+func (repo *AuthRepo) DeleteUser(ctx context.Context, user auth.User) error {
 	query, err := repo.Query().Get(featAuth, resUser, "Delete")
 	if err != nil {
 		return err
 	}
 
+	userDA := auth.ToUserDA(user)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, id)
+	_, err = exec.ExecContext(ctx, query, userDA.DeletedBy, userDA.DeletedAt, userDA.ID)
 	return err
 }
 
+func (repo *AuthRepo) GetDeletedUsers(ctx context.Context) ([]auth.User, error) {
+	query, err := repo.Query().Get(featAuth, resUser, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+
+	var users []auth.UserDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &users, query)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToUsers(users), nil
+}
+
+func (repo *AuthRepo) RestoreUser(ctx context.Context, user auth.User) error {
+	query, err := repo.Query().Get(featAuth, resUser, "Restore")
+	if err != nil {
+		return err
+	}
+
+	userDA := auth.ToUserDA(user)
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, userDA.UpdatedBy, userDA.UpdatedAt, userDA.ID)
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
 func (repo *AuthRepo) UpdatePassword(ctx context.Context, user auth.User) error {
 	query, err := repo.Query().Get(featAuth, resUser, "UpdatePassword")
 	if err != nil {
@@ -242,6 +285,19 @@ func (repo *AuthRepo) UpdatePassword(ctx context.Context, user auth.User) error
 	return err
 }
 
+// SetUserActive stores the active flag of the user.
+func (repo *AuthRepo) SetUserActive(ctx context.Context, user auth.User) error {
+	query, err := repo.Query().Get(featAuth, resUser, "SetActive")
+	if err != nil {
+		return err
+	}
+
+	userDA := auth.ToUserDA(user)
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, userDA.IsActive, userDA.UpdatedBy, userDA.UpdatedAt, userDA.ID)
+	return err
+}
+
 func (repo *AuthRepo) GetAllRoles(ctx context.Context) ([]auth.Role, error) {
 	query, err := repo.Query().Get(featAuth, resRole, "GetAll")
 	if err != nil {
@@ -249,7 +305,7 @@ func (repo *AuthRepo) GetAllRoles(ctx context.Context) ([]auth.Role, error) {
 	}
 
 	var rolesDA []auth.RoleDA
-	err = repo.db.SelectContext(ctx, &rolesDA, query)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +327,7 @@ func (repo *AuthRepo) getRole(ctx context.Context, id uuid.UUID) (auth.Role, err
 	}
 
 	var roleDA auth.RoleDA
-	err = repo.db.GetContext(ctx, &roleDA, query, id)
+	err = sqlx.GetContext(ctx, repo.getExec(ctx), &roleDA, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return auth.Role{}, errors.New("role not found")
@@ -287,7 +343,7 @@ func (repo *AuthRepo) getRolePreload(ctx context.Context, id uuid.UUID) (auth.Ro
 		return auth.Role{}, err
 	}
 
-	rows, err := repo.db.QueryxContext(ctx, query, id)
+	rows, err := repo.getExec(ctx).QueryxContext(ctx, query, id)
 	if err != nil {
 		return auth.Role{}, err
 	}
@@ -347,27 +403,61 @@ func (repo *AuthRepo) UpdateRole(ctx context.Context, role auth.Role) error {
 
 	roleDA := auth.ToRoleDA(role)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		roleDA.Name,
 		roleDA.Description,
 		roleDA.ShortID,
 		roleDA.UpdatedBy,
 		roleDA.UpdatedAt,
-		roleDA.ID)
-	return err
+		roleDA.ID,
+		roleDA.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, role)
 }
 
-func (repo *AuthRepo) DeleteRole(ctx context.Context, roleID uuid.UUID) error {
+func (repo *AuthRepo) DeleteRole(ctx context.Context, role auth.Role) error {
 	query, err := repo.Query().Get(featAuth, resRole, "Delete")
 	if err != nil {
 		return err
 	}
 
+	roleDA := auth.ToRoleDA(role)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, roleID)
+	_, err = exec.ExecContext(ctx, query, roleDA.DeletedBy, roleDA.DeletedAt, roleDA.ID)
 	return err
 }
 
+func (repo *AuthRepo) GetDeletedRoles(ctx context.Context) ([]auth.Role, error) {
+	query, err := repo.Query().Get(featAuth, resRole, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []auth.RoleDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &roles, query)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToRoles(roles), nil
+}
+
+func (repo *AuthRepo) RestoreRole(ctx context.Context, role auth.Role) error {
+	query, err := repo.Query().Get(featAuth, resRole, "Restore")
+	if err != nil {
+		return err
+	}
+
+	roleDA := auth.ToRoleDA(role)
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, roleDA.UpdatedBy, roleDA.UpdatedAt, roleDA.ID)
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
 func (repo *AuthRepo) GetAllPermissions(ctx context.Context) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resPerm, "GetAll")
 	if err != nil {
@@ -375,7 +465,7 @@ func (repo *AuthRepo) GetAllPermissions(ctx context.Context) ([]auth.Permission,
 	}
 
 	var permissionsDA []auth.PermissionDA
-	err = repo.db.SelectContext(ctx, &permissionsDA, query)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query)
 	if err != nil {
 		return nil, err
 	}
@@ -390,7 +480,7 @@ func (repo *AuthRepo) GetPermission(ctx context.Context, id uuid.UUID) (auth.Per
 	}
 
 	var permissionDA auth.PermissionDA
-	if err := repo.db.GetContext(ctx, &permissionDA, query, id); err != nil {
+	if err := sqlx.GetContext(ctx, repo.getExec(ctx), &permissionDA, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return auth.Permission{}, auth.ErrPermissionNotFound
 		}
@@ -429,27 +519,61 @@ func (repo *AuthRepo) UpdatePermission(ctx context.Context, permission auth.Perm
 
 	permissionDA := auth.ToPermissionDA(permission)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		permissionDA.ShortID,
 		permissionDA.Name,
 		permissionDA.Description,
 		permissionDA.UpdatedBy,
 		permissionDA.UpdatedAt,
-		permissionDA.ID)
-	return err
+		permissionDA.ID,
+		permissionDA.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, permission)
 }
 
-func (repo *AuthRepo) DeletePermission(ctx context.Context, id uuid.UUID) error {
+func (repo *AuthRepo) DeletePermission(ctx context.Context, permission auth.Permission) error {
 	query, err := repo.Query().Get(featAuth, resPerm, "Delete")
 	if err != nil {
 		return err
 	}
 
+	permissionDA := auth.ToPermissionDA(permission)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, id)
+	_, err = exec.ExecContext(ctx, query, permissionDA.DeletedBy, permissionDA.DeletedAt, permissionDA.ID)
 	return err
 }
 
+func (repo *AuthRepo) GetDeletedPermissions(ctx context.Context) ([]auth.Permission, error) {
+	query, err := repo.Query().Get(featAuth, resPerm, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []auth.PermissionDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissions, query)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToPermissions(permissions), nil
+}
+
+func (repo *AuthRepo) RestorePermission(ctx context.Context, permission auth.Permission) error {
+	query, err := repo.Query().Get(featAuth, resPerm, "Restore")
+	if err != nil {
+		return err
+	}
+
+	permissionDA := auth.ToPermissionDA(permission)
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, permissionDA.UpdatedBy, permissionDA.UpdatedAt, permissionDA.ID)
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
 func (repo *AuthRepo) GetAllResources(ctx context.Context) ([]auth.Resource, error) {
 	query, err := repo.Query().Get(featAuth, resRes, "GetAll")
 	if err != nil {
@@ -457,7 +581,7 @@ func (repo *AuthRepo) GetAllResources(ctx context.Context) ([]auth.Resource, err
 	}
 
 	var resourcesDA []auth.ResourceDA
-	err = repo.db.SelectContext(ctx, &resourcesDA, query)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &resourcesDA, query)
 	if err != nil {
 		return nil, err
 	}
@@ -479,7 +603,7 @@ func (repo *AuthRepo) getResource(ctx context.Context, id uuid.UUID) (auth.Resou
 	}
 
 	var resourceDA auth.ResourceDA
-	if err := repo.db.GetContext(ctx, &resourceDA, query, id); err != nil {
+	if err := sqlx.GetContext(ctx, repo.getExec(ctx), &resourceDA, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return auth.Resource{}, auth.ErrResourceNotFound
 		}
@@ -495,7 +619,7 @@ func (repo *AuthRepo) getResourcePreload(ctx context.Context, id uuid.UUID) (aut
 		return auth.Resource{}, err
 	}
 
-	rows, err := repo.db.QueryxContext(ctx, query, id)
+	rows, err := repo.getExec(ctx).QueryxContext(ctx, query, id)
 	if err != nil {
 		return auth.Resource{}, err
 	}
@@ -556,27 +680,61 @@ func (repo *AuthRepo) UpdateResource(ctx context.Context, resource auth.Resource
 
 	resourceDA := auth.ToResourceDA(resource)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query,
+	result, err := exec.ExecContext(ctx, query,
 		resourceDA.Name,
 		resourceDA.Description,
 		resourceDA.ShortID,
 		resourceDA.UpdatedBy,
 		resourceDA.UpdatedAt,
-		resourceDA.ID)
-	return err
+		resourceDA.ID,
+		resourceDA.Version)
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, resource)
 }
 
-func (repo *AuthRepo) DeleteResource(ctx context.Context, id uuid.UUID) error {
+func (repo *AuthRepo) DeleteResource(ctx context.Context, resource auth.Resource) error {
 	query, err := repo.Query().Get(featAuth, resRes, "Delete")
 	if err != nil {
 		return err
 	}
 
+	resourceDA := auth.ToResourceDA(resource)
 	exec := repo.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, id)
+	_, err = exec.ExecContext(ctx, query, resourceDA.DeletedBy, resourceDA.DeletedAt, resourceDA.ID)
 	return err
 }
 
+func (repo *AuthRepo) GetDeletedResources(ctx context.Context) ([]auth.Resource, error) {
+	query, err := repo.Query().Get(featAuth, resRes, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []auth.ResourceDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &resources, query)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToResources(resources), nil
+}
+
+func (repo *AuthRepo) RestoreResource(ctx context.Context, resource auth.Resource) error {
+	query, err := repo.Query().Get(featAuth, resRes, "Restore")
+	if err != nil {
+		return err
+	}
+
+	resourceDA := auth.ToResourceDA(resource)
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, resourceDA.UpdatedBy, resourceDA.UpdatedAt, resourceDA.ID)
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
 func (repo *AuthRepo) GetUserAssignedRoles(ctx context.Context, userID uuid.UUID, contextType, contextID string) ([]auth.Role, error) {
 	query, err := repo.Query().Get(featAuth, resUserRole, "GetUserAssignedRoles")
 	if err != nil {
@@ -584,7 +742,7 @@ func (repo *AuthRepo) GetUserAssignedRoles(ctx context.Context, userID uuid.UUID
 	}
 
 	var rolesDA []auth.RoleDA
-	err = repo.db.SelectContext(ctx, &rolesDA, query,
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query,
 		userID.String(), contextType, contextID,
 	)
 	if err != nil {
@@ -593,7 +751,8 @@ func (repo *AuthRepo) GetUserAssignedRoles(ctx context.Context, userID uuid.UUID
 	return auth.ToRoles(rolesDA), nil
 }
 
-// GetUserAssignedPermissions retrieves all permissions assigned to a user, both directly and through roles.
+// GetUserAssignedPermissions retrieves all permissions assigned to a user, both directly and through roles,
+// including the ones those roles inherit from the roles they extend.
 func (repo *AuthRepo) GetUserAssignedPermissions(ctx context.Context, userID uuid.UUID) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resUserPerm, "GetUserAssignedPermissions")
 	if err != nil {
@@ -601,7 +760,7 @@ func (repo *AuthRepo) GetUserAssignedPermissions(ctx context.Context, userID uui
 	}
 
 	var permissionsDA []auth.PermissionDA
-	err = repo.db.SelectContext(ctx, &permissionsDA, query, userID, userID)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, userID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -609,6 +768,7 @@ func (repo *AuthRepo) GetUserAssignedPermissions(ctx context.Context, userID uui
 	return auth.ToPermissions(permissionsDA), nil
 }
 
+// GetUserIndirectPermissions retrieves the permissions a user gets through roles and the roles they extend.
 func (repo *AuthRepo) GetUserIndirectPermissions(ctx context.Context, userID uuid.UUID) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resUserPerm, "GetUserIndirectPermissions")
 	if err != nil {
@@ -616,7 +776,7 @@ func (repo *AuthRepo) GetUserIndirectPermissions(ctx context.Context, userID uui
 	}
 
 	var permissionsDA []auth.PermissionDA
-	err = repo.db.SelectContext(ctx, &permissionsDA, query, userID)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -632,7 +792,7 @@ func (repo *AuthRepo) GetUserDirectPermissions(ctx context.Context, userID uuid.
 	}
 
 	var permissionsDA []auth.PermissionDA
-	err = repo.db.SelectContext(ctx, &permissionsDA, query, userID)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -640,7 +800,8 @@ func (repo *AuthRepo) GetUserDirectPermissions(ctx context.Context, userID uuid.
 	return auth.ToPermissions(permissionsDA), nil
 }
 
-// GetUserUnassignedPermissions retrieves permissions not assigned to a user, either directly or through roles.
+// GetUserUnassignedPermissions retrieves permissions not assigned to a user, either directly or through roles
+// and the roles they extend.
 func (repo *AuthRepo) GetUserUnassignedPermissions(ctx context.Context, userID uuid.UUID) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resUserPerm, "GetUserUnassignedPermissions")
 	if err != nil {
@@ -648,7 +809,7 @@ func (repo *AuthRepo) GetUserUnassignedPermissions(ctx context.Context, userID u
 	}
 
 	var permissionsDA []auth.PermissionDA
-	err = repo.db.SelectContext(ctx, &permissionsDA, query, userID, userID)
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, userID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -686,7 +847,7 @@ func (repo *AuthRepo) GetUserUnassignedRoles(ctx context.Context, userID uuid.UU
 	}
 
 	var rolesDA []auth.RoleDA
-	err = repo.db.SelectContext(ctx, &rolesDA, query,
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query,
 		userID.String(), contextType, contextID,
 	)
 	if err != nil {
@@ -730,7 +891,7 @@ func (repo *AuthRepo) GetUserRole(ctx context.Context, userID, roleID uuid.UUID)
 	}
 
 	var roleDA auth.RoleDA
-	err = repo.db.GetContext(ctx, &roleDA, query, userID, roleID)
+	err = sqlx.GetContext(ctx, repo.getExec(ctx), &roleDA, query, userID, roleID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return auth.Role{}, errors.New("role not found")
@@ -809,13 +970,105 @@ func (repo *AuthRepo) GetRolePermissions(ctx context.Context, roleID uuid.UUID)
 	}
 
 	var permissionsDA []auth.PermissionDA
-	if err := repo.db.SelectContext(ctx, &permissionsDA, query, roleID); err != nil {
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, roleID); err != nil {
 		return nil, err
 	}
 
 	return auth.ToPermissions(permissionsDA), nil
 }
 
+// GetRoleParents returns the roles the given role extends.
+func (repo *AuthRepo) GetRoleParents(ctx context.Context, roleID uuid.UUID) ([]auth.Role, error) {
+	query, err := repo.Query().Get(featAuth, resRoleParent, "GetParents")
+	if err != nil {
+		return nil, err
+	}
+
+	var rolesDA []auth.RoleDA
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query, roleID.String()); err != nil {
+		return nil, err
+	}
+
+	return auth.ToRoles(rolesDA), nil
+}
+
+// GetRoleAncestors returns every role the given role extends, directly or transitively, closest first.
+// The hierarchy is walked in a single recursive query; paths are bounded by the number of roles so a cycle in the
+// stored data cannot recurse forever.
+func (repo *AuthRepo) GetRoleAncestors(ctx context.Context, roleID uuid.UUID) ([]auth.Role, error) {
+	query, err := repo.Query().Get(featAuth, resRoleParent, "GetAncestors")
+	if err != nil {
+		return nil, err
+	}
+
+	var rolesDA []auth.RoleDA
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query, roleID.String(), roleID.String()); err != nil {
+		return nil, err
+	}
+
+	return auth.ToRoles(rolesDA), nil
+}
+
+// AddRoleParent makes the role extend the parent role.
+func (repo *AuthRepo) AddRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error {
+	query, err := repo.Query().Get(featAuth, resRoleParent, "Add")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, roleID.String(), parentID.String())
+	if err != nil {
+		return fmt.Errorf("failed to add parent to role: %w", err)
+	}
+	return nil
+}
+
+// RemoveRoleParent stops the role from extending the parent role.
+func (repo *AuthRepo) RemoveRoleParent(ctx context.Context, roleID uuid.UUID, parentID uuid.UUID) error {
+	query, err := repo.Query().Get(featAuth, resRoleParent, "Remove")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, roleID.String(), parentID.String())
+	if err != nil {
+		return fmt.Errorf("failed to remove parent from role: %w", err)
+	}
+	return nil
+}
+
+// GetUserAllRoles returns the roles assigned to a user in any context.
+func (repo *AuthRepo) GetUserAllRoles(ctx context.Context, userID uuid.UUID) ([]auth.Role, error) {
+	query, err := repo.Query().Get(featAuth, resUserRole, "GetUserAllRoles")
+	if err != nil {
+		return nil, err
+	}
+
+	var rolesDA []auth.RoleDA
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query, userID.String()); err != nil {
+		return nil, err
+	}
+
+	return auth.ToRoles(rolesDA), nil
+}
+
+// GetUserRoleAssignments returns the roles assigned to a user along with the context of each assignment.
+func (repo *AuthRepo) GetUserRoleAssignments(ctx context.Context, userID uuid.UUID) ([]auth.RoleAssignment, error) {
+	query, err := repo.Query().Get(featAuth, resUserRole, "GetUserRoleAssignments")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []auth.RoleAssignmentDA
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &das, query, userID.String()); err != nil {
+		return nil, err
+	}
+
+	return auth.ToRoleAssignments(das), nil
+}
+
 // GetResourcePermissions returns all permissions assigned to a resource
 func (repo *AuthRepo) GetResourcePermissions(ctx context.Context, resourceID uuid.UUID) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resResPerm, "GetResourcePermissions")
@@ -824,7 +1077,7 @@ func (repo *AuthRepo) GetResourcePermissions(ctx context.Context, resourceID uui
 	}
 
 	var permissionsDA []auth.PermissionDA
-	if err := repo.db.SelectContext(ctx, &permissionsDA, query, resourceID); err != nil {
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, resourceID); err != nil {
 		return nil, err
 	}
 
@@ -839,14 +1092,14 @@ func (repo *AuthRepo) GetResourceUnassignedPermissions(ctx context.Context, reso
 	}
 
 	var permissionsDA []auth.PermissionDA
-	if err := repo.db.SelectContext(ctx, &permissionsDA, query, resourceID); err != nil {
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, resourceID); err != nil {
 		return nil, err
 	}
 
 	return auth.ToPermissions(permissionsDA), nil
 }
 
-// GetRoleUnassignedPermissions returns all permissions not assigned to a role
+// GetRoleUnassignedPermissions returns all permissions not assigned to a role nor inherited from the roles it extends
 func (repo *AuthRepo) GetRoleUnassignedPermissions(ctx context.Context, roleID uuid.UUID) ([]auth.Permission, error) {
 	query, err := repo.Query().Get(featAuth, resRolePerm, "GetRoleUnassignedPermissions")
 	if err != nil {
@@ -854,7 +1107,7 @@ func (repo *AuthRepo) GetRoleUnassignedPermissions(ctx context.Context, roleID u
 	}
 
 	var permissionsDA []auth.PermissionDA
-	if err := repo.db.SelectContext(ctx, &permissionsDA, query, roleID); err != nil {
+	if err := sqlx.SelectContext(ctx, repo.getExec(ctx), &permissionsDA, query, roleID); err != nil {
 		return nil, err
 	}
 
@@ -879,32 +1132,178 @@ func (r *AuthRepo) GetDefaultOrg(ctx context.Context) (auth.Org, error) {
 	if err != nil {
 		return auth.Org{}, err
 	}
-	row := r.db.QueryRowContext(ctx, query)
 	var orgDA auth.OrgDA
-	err = row.Scan(
-		&orgDA.ID,
-		&orgDA.ShortID,
-		&orgDA.Name,
-		&orgDA.ShortDescription,
-		&orgDA.Description,
-		&orgDA.CreatedBy,
-		&orgDA.UpdatedBy,
-		&orgDA.CreatedAt,
-		&orgDA.UpdatedAt,
-	)
+	err = sqlx.GetContext(ctx, r.getExec(ctx), &orgDA, query)
 	if err != nil {
 		return auth.Org{}, err
 	}
 	return auth.ToOrg(orgDA), nil
 }
 
+func (r *AuthRepo) GetAllOrgs(ctx context.Context) ([]auth.Org, error) {
+	query, err := r.Query().Get(featAuth, resOrg, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+	var orgsDA []auth.OrgDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &orgsDA, query)
+	if err != nil {
+		return nil, err
+	}
+	return toOrgs(orgsDA), nil
+}
+
+func (r *AuthRepo) GetUserOrgs(ctx context.Context, userID uuid.UUID) ([]auth.Org, error) {
+	query, err := r.Query().Get(featAuth, resOrg, "GetForUser")
+	if err != nil {
+		return nil, err
+	}
+	var orgsDA []auth.OrgDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &orgsDA, query, userID.String(), userID.String())
+	if err != nil {
+		return nil, err
+	}
+	return toOrgs(orgsDA), nil
+}
+
+func (r *AuthRepo) GetOrg(ctx context.Context, id uuid.UUID) (auth.Org, error) {
+	query, err := r.Query().Get(featAuth, resOrg, "Get")
+	if err != nil {
+		return auth.Org{}, err
+	}
+	var da auth.OrgDA
+	err = sqlx.GetContext(ctx, r.getExec(ctx), &da, query, id.String())
+	if err != nil {
+		return auth.Org{}, err
+	}
+	return auth.ToOrg(da), nil
+}
+
+func (r *AuthRepo) UpdateOrg(ctx context.Context, org auth.Org) error {
+	query, err := r.Query().Get(featAuth, resOrg, "Update")
+	if err != nil {
+		return err
+	}
+	exec := r.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query,
+		org.ShortID(), org.Name, org.ShortDescription, org.Description, org.UpdatedBy().String(), org.UpdatedAt(), org.ID().String(), org.Version(),
+	)
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, org)
+}
+
+func (r *AuthRepo) DeleteOrg(ctx context.Context, org auth.Org) error {
+	query, err := r.Query().Get(featAuth, resOrg, "Delete")
+	if err != nil {
+		return err
+	}
+	deletedBy := sql.NullString{String: org.DeletedBy().String(), Valid: org.DeletedBy() != uuid.Nil}
+	exec := r.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, deletedBy, org.DeletedAt(), org.ID().String())
+	return err
+}
+
+func (r *AuthRepo) GetDeletedOrgs(ctx context.Context) ([]auth.Org, error) {
+	query, err := r.Query().Get(featAuth, resOrg, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+	var orgsDA []auth.OrgDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &orgsDA, query)
+	if err != nil {
+		return nil, err
+	}
+	return toOrgs(orgsDA), nil
+}
+
+func (r *AuthRepo) RestoreOrg(ctx context.Context, org auth.Org) error {
+	query, err := r.Query().Get(featAuth, resOrg, "Restore")
+	if err != nil {
+		return err
+	}
+	updatedBy := sql.NullString{String: org.UpdatedBy().String(), Valid: org.UpdatedBy() != uuid.Nil}
+	exec := r.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, updatedBy, org.UpdatedAt(), org.ID().String())
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
+func (r *AuthRepo) GetOrgMembers(ctx context.Context, orgID uuid.UUID) ([]auth.User, error) {
+	query, err := r.Query().Get(featAuth, resOrgMember, "GetOrgMembers")
+	if err != nil {
+		return nil, err
+	}
+	var usersDA []auth.UserDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &usersDA, query, orgID.String())
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToUsers(usersDA), nil
+}
+
+func (r *AuthRepo) GetOrgNonMembers(ctx context.Context, orgID uuid.UUID) ([]auth.User, error) {
+	query, err := r.Query().Get(featAuth, resOrgMember, "GetOrgNonMembers")
+	if err != nil {
+		return nil, err
+	}
+	var usersDA []auth.UserDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &usersDA, query, orgID.String())
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToUsers(usersDA), nil
+}
+
+func (r *AuthRepo) AddUserToOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	query, err := r.Query().Get(featAuth, resOrgMember, "Add")
+	if err != nil {
+		return err
+	}
+	id := uuid.New()
+	exec := r.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, id.String(), orgID.String(), userID.String())
+	return err
+}
+
+// RemoveUserFromOrg removes the membership and the roles the user had in the org.
+func (r *AuthRepo) RemoveUserFromOrg(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	query, err := r.Query().Get(featAuth, resOrgMember, "Remove")
+	if err != nil {
+		return err
+	}
+	exec := r.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query, orgID.String(), userID.String())
+	if err != nil {
+		return err
+	}
+
+	query, err = r.Query().Get(featAuth, resUserRole, "RemoveContext")
+	if err != nil {
+		return err
+	}
+	_, err = exec.ExecContext(ctx, query, userID.String(), auth.ContextTypeOrg, orgID.String())
+	return err
+}
+
+func toOrgs(orgsDA []auth.OrgDA) []auth.Org {
+	orgs := make([]auth.Org, len(orgsDA))
+	for i, da := range orgsDA {
+		orgs[i] = auth.ToOrg(da)
+	}
+	return orgs
+}
+
 func (r *AuthRepo) GetOrgOwners(ctx context.Context, orgID uuid.UUID) ([]auth.User, error) {
 	query, err := r.Query().Get(featAuth, resOrgOwner, "GetOrgOwners")
 	if err != nil {
 		return nil, err
 	}
 	var usersDA []auth.UserDA
-	err = r.db.SelectContext(ctx, &usersDA, query, orgID.String())
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &usersDA, query, orgID.String())
 	if err != nil {
 		return nil, err
 	}
@@ -917,7 +1316,7 @@ func (r *AuthRepo) GetOrgUnassignedOwners(ctx context.Context, orgID uuid.UUID)
 		return nil, err
 	}
 	var usersDA []auth.UserDA
-	err = r.db.SelectContext(ctx, &usersDA, query, orgID.String())
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &usersDA, query, orgID.String())
 	if err != nil {
 		return nil, err
 	}
@@ -930,7 +1329,25 @@ func (r *AuthRepo) GetAllTeams(ctx context.Context, orgID uuid.UUID) ([]auth.Tea
 		return nil, err
 	}
 	var teamsDA []auth.TeamDA
-	err = r.db.SelectContext(ctx, &teamsDA, query, orgID.String())
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &teamsDA, query, orgID.String())
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]auth.Team, len(teamsDA))
+	for i, da := range teamsDA {
+		teams[i] = auth.ToTeam(da)
+	}
+	return teams, nil
+}
+
+// GetUserTeams returns the teams the user is a direct member of, across all orgs.
+func (r *AuthRepo) GetUserTeams(ctx context.Context, userID uuid.UUID) ([]auth.Team, error) {
+	query, err := r.Query().Get(featAuth, resTeam, "GetUserTeams")
+	if err != nil {
+		return nil, err
+	}
+	var teamsDA []auth.TeamDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &teamsDA, query, userID.String())
 	if err != nil {
 		return nil, err
 	}
@@ -947,7 +1364,7 @@ func (r *AuthRepo) GetTeam(ctx context.Context, id uuid.UUID) (auth.Team, error)
 		return auth.Team{}, err
 	}
 	var da auth.TeamDA
-	err = r.db.GetContext(ctx, &da, query, id.String())
+	err = sqlx.GetContext(ctx, r.getExec(ctx), &da, query, id.String())
 	if err != nil {
 		return auth.Team{}, err
 	}
@@ -958,6 +1375,7 @@ func (r *AuthRepo) CreateTeam(ctx context.Context, team auth.Team) error {
 	da := auth.TeamDA{
 		ID:               sql.NullString{String: team.ID().String(), Valid: team.ID() != uuid.Nil},
 		OrgID:            sql.NullString{String: team.OrgID.String(), Valid: team.OrgID != uuid.Nil},
+		ParentID:         am.NullUUID(team.ParentID),
 		ShortID:          team.ShortID(),
 		Name:             team.Name,
 		ShortDescription: team.ShortDescription,
@@ -973,7 +1391,7 @@ func (r *AuthRepo) CreateTeam(ctx context.Context, team auth.Team) error {
 	}
 	exec := r.getExec(ctx)
 	_, err = exec.ExecContext(ctx, query,
-		da.ID, da.OrgID, da.ShortID, da.Name, da.ShortDescription, da.Description, da.CreatedBy, da.UpdatedBy, da.CreatedAt, da.UpdatedAt,
+		da.ID, da.OrgID, da.ParentID, da.ShortID, da.Name, da.ShortDescription, da.Description, da.CreatedBy, da.UpdatedBy, da.CreatedAt, da.UpdatedAt,
 	)
 	return err
 }
@@ -984,22 +1402,57 @@ func (r *AuthRepo) UpdateTeam(ctx context.Context, team auth.Team) error {
 		return err
 	}
 	exec := r.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query,
-		team.ShortID(), team.Name, team.ShortDescription, team.Description, team.UpdatedBy().String(), team.UpdatedAt(), team.ID().String(),
+	result, err := exec.ExecContext(ctx, query,
+		am.NullUUID(team.ParentID), team.ShortID(), team.Name, team.ShortDescription, team.Description, team.UpdatedBy().String(), team.UpdatedAt(), team.ID().String(), team.Version(),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return checkVersion(result, team)
 }
 
-func (r *AuthRepo) DeleteTeam(ctx context.Context, id uuid.UUID) error {
+func (r *AuthRepo) DeleteTeam(ctx context.Context, team auth.Team) error {
 	query, err := r.Query().Get(featAuth, resTeam, "Delete")
 	if err != nil {
 		return err
 	}
+	deletedBy := sql.NullString{String: team.DeletedBy().String(), Valid: team.DeletedBy() != uuid.Nil}
 	exec := r.getExec(ctx)
-	_, err = exec.ExecContext(ctx, query, id.String())
+	_, err = exec.ExecContext(ctx, query, deletedBy, team.DeletedAt(), team.ID().String())
 	return err
 }
 
+func (r *AuthRepo) GetDeletedTeams(ctx context.Context, orgID uuid.UUID) ([]auth.Team, error) {
+	query, err := r.Query().Get(featAuth, resTeam, "GetDeleted")
+	if err != nil {
+		return nil, err
+	}
+	var teamsDA []auth.TeamDA
+	err = sqlx.SelectContext(ctx, r.getExec(ctx), &teamsDA, query, orgID.String())
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]auth.Team, len(teamsDA))
+	for i, da := range teamsDA {
+		teams[i] = auth.ToTeam(da)
+	}
+	return teams, nil
+}
+
+func (r *AuthRepo) RestoreTeam(ctx context.Context, team auth.Team) error {
+	query, err := r.Query().Get(featAuth, resTeam, "Restore")
+	if err != nil {
+		return err
+	}
+	updatedBy := sql.NullString{String: team.UpdatedBy().String(), Valid: team.UpdatedBy() != uuid.Nil}
+	exec := r.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, updatedBy, team.UpdatedAt(), team.ID().String())
+	if err != nil {
+		return err
+	}
+	return checkRestored(result)
+}
+
 func (r *AuthRepo) AddOrgOwner(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
 	query, err := r.Query().Get(featAuth, resOrgOwner, "Add")
 	if err != nil {
@@ -1028,7 +1481,7 @@ func (repo *AuthRepo) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]a
 		return nil, err
 	}
 	var usersDA []auth.UserDA
-	err = repo.db.SelectContext(ctx, &usersDA, query, teamID.String())
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &usersDA, query, teamID.String())
 	repo.Log().Debugf("GetTeamMembers teamID: %s", teamID.String())
 	for _, user := range usersDA {
 		repo.Log().Debugf("User: %+v", user)
@@ -1045,7 +1498,7 @@ func (repo *AuthRepo) GetTeamUnassignedUsers(ctx context.Context, teamID uuid.UU
 		return nil, err
 	}
 	var usersDA []auth.UserDA
-	err = repo.db.SelectContext(ctx, &usersDA, query, teamID.String())
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &usersDA, query, teamID.String())
 	if err != nil {
 		return nil, err
 	}
@@ -1073,32 +1526,375 @@ func (repo *AuthRepo) RemoveUserFromTeam(ctx context.Context, teamID uuid.UUID,
 	return err
 }
 
-func (repo *AuthRepo) GetUserContextualRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]auth.Role, error) {
+func (repo *AuthRepo) GetUserContextualRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]auth.Role, error) {
 	query, err := repo.Query().Get(featAuth, resUserRole, "GetContextualAssignedRoles")
 	if err != nil {
 		return nil, err
 	}
 
 	var rolesDA []auth.RoleDA
-	err = repo.db.SelectContext(ctx, &rolesDA, query,
-		userID.String(), "team", teamID.String())
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query,
+		userID.String(), contextType, contextID.String())
 	if err != nil {
 		return nil, err
 	}
 	return auth.ToRoles(rolesDA), nil
 }
 
-func (repo *AuthRepo) GetUserContextualUnassignedRoles(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) ([]auth.Role, error) {
+func (repo *AuthRepo) GetUserContextualUnassignedRoles(ctx context.Context, contextType string, contextID uuid.UUID, userID uuid.UUID) ([]auth.Role, error) {
 	query, err := repo.Query().Get(featAuth, resUserRole, "GetContextualUnassignedRoles")
 	if err != nil {
 		return nil, err
 	}
 
 	var rolesDA []auth.RoleDA
-	err = repo.db.SelectContext(ctx, &rolesDA, query,
-		userID.String(), "team", teamID.String())
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &rolesDA, query,
+		userID.String(), contextType, contextID.String())
 	if err != nil {
 		return nil, err
 	}
 	return auth.ToRoles(rolesDA), nil
 }
+
+func (repo *AuthRepo) CreateInvitation(ctx context.Context, inv auth.Invitation) error {
+	query, err := repo.Query().Get(featAuth, resInvitation, "Create")
+	if err != nil {
+		return err
+	}
+
+	da := auth.ToInvitationDA(inv)
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query,
+		da.ID, da.ShortID, da.EmailEnc, da.TokenHash, da.InviterID, da.OrgID, da.TeamID, da.RoleID,
+		da.Status, da.ExpiresAt, da.CreatedBy, da.UpdatedBy, da.CreatedAt, da.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *AuthRepo) GetInvitation(ctx context.Context, id uuid.UUID) (auth.Invitation, error) {
+	return repo.getInvitation(ctx, "Get", id.String())
+}
+
+func (repo *AuthRepo) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (auth.Invitation, error) {
+	return repo.getInvitation(ctx, "GetByTokenHash", tokenHash)
+}
+
+func (repo *AuthRepo) getInvitation(ctx context.Context, name string, arg string) (auth.Invitation, error) {
+	query, err := repo.Query().Get(featAuth, resInvitation, name)
+	if err != nil {
+		return auth.Invitation{}, err
+	}
+
+	var da auth.InvitationDA
+	err = sqlx.GetContext(ctx, repo.getExec(ctx), &da, query, arg)
+	if errors.Is(err, sql.ErrNoRows) {
+		return auth.Invitation{}, auth.ErrInvitationNotFound
+	}
+	if err != nil {
+		return auth.Invitation{}, err
+	}
+	return auth.ToInvitation(da), nil
+}
+
+// GetTeamInvitations returns the pending invitations to a team.
+func (repo *AuthRepo) GetTeamInvitations(ctx context.Context, teamID uuid.UUID) ([]auth.Invitation, error) {
+	return repo.getInvitations(ctx, "GetPendingForTeam", teamID)
+}
+
+// GetOrgInvitations returns the pending invitations to an org that do not target a team.
+func (repo *AuthRepo) GetOrgInvitations(ctx context.Context, orgID uuid.UUID) ([]auth.Invitation, error) {
+	return repo.getInvitations(ctx, "GetPendingForOrg", orgID)
+}
+
+func (repo *AuthRepo) getInvitations(ctx context.Context, name string, id uuid.UUID) ([]auth.Invitation, error) {
+	query, err := repo.Query().Get(featAuth, resInvitation, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var das []auth.InvitationDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &das, query, id.String())
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToInvitations(das), nil
+}
+
+// RespondInvitation records the new status of a pending invitation.
+// It returns auth.ErrInvitationClosed if the invitation was not pending anymore.
+func (repo *AuthRepo) RespondInvitation(ctx context.Context, inv auth.Invitation) error {
+	query, err := repo.Query().Get(featAuth, resInvitation, "Respond")
+	if err != nil {
+		return err
+	}
+
+	da := auth.ToInvitationDA(inv)
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query,
+		da.Status, da.UserID, da.RespondedAt, da.UpdatedBy, da.UpdatedAt, da.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return auth.ErrInvitationClosed
+	}
+	return nil
+}
+
+// GetPolicies returns the policies stored in the database.
+func (repo *AuthRepo) GetPolicies(ctx context.Context) ([]auth.Policy, error) {
+	query, err := repo.Query().Get(featAuth, resPolicy, "GetAll")
+	if err != nil {
+		return nil, err
+	}
+
+	var das []auth.PolicyDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &das, query)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ToPolicies(das)
+}
+
+func (repo *AuthRepo) GetPolicy(ctx context.Context, id uuid.UUID) (auth.Policy, error) {
+	query, err := repo.Query().Get(featAuth, resPolicy, "Get")
+	if err != nil {
+		return auth.Policy{}, err
+	}
+
+	var da auth.PolicyDA
+	err = sqlx.GetContext(ctx, repo.getExec(ctx), &da, query, id.String())
+	if errors.Is(err, sql.ErrNoRows) {
+		return auth.Policy{}, auth.ErrPolicyNotFound
+	}
+	if err != nil {
+		return auth.Policy{}, err
+	}
+	return auth.ToPolicy(da)
+}
+
+func (repo *AuthRepo) CreatePolicy(ctx context.Context, policy auth.Policy) error {
+	query, err := repo.Query().Get(featAuth, resPolicy, "Create")
+	if err != nil {
+		return err
+	}
+
+	da, err := auth.ToPolicyDA(policy)
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query,
+		da.ID, da.ShortID, da.Name, da.Description, da.Effect, da.Actions, da.Resources, da.Conditions,
+		da.Enabled, da.CreatedBy, da.UpdatedBy, da.CreatedAt, da.UpdatedAt,
+	)
+	return err
+}
+
+func (repo *AuthRepo) UpdatePolicy(ctx context.Context, policy auth.Policy) error {
+	query, err := repo.Query().Get(featAuth, resPolicy, "Update")
+	if err != nil {
+		return err
+	}
+
+	da, err := auth.ToPolicyDA(policy)
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query,
+		da.Name, da.Description, da.Effect, da.Actions, da.Resources, da.Conditions,
+		da.Enabled, da.UpdatedBy, da.UpdatedAt, da.ID,
+	)
+	if err != nil {
+		return err
+	}
+	return checkPolicyFound(result)
+}
+
+func (repo *AuthRepo) DeletePolicy(ctx context.Context, id uuid.UUID) error {
+	query, err := repo.Query().Get(featAuth, resPolicy, "Delete")
+	if err != nil {
+		return err
+	}
+
+	exec := repo.getExec(ctx)
+	result, err := exec.ExecContext(ctx, query, id.String())
+	if err != nil {
+		return err
+	}
+	return checkPolicyFound(result)
+}
+
+func checkPolicyFound(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return auth.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// purgeSteps lists the queries run by PurgeDeleted.
+// Relationship rows go first so that no dangling references are left behind.
+var purgeSteps = []struct{ res, name string }{
+	{resUserRole, "PurgeByUser"},
+	{resUserRole, "PurgeByRole"},
+	{resUserRole, "PurgeByTeam"},
+	{resUserRole, "PurgeByOrg"},
+	{resUserPerm, "PurgeByUser"},
+	{resUserPerm, "PurgeByPermission"},
+	{resRolePerm, "PurgeByRole"},
+	{resRoleParent, "PurgeByRole"},
+	{resRoleParent, "PurgeByParent"},
+	{resRolePerm, "PurgeByPermission"},
+	{resResPerm, "PurgeByResource"},
+	{resResPerm, "PurgeByPermission"},
+	{resTeamMember, "PurgeByUser"},
+	{resTeamMember, "PurgeByTeam"},
+	{resOrgOwner, "PurgeByUser"},
+	{resOrgOwner, "PurgeByOrg"},
+	{resOrgMember, "PurgeByUser"},
+	{resOrgMember, "PurgeByOrg"},
+	{resInvitation, "PurgeByTeam"},
+	{resInvitation, "PurgeByOrg"},
+	{resTeam, "DetachPurgedChildren"},
+	{resTeam, "Purge"},
+	{resRes, "Purge"},
+	{resPerm, "Purge"},
+	{resRole, "Purge"},
+	{resUser, "Purge"},
+	{resOrg, "Purge"},
+}
+
+// PurgeDeleted hard deletes the rows that were soft deleted before the given time.
+// It returns the number of purged entities, relationship rows are not counted.
+func (repo *AuthRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	exec := repo.getExec(ctx)
+
+	var purged int64
+	for _, step := range purgeSteps {
+		query, err := repo.Query().Get(featAuth, step.res, step.name)
+		if err != nil {
+			return purged, err
+		}
+
+		result, err := exec.ExecContext(ctx, query, before)
+		if err != nil {
+			return purged, fmt.Errorf("cannot purge %s: %w", step.res, err)
+		}
+
+		if step.name == "Purge" {
+			n, err := result.RowsAffected()
+			if err == nil {
+				purged += n
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+// CreateAuditEntry appends an entry to the audit log.
+// It is expected to run inside the transaction of the change being audited.
+func (repo *AuthRepo) CreateAuditEntry(ctx context.Context, entry auth.AuditEntry) error {
+	query, err := repo.Query().Get(featAuth, resAuditLog, "Create")
+	if err != nil {
+		return err
+	}
+
+	da := auth.ToAuditEntryDA(entry)
+	exec := repo.getExec(ctx)
+	_, err = exec.ExecContext(ctx, query,
+		da.ID,
+		da.ShortID,
+		da.ActorID,
+		da.Action,
+		da.TargetType,
+		da.TargetID,
+		da.Before,
+		da.After,
+		da.IP,
+		da.CreatedAt,
+	)
+	return err
+}
+
+// GetAuditEntries returns the audit entries matching the filter, newest first.
+func (repo *AuthRepo) GetAuditEntries(ctx context.Context, filter auth.AuditFilter) ([]auth.AuditEntry, error) {
+	query, err := repo.Query().Get(featAuth, resAuditLog, "List")
+	if err != nil {
+		return nil, err
+	}
+
+	actorID := uuidOrEmpty(filter.ActorID)
+	targetID := uuidOrEmpty(filter.TargetID)
+	from := sql.NullTime{Time: filter.From, Valid: !filter.From.IsZero()}
+	to := sql.NullTime{Time: filter.To, Valid: !filter.To.IsZero()}
+
+	var entriesDA []auth.AuditEntryDA
+	err = sqlx.SelectContext(ctx, repo.getExec(ctx), &entriesDA, query,
+		actorID, actorID,
+		filter.Action, filter.Action,
+		filter.TargetType, filter.TargetType,
+		targetID, targetID,
+		from, from,
+		to, to,
+		filter.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.ToAuditEntries(entriesDA), nil
+}
+
+func uuidOrEmpty(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// versioned is satisfied by models whose updates are guarded by a row version.
+type versioned interface {
+	Type() string
+	ID() uuid.UUID
+	am.Versioned
+}
+
+// checkVersion returns an am.ConflictError if the versioned update did not match any row,
+// otherwise it advances the model to the version now stored.
+func checkVersion(result sql.Result, m versioned) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return am.NewConflictError(m.Type(), m.ID(), m.Version())
+	}
+	m.SetVersion(m.Version() + 1)
+	return nil
+}
+
+// checkRestored returns auth.ErrNotDeleted if the restore did not match any soft deleted row.
+func checkRestored(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return auth.ErrNotDeleted
+	}
+	return nil
+}
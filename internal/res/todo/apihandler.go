@@ -2,6 +2,7 @@ package todo
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/aquamarinepk/todo/internal/am"
@@ -23,7 +24,7 @@ func NewAPIHandler(service Service, options ...am.Option) *APIHandler {
 }
 
 func (h *APIHandler) List(w http.ResponseWriter, r *http.Request) {
-	lists, err := h.service.GetLists(r.Context())
+	lists, err := h.service.GetLists(r.Context(), r.URL.Query().Get("filter"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -38,7 +39,7 @@ func (h *APIHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.Create(r.Context(), list); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiErr(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusCreated)
@@ -52,9 +53,10 @@ func (h *APIHandler) Show(w http.ResponseWriter, r *http.Request) {
 	}
 	list, err := h.service.Get(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		apiErr(w, err)
 		return
 	}
+	am.SetETag(w, list)
 	json.NewEncoder(w).Encode(list)
 }
 
@@ -64,16 +66,43 @@ func (h *APIHandler) Update(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid ID", http.StatusBadRequest)
 		return
 	}
-	var list List
-	if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+	var payload struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	list.SetID(id)
+	list, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	version, ok, err := am.IfMatchVersion(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ok && version != list.Version() {
+		am.SetETag(w, list)
+		http.Error(w, am.ErrPreconditionFailed, http.StatusPreconditionFailed)
+		return
+	}
+	list.Name = payload.Name
+	list.Description = payload.Description
 	if err := h.service.Update(r.Context(), list); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		switch {
+		case am.IsConflict(err) && ok:
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case am.IsConflict(err):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			apiErr(w, err)
+		}
 		return
 	}
+	am.SetETag(w, list)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -84,8 +113,87 @@ func (h *APIHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) Shares(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	shares, err := h.service.GetShares(r.Context(), id)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(shares)
+}
+
+func (h *APIHandler) Share(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var share Share
+	if err := json.NewDecoder(r.Body).Decode(&share); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	share.ListID = id
+	if err := h.service.ShareList(r.Context(), share); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *APIHandler) Unshare(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	granteeID, err := uuid.Parse(chi.URLParam(r, "granteeID"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.UnshareList(r.Context(), id, chi.URLParam(r, "granteeType"), granteeID); err != nil {
+		apiErr(w, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func apiErr(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrListNotFound), errors.Is(err, ErrShareNotFound), errors.Is(err, ErrItemNotFound), errors.Is(err, ErrFilterNotFound),
+		errors.Is(err, ErrFeedNotFound), errors.Is(err, ErrCommentNotFound),
+		errors.Is(err, ErrAttachmentNotFound), errors.Is(err, ErrAssigneeNotFound), errors.Is(err, ErrRevisionNotFound), errors.Is(err, ErrTimeEntryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrUnauthenticated):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, ErrInvalidShare), errors.Is(err, ErrInvalidItem), errors.Is(err, ErrInvalidFilter), errors.Is(err, ErrInvalidTimeZone), errors.Is(err, ErrInvalidOffset),
+		errors.Is(err, ErrInvalidRRule), errors.Is(err, ErrNotRecurring),
+		errors.Is(err, ErrInvalidParent), errors.Is(err, ErrInvalidDependency), errors.Is(err, ErrDependencyCycle),
+		errors.Is(err, ErrInvalidTag), errors.Is(err, ErrInvalidPriority), errors.Is(err, ErrInvalidStatus),
+		errors.Is(err, ErrInvalidColumns), errors.Is(err, ErrInvalidFormat), errors.Is(err, ErrInvalidComment),
+		errors.Is(err, ErrInvalidAssignee), errors.Is(err, ErrInvalidBulk), errors.Is(err, ErrNotTemplate), errors.Is(err, ErrInvalidTimeEntry):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrRecurrenceEnded), errors.Is(err, ErrItemBlocked), errors.Is(err, ErrTimerRunning), errors.Is(err, ErrNoTimerRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,62 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Assigned returns the items assigned to the actor, or to their teams when the view query param is teams.
+func (h *APIHandler) Assigned(w http.ResponseWriter, r *http.Request) {
+	view := AssignedToMe
+	if r.URL.Query().Get("view") == AssignedToTeams {
+		view = AssignedToTeams
+	}
+	items, err := h.service.GetAssignedItems(r.Context(), view)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(listedItems(items))
+}
+
+// AssignItem assigns the item to the user or team in the body, e.g. {"type": "team", "id": "<id>"}.
+func (h *APIHandler) AssignItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var assignee Assignee
+	if err := json.NewDecoder(r.Body).Decode(&assignee); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item, err := h.service.AssignItem(r.Context(), listID, itemID, assignee)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *APIHandler) UnassignItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	assigneeID, err := uuid.Parse(chi.URLParam(r, "assigneeID"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	assignee := Assignee{Type: chi.URLParam(r, "assigneeType"), ID: assigneeID}
+	if _, err := h.service.UnassignItem(r.Context(), listID, itemID, assignee); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,97 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// Attachments returns the attachments of the item, oldest first.
+func (h *APIHandler) Attachments(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	attachments, err := h.service.GetAttachments(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if attachments == nil {
+		attachments = []Attachment{}
+	}
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// UploadAttachment attaches the file field of a multipart body to the item,
+// or the raw body named by the name query param when the request is not multipart.
+func (h *APIHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	limits := h.service.AttachmentLimits()
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxSize+multipartOverhead)
+
+	var name string
+	var content io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				apiErr(w, ErrAttachmentTooLarge)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		name, content = header.Filename, file
+	} else {
+		name = r.URL.Query().Get("name")
+	}
+
+	attachment, err := h.service.AddAttachment(r.Context(), listID, itemID, name, content)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = ErrAttachmentTooLarge
+	}
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// DownloadAttachment sends the content of an attachment, in ranges when asked.
+func (h *APIHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	err = serveAttachment(w, r, h.service, listID, itemID, attachmentID)
+	if err != nil {
+		apiErr(w, err)
+	}
+}
+
+func (h *APIHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteAttachment(r.Context(), listID, itemID, attachmentID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,111 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// boardResponse is the board of a list, each column with its items in position order.
+type boardResponse struct {
+	Columns []boardColumnResponse `json:"columns"`
+	Hidden  int                   `json:"hidden"`
+}
+
+type boardColumnResponse struct {
+	BoardColumn
+	Items []Item `json:"items"`
+}
+
+// movePayload is the body of the move item request, the index is taken in the column without the item.
+type movePayload struct {
+	Status Status `json:"status"`
+	Index  int    `json:"index"`
+}
+
+// reorderPayload is the body of the reorder request, the items of the column in their new order.
+type reorderPayload struct {
+	Status  Status      `json:"status"`
+	ItemIDs []uuid.UUID `json:"item_ids"`
+}
+
+func (h *APIHandler) Board(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	board, err := h.service.GetBoard(r.Context(), listID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+
+	res := boardResponse{Columns: make([]boardColumnResponse, len(board.Lanes)), Hidden: board.Hidden}
+	for i, lane := range board.Lanes {
+		items := make([]Item, len(lane.Cards))
+		for j, card := range lane.Cards {
+			items[j] = card.Item
+		}
+		res.Columns[i] = boardColumnResponse{BoardColumn: lane.BoardColumn, Items: items}
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func (h *APIHandler) UpdateBoardColumns(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var columns []BoardColumn
+	if err := json.NewDecoder(r.Body).Decode(&columns); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.service.SetBoardColumns(r.Context(), listID, columns); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) MoveItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload movePayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+	if err := h.service.MoveItem(r.Context(), listID, itemID, payload.Status, payload.Index, force); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload reorderPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+	if err := h.service.ReorderItems(r.Context(), listID, payload.Status, payload.ItemIDs, force); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
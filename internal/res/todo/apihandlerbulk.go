@@ -0,0 +1,33 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// BulkItems runs a bulk command on items of the list, e.g. {"command": "move", "item_ids": [...], "status": "waiting"}.
+// The response holds the result of each item; nothing is applied, and 409 is returned, if any of them failed.
+func (h *APIHandler) BulkItems(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var cmd BulkCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	batch, err := h.service.BulkItems(r.Context(), listID, cmd)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if !batch.Applied {
+		w.WriteHeader(http.StatusConflict)
+	}
+	json.NewEncoder(w).Encode(batch)
+}
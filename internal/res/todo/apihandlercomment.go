@@ -0,0 +1,112 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// commentPayload is the body of the create and update comment requests.
+type commentPayload struct {
+	Body string `json:"body"`
+}
+
+// Comments returns the comments on the list, or on the item of the route, oldest first.
+func (h *APIHandler) Comments(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := commentTarget(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	comments, err := h.service.GetComments(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if comments == nil {
+		comments = []Comment{}
+	}
+	json.NewEncoder(w).Encode(comments)
+}
+
+// CreateComment comments on the list, or on the item of the route.
+func (h *APIHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := commentTarget(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload commentPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	comment, err := h.service.AddComment(r.Context(), NewComment(listID, itemID, payload.Body))
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *APIHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	listID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload commentPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	comment, err := h.service.UpdateComment(r.Context(), listID, commentID, payload.Body)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(comment)
+}
+
+func (h *APIHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	listID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteComment(r.Context(), listID, commentID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Activity returns the timeline of the list, its comments and changes merged newest first.
+func (h *APIHandler) Activity(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	timeline, err := h.service.GetTimeline(r.Context(), listID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(timeline)
+}
+
+// commentTarget returns the list of the route and its item, Nil on the list comment routes.
+func commentTarget(r *http.Request) (listID, itemID uuid.UUID, err error) {
+	listID, err = uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	if id := chi.URLParam(r, "itemID"); id != "" {
+		itemID, err = uuid.Parse(id)
+	}
+	return listID, itemID, err
+}
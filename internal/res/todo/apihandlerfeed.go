@@ -0,0 +1,43 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// feedResponse is the feed token of the actor with the calendar URLs it grants access to.
+type feedResponse struct {
+	Token     string `json:"token"`
+	TodosURL  string `json:"todos_url"`
+	EventsURL string `json:"events_url"`
+}
+
+// FeedToken returns the calendar feed of the actor, creating its token on first use.
+func (h *APIHandler) FeedToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.service.GetFeedToken(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(newFeedResponse(r, token))
+}
+
+// RegenerateFeedToken replaces the feed token of the actor and returns the new calendar URLs.
+func (h *APIHandler) RegenerateFeedToken(w http.ResponseWriter, r *http.Request) {
+	token, err := h.service.RegenerateFeedToken(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newFeedResponse(r, token))
+}
+
+func newFeedResponse(r *http.Request, token FeedToken) feedResponse {
+	base := baseURL(r)
+	return feedResponse{
+		Token:     token.Token,
+		TodosURL:  base + token.Path(FeedTodos),
+		EventsURL: base + token.Path(FeedEvents),
+	}
+}
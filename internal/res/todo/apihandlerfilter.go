@@ -0,0 +1,83 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// filterPayload is the body of the save filter request.
+type filterPayload struct {
+	Name   string     `json:"name"`
+	Filter ItemFilter `json:"filter"`
+}
+
+// FindItems returns the items of all the visible lists that pass the filter in the query,
+// each one along with the name of its list.
+func (h *APIHandler) FindItems(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseItemFilter(r.URL.Query())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	items, err := h.service.FindItems(r.Context(), filter)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(listedItems(items))
+}
+
+func (h *APIHandler) Tags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.GetTags(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(tags)
+}
+
+func (h *APIHandler) SavedFilters(w http.ResponseWriter, r *http.Request) {
+	filters, err := h.service.GetSavedFilters(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(filters)
+}
+
+func (h *APIHandler) SaveFilter(w http.ResponseWriter, r *http.Request) {
+	var payload filterPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tags, err := NormalizeTags(payload.Filter.Tags)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	payload.Filter.Tags = tags
+	saved, err := h.service.SaveFilter(r.Context(), payload.Name, payload.Filter)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+func (h *APIHandler) DeleteSavedFilter(w http.ResponseWriter, r *http.Request) {
+	filterID, err := uuid.Parse(chi.URLParam(r, "filterID"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteSavedFilter(r.Context(), filterID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
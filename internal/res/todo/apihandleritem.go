@@ -0,0 +1,323 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// itemPayload is the body of the create and update item requests.
+// Times are RFC 3339 instants, the time zone is the one they are shown in and the one recurrences follow.
+// The parent and the dependencies are IDs of other items of the same list.
+type itemPayload struct {
+	Title     string      `json:"title"`
+	Notes     string      `json:"notes"`
+	StartAt   time.Time   `json:"start_at"`
+	DueAt     time.Time   `json:"due_at"`
+	TimeZone  string      `json:"time_zone"`
+	Reminders []Offset    `json:"reminders"`
+	RRule     string      `json:"rrule"`
+	ParentID  uuid.UUID   `json:"parent_id"`
+	BlockedBy []uuid.UUID `json:"blocked_by"`
+	Tags      []string    `json:"tags"`
+	Priority  Priority    `json:"priority"`
+	Status    Status      `json:"status"`
+}
+
+// apply sets the payload values on the item, a missing status keeps the current one.
+func (p itemPayload) apply(item Item) (Item, error) {
+	tags, err := NormalizeTags(p.Tags)
+	if err != nil {
+		return item, err
+	}
+	item.Title = p.Title
+	item.Notes = p.Notes
+	item.StartAt = p.StartAt.UTC()
+	item.DueAt = p.DueAt.UTC()
+	item.TimeZone = p.TimeZone
+	if item.TimeZone == "" {
+		item.TimeZone = "UTC"
+	}
+	item.Reminders = p.Reminders
+	item.RRule = p.RRule
+	item.ParentID = p.ParentID
+	item.BlockedBy = p.BlockedBy
+	item.Tags = tags
+	item.Priority = p.Priority
+	if p.Status != "" {
+		item.Status = p.Status
+	}
+	return item, nil
+}
+
+func (h *APIHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	filter, err := ParseItemFilter(r.URL.Query())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	items, err := h.service.GetItems(r.Context(), listID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(filter.Apply(items))
+}
+
+func (h *APIHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload itemPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item, err := payload.apply(NewItem(listID, "", ""))
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if err := h.service.CreateItem(r.Context(), item); err != nil {
+		apiErr(w, err)
+		return
+	}
+	am.SetETag(w, item)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *APIHandler) ShowItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	item, err := h.service.GetItem(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	am.SetETag(w, item)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *APIHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload itemPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	item, err := h.service.GetItem(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	version, ok, err := am.IfMatchVersion(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ok && version != item.Version() {
+		am.SetETag(w, item)
+		http.Error(w, am.ErrPreconditionFailed, http.StatusPreconditionFailed)
+		return
+	}
+	item, err = payload.apply(item)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if err := h.service.UpdateItem(r.Context(), item); err != nil {
+		switch {
+		case am.IsConflict(err) && ok:
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case am.IsConflict(err):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			apiErr(w, err)
+		}
+		return
+	}
+	am.SetETag(w, item)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *APIHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteItem(r.Context(), listID, itemID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	force := r.URL.Query().Get("force") == "true"
+	if err := h.service.CompleteItem(r.Context(), listID, itemID, force); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) ReopenItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.ReopenItem(r.Context(), listID, itemID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) SkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.SkipOccurrence(r.Context(), listID, itemID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIHandler) EndRecurrence(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.EndRecurrence(r.Context(), listID, itemID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ItemOccurrences returns the due times of the occurrences that follow an item.
+func (h *APIHandler) ItemOccurrences(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	item, err := h.service.GetItem(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(item.UpcomingOccurrences(occurrenceCount(r)))
+}
+
+// Occurrences previews a rule without saving anything, the rrule, due_at and time_zone query params
+// are the ones of an item payload. The response starts with due_at itself.
+func (h *APIHandler) Occurrences(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	due, err := time.Parse(time.RFC3339, q.Get("due_at"))
+	if err != nil {
+		http.Error(w, "invalid due_at", http.StatusBadRequest)
+		return
+	}
+	tz := q.Get("time_zone")
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		apiErr(w, ErrInvalidTimeZone)
+		return
+	}
+	rec, err := ParseRRule(q.Get("rrule"), loc)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	due = due.In(loc)
+	occurrences := append([]time.Time{due}, rec.Occurrences(due, 1, occurrenceCount(r)-1)...)
+	json.NewEncoder(w).Encode(occurrences)
+}
+
+// occurrenceCount returns the count query param, the preview size if missing or out of range.
+func occurrenceCount(r *http.Request) int {
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 1 || count > maxOccurrences {
+		return previewSize
+	}
+	return count
+}
+
+func (h *APIHandler) Today(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewToday)
+}
+
+func (h *APIHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewUpcoming)
+}
+
+func (h *APIHandler) Overdue(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewOverdue)
+}
+
+// due returns the items of a due view, each one along with the name of its list.
+func (h *APIHandler) due(w http.ResponseWriter, r *http.Request, view string) {
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	items, err := h.service.GetDueItems(r.Context(), view, time.Now(), loc, days)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(listedItems(items))
+}
+
+// listedItem is an item along with the name of its list.
+// The item is nested, its JSON form would otherwise take over the whole entry.
+type listedItem struct {
+	Item     Item   `json:"item"`
+	ListName string `json:"list_name"`
+}
+
+func listedItems(items []DueItem) []listedItem {
+	res := make([]listedItem, len(items))
+	for i, item := range items {
+		res[i] = listedItem{Item: item.Item, ListName: item.List.Name}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Revisions returns the revisions of a list and of its items, newest first.
+// The item_id query param narrows them to the revisions of a single item.
+func (h *APIHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var itemID uuid.UUID
+	if id := r.URL.Query().Get("item_id"); id != "" {
+		itemID, err = uuid.Parse(id)
+		if err != nil {
+			http.Error(w, "invalid ID", http.StatusBadRequest)
+			return
+		}
+	}
+	revisions, err := h.service.GetRevisions(r.Context(), listID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	found := []Revision{}
+	for _, revision := range revisions {
+		if itemID == uuid.Nil || revision.IsOf(itemID) {
+			found = append(found, revision)
+		}
+	}
+	json.NewEncoder(w).Encode(found)
+}
+
+func (h *APIHandler) ShowRevision(w http.ResponseWriter, r *http.Request) {
+	listID, revisionID, err := revisionIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := h.service.GetRevision(r.Context(), listID, revisionID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(revision)
+}
+
+// RestoreRevision brings the list or the item back to the state of a revision and returns the revision of the restore.
+func (h *APIHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	listID, revisionID, err := revisionIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	revision, err := h.service.RestoreRevision(r.Context(), listID, revisionID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(revision)
+}
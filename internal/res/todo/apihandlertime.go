@@ -0,0 +1,207 @@
+package todo
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// FindTime returns the time entries that pass the filter of the query params, with the names of their list, item and user.
+func (h *APIHandler) FindTime(w http.ResponseWriter, r *http.Request) {
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, err := ParseTimeFilter(r.URL.Query(), loc)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	records, err := h.service.FindTimeEntries(r.Context(), filter)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if records == nil {
+		records = []TimeRecord{}
+	}
+	json.NewEncoder(w).Encode(records)
+}
+
+// ExportTime sends the time entries that pass the filter of the query params as a CSV or JSON file, JSON by default.
+func (h *APIHandler) ExportTime(w http.ResponseWriter, r *http.Request) {
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, err := ParseTimeFilter(r.URL.Query(), loc)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	f := r.URL.Query().Get("format")
+	if f == "" {
+		f = FormatJSON
+	}
+	f, err = ParseFormat(f)
+	if err != nil || !slices.Contains(TimeFormats, f) {
+		http.Error(w, ErrInvalidFormat.Error(), http.StatusBadRequest)
+		return
+	}
+	records, err := h.service.FindTimeEntries(r.Context(), filter)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	writeTimeExport(w, f, records, loc)
+}
+
+// Timesheet returns the time the actor tracked in the week of the week query param, the current one when empty.
+func (h *APIHandler) Timesheet(w http.ResponseWriter, r *http.Request) {
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	day := time.Now().In(loc)
+	if week := r.URL.Query().Get("week"); week != "" {
+		day, err = time.ParseInLocation(time.DateOnly, week, loc)
+		if err != nil {
+			http.Error(w, "invalid week", http.StatusBadRequest)
+			return
+		}
+	}
+	sheet, err := h.service.GetTimesheet(r.Context(), day)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(sheet)
+}
+
+// RunningTimer returns the running timer of the actor.
+func (h *APIHandler) RunningTimer(w http.ResponseWriter, r *http.Request) {
+	timer, err := h.service.GetRunningTimer(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(timer)
+}
+
+// StopTimer stops the running timer of the actor and returns it.
+func (h *APIHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	timer, err := h.service.StopTimer(r.Context())
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(timer)
+}
+
+func (h *APIHandler) ItemTime(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	entries, err := h.service.GetTimeEntries(r.Context(), listID, itemID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	if entries == nil {
+		entries = []TimeEntry{}
+	}
+	json.NewEncoder(w).Encode(entries)
+}
+
+// StartTimer starts a timer of the actor on the item and returns it.
+func (h *APIHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload struct {
+		Notes string `json:"notes"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	timer, err := h.service.StartTimer(r.Context(), listID, itemID, payload.Notes)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(timer)
+}
+
+// AddTimeEntry stores a manual entry of the actor on the item.
+// The date is a YYYY-MM-DD day and spent is written as "1:30", "1.5" or "1h30m".
+func (h *APIHandler) AddTimeEntry(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	var payload struct {
+		Date  string `json:"date"`
+		Spent string `json:"spent"`
+		Notes string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	day, err := time.ParseInLocation(time.DateOnly, payload.Date, loc)
+	if err != nil {
+		http.Error(w, "invalid date", http.StatusBadRequest)
+		return
+	}
+	spent, err := ParseSpent(payload.Spent)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	entry, err := h.service.AddTimeEntry(r.Context(), NewManualEntry(listID, itemID, uuid.Nil, day, spent, payload.Notes))
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *APIHandler) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	entryID, err := uuid.Parse(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.DeleteTimeEntry(r.Context(), listID, entryID); err != nil {
+		apiErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
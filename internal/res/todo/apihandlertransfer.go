@@ -0,0 +1,60 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Export returns the list, or every visible list, in the format of the query, JSON when not given.
+func (h *APIHandler) Export(w http.ResponseWriter, r *http.Request) {
+	listID, err := optionalListID(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	f := r.URL.Query().Get("format")
+	if f == "" {
+		f = FormatJSON
+	}
+	f, err = ParseFormat(f)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	lists, err := h.service.ExportLists(r.Context(), listID)
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+	writeExport(w, f, lists)
+}
+
+// Import reads the request body in the format of the query and answers with the import report.
+// A dry run only reports, an import with problems is rejected with the report of its problems.
+func (h *APIHandler) Import(w http.ResponseWriter, r *http.Request) {
+	listID, err := optionalListID(r)
+	if err != nil {
+		http.Error(w, "invalid ID", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	f, err := ParseFormat(query.Get("format"))
+	if err != nil {
+		apiErr(w, err)
+		return
+	}
+
+	opts := ImportOptions{Format: f, ListID: listID, TimeZone: query.Get("time_zone"), DryRun: query.Get("dry_run") == "true"}
+	report, err := h.service.Import(r.Context(), opts, http.MaxBytesReader(w, r.Body, maxImportSize))
+	switch {
+	case errors.Is(err, ErrInvalidImport):
+		w.WriteHeader(http.StatusBadRequest)
+	case err != nil:
+		apiErr(w, err)
+		return
+	case !opts.DryRun:
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(report)
+}
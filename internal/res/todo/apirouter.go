@@ -8,11 +8,82 @@ import (
 func NewAPIRouter(handler *APIHandler, opts ...am.Option) *am.Router {
 	r := am.NewRouter("api-router", opts...)
 
-	r.Get("/", handler.List)          // GET /api/todo
-	r.Post("/", handler.Create)       // POST /api/todo
-	r.Get("/{id}", handler.Show)      // GET /api/todo/{id}
-	r.Put("/{id}", handler.Update)    // PUT /api/todo/{id}
-	r.Delete("/{id}", handler.Delete) // DELETE /api/todo/{id}
+	r.Get("/", handler.List)                                   // GET /api/todo
+	r.Get("/today", handler.Today)                             // GET /api/todo/today
+	r.Get("/upcoming", handler.Upcoming)                       // GET /api/todo/upcoming
+	r.Get("/overdue", handler.Overdue)                         // GET /api/todo/overdue
+	r.Get("/occurrences", handler.Occurrences)                 // GET /api/todo/occurrences
+	r.Get("/items", handler.FindItems)                         // GET /api/todo/items
+	r.Get("/assigned", handler.Assigned)                       // GET /api/todo/assigned
+	r.Get("/tags", handler.Tags)                               // GET /api/todo/tags
+	r.Get("/filters", handler.SavedFilters)                    // GET /api/todo/filters
+	r.Post("/filters", handler.SaveFilter)                     // POST /api/todo/filters
+	r.Delete("/filters/{filterID}", handler.DeleteSavedFilter) // DELETE /api/todo/filters/{filterID}
+	r.Get("/export", handler.Export)                           // GET /api/todo/export
+	r.Post("/import", handler.Import)                          // POST /api/todo/import
+	r.Get("/feed", handler.FeedToken)                          // GET /api/todo/feed
+	r.Post("/feed/token", handler.RegenerateFeedToken)         // POST /api/todo/feed/token
+	r.Get("/time", handler.FindTime)                           // GET /api/todo/time
+	r.Get("/time/export", handler.ExportTime)                  // GET /api/todo/time/export
+	r.Get("/timesheet", handler.Timesheet)                     // GET /api/todo/timesheet
+	r.Get("/timer", handler.RunningTimer)                      // GET /api/todo/timer
+	r.Post("/timer/stop", handler.StopTimer)                   // POST /api/todo/timer/stop
+	r.Post("/", handler.Create)                                // POST /api/todo
+	r.Get("/{id}", handler.Show)                               // GET /api/todo/{id}
+	r.Put("/{id}", handler.Update)                             // PUT /api/todo/{id}
+	r.Delete("/{id}", handler.Delete)                          // DELETE /api/todo/{id}
+	r.Post("/{id}/archive", handler.Archive)                   // POST /api/todo/{id}/archive
+	r.Post("/{id}/unarchive", handler.Unarchive)               // POST /api/todo/{id}/unarchive
+	r.Post("/{id}/template", handler.SaveAsTemplate)           // POST /api/todo/{id}/template
+	r.Post("/{id}/use", handler.UseTemplate)                   // POST /api/todo/{id}/use
+
+	r.Get("/{id}/export", handler.Export)                    // GET /api/todo/{id}/export
+	r.Post("/{id}/import", handler.Import)                   // POST /api/todo/{id}/import
+	r.Get("/{id}/board", handler.Board)                      // GET /api/todo/{id}/board
+	r.Put("/{id}/board/columns", handler.UpdateBoardColumns) // PUT /api/todo/{id}/board/columns
+	r.Put("/{id}/board/reorder", handler.ReorderItems)       // PUT /api/todo/{id}/board/reorder
+
+	r.Get("/{id}/shares", handler.Shares)                               // GET /api/todo/{id}/shares
+	r.Post("/{id}/shares", handler.Share)                               // POST /api/todo/{id}/shares
+	r.Delete("/{id}/shares/{granteeType}/{granteeID}", handler.Unshare) // DELETE /api/todo/{id}/shares/{granteeType}/{granteeID}
+
+	r.Get("/{id}/activity", handler.Activity)                     // GET /api/todo/{id}/activity
+	r.Get("/{id}/comments", handler.Comments)                     // GET /api/todo/{id}/comments
+	r.Post("/{id}/comments", handler.CreateComment)               // POST /api/todo/{id}/comments
+	r.Put("/{id}/comments/{commentID}", handler.UpdateComment)    // PUT /api/todo/{id}/comments/{commentID}
+	r.Delete("/{id}/comments/{commentID}", handler.DeleteComment) // DELETE /api/todo/{id}/comments/{commentID}
+
+	r.Get("/{id}/revisions", handler.Revisions)                             // GET /api/todo/{id}/revisions
+	r.Get("/{id}/revisions/{revisionID}", handler.ShowRevision)             // GET /api/todo/{id}/revisions/{revisionID}
+	r.Post("/{id}/revisions/{revisionID}/restore", handler.RestoreRevision) // POST /api/todo/{id}/revisions/{revisionID}/restore
+
+	r.Get("/{id}/items", handler.ListItems)                              // GET /api/todo/{id}/items
+	r.Post("/{id}/items", handler.CreateItem)                            // POST /api/todo/{id}/items
+	r.Post("/{id}/items/bulk", handler.BulkItems)                        // POST /api/todo/{id}/items/bulk
+	r.Get("/{id}/items/{itemID}", handler.ShowItem)                      // GET /api/todo/{id}/items/{itemID}
+	r.Put("/{id}/items/{itemID}", handler.UpdateItem)                    // PUT /api/todo/{id}/items/{itemID}
+	r.Delete("/{id}/items/{itemID}", handler.DeleteItem)                 // DELETE /api/todo/{id}/items/{itemID}
+	r.Post("/{id}/items/{itemID}/complete", handler.CompleteItem)        // POST /api/todo/{id}/items/{itemID}/complete
+	r.Post("/{id}/items/{itemID}/reopen", handler.ReopenItem)            // POST /api/todo/{id}/items/{itemID}/reopen
+	r.Post("/{id}/items/{itemID}/move", handler.MoveItem)                // POST /api/todo/{id}/items/{itemID}/move
+	r.Post("/{id}/items/{itemID}/skip", handler.SkipOccurrence)          // POST /api/todo/{id}/items/{itemID}/skip
+	r.Post("/{id}/items/{itemID}/end-recurrence", handler.EndRecurrence) // POST /api/todo/{id}/items/{itemID}/end-recurrence
+	r.Get("/{id}/items/{itemID}/comments", handler.Comments)             // GET /api/todo/{id}/items/{itemID}/comments
+	r.Post("/{id}/items/{itemID}/comments", handler.CreateComment)       // POST /api/todo/{id}/items/{itemID}/comments
+	r.Get("/{id}/items/{itemID}/occurrences", handler.ItemOccurrences)   // GET /api/todo/{id}/items/{itemID}/occurrences
+
+	r.Post("/{id}/items/{itemID}/assignees", handler.AssignItem)                                 // POST /api/todo/{id}/items/{itemID}/assignees
+	r.Delete("/{id}/items/{itemID}/assignees/{assigneeType}/{assigneeID}", handler.UnassignItem) // DELETE /api/todo/{id}/items/{itemID}/assignees/{assigneeType}/{assigneeID}
+
+	r.Get("/{id}/items/{itemID}/attachments", handler.Attachments)                        // GET /api/todo/{id}/items/{itemID}/attachments
+	r.Post("/{id}/items/{itemID}/attachments", handler.UploadAttachment)                  // POST /api/todo/{id}/items/{itemID}/attachments
+	r.Get("/{id}/items/{itemID}/attachments/{attachmentID}", handler.DownloadAttachment)  // GET /api/todo/{id}/items/{itemID}/attachments/{attachmentID}
+	r.Delete("/{id}/items/{itemID}/attachments/{attachmentID}", handler.DeleteAttachment) // DELETE /api/todo/{id}/items/{itemID}/attachments/{attachmentID}
+
+	r.Get("/{id}/items/{itemID}/time", handler.ItemTime)                     // GET /api/todo/{id}/items/{itemID}/time
+	r.Post("/{id}/items/{itemID}/time", handler.AddTimeEntry)                // POST /api/todo/{id}/items/{itemID}/time
+	r.Delete("/{id}/items/{itemID}/time/{entryID}", handler.DeleteTimeEntry) // DELETE /api/todo/{id}/items/{itemID}/time/{entryID}
+	r.Post("/{id}/items/{itemID}/timer", handler.StartTimer)                 // POST /api/todo/{id}/items/{itemID}/timer
 
 	return r
 }
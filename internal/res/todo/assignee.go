@@ -0,0 +1,125 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/google/uuid"
+)
+
+// Assigned views.
+const (
+	AssignedToMe    = "me"
+	AssignedToTeams = "teams"
+)
+
+// ErrInvalidAssignee is returned when an assignee is malformed or cannot see the list of the item.
+var ErrInvalidAssignee = errors.New("invalid assignee")
+
+// ErrAssigneeNotFound is returned when unassigning someone the item is not assigned to.
+var ErrAssigneeNotFound = errors.New("assignee not found")
+
+// Assignee is a user or a team an item is assigned to, the types are the ones of share grantees.
+type Assignee struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// ParseAssignee parses an assignee written as type:id, e.g. "team:<id>".
+func ParseAssignee(s string) (Assignee, error) {
+	kind, id, _ := strings.Cut(strings.TrimSpace(s), ":")
+	assigneeID, err := uuid.Parse(id)
+	if err != nil {
+		return Assignee{}, fmt.Errorf("%w: %q", ErrInvalidAssignee, s)
+	}
+	assignee := Assignee{Type: kind, ID: assigneeID}
+	if !assignee.Valid() {
+		return Assignee{}, fmt.Errorf("%w: %q", ErrInvalidAssignee, s)
+	}
+	return assignee, nil
+}
+
+// Valid reports whether the type is known and the ID is set.
+func (a Assignee) Valid() bool {
+	return (a.Type == GranteeUser || a.Type == GranteeTeam) && a.ID != uuid.Nil
+}
+
+// IsTeam reports whether the assignee is a team.
+func (a Assignee) IsTeam() bool {
+	return a.Type == GranteeTeam
+}
+
+func (a Assignee) String() string {
+	return a.Type + ":" + a.ID.String()
+}
+
+// formatAssignees writes assignees as a comma separated list of type:id.
+func formatAssignees(assignees []Assignee) string {
+	strs := make([]string, len(assignees))
+	for i, a := range assignees {
+		strs[i] = a.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+// parseAssignees reads what formatAssignees writes, malformed entries are skipped.
+func parseAssignees(s string) []Assignee {
+	var assignees []Assignee
+	for _, str := range strings.Split(s, ",") {
+		if a, err := ParseAssignee(str); err == nil {
+			assignees = append(assignees, a)
+		}
+	}
+	return assignees
+}
+
+// Assignment is the notification that an item was assigned to a user or a team.
+// Recipients are the assigned user or the members of the assigned team, without the one who made the assignment.
+type Assignment struct {
+	Item       Item
+	List       List
+	Assignee   Assignee
+	Name       string
+	AssignedBy uuid.UUID
+	Recipients []auth.User
+}
+
+// AssignmentNotifier delivers assignments.
+type AssignmentNotifier interface {
+	am.Core
+	NotifyAssignment(ctx context.Context, assignment Assignment) error
+}
+
+// AssignmentSwitch is the notifier selected in assignment.notifier among the available ones.
+type AssignmentSwitch struct {
+	am.Core
+	notifiers map[string]AssignmentNotifier
+	notifier  AssignmentNotifier
+}
+
+func NewAssignmentSwitch(notifiers map[string]AssignmentNotifier, opts ...am.Option) *AssignmentSwitch {
+	return &AssignmentSwitch{
+		Core:      am.NewCore("assignment-switch", opts...),
+		notifiers: notifiers,
+	}
+}
+
+// Setup selects the notifier, assignments are logged unless another one is configured.
+func (s *AssignmentSwitch) Setup(ctx context.Context) error {
+	name := s.Cfg().StrValOrDef(am.Key.AssignmentNotifier, NotifierLog)
+	notifier, ok := s.notifiers[name]
+	if !ok {
+		return fmt.Errorf("unknown assignment notifier: %s", name)
+	}
+	s.notifier = notifier
+	s.Log().Infof("Assignments are sent through the %s notifier", name)
+	return nil
+}
+
+func (s *AssignmentSwitch) NotifyAssignment(ctx context.Context, assignment Assignment) error {
+	return s.notifier.NotifyAssignment(ctx, assignment)
+}
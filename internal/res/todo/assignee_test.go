@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseAssignee(t *testing.T) {
+	id := uuid.New()
+	a, err := ParseAssignee(" team:" + id.String())
+	if err != nil || a.Type != GranteeTeam || a.ID != id {
+		t.Fatalf("expected team %s, got %+v (%v)", id, a, err)
+	}
+	if a.String() != "team:"+id.String() {
+		t.Errorf("expected it to print as it was written, got %q", a.String())
+	}
+
+	for _, s := range []string{"", "user", "user:nope", "org:" + id.String(), "user:" + uuid.Nil.String()} {
+		if _, err := ParseAssignee(s); !errors.Is(err, ErrInvalidAssignee) {
+			t.Errorf("ParseAssignee(%q): expected ErrInvalidAssignee, got %v", s, err)
+		}
+	}
+}
+
+func TestAssigneesRoundTrip(t *testing.T) {
+	assignees := []Assignee{{Type: GranteeUser, ID: uuid.New()}, {Type: GranteeTeam, ID: uuid.New()}}
+	got := parseAssignees(formatAssignees(assignees))
+	if len(got) != 2 || got[0] != assignees[0] || got[1] != assignees[1] {
+		t.Errorf("expected %v, got %v", assignees, got)
+	}
+	if got := parseAssignees(""); len(got) != 0 {
+		t.Errorf("expected no assignees, got %v", got)
+	}
+}
+
+func TestItemIsAssignedToAny(t *testing.T) {
+	userID, teamID, otherTeamID := uuid.New(), uuid.New(), uuid.New()
+	item := NewItem(uuid.New(), "Review", "")
+	item.Assignees = []Assignee{{Type: GranteeTeam, ID: teamID}}
+
+	if item.IsAssignedToAny(userID, nil) {
+		t.Error("expected the item not to be assigned to the user")
+	}
+	if !item.IsAssignedToAny(uuid.Nil, map[uuid.UUID]bool{teamID: true}) {
+		t.Error("expected the item to be assigned to one of the teams")
+	}
+	if item.IsAssignedToAny(uuid.Nil, map[uuid.UUID]bool{otherTeamID: true}) {
+		t.Error("expected the item not to be assigned to other teams")
+	}
+
+	item.Assignees = append(item.Assignees, Assignee{Type: GranteeUser, ID: userID})
+	if !item.IsAssignedToAny(userID, nil) || !item.IsAssignedTo(Assignee{Type: GranteeUser, ID: userID}) {
+		t.Error("expected the item to be assigned to the user")
+	}
+}
@@ -0,0 +1,153 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultAttachmentMaxSize is the largest attachment in bytes unless attachment.max.size says otherwise.
+	defaultAttachmentMaxSize = 10 << 20
+	// defaultAttachmentTypes are the content types accepted unless attachment.types says otherwise.
+	// Types that browsers run, such as HTML and SVG, are left out.
+	defaultAttachmentTypes = "image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain,application/zip"
+	// sniffSize is the number of bytes the content type is detected from.
+	sniffSize = 512
+	// maxAttachmentName is the longest file name kept, in characters.
+	maxAttachmentName = 200
+)
+
+var (
+	// ErrAttachmentNotFound is returned when the item has no attachment with the given ID.
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrAttachmentTooLarge is returned when an upload is over the size limit.
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentType is returned when the content of an upload is not of an accepted type.
+	ErrAttachmentType = errors.New("attachment type not allowed")
+)
+
+// Attachment is a file attached to an item, its content is kept in the blob store under Key.
+// ContentType is detected from the content, the one declared by the client is not trusted.
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	ListID      uuid.UUID `json:"list_id"`
+	ItemID      uuid.UUID `json:"item_id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Key         string    `json:"-"`
+	UploadedBy  uuid.UUID `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewAttachment creates an attachment of the item with a clean file name and the blob key it is stored under.
+func NewAttachment(listID, itemID uuid.UUID, name string) Attachment {
+	id := uuid.New()
+	return Attachment{
+		ID:     id,
+		ListID: listID,
+		ItemID: itemID,
+		Name:   AttachmentName(name),
+		Key:    fmt.Sprintf("%s/%s/%s", listID, itemID, id),
+	}
+}
+
+// IsImage reports whether the attachment can be shown as an image.
+func (a Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
+
+// IsInline reports whether the attachment is shown in the browser rather than downloaded.
+func (a Attachment) IsInline() bool {
+	return a.IsImage() || a.ContentType == "application/pdf"
+}
+
+// SizeText returns the size in human readable form.
+func (a Attachment) SizeText() string {
+	return sizeText(a.Size)
+}
+
+// AttachmentName returns the base name of an uploaded file without control characters.
+func AttachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if runes := []rune(name); len(runes) > maxAttachmentName {
+		name = string(runes[len(runes)-maxAttachmentName:])
+	}
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// AttachmentLimits are the size and the content types uploads are checked against.
+type AttachmentLimits struct {
+	MaxSize int64
+	Types   []string
+}
+
+// NewAttachmentLimits reads the limits from attachment.max.size and attachment.types, a comma separated
+// list of content types where image/* stands for every image type.
+func NewAttachmentLimits(cfg *am.Config) AttachmentLimits {
+	limits := AttachmentLimits{MaxSize: defaultAttachmentMaxSize}
+	types := defaultAttachmentTypes
+	if cfg != nil {
+		limits.MaxSize = cfg.IntVal(am.Key.AttachmentMaxSize, defaultAttachmentMaxSize)
+		types = cfg.StrValOrDef(am.Key.AttachmentTypes, defaultAttachmentTypes)
+	}
+	for _, t := range strings.Split(types, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			limits.Types = append(limits.Types, t)
+		}
+	}
+	return limits
+}
+
+// Allows reports whether the content type is accepted.
+func (l AttachmentLimits) Allows(contentType string) bool {
+	for _, t := range l.Types {
+		if t == contentType || strings.HasSuffix(t, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(t, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSizeText returns the size limit in human readable form.
+func (l AttachmentLimits) MaxSizeText() string {
+	return sizeText(l.MaxSize)
+}
+
+// SniffContentType detects the media type of the content from its first bytes, without parameters.
+func SniffContentType(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+func sizeText(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,179 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+)
+
+// Blob store names, the attachment.store config value selects the one attachments are kept in.
+const (
+	BlobStoreFS = "fs"
+	BlobStoreS3 = "s3"
+)
+
+const defaultBlobPath = "data/attachments"
+
+// ErrBlobNotFound is returned when the store has nothing under the key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Blob is the content of a stored file, seekable so it can be served in ranges.
+type Blob interface {
+	io.ReadSeekCloser
+}
+
+// BlobStore keeps the content of attachments under keys made of slash separated segments.
+type BlobStore interface {
+	am.Core
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (Blob, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobSwitch is the blob store selected in attachment.store among the available ones.
+// The service depends on it so the store can be chosen once the config is loaded.
+type BlobSwitch struct {
+	am.Core
+	stores map[string]BlobStore
+	store  BlobStore
+}
+
+func NewBlobSwitch(stores map[string]BlobStore, opts ...am.Option) *BlobSwitch {
+	return &BlobSwitch{
+		Core:   am.NewCore("blob-switch", opts...),
+		stores: stores,
+	}
+}
+
+// Setup selects the store, attachments are kept in the filesystem unless another one is configured.
+func (s *BlobSwitch) Setup(ctx context.Context) error {
+	name := s.Cfg().StrValOrDef(am.Key.AttachmentStore, BlobStoreFS)
+	store, ok := s.stores[name]
+	if !ok {
+		return fmt.Errorf("unknown attachment store: %s", name)
+	}
+	s.store = store
+	s.Log().Infof("Attachments are kept in the %s store", name)
+	return nil
+}
+
+func (s *BlobSwitch) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return s.store.Put(ctx, key, r, size, contentType)
+}
+
+func (s *BlobSwitch) Open(ctx context.Context, key string) (Blob, error) {
+	return s.store.Open(ctx, key)
+}
+
+func (s *BlobSwitch) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, key)
+}
+
+// FSBlobStore keeps blobs as files under the directory set in attachment.fs.path.
+type FSBlobStore struct {
+	am.Core
+	root string
+}
+
+func NewFSBlobStore(opts ...am.Option) *FSBlobStore {
+	return &FSBlobStore{
+		Core: am.NewCore("fs-blob-store", opts...),
+	}
+}
+
+func (s *FSBlobStore) Setup(ctx context.Context) error {
+	if s.root == "" {
+		s.root = s.Cfg().StrValOrDef(am.Key.AttachmentFSPath, defaultBlobPath)
+	}
+	return nil
+}
+
+// SetRoot sets the directory blobs are kept in, overriding the config.
+func (s *FSBlobStore) SetRoot(root string) {
+	s.root = root
+}
+
+// Put writes the blob to a temporary file first, so a failed upload never leaves a partial blob under the key.
+func (s *FSBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err == nil && size >= 0 && n != size {
+		err = fmt.Errorf("blob %s: wrote %d of %d bytes", key, n, size)
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FSBlobStore) Open(ctx context.Context, key string) (Blob, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return f, err
+}
+
+// Delete removes the blob, deleting a missing one is not an error.
+func (s *FSBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path maps the key to a file under the root, keys cannot point outside of it.
+func (s *FSBlobStore) path(key string) (string, error) {
+	if !validBlobKey(key) {
+		return "", fmt.Errorf("invalid blob key: %q", key)
+	}
+	root := s.root
+	if root == "" {
+		root = defaultBlobPath
+	}
+	return filepath.Join(root, filepath.FromSlash(key)), nil
+}
+
+func validBlobKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
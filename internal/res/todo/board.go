@@ -0,0 +1,224 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// positionGap is the distance between items when they are numbered anew, it leaves room to insert between them.
+	positionGap = 1024.0
+	// minPositionGap is the smallest distance between neighbors before a column is numbered anew.
+	minPositionGap = 1e-6
+)
+
+// ErrInvalidColumns is returned when the board columns are empty, repeat a status or use an unknown one.
+var ErrInvalidColumns = errors.New("invalid board columns")
+
+// BoardColumn is a column of the board of a list, it shows the items with its status.
+type BoardColumn struct {
+	Status Status `json:"status"`
+	Title  string `json:"title"`
+}
+
+// DefaultColumns returns a column for each status in workflow order.
+func DefaultColumns() []BoardColumn {
+	columns := make([]BoardColumn, len(Statuses))
+	for i, s := range Statuses {
+		columns[i] = BoardColumn{Status: s, Title: s.Label()}
+	}
+	return columns
+}
+
+// NormalizeColumns checks the columns and gives the untitled ones the label of their status.
+// Statuses without a column are hidden from the board.
+func NormalizeColumns(columns []BoardColumn) ([]BoardColumn, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: at least one column is needed", ErrInvalidColumns)
+	}
+
+	seen := map[Status]bool{}
+	result := make([]BoardColumn, len(columns))
+	for i, c := range columns {
+		if !c.Status.Valid() {
+			return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidColumns, c.Status)
+		}
+		if seen[c.Status] {
+			return nil, fmt.Errorf("%w: status %q is used twice", ErrInvalidColumns, c.Status)
+		}
+		seen[c.Status] = true
+		c.Title = strings.TrimSpace(c.Title)
+		if c.Title == "" {
+			c.Title = c.Status.Label()
+		}
+		result[i] = c
+	}
+	return result, nil
+}
+
+// BoardColumns returns the columns of the board of the list, the default ones if not configured.
+func (l List) BoardColumns() []BoardColumn {
+	if len(l.Columns) == 0 {
+		return DefaultColumns()
+	}
+	return l.Columns
+}
+
+// encodeColumns returns the columns as stored, empty for the default ones.
+func encodeColumns(columns []BoardColumn) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(columns)
+	return string(b)
+}
+
+func decodeColumns(s string) []BoardColumn {
+	var columns []BoardColumn
+	if s != "" {
+		_ = json.Unmarshal([]byte(s), &columns)
+	}
+	return columns
+}
+
+// Board is the board of a list, Hidden counts the items whose status has no column.
+type Board struct {
+	Lanes  []BoardLane
+	Hidden int
+}
+
+// BoardLane is a column of the board along with its items in position order.
+// Prev and Next are the statuses of the neighbor columns, empty at the ends.
+type BoardLane struct {
+	BoardColumn
+	Cards []BoardCard
+	Prev  Status
+	Next  Status
+}
+
+// BoardCard is an item on the board, Moves are the single steps it can take, they back the move buttons.
+type BoardCard struct {
+	ItemNode
+	Moves []BoardMove
+}
+
+// BoardMove is a move of a card to the index of the column of a status.
+type BoardMove struct {
+	Label  string
+	Status Status
+	Index  int
+}
+
+// BuildBoard places the items of the list in the columns of its board.
+func BuildBoard(list List, items []Item) Board {
+	columns := list.BoardColumns()
+	index := make(map[Status]int, len(columns))
+	board := Board{Lanes: make([]BoardLane, len(columns))}
+	for i, c := range columns {
+		index[c.Status] = i
+		board.Lanes[i].BoardColumn = c
+		if i > 0 {
+			board.Lanes[i].Prev = columns[i-1].Status
+		}
+		if i < len(columns)-1 {
+			board.Lanes[i].Next = columns[i+1].Status
+		}
+	}
+
+	nodes := BuildItemTree(items)
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Position < nodes[j].Position })
+	for _, node := range nodes {
+		i, ok := index[node.Status]
+		if !ok {
+			board.Hidden++
+			continue
+		}
+		node.Depth = 0
+		lane := &board.Lanes[i]
+		lane.Cards = append(lane.Cards, BoardCard{ItemNode: node})
+	}
+
+	for i := range board.Lanes {
+		lane := &board.Lanes[i]
+		for j := range lane.Cards {
+			lane.Cards[j].Moves = cardMoves(board, i, j)
+		}
+	}
+	return board
+}
+
+// cardMoves returns the moves of a card: to the end of the previous column, one place up or down and to the end of the next one.
+// Indexes are taken without the card, as MoveItem expects.
+func cardMoves(board Board, lane, card int) []BoardMove {
+	l := board.Lanes[lane]
+	var moves []BoardMove
+	if l.Prev != "" {
+		moves = append(moves, BoardMove{Label: "←", Status: l.Prev, Index: len(board.Lanes[lane-1].Cards)})
+	}
+	if card > 0 {
+		moves = append(moves, BoardMove{Label: "↑", Status: l.Status, Index: card - 1})
+	}
+	if card < len(l.Cards)-1 {
+		moves = append(moves, BoardMove{Label: "↓", Status: l.Status, Index: card + 1})
+	}
+	if l.Next != "" {
+		moves = append(moves, BoardMove{Label: "→", Status: l.Next, Index: len(board.Lanes[lane+1].Cards)})
+	}
+	return moves
+}
+
+// columnItems returns the items with the given status in position order, leaving out the one with the given ID.
+func columnItems(items []Item, status Status, except uuid.UUID) []Item {
+	var column []Item
+	for _, item := range items {
+		if item.Status == status && item.ID() != except {
+			column = append(column, item)
+		}
+	}
+	sort.SliceStable(column, func(i, j int) bool { return column[i].Position < column[j].Position })
+	return column
+}
+
+// checkBlockers fails with ErrItemBlocked when moving the items to the status, in order, completes one of them
+// while it waits on open items. An item can be unblocked by one completed before it.
+func checkBlockers(moved []Item, status Status, items []Item) error {
+	if status != StatusDone {
+		return nil
+	}
+	byID := itemsByID(items)
+	for _, item := range moved {
+		current, ok := byID[item.ID()]
+		if !ok || current.Done {
+			continue
+		}
+		if len(openBlockers(current, byID)) > 0 {
+			return ErrItemBlocked
+		}
+		current.Done = true
+		byID[current.ID()] = current
+	}
+	return nil
+}
+
+// positionAt returns the position that places an item at the index of the column, between its neighbors.
+// It reports false when the neighbors are too close and the column has to be numbered anew first.
+func positionAt(column []Item, index int) (float64, bool) {
+	switch {
+	case len(column) == 0:
+		return positionGap, true
+	case index <= 0:
+		return column[0].Position - positionGap, true
+	case index >= len(column):
+		return column[len(column)-1].Position + positionGap, true
+	}
+	prev, next := column[index-1].Position, column[index].Position
+	if next-prev < minPositionGap {
+		return 0, false
+	}
+	return prev + (next-prev)/2, true
+}
@@ -0,0 +1,112 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPositionAt(t *testing.T) {
+	column := []Item{{Position: 1024}, {Position: 2048}, {Position: 3072}}
+
+	cases := []struct {
+		name  string
+		index int
+		want  float64
+	}{
+		{"first", 0, 0},
+		{"before first", -1, 0},
+		{"between", 1, 1536},
+		{"last", 3, 4096},
+		{"past last", 10, 4096},
+	}
+
+	for _, tc := range cases {
+		got, ok := positionAt(column, tc.index)
+		if !ok || got != tc.want {
+			t.Errorf("%s: expected %v, got %v (%v)", tc.name, tc.want, got, ok)
+		}
+	}
+
+	if got, ok := positionAt(nil, 3); !ok || got != positionGap {
+		t.Errorf("empty column: expected %v, got %v (%v)", positionGap, got, ok)
+	}
+
+	crowded := []Item{{Position: 1}, {Position: 1 + minPositionGap/2}}
+	if _, ok := positionAt(crowded, 1); ok {
+		t.Error("expected crowded neighbors to need renumbering")
+	}
+}
+
+func TestBuildBoard(t *testing.T) {
+	listID := uuid.New()
+	list := NewList("board", "")
+	list.Columns = []BoardColumn{{Status: StatusTodo, Title: "Backlog"}, {Status: StatusDone, Title: "Shipped"}}
+
+	a := newTestItem(listID, "a")
+	b := newTestItem(listID, "b")
+	c := newTestItem(listID, "c")
+	d := newTestItem(listID, "d")
+	a.Position, b.Position = 2, 1
+	c.Status = StatusDone
+	d.Status = StatusWaiting
+
+	board := BuildBoard(list, []Item{a, b, c, d})
+
+	if len(board.Lanes) != 2 || board.Hidden != 1 {
+		t.Fatalf("expected 2 lanes and 1 hidden item, got %d and %d", len(board.Lanes), board.Hidden)
+	}
+	todo := board.Lanes[0]
+	if todo.Title != "Backlog" || len(todo.Cards) != 2 || todo.Cards[0].Title != "b" {
+		t.Errorf("unexpected todo lane: %+v", todo)
+	}
+	if todo.Prev != "" || todo.Next != StatusDone {
+		t.Errorf("expected todo lane neighbors none and done, got %q and %q", todo.Prev, todo.Next)
+	}
+
+	moves := todo.Cards[0].Moves
+	if len(moves) != 2 || moves[0].Status != StatusTodo || moves[0].Index != 1 || moves[1].Status != StatusDone || moves[1].Index != 1 {
+		t.Errorf("unexpected moves of the first card: %+v", moves)
+	}
+}
+
+func TestNormalizeColumns(t *testing.T) {
+	columns, err := NormalizeColumns([]BoardColumn{{Status: StatusDone}})
+	if err != nil || columns[0].Title != StatusDone.Label() {
+		t.Errorf("expected untitled column to take the status label, got %+v (%v)", columns, err)
+	}
+
+	invalid := [][]BoardColumn{
+		nil,
+		{{Status: "later"}},
+		{{Status: StatusTodo}, {Status: StatusTodo}},
+	}
+	for _, columns := range invalid {
+		if _, err := NormalizeColumns(columns); !errors.Is(err, ErrInvalidColumns) {
+			t.Errorf("%+v: expected %v, got %v", columns, ErrInvalidColumns, err)
+		}
+	}
+}
+
+func TestCheckBlockers(t *testing.T) {
+	first := NewItem(uuid.New(), "first", "")
+	first.GenID()
+	second := NewItem(first.ListID, "second", "")
+	second.GenID()
+	second.BlockedBy = []uuid.UUID{first.ID()}
+	items := []Item{first, second}
+
+	if err := checkBlockers([]Item{second}, StatusDone, items); !errors.Is(err, ErrItemBlocked) {
+		t.Errorf("expected the blocked item to be refused, got %v", err)
+	}
+	if err := checkBlockers([]Item{first, second}, StatusDone, items); err != nil {
+		t.Errorf("expected the blocker completed first to unblock the item, got %v", err)
+	}
+	if err := checkBlockers([]Item{second, first}, StatusDone, items); !errors.Is(err, ErrItemBlocked) {
+		t.Errorf("expected the blocker completed after the item not to unblock it, got %v", err)
+	}
+	if err := checkBlockers([]Item{second}, StatusInProgress, items); err != nil {
+		t.Errorf("expected moves to open statuses to ignore blockers, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Bulk item commands.
+const (
+	BulkComplete = "complete"
+	BulkMove     = "move"
+	BulkTag      = "tag"
+	BulkDelete   = "delete"
+)
+
+// ErrInvalidBulk is returned when a bulk command is unknown or misses one of its arguments.
+var ErrInvalidBulk = errors.New("invalid bulk command")
+
+// BulkCommand is a command applied to several items of a list at once.
+// Status is the board column items are moved to and Tag the tag they get, Force completes blocked items.
+type BulkCommand struct {
+	Command string      `json:"command"`
+	ItemIDs []uuid.UUID `json:"item_ids"`
+	Status  Status      `json:"status,omitempty"`
+	Tag     string      `json:"tag,omitempty"`
+	Force   bool        `json:"force,omitempty"`
+}
+
+// Validate checks the command is known and has what it needs, the tag is normalized.
+func (c *BulkCommand) Validate() error {
+	switch c.Command {
+	case BulkComplete, BulkDelete:
+	case BulkMove:
+		if !c.Status.Valid() {
+			return fmt.Errorf("%w: %s", ErrInvalidStatus, c.Status)
+		}
+	case BulkTag:
+		tag, err := NormalizeTag(c.Tag)
+		if err != nil {
+			return err
+		}
+		c.Tag = tag
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidBulk, c.Command)
+	}
+	if len(c.ItemIDs) == 0 {
+		return fmt.Errorf("%w: no items selected", ErrInvalidBulk)
+	}
+	return nil
+}
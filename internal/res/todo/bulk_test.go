@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestBulkCommandValidate(t *testing.T) {
+	ids := []uuid.UUID{uuid.New()}
+	tests := []struct {
+		name string
+		cmd  BulkCommand
+		want error
+	}{
+		{"complete", BulkCommand{Command: BulkComplete, ItemIDs: ids}, nil},
+		{"move", BulkCommand{Command: BulkMove, ItemIDs: ids, Status: StatusWaiting}, nil},
+		{"move without status", BulkCommand{Command: BulkMove, ItemIDs: ids}, ErrInvalidStatus},
+		{"tag", BulkCommand{Command: BulkTag, ItemIDs: ids, Tag: "#Home"}, nil},
+		{"tag without tag", BulkCommand{Command: BulkTag, ItemIDs: ids}, ErrInvalidTag},
+		{"unknown", BulkCommand{Command: "archive", ItemIDs: ids}, ErrInvalidBulk},
+		{"no items", BulkCommand{Command: BulkDelete}, ErrInvalidBulk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.Validate()
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	cmd := BulkCommand{Command: BulkTag, ItemIDs: ids, Tag: " #Home Office "}
+	if err := cmd.Validate(); err != nil || cmd.Tag != "home-office" {
+		t.Errorf("expected the tag to be normalized, got %q (%v)", cmd.Tag, err)
+	}
+}
+
+func TestRepoTxRollback(t *testing.T) {
+	repo := NewRepo(nil)
+	ctx := context.Background()
+	kept := NewItem(uuid.New(), "Kept", "")
+	kept.GenID()
+	if err := repo.CreateItem(ctx, kept); err != nil {
+		t.Fatal(err)
+	}
+
+	batch, err := am.RunBatch(ctx, repo.BeginTx, BulkDelete, []uuid.UUID{kept.ID(), uuid.New()}, func(ctx context.Context, id uuid.UUID) (string, error) {
+		if _, ok := memTxFromContext(ctx); !ok {
+			t.Error("expected the transaction in the context")
+		}
+		if _, ok := am.TxFromContext(ctx); ok {
+			t.Error("expected the transaction out of the reach of the directory")
+		}
+		return "", repo.DeleteItem(ctx, id)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.Applied || len(batch.Results) != 2 || !batch.Results[0].OK || batch.Results[1].OK {
+		t.Fatalf("expected the first delete to succeed and the second to fail, got %+v", batch)
+	}
+	if _, err := repo.GetItem(ctx, kept.ID()); err != nil {
+		t.Errorf("expected the delete to be rolled back, got %v", err)
+	}
+
+	batch, err = am.RunBatch(ctx, repo.BeginTx, BulkDelete, []uuid.UUID{kept.ID()}, func(ctx context.Context, id uuid.UUID) (string, error) {
+		return "", repo.DeleteItem(ctx, id)
+	})
+	if err != nil || !batch.Applied {
+		t.Fatalf("expected the batch to be applied, got %+v (%v)", batch, err)
+	}
+	if _, err := repo.GetItem(ctx, kept.ID()); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("expected the item to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxCommentSize is the longest comment body in characters.
+const maxCommentSize = 10000
+
+// ErrInvalidComment is returned when a comment is empty or too long.
+var ErrInvalidComment = errors.New("invalid comment")
+
+// ErrCommentNotFound is returned when the list has no comment with the given ID.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// Comment is a Markdown message on a list, or on one of its items when ItemID is set.
+// Mentions are the users named as @username in the body when it was written.
+type Comment struct {
+	ID        uuid.UUID   `json:"id"`
+	ListID    uuid.UUID   `json:"list_id"`
+	ItemID    uuid.UUID   `json:"item_id"`
+	AuthorID  uuid.UUID   `json:"author_id"`
+	Body      string      `json:"body"`
+	Mentions  []uuid.UUID `json:"mentions,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// NewComment creates a comment on the list, or on the item when itemID is not Nil.
+func NewComment(listID, itemID uuid.UUID, body string) Comment {
+	return Comment{
+		ID:     uuid.New(),
+		ListID: listID,
+		ItemID: itemID,
+		Body:   strings.TrimSpace(body),
+	}
+}
+
+// Validate checks that the body is not empty and not longer than maxCommentSize.
+func (c Comment) Validate() error {
+	switch n := utf8.RuneCountInString(strings.TrimSpace(c.Body)); {
+	case n == 0:
+		return fmt.Errorf("%w: the comment is empty", ErrInvalidComment)
+	case n > maxCommentSize:
+		return fmt.Errorf("%w: the comment is longer than %d characters", ErrInvalidComment, maxCommentSize)
+	}
+	return nil
+}
+
+// IsEdited reports whether the comment was changed after it was written.
+func (c Comment) IsEdited() bool {
+	return c.UpdatedAt.After(c.CreatedAt)
+}
+
+// IsOnItem reports whether the comment is about an item rather than the list.
+func (c Comment) IsOnItem() bool {
+	return c.ItemID != uuid.Nil
+}
+
+// HTML renders the body, marking the mentions of the given user names.
+func (c Comment) HTML(usernames map[uuid.UUID]string) template.HTML {
+	mentioned := make(map[string]bool, len(c.Mentions))
+	for _, id := range c.Mentions {
+		if name, ok := usernames[id]; ok {
+			mentioned[strings.ToLower(name)] = true
+		}
+	}
+	return RenderMarkdown(c.Body, func(name string) bool { return mentioned[strings.ToLower(name)] })
+}
+
+// Event kinds, the changes recorded in the activity of a list.
+const (
+	EventCreated    = "created"
+	EventRenamed    = "renamed"
+	EventCompleted  = "completed"
+	EventReopened   = "reopened"
+	EventReassigned = "reassigned"
+	EventDeleted    = "deleted"
+	EventArchived   = "archived"
+	EventUnarchived = "unarchived"
+)
+
+// Event is a change made to a list or, when ItemID is set, to one of its items.
+// Subject is the name of the list or the title of the item when the change was made,
+// From and To hold the previous and the new value of a rename or a reassignment.
+type Event struct {
+	ID      uuid.UUID `json:"id"`
+	ListID  uuid.UUID `json:"list_id"`
+	ItemID  uuid.UUID `json:"item_id"`
+	ActorID uuid.UUID `json:"actor_id"`
+	Kind    string    `json:"kind"`
+	Subject string    `json:"subject"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// NewEvent creates an event of the list, or of the item when itemID is not Nil.
+func NewEvent(listID, itemID uuid.UUID, kind, subject string) Event {
+	return Event{
+		ID:      uuid.New(),
+		ListID:  listID,
+		ItemID:  itemID,
+		Kind:    kind,
+		Subject: subject,
+		At:      time.Now(),
+	}
+}
+
+// IsOnItem reports whether the event is a change of an item rather than of the list.
+func (e Event) IsOnItem() bool {
+	return e.ItemID != uuid.Nil
+}
+
+// Text describes the change, without its actor.
+func (e Event) Text() string {
+	what := "the list"
+	if e.IsOnItem() {
+		what = fmt.Sprintf("%q", e.Subject)
+	}
+	switch e.Kind {
+	case EventCreated:
+		return "created " + what
+	case EventRenamed:
+		return fmt.Sprintf("renamed %q to %q", e.From, e.To)
+	case EventCompleted:
+		return "completed " + what
+	case EventReopened:
+		return "reopened " + what
+	case EventReassigned:
+		if e.To == "" {
+			return fmt.Sprintf("unassigned %s from %s", what, e.From)
+		}
+		return fmt.Sprintf("assigned %s to %s", what, e.To)
+	case EventDeleted:
+		return "deleted " + what
+	}
+	return e.Kind + " " + what
+}
+
+// Activity types.
+const (
+	ActivityComment = "comment"
+	ActivityEvent   = "event"
+)
+
+// Activity is an entry of the timeline of a list, either a comment or an event.
+type Activity struct {
+	Type    string    `json:"type"`
+	At      time.Time `json:"at"`
+	Comment *Comment  `json:"comment,omitempty"`
+	Event   *Event    `json:"event,omitempty"`
+}
+
+// ActorID returns the author of the comment or the actor of the event.
+func (a Activity) ActorID() uuid.UUID {
+	if a.Comment != nil {
+		return a.Comment.AuthorID
+	}
+	return a.Event.ActorID
+}
+
+// BuildTimeline merges comments and events, newest first.
+// A comment is placed at the time it was written, edits do not move it.
+func BuildTimeline(comments []Comment, events []Event) []Activity {
+	timeline := make([]Activity, 0, len(comments)+len(events))
+	for i := range comments {
+		timeline = append(timeline, Activity{Type: ActivityComment, At: comments[i].CreatedAt, Comment: &comments[i]})
+	}
+	for i := range events {
+		timeline = append(timeline, Activity{Type: ActivityEvent, At: events[i].At, Event: &events[i]})
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].At.After(timeline[j].At) })
+	return timeline
+}
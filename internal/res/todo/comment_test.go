@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"paragraphs", "one\ntwo\n\nthree", "<p>one<br>two</p><p>three</p>"},
+		{"emphasis", "**bold** and *it* and snake_case_name", "<p><strong>bold</strong> and <em>it</em> and snake_case_name</p>"},
+		{"code", "run `a < b`", "<p>run <code>a &lt; b</code></p>"},
+		{"list", "- one\n- two", "<ul><li>one</li><li>two</li></ul>"},
+		{"ordered", "1. one\n2. two", "<ol><li>one</li><li>two</li></ol>"},
+		{"fence", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;\n</code></pre>"},
+		{"link", "[docs](https://example.com/a?b=1&c=2)", `<p><a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">docs</a></p>`},
+		{"bare url", "see https://example.com.", `<p>see <a href="https://example.com" rel="nofollow noopener">https://example.com</a>.</p>`},
+		{"mention", "ping @ana and @bob", `<p>ping <span class="mention">@ana</span> and @bob</p>`},
+	}
+	isMention := func(name string) bool { return name == "ana" }
+	for _, tt := range tests {
+		got := string(RenderMarkdown(tt.src, isMention))
+		if got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestRenderMarkdownIsSafe(t *testing.T) {
+	inputs := []string{
+		`<script>alert(1)</script>`,
+		`[x](javascript:alert(1))`,
+		`[x](https://a.com" onclick="alert(1))`,
+		`<img src=x onerror=alert(1)>`,
+		"**<b>**",
+		`> <iframe src="https://evil">`,
+	}
+	for _, in := range inputs {
+		got := string(RenderMarkdown(in, nil))
+		for _, bad := range []string{"<script", "<img", "<iframe", "<b>", `href="javascript`, `" onclick`} {
+			if strings.Contains(got, bad) {
+				t.Errorf("%q rendered unsafe %q", in, got)
+			}
+		}
+	}
+}
+
+func TestMentions(t *testing.T) {
+	got := Mentions("@ana, thanks. cc @Bob.\nmail me at me@example.com\n`@code` @ana\n```\n@fenced\n```")
+	want := []string{"ana", "Bob"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBuildTimeline(t *testing.T) {
+	listID := uuid.New()
+	now := time.Now()
+
+	c := NewComment(listID, uuid.Nil, "hello")
+	c.CreatedAt = now.Add(-time.Minute)
+	c.UpdatedAt = now
+	created := NewEvent(listID, uuid.Nil, EventCreated, "Plan")
+	created.At = now.Add(-time.Hour)
+	done := NewEvent(listID, uuid.New(), EventCompleted, "Ship")
+	done.At = now.Add(-time.Second)
+
+	timeline := BuildTimeline([]Comment{c}, []Event{created, done})
+	if len(timeline) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(timeline))
+	}
+	if timeline[0].Event == nil || timeline[0].Event.Kind != EventCompleted {
+		t.Errorf("expected the completion first, got %+v", timeline[0])
+	}
+	if timeline[1].Comment == nil || !timeline[1].Comment.IsEdited() {
+		t.Errorf("expected the edited comment second, got %+v", timeline[1])
+	}
+	if timeline[2].Event == nil || timeline[2].Event.Text() != "created the list" {
+		t.Errorf("expected the list creation last, got %+v", timeline[2])
+	}
+}
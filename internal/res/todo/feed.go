@@ -0,0 +1,272 @@
+package todo
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Feed kinds, calendar clients that ignore tasks can subscribe to the items as events.
+const (
+	FeedTodos  = "todo"
+	FeedEvents = "event"
+)
+
+const (
+	// feedPath is the action of the feed page and the prefix of the calendar URLs.
+	feedPath = "feed"
+	// feedTokenSize is the number of random bytes of a feed token.
+	feedTokenSize = 24
+	// icalLineSize is the longest line of a calendar in octets, longer ones are folded.
+	icalLineSize = 75
+	icalProdID   = "-//aquamarinepk//todo//EN"
+)
+
+// ErrFeedNotFound is returned when no user has the given feed token, as happens once it is regenerated.
+var ErrFeedNotFound = errors.New("feed not found")
+
+// FeedToken is the secret that grants access to the calendar feed of a user.
+// The feed shows what the user can see in the org the token was created in, all of them when OrgID is Nil.
+type FeedToken struct {
+	UserID    uuid.UUID `json:"user_id"`
+	OrgID     uuid.UUID `json:"org_id"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewFeedToken creates a random feed token for the user.
+func NewFeedToken(userID, orgID uuid.UUID) (FeedToken, error) {
+	b := make([]byte, feedTokenSize)
+	_, err := rand.Read(b)
+	if err != nil {
+		return FeedToken{}, err
+	}
+	return FeedToken{
+		UserID:    userID,
+		OrgID:     orgID,
+		Token:     base64.RawURLEncoding.EncodeToString(b),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Path returns the web path of the calendar of the token, items are given as tasks unless kind asks for events.
+func (t FeedToken) Path(kind string) string {
+	path := todoResPath + "/" + feedPath + "/" + t.Token + ".ics"
+	if kind == FeedEvents {
+		path += "?kind=" + FeedEvents
+	}
+	return path
+}
+
+// Feed is the calendar of a user: the items with a due time in the lists they can see, earliest first.
+type Feed struct {
+	Token FeedToken
+	Items []DueItem
+}
+
+// ETag returns the entity tag of the calendar of the given kind.
+// It changes with any item or list version, so clients polling an unchanged feed get a 304 without it being written.
+func (f Feed) ETag(kind string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", f.Token.Token, kind)
+	for _, item := range f.Items {
+		fmt.Fprintf(h, "%s %d %s %d\n", item.ID(), item.Version(), item.ListID, item.List.Version())
+	}
+	return strconv.Quote(hex.EncodeToString(h.Sum(nil))[:32])
+}
+
+// WriteCalendar writes the feed as an iCalendar, each item as a VTODO or, for the events kind, as a VEVENT.
+// Times keep the time zone of their item as an IANA TZID, which calendar clients resolve without a VTIMEZONE.
+// Only the open occurrence of a recurring item carries the rule, the done ones are written as single entries.
+func WriteCalendar(w io.Writer, feed Feed, kind string) error {
+	cw := &icalWriter{w: bufio.NewWriter(w)}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", icalProdID)
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("X-WR-CALNAME", icalText("Todo"))
+	for _, item := range feed.Items {
+		if kind == FeedEvents {
+			writeEvent(cw, item)
+		} else {
+			writeTodo(cw, item)
+		}
+	}
+	cw.line("END", "VCALENDAR")
+	return cw.flush()
+}
+
+func writeTodo(cw *icalWriter, item DueItem) {
+	cw.line("BEGIN", "VTODO")
+	writeCommon(cw, item, item.Title)
+	rrule := feedRRule(item.Item)
+	switch {
+	case item.HasStart():
+		cw.time("DTSTART", item.StartAt, item.TimeZone)
+	case rrule != "":
+		// Recurrences are defined from DTSTART, the due time stands for it when the item has no start.
+		cw.time("DTSTART", item.DueAt, item.TimeZone)
+	}
+	cw.time("DUE", item.DueAt, item.TimeZone)
+	if rrule != "" {
+		cw.line("RRULE", rrule)
+	}
+	if item.Priority != PriorityNone {
+		cw.line("PRIORITY", strconv.Itoa(icalPriority(item.Priority)))
+	}
+	cw.line("STATUS", icalStatus(item.Status))
+	if item.Done {
+		cw.line("PERCENT-COMPLETE", "100")
+		if !item.CompletedAt.IsZero() {
+			cw.time("COMPLETED", item.CompletedAt, "")
+		}
+	}
+	if item.HasParent() {
+		cw.line("RELATED-TO", icalUID(item.ParentID))
+	}
+	cw.line("END", "VTODO")
+}
+
+// writeEvent writes the item as an event from its start, or its due time when it has none, to its due time.
+// Events have no completion, done items are marked in their summary.
+func writeEvent(cw *icalWriter, item DueItem) {
+	summary := item.Title
+	if item.Done {
+		summary = "✓ " + summary
+	}
+	cw.line("BEGIN", "VEVENT")
+	writeCommon(cw, item, summary)
+	if item.HasStart() && item.StartAt.Before(item.DueAt) {
+		cw.time("DTSTART", item.StartAt, item.TimeZone)
+		cw.time("DTEND", item.DueAt, item.TimeZone)
+	} else {
+		cw.time("DTSTART", item.DueAt, item.TimeZone)
+	}
+	if rrule := feedRRule(item.Item); rrule != "" {
+		cw.line("RRULE", rrule)
+	}
+	cw.line("TRANSP", "TRANSPARENT")
+	cw.line("END", "VEVENT")
+}
+
+func writeCommon(cw *icalWriter, item DueItem, summary string) {
+	stamp := item.UpdatedAt()
+	if stamp.IsZero() {
+		stamp = item.CreatedAt()
+	}
+	cw.line("UID", icalUID(item.ID()))
+	cw.time("DTSTAMP", stamp, "")
+	if !item.CreatedAt().IsZero() {
+		cw.time("CREATED", item.CreatedAt(), "")
+		cw.time("LAST-MODIFIED", stamp, "")
+	}
+	cw.line("SUMMARY", icalText(summary))
+	if item.Notes != "" {
+		cw.line("DESCRIPTION", icalText(item.Notes))
+	}
+	categories := []string{icalText(item.List.Name)}
+	for _, tag := range item.Tags {
+		categories = append(categories, icalText(tag))
+	}
+	cw.line("CATEGORIES", strings.Join(categories, ","))
+}
+
+// feedRRule returns the rule of the open occurrence of a series, its COUNT reduced by the occurrences before it.
+func feedRRule(item Item) string {
+	if !item.IsRecurring() || item.Done {
+		return ""
+	}
+	r, err := item.Recurrence()
+	if err != nil {
+		return ""
+	}
+	if r.Count > 0 {
+		r.Count -= item.OccurrenceIndex() - 1
+		if r.Count < 1 {
+			return ""
+		}
+	}
+	return r.String()
+}
+
+func icalUID(id uuid.UUID) string {
+	return id.String() + "@todo"
+}
+
+// icalPriority maps P1 to P4 on the iCalendar scale where 1 is the highest and 9 the lowest.
+func icalPriority(p Priority) int {
+	return 1 + 2*int(p-Priority1)
+}
+
+func icalStatus(s Status) string {
+	switch s {
+	case StatusDone:
+		return "COMPLETED"
+	case StatusInProgress:
+		return "IN-PROCESS"
+	}
+	return "NEEDS-ACTION"
+}
+
+// icalText escapes a TEXT value.
+func icalText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+	return r.Replace(s)
+}
+
+// icalWriter writes content lines ended in CRLF and folded at icalLineSize octets.
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (cw *icalWriter) line(name, value string) {
+	s := name + ":" + value
+	for len(s) > icalLineSize {
+		cut := icalLineSize
+		// Folds must not split a UTF-8 sequence.
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		cw.write(s[:cut] + "\r\n")
+		s = " " + s[cut:]
+	}
+	cw.write(s + "\r\n")
+}
+
+// time writes a date-time in UTC, or as local time of the zone when it is not UTC.
+func (cw *icalWriter) time(name string, t time.Time, zone string) {
+	if zone == "" || zone == "UTC" {
+		cw.line(name, t.UTC().Format("20060102T150405Z"))
+		return
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		cw.line(name, t.UTC().Format("20060102T150405Z"))
+		return
+	}
+	cw.line(name+";TZID="+zone, t.In(loc).Format("20060102T150405"))
+}
+
+func (cw *icalWriter) write(s string) {
+	if cw.err == nil {
+		_, cw.err = cw.w.WriteString(s)
+	}
+}
+
+func (cw *icalWriter) flush() error {
+	if cw.err != nil {
+		return cw.err
+	}
+	return cw.w.Flush()
+}
@@ -0,0 +1,72 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+func TestICalText(t *testing.T) {
+	got := icalText("Buy milk, eggs; bread\\butter\r\nthen cook")
+	want := `Buy milk\, eggs\; bread\\butter\nthen cook`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestICalFolding(t *testing.T) {
+	var buf bytes.Buffer
+	cw := &icalWriter{w: bufio.NewWriter(&buf)}
+	cw.line("SUMMARY", strings.Repeat("é", 60))
+	if err := cw.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected a folded line, got %q", buf.String())
+	}
+	var unfolded strings.Builder
+	for i, line := range lines {
+		if len(line) > icalLineSize {
+			t.Errorf("line %d has %d octets", i, len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+		}
+		if i > 0 {
+			line = strings.TrimPrefix(line, " ")
+		}
+		unfolded.WriteString(line)
+	}
+	if unfolded.String() != "SUMMARY:"+strings.Repeat("é", 60) {
+		t.Errorf("unexpected unfolded line %q", unfolded.String())
+	}
+}
+
+func TestFeedRRule(t *testing.T) {
+	item := newTestItem(uuid.New(), "Water plants")
+	item.DueAt = time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	item.RRule = "FREQ=WEEKLY;COUNT=5"
+
+	item.Occurrence = 3
+	if got := feedRRule(item); !strings.Contains(got, "COUNT=3") {
+		t.Errorf("expected the 3 remaining occurrences, got %q", got)
+	}
+
+	item.Occurrence = 6
+	if got := feedRRule(item); got != "" {
+		t.Errorf("expected no rule past the count, got %q", got)
+	}
+
+	item.Occurrence = 1
+	item.Done = true
+	if got := feedRRule(item); got != "" {
+		t.Errorf("expected no rule for a done occurrence, got %q", got)
+	}
+}
@@ -0,0 +1,362 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+const (
+	itemType = "item"
+)
+
+// ErrInvalidTimeZone is returned when an item uses a time zone that is not a valid IANA name.
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
+// ErrInvalidItem is returned when an item fails validation.
+var ErrInvalidItem = errors.New("invalid item")
+
+// ErrInvalidOffset is returned when a reminder offset cannot be parsed.
+var ErrInvalidOffset = errors.New("invalid reminder offset")
+
+// Item is an entry of a todo list.
+// Due and start times are absolute instants, TimeZone is the zone they were entered in and the one used to show them.
+// Reminders are offsets before the due time, RemindedAt is the latest reminder time already notified.
+// Recurring items have an RRULE, each occurrence is an item of its own linked to the others by SeriesID
+// and numbered by Occurrence starting at 1.
+// ParentID nests the item as a subtask of another one, BlockedBy lists the items that must be done before it.
+// Status follows the item through its workflow, it is done exactly when Done is set. Tags are normalized.
+// Position orders the items of a board column, lower first.
+// Assignees are the users and teams the item is assigned to, changed only through assignment.
+type Item struct {
+	*am.BaseModel
+	ListID      uuid.UUID   `json:"list_id"`
+	Title       string      `json:"title"`
+	Notes       string      `json:"notes"`
+	Done        bool        `json:"done"`
+	CompletedAt time.Time   `json:"completed_at"`
+	StartAt     time.Time   `json:"start_at"`
+	DueAt       time.Time   `json:"due_at"`
+	TimeZone    string      `json:"time_zone"`
+	Reminders   []Offset    `json:"reminders"`
+	RemindedAt  time.Time   `json:"reminded_at"`
+	RRule       string      `json:"rrule"`
+	SeriesID    uuid.UUID   `json:"series_id"`
+	Occurrence  int         `json:"occurrence"`
+	ParentID    uuid.UUID   `json:"parent_id"`
+	BlockedBy   []uuid.UUID `json:"blocked_by"`
+	Tags        []string    `json:"tags"`
+	Priority    Priority    `json:"priority"`
+	Status      Status      `json:"status"`
+	Position    float64     `json:"position"`
+	Assignees   []Assignee  `json:"assignees"`
+}
+
+// IsAssignedTo reports whether the item is assigned to the given assignee.
+func (i Item) IsAssignedTo(assignee Assignee) bool {
+	for _, a := range i.Assignees {
+		if a == assignee {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAssignedToAny reports whether the item is assigned to the user or to one of the given teams.
+func (i Item) IsAssignedToAny(userID uuid.UUID, teamIDs map[uuid.UUID]bool) bool {
+	for _, a := range i.Assignees {
+		if a.Type == GranteeUser && a.ID == userID || a.IsTeam() && teamIDs[a.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// NewItem creates a new item in the list.
+func NewItem(listID uuid.UUID, title, notes string) Item {
+	return Item{
+		BaseModel: am.NewModel(am.WithType(itemType)),
+		ListID:    listID,
+		Title:     title,
+		Notes:     notes,
+		TimeZone:  "UTC",
+		Status:    StatusTodo,
+	}
+}
+
+// MarshalJSON includes the identity and version of the item, API clients need both to address it.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type Alias Item
+	return json.Marshal(struct {
+		ID      uuid.UUID `json:"id"`
+		Version int       `json:"version"`
+		Alias
+	}{
+		ID:      i.ID(),
+		Version: i.Version(),
+		Alias:   Alias(i),
+	})
+}
+
+// Location returns the time zone of the item, UTC when it is not set or not valid.
+func (i Item) Location() *time.Location {
+	loc, err := time.LoadLocation(i.TimeZone)
+	if err != nil || i.TimeZone == "" {
+		return time.UTC
+	}
+	return loc
+}
+
+// HasDue reports whether the item has a due time.
+func (i Item) HasDue() bool {
+	return !i.DueAt.IsZero()
+}
+
+// HasStart reports whether the item has a start time.
+func (i Item) HasStart() bool {
+	return !i.StartAt.IsZero()
+}
+
+// LocalDue returns the due time in the time zone of the item.
+func (i Item) LocalDue() time.Time {
+	return i.DueAt.In(i.Location())
+}
+
+// LocalStart returns the start time in the time zone of the item.
+func (i Item) LocalStart() time.Time {
+	return i.StartAt.In(i.Location())
+}
+
+// IsOverdue reports whether the item is still open after its due time.
+func (i Item) IsOverdue(now time.Time) bool {
+	return !i.Done && i.HasDue() && i.DueAt.Before(now)
+}
+
+// ReminderTimes returns the times reminders are due at, earliest first.
+func (i Item) ReminderTimes() []time.Time {
+	if !i.HasDue() {
+		return nil
+	}
+	times := make([]time.Time, 0, len(i.Reminders))
+	for _, offset := range i.Reminders {
+		times = append(times, i.DueAt.Add(-time.Duration(offset)))
+	}
+	sort.Slice(times, func(a, b int) bool { return times[a].Before(times[b]) })
+	return times
+}
+
+// PendingReminder returns the latest reminder time that is already due but has not been notified yet.
+// Reminders missed while the app was down collapse into a single notification.
+func (i Item) PendingReminder(now time.Time) (time.Time, bool) {
+	if i.Done {
+		return time.Time{}, false
+	}
+	var pending time.Time
+	for _, t := range i.ReminderTimes() {
+		if t.After(now) {
+			break
+		}
+		if t.After(i.RemindedAt) {
+			pending = t
+		}
+	}
+	return pending, !pending.IsZero()
+}
+
+// IsRecurring reports whether the item has a recurrence rule.
+func (i Item) IsRecurring() bool {
+	return i.RRule != ""
+}
+
+// Recurrence returns the parsed recurrence rule of the item.
+func (i Item) Recurrence() (Recurrence, error) {
+	return ParseRRule(i.RRule, i.Location())
+}
+
+// RecurrenceText returns the recurrence rule in human readable form, empty if the item does not recur.
+func (i Item) RecurrenceText() string {
+	r, err := i.Recurrence()
+	if !i.IsRecurring() || err != nil {
+		return ""
+	}
+	return r.Describe(i.Location())
+}
+
+// NextOccurrence returns the due time of the occurrence that follows this one.
+func (i Item) NextOccurrence() (time.Time, bool) {
+	if !i.IsRecurring() || !i.HasDue() {
+		return time.Time{}, false
+	}
+	r, err := i.Recurrence()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return r.Next(i.LocalDue(), i.OccurrenceIndex())
+}
+
+// UpcomingOccurrences returns the due times of up to n occurrences that follow this one.
+func (i Item) UpcomingOccurrences(n int) []time.Time {
+	if !i.IsRecurring() || !i.HasDue() {
+		return nil
+	}
+	r, err := i.Recurrence()
+	if err != nil {
+		return nil
+	}
+	return r.Occurrences(i.LocalDue(), i.OccurrenceIndex(), n)
+}
+
+// OccurrenceIndex returns the position of the item in its series, 1 for the first one.
+func (i Item) OccurrenceIndex() int {
+	if i.Occurrence < 1 {
+		return 1
+	}
+	return i.Occurrence
+}
+
+// SeriesKey returns the ID shared by the occurrences of the series, the item's own ID for the first one.
+func (i Item) SeriesKey() uuid.UUID {
+	if i.SeriesID != uuid.Nil {
+		return i.SeriesID
+	}
+	return i.ID()
+}
+
+// Shift moves the item to the given due time, the start time keeps its distance to the due time.
+func (i *Item) Shift(due time.Time) {
+	if i.HasStart() {
+		i.StartAt = due.Add(-i.DueAt.Sub(i.StartAt)).UTC()
+	}
+	i.DueAt = due.UTC()
+}
+
+// Validate checks the time zone, the dates and the recurrence rule of the item.
+func (i Item) Validate() error {
+	if strings.TrimSpace(i.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidItem)
+	}
+	if _, err := time.LoadLocation(i.TimeZone); err != nil {
+		return ErrInvalidTimeZone
+	}
+	if i.HasStart() && i.HasDue() && i.StartAt.After(i.DueAt) {
+		return fmt.Errorf("%w: start date is after the due date", ErrInvalidItem)
+	}
+	if !i.Priority.Valid() {
+		return ErrInvalidPriority
+	}
+	if !i.Status.Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, i.Status)
+	}
+	for _, t := range i.Tags {
+		if tag, err := NormalizeTag(t); err != nil || tag != t {
+			return fmt.Errorf("%w: %q", ErrInvalidTag, t)
+		}
+	}
+	if i.IsRecurring() {
+		if !i.HasDue() {
+			return fmt.Errorf("%w: recurring items need a due date", ErrInvalidItem)
+		}
+		if _, err := i.Recurrence(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Offset is a reminder offset before the due time.
+// Its text form is a Go duration that also accepts days, e.g. "1d", "2h30m" or "15m".
+type Offset time.Duration
+
+// ParseOffset parses the text form of an offset.
+func ParseOffset(s string) (Offset, error) {
+	s = strings.TrimSpace(s)
+	days := 0
+	if before, after, ok := strings.Cut(s, "d"); ok {
+		n, err := strconv.Atoi(before)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidOffset, s)
+		}
+		days, s = n, after
+	}
+
+	var d time.Duration
+	if s != "" {
+		var err error
+		d, err = time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidOffset, s)
+		}
+	}
+
+	return Offset(time.Duration(days)*24*time.Hour + d), nil
+}
+
+// ParseOffsets parses a comma separated list of offsets, empty entries are ignored.
+func ParseOffsets(s string) ([]Offset, error) {
+	var offsets []Offset
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		offset, err := ParseOffset(part)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// FormatOffsets returns the comma separated text form of the offsets.
+func FormatOffsets(offsets []Offset) string {
+	parts := make([]string, len(offsets))
+	for i, o := range offsets {
+		parts[i] = o.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (o Offset) String() string {
+	d := time.Duration(o)
+	days := d / (24 * time.Hour)
+	rest := d % (24 * time.Hour)
+	switch {
+	case days > 0 && rest == 0:
+		return fmt.Sprintf("%dd", days)
+	case days > 0:
+		return fmt.Sprintf("%dd%s", days, trimDuration(rest))
+	}
+	return trimDuration(d)
+}
+
+func (o Offset) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Offset) UnmarshalText(text []byte) error {
+	offset, err := ParseOffset(string(text))
+	if err != nil {
+		return err
+	}
+	*o = offset
+	return nil
+}
+
+// trimDuration drops the zero minutes and seconds Go adds to durations, "1h0m0s" becomes "1h".
+func trimDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
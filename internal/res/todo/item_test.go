@@ -0,0 +1,63 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseOffset(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+		text string
+	}{
+		{"15m", 15 * time.Minute, "15m"},
+		{"2h", 2 * time.Hour, "2h"},
+		{"1d", 24 * time.Hour, "1d"},
+		{"1d2h30m", 26*time.Hour + 30*time.Minute, "1d2h30m"},
+	}
+
+	for _, c := range cases {
+		o, err := ParseOffset(c.in)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.in, err)
+		}
+		if time.Duration(o) != c.want {
+			t.Errorf("%s: expected %v, got %v", c.in, c.want, time.Duration(o))
+		}
+		if o.String() != c.text {
+			t.Errorf("%s: expected text %s, got %s", c.in, c.text, o.String())
+		}
+	}
+
+	for _, in := range []string{"x", "-1h", "d"} {
+		if _, err := ParseOffset(in); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+func TestPendingReminder(t *testing.T) {
+	due := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	item := Item{DueAt: due, Reminders: []Offset{Offset(time.Hour), Offset(24 * time.Hour)}}
+
+	if _, ok := item.PendingReminder(due.Add(-25 * time.Hour)); ok {
+		t.Errorf("expected no reminder before the first one is due")
+	}
+
+	at, ok := item.PendingReminder(due.Add(-30 * time.Minute))
+	if !ok || !at.Equal(due.Add(-time.Hour)) {
+		t.Errorf("expected the latest missed reminder, got %v %v", at, ok)
+	}
+
+	item.RemindedAt = at
+	if _, ok := item.PendingReminder(due.Add(-30 * time.Minute)); ok {
+		t.Errorf("expected no reminder once notified")
+	}
+
+	item.RemindedAt = time.Time{}
+	item.Done = true
+	if _, ok := item.PendingReminder(due); ok {
+		t.Errorf("expected no reminder for a done item")
+	}
+}
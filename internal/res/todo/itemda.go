@@ -0,0 +1,140 @@
+package todo
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// ItemDA represents the data access layer for the Item model.
+// Times are stored in UTC, reminders, dependencies and assignees as comma separated lists.
+// Tags are not part of it, the repo keeps them as item and tag pairs.
+type ItemDA struct {
+	Type        string
+	ID          uuid.UUID      `db:"id"`
+	ShortID     sql.NullString `db:"short_id"`
+	ListID      uuid.UUID      `db:"list_id"`
+	Title       sql.NullString `db:"title"`
+	Notes       sql.NullString `db:"notes"`
+	Done        bool           `db:"done"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	StartAt     sql.NullTime   `db:"start_at"`
+	DueAt       sql.NullTime   `db:"due_at"`
+	TimeZone    sql.NullString `db:"time_zone"`
+	Reminders   sql.NullString `db:"reminders"`
+	RemindedAt  sql.NullTime   `db:"reminded_at"`
+	RRule       sql.NullString `db:"rrule"`
+	SeriesID    uuid.UUID      `db:"series_id"`
+	Occurrence  int            `db:"occurrence"`
+	ParentID    uuid.UUID      `db:"parent_id"`
+	BlockedBy   sql.NullString `db:"blocked_by"`
+	Priority    int            `db:"priority"`
+	Status      sql.NullString `db:"status"`
+	Position    float64        `db:"position"`
+	Assignees   sql.NullString `db:"assignees"`
+	CreatedBy   sql.NullString `db:"created_by"`
+	UpdatedBy   sql.NullString `db:"updated_by"`
+	CreatedAt   sql.NullTime   `db:"created_at"`
+	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	Version     int            `db:"version"`
+}
+
+// Convert ItemDA to Item
+func toItem(da ItemDA) Item {
+	reminders, _ := ParseOffsets(da.Reminders.String)
+	status := Status(da.Status.String)
+	if status == "" {
+		status = StatusTodo
+		if da.Done {
+			status = StatusDone
+		}
+	}
+	return Item{
+		BaseModel: am.NewModel(
+			am.WithID(da.ID),
+			am.WithType(itemType),
+			am.WithShortID(da.ShortID.String),
+			am.WithCreatedBy(am.ParseUUID(da.CreatedBy)),
+			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
+			am.WithCreatedAt(da.CreatedAt.Time),
+			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+		),
+		ListID:      da.ListID,
+		Title:       da.Title.String,
+		Notes:       da.Notes.String,
+		Done:        da.Done,
+		CompletedAt: da.CompletedAt.Time,
+		StartAt:     da.StartAt.Time,
+		DueAt:       da.DueAt.Time,
+		TimeZone:    da.TimeZone.String,
+		Reminders:   reminders,
+		RemindedAt:  da.RemindedAt.Time,
+		RRule:       da.RRule.String,
+		SeriesID:    da.SeriesID,
+		Occurrence:  da.Occurrence,
+		ParentID:    da.ParentID,
+		BlockedBy:   parseIDs(da.BlockedBy.String),
+		Priority:    Priority(da.Priority),
+		Status:      status,
+		Position:    da.Position,
+		Assignees:   parseAssignees(da.Assignees.String),
+	}
+}
+
+// Convert Item to ItemDA
+func toItemDA(item Item) ItemDA {
+	reminders := make([]string, len(item.Reminders))
+	for i, o := range item.Reminders {
+		reminders[i] = o.String()
+	}
+	return ItemDA{
+		Type:        itemType,
+		ID:          item.ID(),
+		ShortID:     am.NewNullString(item.ShortID()),
+		ListID:      item.ListID,
+		Title:       am.NewNullString(item.Title),
+		Notes:       am.NewNullString(item.Notes),
+		Done:        item.Done,
+		CompletedAt: am.NewNullTime(item.CompletedAt),
+		StartAt:     am.NewNullTime(item.StartAt),
+		DueAt:       am.NewNullTime(item.DueAt),
+		TimeZone:    am.NewNullString(item.TimeZone),
+		Reminders:   am.NewNullString(strings.Join(reminders, ",")),
+		RemindedAt:  am.NewNullTime(item.RemindedAt),
+		RRule:       am.NewNullString(item.RRule),
+		SeriesID:    item.SeriesID,
+		Occurrence:  item.Occurrence,
+		ParentID:    item.ParentID,
+		BlockedBy:   am.NewNullString(joinIDs(item.BlockedBy)),
+		Priority:    int(item.Priority),
+		Status:      am.NewNullString(string(item.Status)),
+		Position:    item.Position,
+		Assignees:   am.NewNullString(formatAssignees(item.Assignees)),
+		CreatedBy:   am.NullUUID(item.CreatedBy()),
+		UpdatedBy:   am.NullUUID(item.UpdatedBy()),
+		CreatedAt:   am.NewNullTime(item.CreatedAt()),
+		UpdatedAt:   am.NewNullTime(item.UpdatedAt()),
+		Version:     item.Version(),
+	}
+}
+
+func joinIDs(ids []uuid.UUID) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strings.Join(strs, ",")
+}
+
+func parseIDs(s string) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, str := range strings.Split(s, ",") {
+		if id, err := uuid.Parse(str); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
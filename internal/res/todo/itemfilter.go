@@ -0,0 +1,190 @@
+package todo
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// itemsPath is the action of the filtered items view.
+const itemsPath = "items"
+
+// Item filter query params, each one takes a comma separated list of values or is repeated.
+const (
+	filterTags       = "tags"
+	filterPriorities = "priority"
+	filterStatuses   = "status"
+)
+
+// ItemFilter selects items by tags, priorities and statuses.
+// An item matches when it has all the tags, one of the priorities and one of the statuses, empty parts match any item.
+type ItemFilter struct {
+	Tags       []string   `json:"tags"`
+	Priorities []Priority `json:"priorities"`
+	Statuses   []Status   `json:"statuses"`
+}
+
+// ParseItemFilter reads a filter from query params such as "tags=release&priority=P1,P2&status=todo".
+func ParseItemFilter(values url.Values) (ItemFilter, error) {
+	var filter ItemFilter
+	var err error
+
+	filter.Tags, err = NormalizeTags(queryList(values, filterTags))
+	if err != nil {
+		return ItemFilter{}, err
+	}
+	for _, s := range queryList(values, filterPriorities) {
+		p, err := ParsePriority(s)
+		if err != nil {
+			return ItemFilter{}, err
+		}
+		filter.Priorities = append(filter.Priorities, p)
+	}
+	for _, s := range queryList(values, filterStatuses) {
+		st, err := ParseStatus(s)
+		if err != nil {
+			return ItemFilter{}, err
+		}
+		filter.Statuses = append(filter.Statuses, st)
+	}
+	return filter, nil
+}
+
+// queryList returns the non empty values of a query param, splitting comma separated ones.
+func queryList(values url.Values, key string) []string {
+	var list []string
+	for _, v := range values[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
+
+// IsEmpty reports whether the filter matches every item.
+func (f ItemFilter) IsEmpty() bool {
+	return len(f.Tags) == 0 && len(f.Priorities) == 0 && len(f.Statuses) == 0
+}
+
+// Match reports whether the item passes the filter.
+func (f ItemFilter) Match(item Item) bool {
+	for _, tag := range f.Tags {
+		if !item.HasTag(tag) {
+			return false
+		}
+	}
+	if len(f.Priorities) > 0 && !f.HasPriority(item.Priority) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !f.HasStatus(item.Status) {
+		return false
+	}
+	return true
+}
+
+// Apply returns the items that pass the filter.
+func (f ItemFilter) Apply(items []Item) []Item {
+	if f.IsEmpty() {
+		return items
+	}
+	var result []Item
+	for _, item := range items {
+		if f.Match(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// HasTag reports whether the filter requires the tag.
+func (f ItemFilter) HasTag(tag string) bool {
+	for _, t := range f.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPriority reports whether the filter accepts the priority.
+func (f ItemFilter) HasPriority(p Priority) bool {
+	for _, fp := range f.Priorities {
+		if fp == p {
+			return true
+		}
+	}
+	return false
+}
+
+// HasStatus reports whether the filter accepts the status.
+func (f ItemFilter) HasStatus(s Status) bool {
+	for _, fs := range f.Statuses {
+		if fs == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Params returns the filter as query params with one comma separated value each, as used by menu items.
+func (f ItemFilter) Params() map[string]string {
+	params := map[string]string{}
+	if len(f.Tags) > 0 {
+		params[filterTags] = strings.Join(f.Tags, ",")
+	}
+	if len(f.Priorities) > 0 {
+		priorities := make([]string, len(f.Priorities))
+		for i, p := range f.Priorities {
+			priorities[i] = p.String()
+			if p == PriorityNone {
+				priorities[i] = "none"
+			}
+		}
+		params[filterPriorities] = strings.Join(priorities, ",")
+	}
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = string(s)
+		}
+		params[filterStatuses] = strings.Join(statuses, ",")
+	}
+	return params
+}
+
+// Encode returns the filter as a query string.
+func (f ItemFilter) Encode() string {
+	values := url.Values{}
+	for k, v := range f.Params() {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// SavedFilter is a named item filter of a user.
+type SavedFilter struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Name      string     `json:"name"`
+	Filter    ItemFilter `json:"filter"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NewSavedFilter creates a filter of the user with the given name.
+func NewSavedFilter(userID uuid.UUID, name string, filter ItemFilter) SavedFilter {
+	return SavedFilter{
+		ID:     uuid.New(),
+		UserID: userID,
+		Name:   strings.TrimSpace(name),
+		Filter: filter,
+	}
+}
+
+// Path returns the web path of the items the filter selects.
+func (f SavedFilter) Path() string {
+	return todoResPath + "/" + itemsPath + "?" + f.Filter.Encode()
+}
@@ -0,0 +1,58 @@
+package todo
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestItemFilter(t *testing.T) {
+	values, _ := url.ParseQuery("tags=Release,%23Backend&priority=p1&priority=P2&status=todo")
+	filter, err := ParseItemFilter(values)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	again, err := ParseItemFilter(mustParseQuery(t, filter.Encode()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Encode() != filter.Encode() {
+		t.Errorf("expected %s to round trip, got %s", filter.Encode(), again.Encode())
+	}
+
+	item := NewItem(uuid.New(), "ship", "")
+	item.Tags = []string{"backend", "release", "q3"}
+	item.Priority = Priority2
+	if !filter.Match(item) {
+		t.Errorf("expected %v to match", item.Tags)
+	}
+
+	item.Status = StatusDone
+	if filter.Match(item) {
+		t.Error("expected a done item not to match a todo filter")
+	}
+
+	item.Status = StatusTodo
+	item.Tags = []string{"release"}
+	if filter.Match(item) {
+		t.Error("expected an item missing a tag not to match")
+	}
+
+	for _, q := range []string{"priority=P5", "status=later", "tags=" + strings.Repeat("x", maxTagLen+1)} {
+		if _, err := ParseItemFilter(mustParseQuery(t, q)); err == nil {
+			t.Errorf("%s: expected an error", q)
+		}
+	}
+}
+
+func mustParseQuery(t *testing.T, q string) url.Values {
+	t.Helper()
+	values, err := url.ParseQuery(q)
+	if err != nil {
+		t.Fatalf("cannot parse %s: %v", q, err)
+	}
+	return values
+}
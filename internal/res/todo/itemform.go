@@ -0,0 +1,271 @@
+package todo
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	formDateLayout     = "2006-01-02"
+	formDateTimeLayout = "2006-01-02 15:04"
+	// endOfDayTime is the due time of items that only have a due date.
+	endOfDayTime = "23:59"
+)
+
+// ItemForm holds the values of the item form, dates and times are entered in the time zone of the item.
+// Repeat and the fields after it build the recurrence rule, Repeat is the frequency or empty for items that do not recur.
+// Candidates are the items the form offers as parent and dependencies, they are not read from the request.
+type ItemForm struct {
+	Title      string
+	Notes      string
+	DueDate    string
+	DueTime    string
+	StartDate  string
+	TimeZone   string
+	Reminders  string
+	Repeat     string
+	Interval   string
+	ByDay      string
+	ByMonthDay string
+	Count      string
+	Until      string
+	ParentID   string
+	BlockedBy  []string
+	Tags       string
+	Priority   string
+	Status     string
+	Candidates []Item
+}
+
+// PriorityOptions lists the priorities the form offers.
+func (f ItemForm) PriorityOptions() []Priority {
+	return Priorities
+}
+
+// StatusOptions lists the statuses the form offers.
+func (f ItemForm) StatusOptions() []Status {
+	return Statuses
+}
+
+// Frequencies lists the repeat options of the form.
+func (f ItemForm) Frequencies() []string {
+	return []string{FreqDaily, FreqWeekly, FreqMonthly}
+}
+
+// NewItemForm reads the item form values.
+func NewItemForm(values url.Values) ItemForm {
+	return ItemForm{
+		Title:      strings.TrimSpace(values.Get("title")),
+		Notes:      values.Get("notes"),
+		DueDate:    strings.TrimSpace(values.Get("due_date")),
+		DueTime:    strings.TrimSpace(values.Get("due_time")),
+		StartDate:  strings.TrimSpace(values.Get("start_date")),
+		TimeZone:   strings.TrimSpace(values.Get("time_zone")),
+		Reminders:  values.Get("reminders"),
+		Repeat:     strings.ToUpper(strings.TrimSpace(values.Get("repeat"))),
+		Interval:   strings.TrimSpace(values.Get("interval")),
+		ByDay:      strings.TrimSpace(values.Get("by_day")),
+		ByMonthDay: strings.TrimSpace(values.Get("by_month_day")),
+		Count:      strings.TrimSpace(values.Get("count")),
+		Until:      strings.TrimSpace(values.Get("until")),
+		ParentID:   values.Get("parent_id"),
+		BlockedBy:  values["blocked_by"],
+		Tags:       values.Get("tags"),
+		Priority:   strings.TrimSpace(values.Get("priority")),
+		Status:     strings.TrimSpace(values.Get("status")),
+	}
+}
+
+// IsParent reports whether the item is the selected parent.
+func (f ItemForm) IsParent(id uuid.UUID) bool {
+	return f.ParentID == id.String()
+}
+
+// IsBlocker reports whether the item is one of the selected dependencies.
+func (f ItemForm) IsBlocker(id uuid.UUID) bool {
+	for _, b := range f.BlockedBy {
+		if b == id.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// ItemToForm returns the form values of the item.
+func ItemToForm(item Item) ItemForm {
+	form := ItemForm{
+		Title:     item.Title,
+		Notes:     item.Notes,
+		TimeZone:  item.TimeZone,
+		Reminders: FormatOffsets(item.Reminders),
+		Tags:      strings.Join(item.Tags, ", "),
+		Priority:  item.Priority.String(),
+		Status:    string(item.Status),
+	}
+	if item.HasDue() {
+		due := item.LocalDue()
+		form.DueDate = due.Format(formDateLayout)
+		form.DueTime = due.Format("15:04")
+	}
+	if item.HasStart() {
+		form.StartDate = item.LocalStart().Format(formDateLayout)
+	}
+	if item.HasParent() {
+		form.ParentID = item.ParentID.String()
+	}
+	for _, id := range item.BlockedBy {
+		form.BlockedBy = append(form.BlockedBy, id.String())
+	}
+	if r, err := item.Recurrence(); item.IsRecurring() && err == nil {
+		form.Repeat = r.Freq
+		if r.Interval > 1 {
+			form.Interval = strconv.Itoa(r.Interval)
+		}
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		form.ByDay = strings.Join(days, ",")
+		monthDays := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			monthDays[i] = strconv.Itoa(d)
+		}
+		form.ByMonthDay = strings.Join(monthDays, ",")
+		if r.Count > 0 {
+			form.Count = strconv.Itoa(r.Count)
+		}
+		if !r.Until.IsZero() {
+			form.Until = r.Until.In(item.Location()).Format(formDateLayout)
+		}
+	}
+	return form
+}
+
+// rrule builds the recurrence rule of the form, empty when the item does not repeat.
+// The until date is taken as the end of that day in loc.
+func (f ItemForm) rrule(loc *time.Location) (string, error) {
+	if f.Repeat == "" {
+		return "", nil
+	}
+
+	parts := []string{"FREQ=" + f.Repeat}
+	if f.Interval != "" {
+		parts = append(parts, "INTERVAL="+f.Interval)
+	}
+	if f.ByDay != "" {
+		parts = append(parts, "BYDAY="+strings.ToUpper(strings.ReplaceAll(f.ByDay, " ", "")))
+	}
+	if f.ByMonthDay != "" {
+		parts = append(parts, "BYMONTHDAY="+strings.ReplaceAll(f.ByMonthDay, " ", ""))
+	}
+	if f.Count != "" {
+		parts = append(parts, "COUNT="+f.Count)
+	}
+	if f.Until != "" {
+		until, err := time.ParseInLocation(formDateLayout, f.Until, loc)
+		if err != nil {
+			return "", ErrInvalidRRule
+		}
+		parts = append(parts, "UNTIL="+until.Format(untilDateLayout))
+	}
+
+	r, err := ParseRRule(strings.Join(parts, ";"), loc)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+// FormToItem applies the form values to the item.
+// A due date without a time is due at the end of that day, the start date is taken at its beginning.
+func FormToItem(form ItemForm, item Item) (Item, error) {
+	tz := form.TimeZone
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return item, ErrInvalidTimeZone
+	}
+
+	reminders, err := ParseOffsets(form.Reminders)
+	if err != nil {
+		return item, err
+	}
+
+	var due time.Time
+	if form.DueDate != "" {
+		dueTime := form.DueTime
+		if dueTime == "" {
+			dueTime = endOfDayTime
+		}
+		due, err = time.ParseInLocation(formDateTimeLayout, form.DueDate+" "+dueTime, loc)
+		if err != nil {
+			return item, err
+		}
+	}
+
+	var start time.Time
+	if form.StartDate != "" {
+		start, err = time.ParseInLocation(formDateLayout, form.StartDate, loc)
+		if err != nil {
+			return item, err
+		}
+	}
+
+	rrule, err := form.rrule(loc)
+	if err != nil {
+		return item, err
+	}
+
+	parentID := uuid.Nil
+	if form.ParentID != "" {
+		parentID, err = uuid.Parse(form.ParentID)
+		if err != nil {
+			return item, ErrInvalidParent
+		}
+	}
+
+	var blockedBy []uuid.UUID
+	for _, str := range form.BlockedBy {
+		if str == "" {
+			continue
+		}
+		id, err := uuid.Parse(str)
+		if err != nil {
+			return item, ErrInvalidDependency
+		}
+		blockedBy = append(blockedBy, id)
+	}
+
+	tags, err := ParseTags(form.Tags)
+	if err != nil {
+		return item, err
+	}
+	priority, err := ParsePriority(form.Priority)
+	if err != nil {
+		return item, err
+	}
+	status, err := ParseStatus(form.Status)
+	if err != nil {
+		return item, err
+	}
+
+	item.Title = form.Title
+	item.Notes = form.Notes
+	item.TimeZone = tz
+	item.DueAt = due.UTC()
+	item.StartAt = start.UTC()
+	item.Reminders = reminders
+	item.RRule = rrule
+	item.ParentID = parentID
+	item.BlockedBy = blockedBy
+	item.Tags = tags
+	item.Priority = priority
+	item.Status = status
+	return item, nil
+}
@@ -0,0 +1,153 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidParent is returned when an item is nested under an item it cannot be a subtask of.
+var ErrInvalidParent = errors.New("invalid parent item")
+
+// ErrInvalidDependency is returned when an item is blocked by an item it cannot depend on.
+var ErrInvalidDependency = errors.New("invalid dependency")
+
+// ErrDependencyCycle is returned when a dependency would make an item wait on itself.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// ErrItemBlocked is returned when completing an item that still waits on open items.
+var ErrItemBlocked = errors.New("item is blocked by open items")
+
+// ItemNode is an item placed in the tree of its list.
+// Subtasks counts the direct subtasks and SubtasksDone the completed ones, Blockers are the open items it waits on.
+type ItemNode struct {
+	Item
+	Depth        int
+	Subtasks     int
+	SubtasksDone int
+	Blockers     []Item
+}
+
+// IsBlocked reports whether the item waits on open items.
+func (n ItemNode) IsBlocked() bool {
+	return len(n.Blockers) > 0
+}
+
+// Indent returns the left padding of the item in pixels.
+func (n ItemNode) Indent() int {
+	return n.Depth * 24
+}
+
+// HasParent reports whether the item is a subtask.
+func (i Item) HasParent() bool {
+	return i.ParentID != uuid.Nil
+}
+
+// IsBlockedBy reports whether the item depends on the given one.
+func (i Item) IsBlockedBy(id uuid.UUID) bool {
+	for _, b := range i.BlockedBy {
+		if b == id {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildItemTree returns the items in tree order, each subtask right after its parent and its earlier siblings.
+// Items keep their relative order, those whose parent is missing are shown as top level ones.
+func BuildItemTree(items []Item) []ItemNode {
+	byID := itemsByID(items)
+
+	children := make(map[uuid.UUID][]Item)
+	var roots []Item
+	for _, item := range items {
+		if _, ok := byID[item.ParentID]; item.HasParent() && ok {
+			children[item.ParentID] = append(children[item.ParentID], item)
+			continue
+		}
+		roots = append(roots, item)
+	}
+
+	nodes := make([]ItemNode, 0, len(items))
+	var walk func(item Item, depth int)
+	walk = func(item Item, depth int) {
+		node := ItemNode{Item: item, Depth: depth, Subtasks: len(children[item.ID()])}
+		for _, child := range children[item.ID()] {
+			if child.Done {
+				node.SubtasksDone++
+			}
+		}
+		node.Blockers = openBlockers(item, byID)
+		nodes = append(nodes, node)
+		for _, child := range children[item.ID()] {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return nodes
+}
+
+// openBlockers returns the items the item waits on that are not done yet.
+func openBlockers(item Item, byID map[uuid.UUID]Item) []Item {
+	var blockers []Item
+	for _, id := range item.BlockedBy {
+		if blocker, ok := byID[id]; ok && !blocker.Done {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers
+}
+
+// checkItemLinks validates the parent and the dependencies of an item against the other items of its list.
+// An item cannot be nested under itself or one of its subtasks, and it cannot depend on an item that,
+// directly or not, depends on it.
+func checkItemLinks(item Item, items []Item) error {
+	byID := itemsByID(items)
+	byID[item.ID()] = item
+
+	if item.HasParent() {
+		if _, ok := byID[item.ParentID]; !ok || item.ParentID == item.ID() {
+			return fmt.Errorf("%w: parent must be another item of the list", ErrInvalidParent)
+		}
+		seen := map[uuid.UUID]bool{}
+		for id := item.ParentID; id != uuid.Nil && !seen[id]; id = byID[id].ParentID {
+			if id == item.ID() {
+				return fmt.Errorf("%w: an item cannot be nested under its own subtask", ErrInvalidParent)
+			}
+			seen[id] = true
+		}
+	}
+
+	seen := map[uuid.UUID]bool{}
+	for _, id := range item.BlockedBy {
+		if _, ok := byID[id]; !ok || id == item.ID() || seen[id] {
+			return fmt.Errorf("%w: dependencies must be other items of the list", ErrInvalidDependency)
+		}
+		seen[id] = true
+	}
+
+	if dependsOn(item.ID(), item.BlockedBy, byID, map[uuid.UUID]bool{}) {
+		return ErrDependencyCycle
+	}
+	return nil
+}
+
+// dependsOn reports whether target is reachable following the dependencies of the given items.
+func dependsOn(target uuid.UUID, ids []uuid.UUID, byID map[uuid.UUID]Item, visited map[uuid.UUID]bool) bool {
+	for _, id := range ids {
+		if id == target {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if dependsOn(target, byID[id].BlockedBy, byID, visited) {
+			return true
+		}
+	}
+	return false
+}
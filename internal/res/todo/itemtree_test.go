@@ -0,0 +1,82 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCheckItemLinks(t *testing.T) {
+	listID := uuid.New()
+	a := newTestItem(listID, "a")
+	b := newTestItem(listID, "b")
+	c := newTestItem(listID, "c")
+	b.BlockedBy = []uuid.UUID{a.ID()}
+	c.BlockedBy = []uuid.UUID{b.ID()}
+	c.ParentID = a.ID()
+	items := []Item{a, b, c}
+
+	cases := []struct {
+		name   string
+		update func(Item) Item
+		want   error
+	}{
+		{"no links", func(i Item) Item { return i }, nil},
+		{"self dependency", func(i Item) Item { i.BlockedBy = []uuid.UUID{a.ID()}; return i }, ErrInvalidDependency},
+		{"unknown dependency", func(i Item) Item { i.BlockedBy = []uuid.UUID{uuid.New()}; return i }, ErrInvalidDependency},
+		{"direct cycle", func(i Item) Item { i.BlockedBy = []uuid.UUID{b.ID()}; return i }, ErrDependencyCycle},
+		{"indirect cycle", func(i Item) Item { i.BlockedBy = []uuid.UUID{c.ID()}; return i }, ErrDependencyCycle},
+		{"self parent", func(i Item) Item { i.ParentID = a.ID(); return i }, ErrInvalidParent},
+		{"nested under subtask", func(i Item) Item { i.ParentID = c.ID(); return i }, ErrInvalidParent},
+	}
+
+	for _, tc := range cases {
+		if err := checkItemLinks(tc.update(a), items); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+
+	c.BlockedBy = []uuid.UUID{a.ID(), b.ID()}
+	if err := checkItemLinks(c, items); err != nil {
+		t.Errorf("expected shared dependencies to be valid, got %v", err)
+	}
+}
+
+func TestBuildItemTree(t *testing.T) {
+	listID := uuid.New()
+	a := newTestItem(listID, "a")
+	b := newTestItem(listID, "b")
+	c := newTestItem(listID, "c")
+	d := newTestItem(listID, "d")
+	c.ParentID = a.ID()
+	d.ParentID = c.ID()
+	d.Done = true
+	b.BlockedBy = []uuid.UUID{c.ID(), d.ID()}
+
+	nodes := BuildItemTree([]Item{a, b, c, d})
+
+	var titles []string
+	for _, n := range nodes {
+		titles = append(titles, n.Title)
+	}
+	if got := fmt.Sprint(titles); got != "[a c d b]" {
+		t.Fatalf("expected tree order [a c d b], got %s", got)
+	}
+	if nodes[2].Depth != 2 {
+		t.Errorf("expected d at depth 2, got %d", nodes[2].Depth)
+	}
+	if nodes[1].Subtasks != 1 || nodes[1].SubtasksDone != 1 {
+		t.Errorf("expected c to have 1 of 1 subtasks done, got %d of %d", nodes[1].SubtasksDone, nodes[1].Subtasks)
+	}
+	if len(nodes[3].Blockers) != 1 || nodes[3].Blockers[0].ID() != c.ID() {
+		t.Errorf("expected b to be blocked by c only, got %v", nodes[3].Blockers)
+	}
+}
+
+func newTestItem(listID uuid.UUID, title string) Item {
+	item := NewItem(listID, title, "")
+	item.GenID()
+	return item
+}
@@ -1,17 +1,32 @@
 package todo
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
 )
 
 const (
 	listType = "list"
 )
 
+// List is a todo list owned by a user or by an auth.Team.
+// Lists without an owner, e.g. created before ownership existed, are only reachable through their shares.
+// Columns configure the board of the list, empty means a column for each status.
+// Archived lists are left out of the default views until they are unarchived.
+// Templates are lists kept to create new ones from, see TemplateValues.
 type List struct {
 	*am.BaseModel
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	OrgID       uuid.UUID     `json:"org_id"`
+	OwnerUserID uuid.UUID     `json:"owner_user_id"`
+	OwnerTeamID uuid.UUID     `json:"owner_team_id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Columns     []BoardColumn `json:"columns"`
+	ArchivedAt  time.Time     `json:"archived_at"`
+	Template    bool          `json:"template"`
 }
 
 // NewList creates a new list.
@@ -22,3 +37,35 @@ func NewList(name, description string) List {
 		Description: description,
 	}
 }
+
+// MarshalJSON includes the identity and version of the list, API clients need both to address it.
+func (l List) MarshalJSON() ([]byte, error) {
+	type Alias List
+	return json.Marshal(struct {
+		ID      uuid.UUID `json:"id"`
+		Version int       `json:"version"`
+		Alias
+	}{
+		ID:      l.ID(),
+		Version: l.Version(),
+		Alias:   Alias(l),
+	})
+}
+
+// IsArchived reports whether the list is archived.
+func (l List) IsArchived() bool {
+	return !l.ArchivedAt.IsZero()
+}
+
+// HasOwner reports whether the list is owned by a user or a team.
+func (l List) HasOwner() bool {
+	return l.OwnerUserID != uuid.Nil || l.OwnerTeamID != uuid.Nil
+}
+
+// OwnerID returns the ID of the owning user or team.
+func (l List) OwnerID() uuid.UUID {
+	if l.OwnerTeamID != uuid.Nil {
+		return l.OwnerTeamID
+	}
+	return l.OwnerUserID
+}
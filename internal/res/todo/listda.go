@@ -12,12 +12,21 @@ type ListDA struct {
 	Type        string
 	ID          uuid.UUID      `db:"id"`
 	ShortID     sql.NullString `db:"short_id"`
+	OrgID       uuid.UUID      `db:"org_id"`
+	OwnerUserID uuid.UUID      `db:"owner_user_id"`
+	OwnerTeamID uuid.UUID      `db:"owner_team_id"`
 	Name        sql.NullString `db:"name"`
 	Description sql.NullString `db:"description"`
+	Columns     sql.NullString `db:"board_columns"`
+	ArchivedAt  sql.NullTime   `db:"archived_at"`
+	Template    bool           `db:"is_template"`
 	CreatedBy   sql.NullString `db:"created_by"`
 	UpdatedBy   sql.NullString `db:"updated_by"`
 	CreatedAt   sql.NullTime   `db:"created_at"`
 	UpdatedAt   sql.NullTime   `db:"updated_at"`
+	Version     int            `db:"version"`
+	DeletedBy   sql.NullString `db:"deleted_by"`
+	DeletedAt   sql.NullTime   `db:"deleted_at"`
 }
 
 // Convert ListDA to List
@@ -26,13 +35,22 @@ func toList(da ListDA) List {
 		BaseModel: am.NewModel(
 			am.WithID(da.ID),
 			am.WithShortID(da.ShortID.String),
-			am.WithCreatedBy(uuid.MustParse(da.CreatedBy.String)),
-			am.WithUpdatedBy(uuid.MustParse(da.UpdatedBy.String)),
+			am.WithCreatedBy(am.ParseUUID(da.CreatedBy)),
+			am.WithUpdatedBy(am.ParseUUID(da.UpdatedBy)),
 			am.WithCreatedAt(da.CreatedAt.Time),
 			am.WithUpdatedAt(da.UpdatedAt.Time),
+			am.WithVersion(da.Version),
+			am.WithDeletedBy(am.ParseUUID(da.DeletedBy)),
+			am.WithDeletedAt(da.DeletedAt.Time),
 		),
+		OrgID:       da.OrgID,
+		OwnerUserID: da.OwnerUserID,
+		OwnerTeamID: da.OwnerTeamID,
 		Name:        da.Name.String,
 		Description: da.Description.String,
+		Columns:     decodeColumns(da.Columns.String),
+		ArchivedAt:  da.ArchivedAt.Time,
+		Template:    da.Template,
 	}
 }
 
@@ -41,11 +59,20 @@ func toListDA(list List) ListDA {
 	return ListDA{
 		ID:          list.ID(),
 		ShortID:     sql.NullString{String: list.ShortID(), Valid: list.Slug() != ""},
+		OrgID:       list.OrgID,
+		OwnerUserID: list.OwnerUserID,
+		OwnerTeamID: list.OwnerTeamID,
 		Name:        sql.NullString{String: list.Name, Valid: list.Name != ""},
 		Description: sql.NullString{String: list.Description, Valid: list.Description != ""},
+		Columns:     am.NewNullString(encodeColumns(list.Columns)),
+		ArchivedAt:  sql.NullTime{Time: list.ArchivedAt, Valid: list.IsArchived()},
+		Template:    list.Template,
 		CreatedBy:   sql.NullString{String: list.CreatedBy().String(), Valid: list.CreatedBy() != uuid.Nil},
 		UpdatedBy:   sql.NullString{String: list.UpdatedBy().String(), Valid: list.UpdatedBy() != uuid.Nil},
 		CreatedAt:   sql.NullTime{Time: list.CreatedAt(), Valid: !list.CreatedAt().IsZero()},
 		UpdatedAt:   sql.NullTime{Time: list.UpdatedAt(), Valid: !list.UpdatedAt().IsZero()},
+		Version:     list.Version(),
+		DeletedBy:   sql.NullString{String: list.DeletedBy().String(), Valid: list.DeletedBy() != uuid.Nil},
+		DeletedAt:   sql.NullTime{Time: list.DeletedAt(), Valid: list.IsDeleted()},
 	}
 }
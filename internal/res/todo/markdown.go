@@ -0,0 +1,294 @@
+package todo
+
+import (
+	"html/template"
+	"strings"
+	"unicode"
+)
+
+// RenderMarkdown renders the Markdown subset comments are written in as HTML.
+// Raw HTML is not supported: every piece of text is escaped and links only take
+// http, https and mailto URLs, so the result is safe to embed in a page.
+// Supported are paragraphs, line breaks, headings, lists, quotes, fenced code blocks,
+// code spans, bold, italics, links and bare URLs. Words such as @name are marked
+// as mentions when isMention accepts the name, it can be nil.
+func RenderMarkdown(src string, isMention func(name string) bool) template.HTML {
+	md := &markdown{isMention: isMention}
+	md.blocks(strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n"))
+	return template.HTML(md.out.String())
+}
+
+type markdown struct {
+	out       strings.Builder
+	isMention func(name string) bool
+}
+
+func (md *markdown) blocks(lines []string) {
+	var para []string
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		md.out.WriteString("<p>")
+		for i, line := range para {
+			if i > 0 {
+				md.out.WriteString("<br>")
+			}
+			md.inline(strings.TrimSpace(line))
+		}
+		md.out.WriteString("</p>")
+		para = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			md.out.WriteString("<pre><code>")
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				md.out.WriteString(template.HTMLEscapeString(lines[i]))
+				md.out.WriteString("\n")
+			}
+			md.out.WriteString("</code></pre>")
+		case headingLevel(trimmed) > 0:
+			flush()
+			level := headingLevel(trimmed)
+			md.out.WriteString("<h4>")
+			md.inline(strings.TrimSpace(trimmed[level:]))
+			md.out.WriteString("</h4>")
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimSpace(lines[i])[1:], " "))
+			}
+			i--
+			md.out.WriteString("<blockquote>")
+			md.blocks(quoted)
+			md.out.WriteString("</blockquote>")
+		case listMarker(trimmed) != "":
+			flush()
+			tag := "ul"
+			if listMarker(trimmed) == "ol" {
+				tag = "ol"
+			}
+			md.out.WriteString("<" + tag + ">")
+			for ; i < len(lines) && listMarker(strings.TrimSpace(lines[i])) == tag; i++ {
+				md.out.WriteString("<li>")
+				md.inline(listText(strings.TrimSpace(lines[i])))
+				md.out.WriteString("</li>")
+			}
+			i--
+			md.out.WriteString("</" + tag + ">")
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+}
+
+// inline writes a line with its code spans, emphasis, links and mentions.
+func (md *markdown) inline(s string) {
+	text := 0
+	emit := func(end int) {
+		md.out.WriteString(template.HTMLEscapeString(s[text:end]))
+	}
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_[]()@#", s[i+1]) >= 0:
+			emit(i)
+			md.out.WriteString(template.HTMLEscapeString(s[i+1 : i+2]))
+			i += 2
+			text = i
+			continue
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				emit(i)
+				md.out.WriteString("<code>" + template.HTMLEscapeString(s[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				text = i
+				continue
+			}
+		case c == '*' || c == '_':
+			delim := s[i : i+1]
+			tag := "em"
+			if strings.HasPrefix(s[i:], delim+delim) {
+				delim += delim
+				tag = "strong"
+			}
+			inner := i + len(delim)
+			end := strings.Index(s[inner:], delim)
+			// Intraword underscores, as in snake_case, are not emphasis.
+			wordStart := c == '_' && i > 0 && isWordByte(s[i-1])
+			if end > 0 && !wordStart && s[inner] != ' ' && s[inner+end-1] != ' ' {
+				emit(i)
+				md.out.WriteString("<" + tag + ">")
+				md.inline(s[inner : inner+end])
+				md.out.WriteString("</" + tag + ">")
+				i = inner + end + len(delim)
+				text = i
+				continue
+			}
+		case c == '[':
+			if label, url, n, ok := parseLink(s[i:]); ok {
+				emit(i)
+				md.out.WriteString(`<a href="` + template.HTMLEscapeString(url) + `" rel="nofollow noopener">`)
+				md.inline(label)
+				md.out.WriteString("</a>")
+				i += n
+				text = i
+				continue
+			}
+		case c == 'h' && (i == 0 || !isWordByte(s[i-1])):
+			if url := bareURL(s[i:]); url != "" {
+				emit(i)
+				escaped := template.HTMLEscapeString(url)
+				md.out.WriteString(`<a href="` + escaped + `" rel="nofollow noopener">` + escaped + "</a>")
+				i += len(url)
+				text = i
+				continue
+			}
+		case c == '@' && (i == 0 || !isWordByte(s[i-1])):
+			name := mentionName(s[i+1:])
+			if name != "" && md.isMention != nil && md.isMention(name) {
+				emit(i)
+				md.out.WriteString(`<span class="mention">@` + template.HTMLEscapeString(name) + "</span>")
+				i += 1 + len(name)
+				text = i
+				continue
+			}
+		}
+		i++
+	}
+	emit(len(s))
+}
+
+// Mentions returns the distinct names mentioned as @name in the text, in order of appearance.
+// Code spans and code blocks are skipped.
+func Mentions(src string) []string {
+	var names []string
+	seen := map[string]bool{}
+	inFence := false
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		inCode := false
+		for i := 0; i < len(line); i++ {
+			switch {
+			case line[i] == '`':
+				inCode = !inCode
+			case line[i] == '\\':
+				i++
+			case line[i] == '@' && !inCode && (i == 0 || !isWordByte(line[i-1])):
+				name := mentionName(line[i+1:])
+				key := strings.ToLower(name)
+				if name != "" && !seen[key] {
+					seen[key] = true
+					names = append(names, name)
+				}
+				i += len(name)
+			}
+		}
+	}
+	return names
+}
+
+// mentionName returns the user name at the start of s, trailing dots are taken as punctuation.
+func mentionName(s string) string {
+	n := 0
+	for n < len(s) && (isWordByte(s[n]) || s[n] == '.' || s[n] == '-') {
+		n++
+	}
+	return strings.TrimRight(s[:n], ".-")
+}
+
+// parseLink parses a [label](url) link at the start of s and returns the number of bytes it takes.
+func parseLink(s string) (label, url string, n int, ok bool) {
+	closeLabel := strings.Index(s, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+	closeURL := strings.IndexByte(s[closeLabel+2:], ')')
+	if closeURL < 0 {
+		return "", "", 0, false
+	}
+	label = s[1:closeLabel]
+	url = strings.TrimSpace(s[closeLabel+2 : closeLabel+2+closeURL])
+	if label == "" || !safeURL(url) {
+		return "", "", 0, false
+	}
+	return label, url, closeLabel + 3 + closeURL, true
+}
+
+// bareURL returns the http or https URL at the start of s, without the punctuation that ends a sentence.
+func bareURL(s string) string {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return ""
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '<' || r == '>' || r == '"' })
+	if end < 0 {
+		end = len(s)
+	}
+	url := strings.TrimRight(s[:end], ".,;:!?)")
+	if !strings.Contains(url, "://") || strings.HasSuffix(url, "://") {
+		return ""
+	}
+	return url
+}
+
+func safeURL(url string) bool {
+	lower := strings.ToLower(url)
+	if strings.ContainsAny(url, " \t\n") {
+		return false
+	}
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:")
+}
+
+// headingLevel returns the number of # that start a heading line, 0 when it is not one.
+func headingLevel(line string) int {
+	n := 0
+	for n < len(line) && n < 6 && line[n] == '#' {
+		n++
+	}
+	if n == 0 || n >= len(line) || line[n] != ' ' {
+		return 0
+	}
+	return n
+}
+
+// listMarker returns ul or ol when the line is a list item.
+func listMarker(line string) string {
+	if len(line) > 2 && strings.IndexByte("-*+", line[0]) >= 0 && line[1] == ' ' {
+		return "ul"
+	}
+	n := 0
+	for n < len(line) && line[n] >= '0' && line[n] <= '9' {
+		n++
+	}
+	if n > 0 && n+2 < len(line) && line[n] == '.' && line[n+1] == ' ' {
+		return "ol"
+	}
+	return ""
+}
+
+func listText(line string) string {
+	if listMarker(line) == "ul" {
+		return strings.TrimSpace(line[2:])
+	}
+	return strings.TrimSpace(line[strings.IndexByte(line, '.')+1:])
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
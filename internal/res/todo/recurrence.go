@@ -0,0 +1,400 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies.
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+const (
+	untilDateLayout     = "20060102"
+	untilDateTimeLayout = "20060102T150405Z"
+	// maxRecurrenceSteps bounds the search for the next occurrence of rules that rarely match, e.g. the 31st every 2 months.
+	maxRecurrenceSteps = 1000
+)
+
+// ErrInvalidRRule is returned when a recurrence rule is not part of the supported RRULE subset.
+var ErrInvalidRRule = errors.New("invalid recurrence rule")
+
+// ErrRecurrenceEnded is returned when a recurring item has no occurrence left.
+var ErrRecurrenceEnded = errors.New("recurrence has no more occurrences")
+
+// ErrNotRecurring is returned when a recurrence operation targets an item that does not recur.
+var ErrNotRecurring = errors.New("item is not recurring")
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// weekdayNames are the RRULE codes indexed by time.Weekday.
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Weekdays in week order, weeks start on Monday as the RRULE default.
+var weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// WeekdayNum is a BYDAY entry, N is the ordinal within the month for monthly rules, e.g. 1 for the first and -1 for the last.
+// Zero means every such weekday.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Recurrence is a subset of an iCalendar RRULE: FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL,
+// BYDAY, BYMONTHDAY, COUNT and UNTIL.
+// Occurrences keep the wall clock time of the first one in the time zone of the item.
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+// ParseRRule parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", an optional "RRULE:" prefix is accepted.
+// A date only UNTIL is taken as the end of that day in loc.
+func ParseRRule(s string, loc *time.Location) (Recurrence, error) {
+	r := Recurrence{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return Recurrence{}, fmt.Errorf("%w: %s", ErrInvalidRRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(val)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(val)
+			if err == nil && r.Interval < 1 {
+				err = ErrInvalidRRule
+			}
+		case "BYDAY":
+			r.ByDay, err = parseByDay(val)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseByMonthDay(val)
+		case "COUNT":
+			r.Count, err = strconv.Atoi(val)
+			if err == nil && r.Count < 1 {
+				err = ErrInvalidRRule
+			}
+		case "UNTIL":
+			r.Until, err = parseUntil(val, loc)
+		default:
+			err = ErrInvalidRRule
+		}
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("%w: %s", ErrInvalidRRule, part)
+		}
+	}
+
+	return r, r.Validate()
+}
+
+// Validate checks that the parts of the rule fit together.
+func (r Recurrence) Validate() error {
+	switch r.Freq {
+	case FreqDaily, FreqWeekly, FreqMonthly:
+	default:
+		return fmt.Errorf("%w: FREQ must be DAILY, WEEKLY or MONTHLY", ErrInvalidRRule)
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return fmt.Errorf("%w: COUNT and UNTIL cannot be used together", ErrInvalidRRule)
+	}
+	if len(r.ByMonthDay) > 0 && r.Freq != FreqMonthly {
+		return fmt.Errorf("%w: BYMONTHDAY needs FREQ=MONTHLY", ErrInvalidRRule)
+	}
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != FreqMonthly {
+			return fmt.Errorf("%w: BYDAY ordinals need FREQ=MONTHLY", ErrInvalidRRule)
+		}
+	}
+	return nil
+}
+
+// String returns the rule in RRULE form, without the "RRULE:" prefix.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilDateTimeLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe returns a short human readable form of the rule, UNTIL is shown as a date in loc.
+func (r Recurrence) Describe(loc *time.Location) string {
+	unit := map[string]string{FreqDaily: "day", FreqWeekly: "week", FreqMonthly: "month"}[r.Freq]
+
+	var b strings.Builder
+	b.WriteString("Every ")
+	if r.Interval > 1 {
+		fmt.Fprintf(&b, "%d %ss", r.Interval, unit)
+	} else {
+		b.WriteString(unit)
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = wd.String()
+		}
+		b.WriteString(" on " + strings.Join(days, ", "))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		b.WriteString(" on day " + strings.Join(days, ", "))
+	}
+	if r.Count > 0 {
+		fmt.Fprintf(&b, ", %d times", r.Count)
+	}
+	if !r.Until.IsZero() {
+		b.WriteString(", until " + r.Until.In(loc).Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+func (wd WeekdayNum) String() string {
+	code := weekdayNames[wd.Weekday]
+	if wd.N == 0 {
+		return code
+	}
+	return strconv.Itoa(wd.N) + code
+}
+
+// Next returns the first occurrence after cur, cur being an occurrence itself.
+// The index is the position of cur in the series starting at 1, it is only used to honor COUNT.
+func (r Recurrence) Next(cur time.Time, index int) (time.Time, bool) {
+	if r.Count > 0 && index >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case FreqDaily:
+		next = r.nextDaily(cur)
+	case FreqWeekly:
+		next = r.nextWeekly(cur)
+	case FreqMonthly:
+		next = r.nextMonthly(cur)
+	}
+
+	if next.IsZero() || (!r.Until.IsZero() && next.After(r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// Occurrences returns up to n occurrences following cur, cur itself excluded.
+func (r Recurrence) Occurrences(cur time.Time, index, n int) []time.Time {
+	var occurrences []time.Time
+	for len(occurrences) < n {
+		next, ok := r.Next(cur, index)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, next)
+		cur = next
+		index++
+	}
+	return occurrences
+}
+
+func (r Recurrence) nextDaily(cur time.Time) time.Time {
+	for step := 1; step <= maxRecurrenceSteps; step++ {
+		next := cur.AddDate(0, 0, step*r.Interval)
+		if len(r.ByDay) == 0 || r.hasWeekday(next.Weekday()) {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (r Recurrence) nextWeekly(cur time.Time) time.Time {
+	days := []time.Weekday{cur.Weekday()}
+	if len(r.ByDay) > 0 {
+		days = nil
+		for _, wd := range weekdays {
+			if r.hasWeekday(wd) {
+				days = append(days, wd)
+			}
+		}
+	}
+
+	weekStart := cur.AddDate(0, 0, -weekdayIndex(cur.Weekday()))
+	for step := 0; step <= maxRecurrenceSteps; step++ {
+		week := weekStart.AddDate(0, 0, 7*step*r.Interval)
+		for _, wd := range days {
+			next := week.AddDate(0, 0, weekdayIndex(wd))
+			if next.After(cur) {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
+
+func (r Recurrence) nextMonthly(cur time.Time) time.Time {
+	loc := cur.Location()
+	hour, min, sec := cur.Clock()
+	for step := 0; step <= maxRecurrenceSteps; step++ {
+		first := time.Date(cur.Year(), cur.Month()+time.Month(step*r.Interval), 1, hour, min, sec, 0, loc)
+		for _, day := range r.monthDays(first, cur.Day()) {
+			next := time.Date(first.Year(), first.Month(), day, hour, min, sec, 0, loc)
+			if next.After(cur) {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// monthDays returns the sorted days of the month the rule matches, the day of the first occurrence if it has no BY part.
+// Days that do not exist in the month, e.g. the 31st in April, are left out.
+func (r Recurrence) monthDays(first time.Time, day int) []int {
+	last := first.AddDate(0, 1, -1).Day()
+	set := make(map[int]bool)
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if day <= last {
+			set[day] = true
+		}
+	}
+	for _, d := range r.ByMonthDay {
+		if d < 0 {
+			d = last + d + 1
+		}
+		if d >= 1 && d <= last {
+			set[d] = true
+		}
+	}
+	for _, wd := range r.ByDay {
+		var matches []int
+		for d := 1; d <= last; d++ {
+			if time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Weekday {
+				matches = append(matches, d)
+			}
+		}
+		switch {
+		case wd.N == 0:
+			for _, d := range matches {
+				set[d] = true
+			}
+		case wd.N > 0 && wd.N <= len(matches):
+			set[matches[wd.N-1]] = true
+		case wd.N < 0 && -wd.N <= len(matches):
+			set[matches[len(matches)+wd.N]] = true
+		}
+	}
+
+	days := make([]int, 0, len(set))
+	for d := range set {
+		days = append(days, d)
+	}
+	sort.Ints(days)
+	return days
+}
+
+func (r Recurrence) hasWeekday(wd time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d.Weekday == wd {
+			return true
+		}
+	}
+	return false
+}
+
+// weekdayIndex returns the position of the weekday in a week starting on Monday.
+func weekdayIndex(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func parseByDay(val string) ([]WeekdayNum, error) {
+	var days []WeekdayNum
+	for _, s := range strings.Split(strings.ToUpper(val), ",") {
+		s = strings.TrimSpace(s)
+		if len(s) < 2 {
+			return nil, ErrInvalidRRule
+		}
+		wd, ok := weekdayCodes[s[len(s)-2:]]
+		if !ok {
+			return nil, ErrInvalidRRule
+		}
+		n := 0
+		if ord := s[:len(s)-2]; ord != "" {
+			var err error
+			n, err = strconv.Atoi(ord)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, ErrInvalidRRule
+			}
+		}
+		days = append(days, WeekdayNum{Weekday: wd, N: n})
+	}
+	return days, nil
+}
+
+func parseByMonthDay(val string) ([]int, error) {
+	var days []int
+	for _, s := range strings.Split(val, ",") {
+		d, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || d == 0 || d < -31 || d > 31 {
+			return nil, ErrInvalidRRule
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+func parseUntil(val string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(untilDateTimeLayout, val); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseInLocation(untilDateLayout, val, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return d.AddDate(0, 0, 1).Add(-time.Second), nil
+}
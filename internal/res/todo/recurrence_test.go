@@ -0,0 +1,96 @@
+package todo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+
+	cases := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []string
+	}{
+		{
+			name:  "daily every other day",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			start: time.Date(2025, 6, 30, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2025-07-02 09:00", "2025-07-04 09:00", "2025-07-06 09:00"},
+		},
+		{
+			name:  "weekly on monday and wednesday every two weeks",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC), // Monday
+			want:  []string{"2025-06-04 09:00", "2025-06-16 09:00", "2025-06-18 09:00"},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2025-03-31 09:00", "2025-05-31 09:00", "2025-07-31 09:00"},
+		},
+		{
+			name:  "monthly on the last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: time.Date(2025, 5, 30, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2025-06-27 09:00", "2025-07-25 09:00", "2025-08-29 09:00"},
+		},
+		{
+			name:  "count ends the series",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC),
+			want:  []string{"2025-06-02 09:00"},
+		},
+		{
+			name:  "wall clock is kept across daylight saving time",
+			rule:  "FREQ=WEEKLY;UNTIL=20251105",
+			start: time.Date(2025, 10, 22, 9, 0, 0, 0, berlin),
+			want:  []string{"2025-10-29 09:00", "2025-11-05 09:00"},
+		},
+	}
+
+	for _, c := range cases {
+		r, err := ParseRRule(c.rule, c.start.Location())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+
+		got := r.Occurrences(c.start, 1, 3)
+		if len(got) != len(c.want) {
+			t.Fatalf("%s: expected %d occurrences, got %v", c.name, len(c.want), got)
+		}
+		for i, occ := range got {
+			if s := occ.Format("2006-01-02 15:04"); s != c.want[i] {
+				t.Errorf("%s: occurrence %d expected %s, got %s", c.name, i, c.want[i], s)
+			}
+		}
+	}
+}
+
+func TestParseRRuleRejectsUnsupported(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=YEARLY",
+		"FREQ=DAILY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250101",
+		"FREQ=DAILY;BYHOUR=9",
+	} {
+		if _, err := ParseRRule(rule, time.UTC); err == nil {
+			t.Errorf("%s: expected an error", rule)
+		}
+	}
+
+	r, err := ParseRRule("RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=1MO,-1FR", time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := r.String(); s != "FREQ=MONTHLY;INTERVAL=2;BYDAY=1MO,-1FR" {
+		t.Errorf("unexpected rule text: %s", s)
+	}
+}
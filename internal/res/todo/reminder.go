@@ -0,0 +1,371 @@
+package todo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// Notifier names, the reminder.notifier and assignment.notifier config values select the ones
+// reminders and assignments are sent through.
+const (
+	NotifierLog     = "log"
+	NotifierOutbox  = "outbox"
+	NotifierWebhook = "webhook"
+)
+
+const (
+	defaultReminderInterval = time.Minute
+	webhookTimeout          = 10 * time.Second
+)
+
+// Reminder is a notification that an item is coming due.
+// At is the reminder time, the due time minus one of the offsets of the item.
+type Reminder struct {
+	Item Item
+	List List
+	At   time.Time
+}
+
+// Notifier delivers reminders.
+type Notifier interface {
+	am.Core
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// ReminderScheduler periodically looks for reminders that are due and hands them to the configured notifier.
+type ReminderScheduler struct {
+	am.Core
+	service   Service
+	notifiers map[string]Notifier
+	notifier  Notifier
+	done      chan struct{}
+	stop      sync.Once
+}
+
+func NewReminderScheduler(service Service, notifiers map[string]Notifier, opts ...am.Option) *ReminderScheduler {
+	return &ReminderScheduler{
+		Core:      am.NewCore("reminder-scheduler", opts...),
+		service:   service,
+		notifiers: notifiers,
+		done:      make(chan struct{}),
+	}
+}
+
+// Setup selects the notifier, reminders are logged unless another one is configured.
+func (s *ReminderScheduler) Setup(ctx context.Context) error {
+	name := s.Cfg().StrValOrDef(am.Key.ReminderNotifier, NotifierLog)
+	notifier, ok := s.notifiers[name]
+	if !ok {
+		return fmt.Errorf("unknown reminder notifier: %s", name)
+	}
+	s.notifier = notifier
+	s.Log().Infof("Reminders are sent through the %s notifier", name)
+	return nil
+}
+
+// Start checks for reminders right away and then on every interval.
+func (s *ReminderScheduler) Start(ctx context.Context) error {
+	interval := s.interval()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.Run(ctx, time.Now())
+
+			select {
+			case <-ticker.C:
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop ends the scheduler loop, it can be called more than once.
+func (s *ReminderScheduler) Stop(ctx context.Context) error {
+	s.stop.Do(func() {
+		close(s.done)
+	})
+	return nil
+}
+
+// Run notifies the reminders due at the given time, acting as the system actor.
+// A reminder is marked as sent only when the notifier succeeds, failed ones are retried on the next run.
+func (s *ReminderScheduler) Run(ctx context.Context, now time.Time) {
+	ctx = am.WithSystemActor(ctx)
+	reminders, err := s.service.GetPendingReminders(ctx, now)
+	if err != nil {
+		s.Log().Errorf("cannot get pending reminders: %v", err)
+		return
+	}
+
+	for _, reminder := range reminders {
+		err := s.notifier.Notify(ctx, reminder)
+		if err != nil {
+			s.Log().Errorf("cannot notify reminder for item %s: %v", reminder.Item.ID(), err)
+			continue
+		}
+
+		err = s.service.MarkReminded(ctx, reminder)
+		if err != nil {
+			s.Log().Errorf("cannot mark reminder for item %s: %v", reminder.Item.ID(), err)
+		}
+	}
+}
+
+func (s *ReminderScheduler) interval() time.Duration {
+	val, ok := s.Cfg().StrVal(am.Key.ReminderInterval)
+	if !ok {
+		return defaultReminderInterval
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		s.Log().Errorf("invalid duration for %s: %s", am.Key.ReminderInterval, val)
+		return defaultReminderInterval
+	}
+
+	return d
+}
+
+// LogNotifier writes reminders to the app log.
+type LogNotifier struct {
+	am.Core
+}
+
+func NewLogNotifier(opts ...am.Option) *LogNotifier {
+	return &LogNotifier{
+		Core: am.NewCore("reminder-log-notifier", opts...),
+	}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	n.Log().Infof("Reminder: %s in %s is due at %s", reminder.Item.Title, reminder.List.Name,
+		reminder.Item.LocalDue().Format(time.RFC1123))
+	return nil
+}
+
+func (n *LogNotifier) NotifyAssignment(ctx context.Context, assignment Assignment) error {
+	n.Log().Infof("Assignment: %s in %s was assigned to %s", assignment.Item.Title, assignment.List.Name, assignment.Name)
+	return nil
+}
+
+// OutboxMessage is an email waiting in the outbox.
+type OutboxMessage struct {
+	ID        uuid.UUID `json:"id"`
+	To        string    `json:"to"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OutboxNotifier queues a reminder email for each recipient of the list.
+// Messages stay in the outbox until a mailer drains them.
+type OutboxNotifier struct {
+	am.Core
+	service  Service
+	mu       sync.Mutex
+	messages []OutboxMessage
+}
+
+func NewOutboxNotifier(service Service, opts ...am.Option) *OutboxNotifier {
+	return &OutboxNotifier{
+		Core:    am.NewCore("reminder-outbox-notifier", opts...),
+		service: service,
+	}
+}
+
+func (n *OutboxNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	users, err := n.service.GetReminderRecipients(ctx, reminder.List)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Reminder: %s", reminder.Item.Title)
+	body := fmt.Sprintf("%s in %s is due at %s.", reminder.Item.Title, reminder.List.Name,
+		reminder.Item.LocalDue().Format(time.RFC1123))
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	queued := 0
+	for _, user := range users {
+		if user.Email == "" {
+			continue
+		}
+		n.messages = append(n.messages, OutboxMessage{
+			ID:        uuid.New(),
+			To:        user.Email,
+			Subject:   subject,
+			Body:      body,
+			CreatedAt: time.Now(),
+		})
+		queued++
+	}
+
+	n.Log().Infof("Queued %d reminder emails for %s", queued, reminder.Item.Title)
+	return nil
+}
+
+// NotifyAssignment queues an email for each recipient of the assignment.
+func (n *OutboxNotifier) NotifyAssignment(ctx context.Context, assignment Assignment) error {
+	subject := fmt.Sprintf("Assigned: %s", assignment.Item.Title)
+	body := fmt.Sprintf("%s in %s was assigned to %s.", assignment.Item.Title, assignment.List.Name, assignment.Name)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	queued := 0
+	for _, user := range assignment.Recipients {
+		if user.Email == "" {
+			continue
+		}
+		n.messages = append(n.messages, OutboxMessage{
+			ID:        uuid.New(),
+			To:        user.Email,
+			Subject:   subject,
+			Body:      body,
+			CreatedAt: time.Now(),
+		})
+		queued++
+	}
+
+	n.Log().Infof("Queued %d assignment emails for %s", queued, assignment.Item.Title)
+	return nil
+}
+
+// Drain returns the queued messages and empties the outbox.
+func (n *OutboxNotifier) Drain() []OutboxMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	messages := n.messages
+	n.messages = nil
+	return messages
+}
+
+// Messages returns the queued messages.
+func (n *OutboxNotifier) Messages() []OutboxMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]OutboxMessage(nil), n.messages...)
+}
+
+// WebhookNotifier posts reminders as JSON to the URL set in reminder.webhook.url,
+// and assignments to the one in assignment.webhook.url, the reminder one when it is not set.
+type WebhookNotifier struct {
+	am.Core
+	client *http.Client
+}
+
+func NewWebhookNotifier(opts ...am.Option) *WebhookNotifier {
+	return &WebhookNotifier{
+		Core:   am.NewCore("reminder-webhook-notifier", opts...),
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// webhookPayload is the body posted by the webhook notifier.
+type webhookPayload struct {
+	Event    string    `json:"event"`
+	ListID   uuid.UUID `json:"list_id"`
+	ListName string    `json:"list_name"`
+	ItemID   uuid.UUID `json:"item_id"`
+	Title    string    `json:"title"`
+	DueAt    time.Time `json:"due_at"`
+	TimeZone string    `json:"time_zone"`
+	RemindAt time.Time `json:"remind_at"`
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	url, ok := n.Cfg().StrVal(am.Key.ReminderWebhookURL)
+	if !ok || url == "" {
+		return errors.New("reminder webhook URL not configured")
+	}
+
+	return n.post(ctx, url, webhookPayload{
+		Event:    "todo.reminder",
+		ListID:   reminder.List.ID(),
+		ListName: reminder.List.Name,
+		ItemID:   reminder.Item.ID(),
+		Title:    reminder.Item.Title,
+		DueAt:    reminder.Item.DueAt,
+		TimeZone: reminder.Item.TimeZone,
+		RemindAt: reminder.At,
+	})
+}
+
+// assignmentPayload is the body posted by the webhook notifier for assignments.
+type assignmentPayload struct {
+	Event        string    `json:"event"`
+	ListID       uuid.UUID `json:"list_id"`
+	ListName     string    `json:"list_name"`
+	ItemID       uuid.UUID `json:"item_id"`
+	Title        string    `json:"title"`
+	AssigneeType string    `json:"assignee_type"`
+	AssigneeID   uuid.UUID `json:"assignee_id"`
+	AssigneeName string    `json:"assignee_name"`
+	AssignedBy   uuid.UUID `json:"assigned_by"`
+}
+
+func (n *WebhookNotifier) NotifyAssignment(ctx context.Context, assignment Assignment) error {
+	url := n.Cfg().StrValOrDef(am.Key.AssignmentWebhookURL, "")
+	if url == "" {
+		url = n.Cfg().StrValOrDef(am.Key.ReminderWebhookURL, "")
+	}
+	if url == "" {
+		return errors.New("assignment webhook URL not configured")
+	}
+
+	return n.post(ctx, url, assignmentPayload{
+		Event:        "todo.assigned",
+		ListID:       assignment.List.ID(),
+		ListName:     assignment.List.Name,
+		ItemID:       assignment.Item.ID(),
+		Title:        assignment.Item.Title,
+		AssigneeType: assignment.Assignee.Type,
+		AssigneeID:   assignment.Assignee.ID,
+		AssigneeName: assignment.Name,
+		AssignedBy:   assignment.AssignedBy,
+	})
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", res.Status)
+	}
+	return nil
+}
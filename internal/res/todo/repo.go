@@ -2,47 +2,128 @@ package todo
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
 	"github.com/google/uuid"
 )
 
 type Repo interface {
-	GetAll(ctx context.Context) ([]List, error)
+	GetAll(ctx context.Context, orgID uuid.UUID) ([]List, error)
 	Get(ctx context.Context, id uuid.UUID) (List, error)
 	Create(ctx context.Context, list List) error
 	Update(ctx context.Context, list List) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, list List) error
+	GetDeleted(ctx context.Context, orgID uuid.UUID) ([]List, error)
+	Restore(ctx context.Context, list List) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	GetShares(ctx context.Context, listID uuid.UUID) ([]Share, error)
+	SaveShare(ctx context.Context, share Share) error
+	DeleteShare(ctx context.Context, listID uuid.UUID, granteeType string, granteeID uuid.UUID) error
+	GetItems(ctx context.Context, listIDs ...uuid.UUID) ([]Item, error)
+	GetItem(ctx context.Context, id uuid.UUID) (Item, error)
+	CreateItem(ctx context.Context, item Item) error
+	UpdateItem(ctx context.Context, item Item) error
+	DeleteItem(ctx context.Context, id uuid.UUID) error
+	SetItemRemindedAt(ctx context.Context, id uuid.UUID, at time.Time) error
+	GetSavedFilters(ctx context.Context, userID uuid.UUID) ([]SavedFilter, error)
+	SaveFilter(ctx context.Context, filter SavedFilter) (SavedFilter, error)
+	DeleteSavedFilter(ctx context.Context, userID, id uuid.UUID) error
+	GetFeedToken(ctx context.Context, userID uuid.UUID) (FeedToken, error)
+	FindFeedToken(ctx context.Context, token string) (FeedToken, error)
+	SaveFeedToken(ctx context.Context, token FeedToken) error
+	GetComments(ctx context.Context, listID uuid.UUID) ([]Comment, error)
+	GetComment(ctx context.Context, listID, id uuid.UUID) (Comment, error)
+	CreateComment(ctx context.Context, comment Comment) error
+	UpdateComment(ctx context.Context, comment Comment) error
+	DeleteComment(ctx context.Context, listID, id uuid.UUID) error
+	GetEvents(ctx context.Context, listID uuid.UUID) ([]Event, error)
+	AddEvent(ctx context.Context, event Event) error
+	GetRevisions(ctx context.Context, listID uuid.UUID) ([]Revision, error)
+	GetRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error)
+	AddRevision(ctx context.Context, revision Revision) error
+	GetAttachments(ctx context.Context, itemIDs ...uuid.UUID) ([]Attachment, error)
+	GetAttachment(ctx context.Context, itemID, id uuid.UUID) (Attachment, error)
+	CreateAttachment(ctx context.Context, attachment Attachment) error
+	DeleteAttachment(ctx context.Context, itemID, id uuid.UUID) error
+	GetTimeEntries(ctx context.Context, listIDs ...uuid.UUID) ([]TimeEntry, error)
+	GetTimeEntry(ctx context.Context, listID, id uuid.UUID) (TimeEntry, error)
+	GetRunningTimer(ctx context.Context, userID uuid.UUID) (TimeEntry, error)
+	CreateTimeEntry(ctx context.Context, entry TimeEntry) error
+	UpdateTimeEntry(ctx context.Context, entry TimeEntry) error
+	DeleteTimeEntry(ctx context.Context, listID, id uuid.UUID) error
+	BeginTx(ctx context.Context) (context.Context, am.Tx, error)
 	Debug()
 }
 
+// ErrListNotFound is returned when a list does not exist or is not visible in the current org.
+var ErrListNotFound = errors.New("list not found")
+
+// ErrShareNotFound is returned when the list is not shared with the grantee.
+var ErrShareNotFound = errors.New("share not found")
+
 type BaseRepo struct {
 	*am.BaseRepo
-	mu    sync.Mutex
-	lists map[uuid.UUID]ListDA
-	order []uuid.UUID
+	mu sync.Mutex
+	// txMu is held by the open transaction, see BeginTx.
+	txMu   sync.Mutex
+	lists  map[uuid.UUID]ListDA
+	order  []uuid.UUID
+	shares map[uuid.UUID][]Share
+	items  map[uuid.UUID]ItemDA
+	iorder []uuid.UUID
+	// tags holds the item and tag pairs, keyed by item.
+	tags    map[uuid.UUID][]string
+	filters map[uuid.UUID][]SavedFilter
+	// feeds holds the calendar feed token of each user.
+	feeds map[uuid.UUID]FeedToken
+	// comments, events and revisions are keyed by list, item ones included.
+	comments  map[uuid.UUID][]Comment
+	events    map[uuid.UUID][]Event
+	revisions map[uuid.UUID][]Revision
+	// attachments are keyed by item.
+	attachments map[uuid.UUID][]Attachment
+	// timeEntries are keyed by list, they are kept when their item is deleted.
+	timeEntries map[uuid.UUID][]TimeEntry
 }
 
 func NewRepo(qm *am.QueryManager, opts ...am.Option) *BaseRepo {
 	repo := &BaseRepo{
-		BaseRepo: am.NewRepo("todo-repo", qm, opts...),
-		lists:    make(map[uuid.UUID]ListDA),
-		order:    []uuid.UUID{},
+		BaseRepo:    am.NewRepo("todo-repo", qm, opts...),
+		lists:       make(map[uuid.UUID]ListDA),
+		order:       []uuid.UUID{},
+		shares:      make(map[uuid.UUID][]Share),
+		items:       make(map[uuid.UUID]ItemDA),
+		iorder:      []uuid.UUID{},
+		tags:        make(map[uuid.UUID][]string),
+		filters:     make(map[uuid.UUID][]SavedFilter),
+		feeds:       make(map[uuid.UUID]FeedToken),
+		comments:    make(map[uuid.UUID][]Comment),
+		events:      make(map[uuid.UUID][]Event),
+		revisions:   make(map[uuid.UUID][]Revision),
+		attachments: make(map[uuid.UUID][]Attachment),
+		timeEntries: make(map[uuid.UUID][]TimeEntry),
 	}
 
 	return repo
 }
 
-func (repo *BaseRepo) GetAll(ctx context.Context) ([]List, error) {
+// GetAll returns the lists of the org, all of them if orgID is Nil.
+func (repo *BaseRepo) GetAll(ctx context.Context, orgID uuid.UUID) ([]List, error) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
 	var result []List
 	for _, id := range repo.order {
-		result = append(result, toList(repo.lists[id]))
+		listDA := repo.lists[id]
+		if listDA.DeletedAt.Valid || !inOrg(listDA, orgID) {
+			continue
+		}
+		result = append(result, toList(listDA))
 	}
 	return result, nil
 }
@@ -52,8 +133,8 @@ func (repo *BaseRepo) Get(ctx context.Context, id uuid.UUID) (List, error) {
 	defer repo.mu.Unlock()
 
 	listDA, exists := repo.lists[id]
-	if !exists {
-		return List{}, errors.New("list not found")
+	if !exists || listDA.DeletedAt.Valid {
+		return List{}, ErrListNotFound
 	}
 	return toList(listDA), nil
 }
@@ -76,31 +157,137 @@ func (repo *BaseRepo) Update(ctx context.Context, list List) error {
 	defer repo.mu.Unlock()
 
 	listDA := toListDA(list)
-	if _, exists := repo.lists[listDA.ID]; !exists {
+	current, exists := repo.lists[listDA.ID]
+	if !exists || current.DeletedAt.Valid {
 		msg := fmt.Sprintf("list not found for ID: %s", listDA.ID)
 		return errors.New(msg)
 	}
+	if current.Version != listDA.Version {
+		return am.NewConflictError(list.Type(), list.ID(), list.Version())
+	}
+	listDA.Version++
+	repo.lists[listDA.ID] = listDA
+	list.SetVersion(listDA.Version)
+	return nil
+}
+
+func (repo *BaseRepo) Delete(ctx context.Context, list List) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	listDA, exists := repo.lists[list.ID()]
+	if !exists || listDA.DeletedAt.Valid {
+		return ErrListNotFound
+	}
+	deleted := toListDA(list)
+	listDA.DeletedBy = deleted.DeletedBy
+	listDA.DeletedAt = deleted.DeletedAt
+	repo.lists[listDA.ID] = listDA
+	return nil
+}
+
+// GetDeleted returns the soft deleted lists of the org, all of them if orgID is Nil.
+func (repo *BaseRepo) GetDeleted(ctx context.Context, orgID uuid.UUID) ([]List, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var result []List
+	for _, id := range repo.order {
+		listDA := repo.lists[id]
+		if !listDA.DeletedAt.Valid || !inOrg(listDA, orgID) {
+			continue
+		}
+		result = append(result, toList(listDA))
+	}
+	return result, nil
+}
+
+func (repo *BaseRepo) Restore(ctx context.Context, list List) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	listDA, exists := repo.lists[list.ID()]
+	if !exists || !listDA.DeletedAt.Valid {
+		return errors.New("list not found in trash")
+	}
+	restored := toListDA(list)
+	listDA.DeletedBy = sql.NullString{}
+	listDA.DeletedAt = sql.NullTime{}
+	listDA.UpdatedBy = restored.UpdatedBy
+	listDA.UpdatedAt = restored.UpdatedAt
+	listDA.Version++
 	repo.lists[listDA.ID] = listDA
 	return nil
 }
 
-func (repo *BaseRepo) Delete(ctx context.Context, id uuid.UUID) error {
+// PurgeDeleted removes the lists that were soft deleted before the given time.
+func (repo *BaseRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
 
-	if _, exists := repo.lists[id]; !exists {
-		return errors.New("list not found")
+	var purged int64
+	order := repo.order[:0]
+	for _, id := range repo.order {
+		listDA := repo.lists[id]
+		if listDA.DeletedAt.Valid && listDA.DeletedAt.Time.Before(before) {
+			delete(repo.lists, id)
+			delete(repo.shares, id)
+			delete(repo.comments, id)
+			delete(repo.events, id)
+			delete(repo.revisions, id)
+			delete(repo.timeEntries, id)
+			purged++
+			continue
+		}
+		order = append(order, id)
 	}
-	delete(repo.lists, id)
-	for i, oid := range repo.order {
-		if oid == id {
-			repo.order = append(repo.order[:i], repo.order[i+1:]...)
-			break
+	repo.order = order
+	repo.purgeOrphanItems()
+	return purged, nil
+}
+
+// GetShares returns the shares of the list.
+func (repo *BaseRepo) GetShares(ctx context.Context, listID uuid.UUID) ([]Share, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return append([]Share(nil), repo.shares[listID]...), nil
+}
+
+// SaveShare adds the share, replacing the role when the list is already shared with the grantee.
+func (repo *BaseRepo) SaveShare(ctx context.Context, share Share) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	shares := repo.shares[share.ListID]
+	for i, s := range shares {
+		if s.GranteeType == share.GranteeType && s.GranteeID == share.GranteeID {
+			shares[i].Role = share.Role
+			return nil
 		}
 	}
+	repo.shares[share.ListID] = append(shares, share)
 	return nil
 }
 
+func (repo *BaseRepo) DeleteShare(ctx context.Context, listID uuid.UUID, granteeType string, granteeID uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	shares := repo.shares[listID]
+	for i, s := range shares {
+		if s.GranteeType == granteeType && s.GranteeID == granteeID {
+			repo.shares[listID] = append(shares[:i:i], shares[i+1:]...)
+			return nil
+		}
+	}
+	return ErrShareNotFound
+}
+
+func inOrg(listDA ListDA, orgID uuid.UUID) bool {
+	return orgID == uuid.Nil || listDA.OrgID == orgID
+}
+
 func (repo *BaseRepo) Debug() {
 	repo.mu.Lock()
 	defer repo.mu.Unlock()
@@ -0,0 +1,53 @@
+package todo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetAttachments returns the attachments of the items, oldest first.
+func (repo *BaseRepo) GetAttachments(ctx context.Context, itemIDs ...uuid.UUID) ([]Attachment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var result []Attachment
+	for _, id := range itemIDs {
+		result = append(result, repo.attachments[id]...)
+	}
+	return result, nil
+}
+
+func (repo *BaseRepo) GetAttachment(ctx context.Context, itemID, id uuid.UUID) (Attachment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, a := range repo.attachments[itemID] {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return Attachment{}, ErrAttachmentNotFound
+}
+
+func (repo *BaseRepo) CreateAttachment(ctx context.Context, attachment Attachment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.attachments[attachment.ItemID] = append(repo.attachments[attachment.ItemID], attachment)
+	return nil
+}
+
+func (repo *BaseRepo) DeleteAttachment(ctx context.Context, itemID, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	attachments := repo.attachments[itemID]
+	for i, a := range attachments {
+		if a.ID == id {
+			repo.attachments[itemID] = append(attachments[:i:i], attachments[i+1:]...)
+			return nil
+		}
+	}
+	return ErrAttachmentNotFound
+}
@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetComments returns the comments of the list and of its items, oldest first.
+func (repo *BaseRepo) GetComments(ctx context.Context, listID uuid.UUID) ([]Comment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return append([]Comment(nil), repo.comments[listID]...), nil
+}
+
+func (repo *BaseRepo) GetComment(ctx context.Context, listID, id uuid.UUID) (Comment, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, c := range repo.comments[listID] {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return Comment{}, ErrCommentNotFound
+}
+
+func (repo *BaseRepo) CreateComment(ctx context.Context, comment Comment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.comments[comment.ListID] = append(repo.comments[comment.ListID], comment)
+	return nil
+}
+
+func (repo *BaseRepo) UpdateComment(ctx context.Context, comment Comment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	comments := repo.comments[comment.ListID]
+	for i, c := range comments {
+		if c.ID == comment.ID {
+			comments[i] = comment
+			return nil
+		}
+	}
+	return ErrCommentNotFound
+}
+
+func (repo *BaseRepo) DeleteComment(ctx context.Context, listID, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	comments := repo.comments[listID]
+	for i, c := range comments {
+		if c.ID == id {
+			repo.comments[listID] = append(comments[:i:i], comments[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCommentNotFound
+}
+
+// GetEvents returns the events of the list and of its items, oldest first.
+func (repo *BaseRepo) GetEvents(ctx context.Context, listID uuid.UUID) ([]Event, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return append([]Event(nil), repo.events[listID]...), nil
+}
+
+func (repo *BaseRepo) AddEvent(ctx context.Context, event Event) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.events[event.ListID] = append(repo.events[event.ListID], event)
+	return nil
+}
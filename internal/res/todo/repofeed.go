@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/google/uuid"
+)
+
+// GetFeedToken returns the feed token of the user.
+func (repo *BaseRepo) GetFeedToken(ctx context.Context, userID uuid.UUID) (FeedToken, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	token, ok := repo.feeds[userID]
+	if !ok {
+		return FeedToken{}, ErrFeedNotFound
+	}
+	return token, nil
+}
+
+// FindFeedToken returns the feed token with the given secret, tokens are compared in constant time.
+func (repo *BaseRepo) FindFeedToken(ctx context.Context, token string) (FeedToken, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, t := range repo.feeds {
+		if subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			return t, nil
+		}
+	}
+	return FeedToken{}, ErrFeedNotFound
+}
+
+// SaveFeedToken stores the feed token of its user, replacing the previous one.
+func (repo *BaseRepo) SaveFeedToken(ctx context.Context, token FeedToken) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.feeds[token.UserID] = token
+	return nil
+}
@@ -0,0 +1,57 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrFilterNotFound is returned when the user has no saved filter with the given ID.
+var ErrFilterNotFound = errors.New("filter not found")
+
+// GetSavedFilters returns the saved filters of the user sorted by name.
+func (repo *BaseRepo) GetSavedFilters(ctx context.Context, userID uuid.UUID) ([]SavedFilter, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	filters := append([]SavedFilter(nil), repo.filters[userID]...)
+	sort.SliceStable(filters, func(i, j int) bool {
+		return strings.ToLower(filters[i].Name) < strings.ToLower(filters[j].Name)
+	})
+	return filters, nil
+}
+
+// SaveFilter adds a saved filter, a filter of the same user with the same name is replaced keeping its ID.
+func (repo *BaseRepo) SaveFilter(ctx context.Context, filter SavedFilter) (SavedFilter, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	filters := repo.filters[filter.UserID]
+	for i, f := range filters {
+		if strings.EqualFold(f.Name, filter.Name) {
+			filter.ID = f.ID
+			filter.CreatedAt = f.CreatedAt
+			filters[i] = filter
+			return filter, nil
+		}
+	}
+	repo.filters[filter.UserID] = append(filters, filter)
+	return filter, nil
+}
+
+func (repo *BaseRepo) DeleteSavedFilter(ctx context.Context, userID, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	filters := repo.filters[userID]
+	for i, f := range filters {
+		if f.ID == id {
+			repo.filters[userID] = append(filters[:i:i], filters[i+1:]...)
+			return nil
+		}
+	}
+	return ErrFilterNotFound
+}
@@ -0,0 +1,163 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// ErrItemNotFound is returned when an item does not exist.
+var ErrItemNotFound = errors.New("item not found")
+
+// GetItems returns the items of the given lists in creation order, all of them if no list is given.
+func (repo *BaseRepo) GetItems(ctx context.Context, listIDs ...uuid.UUID) ([]Item, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	inLists := make(map[uuid.UUID]bool, len(listIDs))
+	for _, id := range listIDs {
+		inLists[id] = true
+	}
+
+	var result []Item
+	for _, id := range repo.iorder {
+		itemDA := repo.items[id]
+		if len(listIDs) > 0 && !inLists[itemDA.ListID] {
+			continue
+		}
+		result = append(result, repo.withTags(toItem(itemDA)))
+	}
+	return result, nil
+}
+
+func (repo *BaseRepo) GetItem(ctx context.Context, id uuid.UUID) (Item, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	itemDA, exists := repo.items[id]
+	if !exists {
+		return Item{}, ErrItemNotFound
+	}
+	return repo.withTags(toItem(itemDA)), nil
+}
+
+func (repo *BaseRepo) CreateItem(ctx context.Context, item Item) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	itemDA := toItemDA(item)
+	if _, exists := repo.items[itemDA.ID]; exists {
+		return errors.New("item already exists")
+	}
+	repo.items[itemDA.ID] = itemDA
+	repo.iorder = append(repo.iorder, itemDA.ID)
+	repo.setTags(itemDA.ID, item.Tags)
+	return nil
+}
+
+func (repo *BaseRepo) UpdateItem(ctx context.Context, item Item) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	itemDA := toItemDA(item)
+	current, exists := repo.items[itemDA.ID]
+	if !exists {
+		return ErrItemNotFound
+	}
+	if current.Version != itemDA.Version {
+		return am.NewConflictError(item.Type(), item.ID(), item.Version())
+	}
+	itemDA.Version++
+	repo.items[itemDA.ID] = itemDA
+	repo.setTags(itemDA.ID, item.Tags)
+	item.SetVersion(itemDA.Version)
+	return nil
+}
+
+// withTags attaches the tags of the item, the caller must hold the lock.
+func (repo *BaseRepo) withTags(item Item) Item {
+	item.Tags = append([]string(nil), repo.tags[item.ID()]...)
+	return item
+}
+
+// setTags replaces the tags of the item, the caller must hold the lock.
+func (repo *BaseRepo) setTags(id uuid.UUID, tags []string) {
+	if len(tags) == 0 {
+		delete(repo.tags, id)
+		return
+	}
+	repo.tags[id] = append([]string(nil), tags...)
+}
+
+// SetItemRemindedAt records the latest notified reminder of the item.
+// It is bookkeeping of the scheduler, the version is left alone so that it does not conflict with user edits.
+func (repo *BaseRepo) SetItemRemindedAt(ctx context.Context, id uuid.UUID, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	itemDA, exists := repo.items[id]
+	if !exists {
+		return ErrItemNotFound
+	}
+	itemDA.RemindedAt = am.NewNullTime(at)
+	repo.items[id] = itemDA
+	return nil
+}
+
+func (repo *BaseRepo) DeleteItem(ctx context.Context, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if _, exists := repo.items[id]; !exists {
+		return ErrItemNotFound
+	}
+	delete(repo.items, id)
+	delete(repo.tags, id)
+	delete(repo.attachments, id)
+	repo.iorder = removeID(repo.iorder, id)
+	repo.removeDependency(id)
+	return nil
+}
+
+// removeDependency drops a deleted item from the dependencies of the others, the caller must hold the lock.
+// Like a foreign key cascade it leaves the versions alone.
+func (repo *BaseRepo) removeDependency(id uuid.UUID) {
+	ref := id.String()
+	for otherID, itemDA := range repo.items {
+		if !strings.Contains(itemDA.BlockedBy.String, ref) {
+			continue
+		}
+		item := toItem(itemDA)
+		item.BlockedBy = removeID(item.BlockedBy, id)
+		itemDA.BlockedBy = am.NewNullString(joinIDs(item.BlockedBy))
+		repo.items[otherID] = itemDA
+	}
+}
+
+// purgeOrphanItems removes the items whose list no longer exists, the caller must hold the lock.
+func (repo *BaseRepo) purgeOrphanItems() {
+	order := repo.iorder[:0]
+	for _, id := range repo.iorder {
+		if _, exists := repo.lists[repo.items[id].ListID]; !exists {
+			delete(repo.items, id)
+			delete(repo.tags, id)
+			delete(repo.attachments, id)
+			continue
+		}
+		order = append(order, id)
+	}
+	repo.iorder = order
+}
+
+func removeID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i:i], ids[i+1:]...)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,35 @@
+package todo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetRevisions returns the revisions of the list and of its items, oldest first.
+func (repo *BaseRepo) GetRevisions(ctx context.Context, listID uuid.UUID) ([]Revision, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return append([]Revision(nil), repo.revisions[listID]...), nil
+}
+
+func (repo *BaseRepo) GetRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, r := range repo.revisions[listID] {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+func (repo *BaseRepo) AddRevision(ctx context.Context, revision Revision) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.revisions[revision.ListID] = append(repo.revisions[revision.ListID], revision)
+	return nil
+}
@@ -0,0 +1,93 @@
+package todo
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// GetTimeEntries returns the time entries of the lists, oldest first within each list.
+func (repo *BaseRepo) GetTimeEntries(ctx context.Context, listIDs ...uuid.UUID) ([]TimeEntry, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var result []TimeEntry
+	for _, id := range listIDs {
+		result = append(result, repo.timeEntries[id]...)
+	}
+	return result, nil
+}
+
+func (repo *BaseRepo) GetTimeEntry(ctx context.Context, listID, id uuid.UUID) (TimeEntry, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, e := range repo.timeEntries[listID] {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return TimeEntry{}, ErrTimeEntryNotFound
+}
+
+// GetRunningTimer returns the timer of the user that has not been stopped, whatever its list.
+func (repo *BaseRepo) GetRunningTimer(ctx context.Context, userID uuid.UUID) (TimeEntry, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.runningTimer(userID)
+}
+
+// CreateTimeEntry stores the entry, a running timer only when the user has no other one running.
+func (repo *BaseRepo) CreateTimeEntry(ctx context.Context, entry TimeEntry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if entry.IsRunning() {
+		if _, err := repo.runningTimer(entry.UserID); err == nil {
+			return ErrTimerRunning
+		}
+	}
+	repo.timeEntries[entry.ListID] = append(repo.timeEntries[entry.ListID], entry)
+	return nil
+}
+
+func (repo *BaseRepo) UpdateTimeEntry(ctx context.Context, entry TimeEntry) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	entries := repo.timeEntries[entry.ListID]
+	for i, e := range entries {
+		if e.ID == entry.ID {
+			entries[i] = entry
+			return nil
+		}
+	}
+	return ErrTimeEntryNotFound
+}
+
+func (repo *BaseRepo) DeleteTimeEntry(ctx context.Context, listID, id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	entries := repo.timeEntries[listID]
+	for i, e := range entries {
+		if e.ID == id {
+			repo.timeEntries[listID] = append(entries[:i:i], entries[i+1:]...)
+			return nil
+		}
+	}
+	return ErrTimeEntryNotFound
+}
+
+// runningTimer must be called with mu held.
+func (repo *BaseRepo) runningTimer(userID uuid.UUID) (TimeEntry, error) {
+	for _, entries := range repo.timeEntries {
+		for _, e := range entries {
+			if e.UserID == userID && e.IsRunning() {
+				return e, nil
+			}
+		}
+	}
+	return TimeEntry{}, ErrNoTimerRunning
+}
@@ -0,0 +1,126 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// errTxDone is returned when a transaction is committed or rolled back twice.
+var errTxDone = errors.New("transaction already finished")
+
+// memState is a copy of everything the in-memory repo holds.
+type memState struct {
+	lists       map[uuid.UUID]ListDA
+	order       []uuid.UUID
+	shares      map[uuid.UUID][]Share
+	items       map[uuid.UUID]ItemDA
+	iorder      []uuid.UUID
+	tags        map[uuid.UUID][]string
+	filters     map[uuid.UUID][]SavedFilter
+	feeds       map[uuid.UUID]FeedToken
+	comments    map[uuid.UUID][]Comment
+	events      map[uuid.UUID][]Event
+	revisions   map[uuid.UUID][]Revision
+	attachments map[uuid.UUID][]Attachment
+	timeEntries map[uuid.UUID][]TimeEntry
+}
+
+// memTx is a transaction of the in-memory repo, rolling it back puts back the state it began on.
+// Transactions run one at a time; writes made outside of one while it is open are undone by its rollback too.
+type memTx struct {
+	repo  *BaseRepo
+	state memState
+	done  bool
+}
+
+// memTxContextKey keeps the transaction apart from the one am.WithTx stores, which the auth repo
+// joins: a context carrying a todo transaction is handed to the directory too.
+type memTxContextKey struct{}
+
+// BeginTx returns a context carrying a new transaction, see memTxFromContext.
+func (repo *BaseRepo) BeginTx(ctx context.Context) (context.Context, am.Tx, error) {
+	repo.txMu.Lock()
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	tx := &memTx{repo: repo, state: repo.snapshot()}
+	return context.WithValue(ctx, memTxContextKey{}, tx), tx, nil
+}
+
+// memTxFromContext returns the transaction of the in-memory repo carried by the context, if any.
+func memTxFromContext(ctx context.Context) (*memTx, bool) {
+	tx, ok := ctx.Value(memTxContextKey{}).(*memTx)
+	return tx, ok
+}
+
+func (tx *memTx) Commit() error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.done = true
+	tx.repo.txMu.Unlock()
+	return nil
+}
+
+func (tx *memTx) Rollback() error {
+	if tx.done {
+		return errTxDone
+	}
+	tx.done = true
+
+	tx.repo.mu.Lock()
+	tx.repo.restore(tx.state)
+	tx.repo.mu.Unlock()
+
+	tx.repo.txMu.Unlock()
+	return nil
+}
+
+// snapshot copies the state of the repo, slices included so that in place updates do not leak into it.
+func (repo *BaseRepo) snapshot() memState {
+	return memState{
+		lists:       maps.Clone(repo.lists),
+		order:       slices.Clone(repo.order),
+		shares:      cloneSlices(repo.shares),
+		items:       maps.Clone(repo.items),
+		iorder:      slices.Clone(repo.iorder),
+		tags:        cloneSlices(repo.tags),
+		filters:     cloneSlices(repo.filters),
+		feeds:       maps.Clone(repo.feeds),
+		comments:    cloneSlices(repo.comments),
+		events:      cloneSlices(repo.events),
+		revisions:   cloneSlices(repo.revisions),
+		attachments: cloneSlices(repo.attachments),
+		timeEntries: cloneSlices(repo.timeEntries),
+	}
+}
+
+func (repo *BaseRepo) restore(state memState) {
+	repo.lists = state.lists
+	repo.order = state.order
+	repo.shares = state.shares
+	repo.items = state.items
+	repo.iorder = state.iorder
+	repo.tags = state.tags
+	repo.filters = state.filters
+	repo.feeds = state.feeds
+	repo.comments = state.comments
+	repo.events = state.events
+	repo.revisions = state.revisions
+	repo.attachments = state.attachments
+	repo.timeEntries = state.timeEntries
+}
+
+func cloneSlices[K comparable, V any](m map[K][]V) map[K][]V {
+	clone := make(map[K][]V, len(m))
+	for k, v := range m {
+		clone[k] = slices.Clone(v)
+	}
+	return clone
+}
@@ -0,0 +1,138 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRevisionNotFound is returned when the list has no revision with the given ID.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Revision actions.
+const (
+	RevisionCreated  = "created"
+	RevisionUpdated  = "updated"
+	RevisionDeleted  = "deleted"
+	RevisionRestored = "restored"
+)
+
+// revisionIgnored are the snapshot fields left out of diffs: bookkeeping that changes without the user
+// changing anything, and board positions, which change whenever a neighbour moves.
+var revisionIgnored = map[string]bool{
+	"id":          true,
+	"ref":         true,
+	"version":     true,
+	"position":    true,
+	"reminded_at": true,
+}
+
+// Revision is the state of a list, or of one of its items when ItemID is set, after a change.
+// Snapshot is the JSON form of the list or the item, Changes are its differences with the previous revision.
+// The snapshot of a deletion is the state the item or the list was deleted in.
+// RestoredFrom is the revision a restore brought back.
+type Revision struct {
+	ID           uuid.UUID       `json:"id"`
+	ListID       uuid.UUID       `json:"list_id"`
+	ItemID       uuid.UUID       `json:"item_id"`
+	Action       string          `json:"action"`
+	Subject      string          `json:"subject"`
+	Snapshot     json.RawMessage `json:"snapshot"`
+	Changes      []FieldChange   `json:"changes"`
+	ActorID      uuid.UUID       `json:"actor_id"`
+	At           time.Time       `json:"at"`
+	RestoredFrom uuid.UUID       `json:"restored_from"`
+}
+
+// FieldChange is the previous and the new value of a field, as text.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// NewRevision creates a revision of the list, or of the item when itemID is not Nil.
+func NewRevision(listID, itemID uuid.UUID, action, subject string, snapshot json.RawMessage) Revision {
+	return Revision{
+		ID:       uuid.New(),
+		ListID:   listID,
+		ItemID:   itemID,
+		Action:   action,
+		Subject:  subject,
+		Snapshot: snapshot,
+		At:       time.Now(),
+	}
+}
+
+// IsOnItem reports whether the revision is of an item rather than of the list.
+func (r Revision) IsOnItem() bool {
+	return r.ItemID != uuid.Nil
+}
+
+// IsOf reports whether the revision is of the given item, or of the list when itemID is Nil.
+func (r Revision) IsOf(itemID uuid.UUID) bool {
+	return r.ItemID == itemID
+}
+
+// DiffSnapshots returns the fields that differ between two snapshots, in alphabetical order.
+// A missing before snapshot, as in a creation, is taken as empty.
+func DiffSnapshots(before, after json.RawMessage) []FieldChange {
+	from, to := snapshotFields(before), snapshotFields(after)
+
+	names := make([]string, 0, len(to))
+	for name := range to {
+		names = append(names, name)
+	}
+	for name := range from {
+		if _, ok := to[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		if revisionIgnored[name] {
+			continue
+		}
+		f, t := fieldText(from[name]), fieldText(to[name])
+		if f != t {
+			changes = append(changes, FieldChange{Field: name, From: f, To: t})
+		}
+	}
+	return changes
+}
+
+func snapshotFields(snapshot json.RawMessage) map[string]json.RawMessage {
+	fields := map[string]json.RawMessage{}
+	if len(snapshot) > 0 {
+		json.Unmarshal(snapshot, &fields)
+	}
+	return fields
+}
+
+// fieldText returns a snapshot value as text: strings unquoted, zero times, Nil IDs, nulls and
+// empty lists as an empty string, anything else as compact JSON.
+func fieldText(value json.RawMessage) string {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		if s == uuid.Nil.String() || s == (time.Time{}).Format(time.RFC3339) {
+			return ""
+		}
+		return s
+	}
+
+	var buf bytes.Buffer
+	if json.Compact(&buf, value) != nil {
+		return string(value)
+	}
+	text := buf.String()
+	if text == "null" || text == "[]" || text == "{}" {
+		return ""
+	}
+	return text
+}
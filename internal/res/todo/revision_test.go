@@ -0,0 +1,72 @@
+package todo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	item := NewItem(uuid.New(), "Buy milk", "")
+	before, _ := json.Marshal(item)
+
+	item.Title = "Buy oat milk"
+	item.Position = 3
+	item.RemindedAt = time.Now()
+	item.SetVersion(item.Version() + 1)
+	after, _ := json.Marshal(item)
+
+	changes := DiffSnapshots(before, after)
+	if len(changes) != 1 {
+		t.Fatalf("expected only the title to change, got %+v", changes)
+	}
+	if changes[0] != (FieldChange{Field: "title", From: "Buy milk", To: "Buy oat milk"}) {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+}
+
+func TestDiffSnapshotsCreation(t *testing.T) {
+	item := NewItem(uuid.New(), "Buy milk", "")
+	item.Tags = []string{"home"}
+	after, _ := json.Marshal(item)
+
+	changes := DiffSnapshots(nil, after)
+	got := map[string]FieldChange{}
+	for _, c := range changes {
+		got[c.Field] = c
+	}
+	if got["title"].To != "Buy milk" || got["tags"].To != `["home"]` {
+		t.Errorf("expected title and tags to be set, got %+v", changes)
+	}
+	for _, field := range []string{"due_at", "parent_id", "blocked_by", "notes"} {
+		if c, ok := got[field]; ok {
+			t.Errorf("expected empty %s to be left out, got %+v", field, c)
+		}
+	}
+	for i := 1; i < len(changes); i++ {
+		if changes[i-1].Field > changes[i].Field {
+			t.Fatalf("expected changes in alphabetical order, got %+v", changes)
+		}
+	}
+}
+
+func TestPruneLinks(t *testing.T) {
+	listID := uuid.New()
+	kept := NewItem(listID, "Kept", "")
+	item := NewItem(listID, "Restored", "")
+	kept.GenID()
+	item.GenID()
+	item.ParentID = uuid.New()
+	item.BlockedBy = []uuid.UUID{kept.ID(), uuid.New(), item.ID()}
+
+	pruneLinks(&item, []Item{kept, item})
+
+	if item.ParentID != uuid.Nil {
+		t.Errorf("expected the missing parent to be dropped, got %s", item.ParentID)
+	}
+	if len(item.BlockedBy) != 1 || item.BlockedBy[0] != kept.ID() {
+		t.Errorf("expected only the existing dependency to be kept, got %v", item.BlockedBy)
+	}
+}
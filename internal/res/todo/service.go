@@ -2,48 +2,729 @@ package todo
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
 	"github.com/google/uuid"
 )
 
+// ErrForbidden is returned when the actor can see a list but is not allowed to perform the operation.
+var ErrForbidden = errors.New("operation not allowed on this list")
+
+// ErrUnauthenticated is returned when a request that needs an actor has none.
+var ErrUnauthenticated = errors.New("authentication required")
+
+// ErrInvalidShare is returned when a share has an unknown grantee type or role.
+var ErrInvalidShare = errors.New("invalid share")
+
 type Service interface {
-	GetLists(ctx context.Context) ([]List, error)
+	GetLists(ctx context.Context, filter string) ([]List, error)
 	Get(ctx context.Context, id uuid.UUID) (List, error)
 	Create(ctx context.Context, list List) error
 	Update(ctx context.Context, list List) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Archive(ctx context.Context, id uuid.UUID) error
+	Unarchive(ctx context.Context, id uuid.UUID) error
+	SaveAsTemplate(ctx context.Context, id uuid.UUID, name string) (List, error)
+	CreateFromTemplate(ctx context.Context, id uuid.UUID, values TemplateValues) (List, error)
+	GetDeleted(ctx context.Context) ([]List, error)
+	Restore(ctx context.Context, id uuid.UUID) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	Access(ctx context.Context, list List) (Access, error)
+	GetShares(ctx context.Context, listID uuid.UUID) ([]Share, error)
+	ShareList(ctx context.Context, share Share) error
+	UnshareList(ctx context.Context, listID uuid.UUID, granteeType string, granteeID uuid.UUID) error
+	GetShareCandidates(ctx context.Context) ([]auth.User, []auth.Team, error)
+	GetActorTeams(ctx context.Context) ([]auth.Team, error)
+	GetItems(ctx context.Context, listID uuid.UUID) ([]Item, error)
+	GetItem(ctx context.Context, listID, id uuid.UUID) (Item, error)
+	CreateItem(ctx context.Context, item Item) error
+	UpdateItem(ctx context.Context, item Item) error
+	DeleteItem(ctx context.Context, listID, id uuid.UUID) error
+	CompleteItem(ctx context.Context, listID, id uuid.UUID, force bool) error
+	ReopenItem(ctx context.Context, listID, id uuid.UUID) error
+	SkipOccurrence(ctx context.Context, listID, id uuid.UUID) error
+	EndRecurrence(ctx context.Context, listID, id uuid.UUID) error
+	FindItems(ctx context.Context, filter ItemFilter) ([]DueItem, error)
+	GetTags(ctx context.Context) ([]string, error)
+	GetSavedFilters(ctx context.Context) ([]SavedFilter, error)
+	SaveFilter(ctx context.Context, name string, filter ItemFilter) (SavedFilter, error)
+	DeleteSavedFilter(ctx context.Context, id uuid.UUID) error
+	GetBoard(ctx context.Context, listID uuid.UUID) (Board, error)
+	SetBoardColumns(ctx context.Context, listID uuid.UUID, columns []BoardColumn) error
+	MoveItem(ctx context.Context, listID, id uuid.UUID, status Status, index int, force bool) error
+	ReorderItems(ctx context.Context, listID uuid.UUID, status Status, ids []uuid.UUID, force bool) error
+	ExportLists(ctx context.Context, listID uuid.UUID) ([]TransferList, error)
+	Import(ctx context.Context, opts ImportOptions, r io.Reader) (ImportReport, error)
+	GetComments(ctx context.Context, listID, itemID uuid.UUID) ([]Comment, error)
+	GetComment(ctx context.Context, listID, id uuid.UUID) (Comment, error)
+	AddComment(ctx context.Context, comment Comment) (Comment, error)
+	UpdateComment(ctx context.Context, listID, id uuid.UUID, body string) (Comment, error)
+	DeleteComment(ctx context.Context, listID, id uuid.UUID) error
+	GetTimeline(ctx context.Context, listID uuid.UUID) ([]Activity, error)
+	BulkItems(ctx context.Context, listID uuid.UUID, cmd BulkCommand) (am.Batch, error)
+	GetRevisions(ctx context.Context, listID uuid.UUID) ([]Revision, error)
+	GetRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error)
+	RestoreRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error)
+	AttachmentLimits() AttachmentLimits
+	GetAttachments(ctx context.Context, listID, itemID uuid.UUID) ([]Attachment, error)
+	AddAttachment(ctx context.Context, listID, itemID uuid.UUID, name string, r io.Reader) (Attachment, error)
+	OpenAttachment(ctx context.Context, listID, itemID, id uuid.UUID) (Attachment, Blob, error)
+	DeleteAttachment(ctx context.Context, listID, itemID, id uuid.UUID) error
+	AssignItem(ctx context.Context, listID, id uuid.UUID, assignee Assignee) (Item, error)
+	UnassignItem(ctx context.Context, listID, id uuid.UUID, assignee Assignee) (Item, error)
+	GetAssignedItems(ctx context.Context, view string) ([]DueItem, error)
+	GetAssigneeCandidates(ctx context.Context, listID uuid.UUID) ([]auth.User, []auth.Team, error)
+	GetTimeEntries(ctx context.Context, listID, itemID uuid.UUID) ([]TimeEntry, error)
+	StartTimer(ctx context.Context, listID, itemID uuid.UUID, notes string) (TimeEntry, error)
+	StopTimer(ctx context.Context) (TimeEntry, error)
+	GetRunningTimer(ctx context.Context) (TimeEntry, error)
+	AddTimeEntry(ctx context.Context, entry TimeEntry) (TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, listID, id uuid.UUID) error
+	GetTimesheet(ctx context.Context, day time.Time) (Timesheet, error)
+	FindTimeEntries(ctx context.Context, filter TimeFilter) ([]TimeRecord, error)
+	GetFeedToken(ctx context.Context) (FeedToken, error)
+	RegenerateFeedToken(ctx context.Context) (FeedToken, error)
+	GetFeed(ctx context.Context, token string) (Feed, error)
+	GetDueItems(ctx context.Context, view string, now time.Time, loc *time.Location, days int) ([]DueItem, error)
+	GetPendingReminders(ctx context.Context, now time.Time) ([]Reminder, error)
+	MarkReminded(ctx context.Context, reminder Reminder) error
+	GetReminderRecipients(ctx context.Context, list List) ([]auth.User, error)
+}
+
+// Policy actions checked on lists, operations that need editor or owner access are writes.
+const (
+	policyActionRead  = "read"
+	policyActionWrite = "write"
+)
+
+// Directory resolves the users and teams lists are owned by and shared with.
+// It is satisfied by the auth service.
+type Directory interface {
+	GetUsers(ctx context.Context) ([]auth.User, error)
+	GetUser(ctx context.Context, id uuid.UUID) (auth.User, error)
+	GetAllTeams(ctx context.Context, orgID uuid.UUID) ([]auth.Team, error)
+	GetTeam(ctx context.Context, id uuid.UUID) (auth.Team, error)
+	GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]auth.TeamMember, error)
+	RecordAudit(ctx context.Context, entry auth.AuditEntry) error
+	Enforcer(ctx context.Context, userID uuid.UUID) (*auth.Enforcer, error)
 }
 
 type BaseService struct {
 	*am.Service
-	repo Repo
+	repo     Repo
+	dir      Directory
+	blobs    BlobStore
+	notifier AssignmentNotifier
 }
 
-func NewService(repo Repo, opts ...am.Option) *BaseService {
+func NewService(repo Repo, dir Directory, blobs BlobStore, opts ...am.Option) *BaseService {
 	return &BaseService{
 		Service: am.NewService("", opts...),
 		repo:    repo,
+		dir:     dir,
+		blobs:   blobs,
 	}
 }
 
-func (svc *BaseService) GetLists(ctx context.Context) ([]List, error) {
-	return svc.repo.GetAll(ctx)
+// GetLists returns the lists of the org selected for the request that the actor can see.
+// The filter narrows them to the lists the actor owns or to the ones shared with them,
+// archived lists and templates are only returned by the archived and the templates filters.
+func (svc *BaseService) GetLists(ctx context.Context, filter string) ([]List, error) {
+	ctx, authz, err := svc.withListAuthz(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, _ := am.OrgFromContext(ctx)
+	lists, err := svc.repo.GetAll(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var visible []List
+	for _, list := range lists {
+		access, err := svc.access(ctx, authz, list)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case !authz.permits(list, access, AccessViewer):
+			continue
+		case list.Template != (filter == FilterTemplates):
+			continue
+		case !list.Template && list.IsArchived() != (filter == FilterArchived):
+			continue
+		case filter == FilterOwned && access != AccessOwner:
+			continue
+		case filter == FilterShared && access == AccessOwner:
+			continue
+		}
+		visible = append(visible, list)
+	}
+	return visible, nil
 }
 
+// Get returns the list if it belongs to the org selected for the request and the actor can see it.
 func (svc *BaseService) Get(ctx context.Context, id uuid.UUID) (List, error) {
-	return svc.repo.Get(ctx, id)
+	authz, err := svc.listAuthz(ctx)
+	if err != nil {
+		return List{}, err
+	}
+	if !authz.hasActor {
+		return List{}, ErrUnauthenticated
+	}
+
+	list, err := svc.repo.Get(ctx, id)
+	if err != nil {
+		return List{}, err
+	}
+
+	if orgID, ok := am.OrgFromContext(ctx); ok && list.OrgID != orgID {
+		return List{}, ErrListNotFound
+	}
+
+	access, err := svc.access(ctx, authz, list)
+	if err != nil {
+		return List{}, err
+	}
+	if !authz.permits(list, access, AccessViewer) {
+		return List{}, ErrListNotFound
+	}
+
+	return list, nil
 }
 
+// Create stores the list owned by the actor, requests without an actor are rejected so every list has an owner.
+// When an owner team is set the actor must be one of its members.
 func (svc *BaseService) Create(ctx context.Context, list List) error {
-	list.GenCreateValues()
-	return svc.repo.Create(ctx, list)
+	actorID, ok := am.ActorFromContext(ctx)
+	if !ok || am.IsSystemActor(ctx) {
+		return ErrUnauthenticated
+	}
+
+	if orgID, ok := am.OrgFromContext(ctx); ok {
+		if orgID == am.NoOrgID {
+			return fmt.Errorf("%w: no organization available", ErrForbidden)
+		}
+		list.OrgID = orgID
+	}
+
+	if list.OwnerTeamID != uuid.Nil {
+		member, err := svc.isTeamMember(ctx, actorID, list.OwnerTeamID)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return ErrForbidden
+		}
+		list.OwnerUserID = uuid.Nil
+	} else {
+		list.OwnerUserID = actorID
+	}
+
+	svc.StampCreate(ctx, list)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.Create(ctx, list)
+		if err != nil {
+			return err
+		}
+		err = svc.reviseList(ctx, RevisionCreated, list)
+		if err != nil {
+			return err
+		}
+		return svc.record(ctx, NewEvent(list.ID(), uuid.Nil, EventCreated, list.Name))
+	})
 }
 
+// Update requires editor access, ownership, archiving and being a template cannot be changed through an update.
 func (svc *BaseService) Update(ctx context.Context, list List) error {
-	return svc.repo.Update(ctx, list)
+	current, err := svc.Get(ctx, list.ID())
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, current, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	list.OrgID = current.OrgID
+	list.OwnerUserID = current.OwnerUserID
+	list.OwnerTeamID = current.OwnerTeamID
+	list.ArchivedAt = current.ArchivedAt
+	list.Template = current.Template
+	svc.StampUpdate(ctx, list)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, list)
+		if err != nil {
+			return err
+		}
+		err = svc.reviseList(ctx, RevisionUpdated, list)
+		if err != nil || list.Name == current.Name {
+			return err
+		}
+		return svc.record(ctx, renamed(list.ID(), uuid.Nil, current.Name, list.Name))
+	})
 }
 
+// Delete requires owner access.
 func (svc *BaseService) Delete(ctx context.Context, id uuid.UUID) error {
-	return svc.repo.Delete(ctx, id)
+	list, err := svc.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, list, AccessOwner)
+	if err != nil {
+		return err
+	}
+	svc.StampDelete(ctx, list)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.Delete(ctx, list)
+		if err != nil {
+			return err
+		}
+		return svc.reviseList(ctx, RevisionDeleted, list)
+	})
+}
+
+// Archive requires owner access, archived lists are kept as they are but left out of the default views.
+func (svc *BaseService) Archive(ctx context.Context, id uuid.UUID) error {
+	return svc.setArchived(ctx, id, true)
+}
+
+// Unarchive requires owner access.
+func (svc *BaseService) Unarchive(ctx context.Context, id uuid.UUID) error {
+	return svc.setArchived(ctx, id, false)
+}
+
+func (svc *BaseService) setArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	list, err := svc.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, list, AccessOwner)
+	if err != nil || list.IsArchived() == archived {
+		return err
+	}
+
+	kind := EventUnarchived
+	list.ArchivedAt = time.Time{}
+	if archived {
+		kind = EventArchived
+		list.ArchivedAt = time.Now()
+	}
+	svc.StampUpdate(ctx, list)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, list)
+		if err != nil {
+			return err
+		}
+		err = svc.reviseList(ctx, RevisionUpdated, list)
+		if err != nil {
+			return err
+		}
+		return svc.record(ctx, NewEvent(list.ID(), uuid.Nil, kind, list.Name))
+	})
+}
+
+// GetDeleted returns the soft deleted lists of the org that the actor owns.
+func (svc *BaseService) GetDeleted(ctx context.Context) ([]List, error) {
+	ctx, authz, err := svc.withListAuthz(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, _ := am.OrgFromContext(ctx)
+	lists, err := svc.repo.GetDeleted(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned []List
+	for _, list := range lists {
+		access, err := svc.access(ctx, authz, list)
+		if err != nil {
+			return nil, err
+		}
+		if authz.permits(list, access, AccessOwner) {
+			owned = append(owned, list)
+		}
+	}
+	return owned, nil
+}
+
+// Restore requires owner access.
+func (svc *BaseService) Restore(ctx context.Context, id uuid.UUID) error {
+	deleted, err := svc.GetDeleted(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, list := range deleted {
+		if list.ID() == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrListNotFound
+	}
+
+	list := List{BaseModel: am.NewModel(am.WithID(id), am.WithType(listType))}
+	svc.StampUpdate(ctx, list)
+	err = svc.repo.Restore(ctx, list)
+	if err != nil {
+		return err
+	}
+
+	list, err = svc.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return svc.reviseList(ctx, RevisionRestored, list)
+}
+
+// PurgeDeleted removes the lists soft deleted before the given time along with the content of their attachments.
+// A purge that removes something is recorded in the audit log of the directory.
+func (svc *BaseService) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	deleted, err := svc.repo.GetDeleted(ctx, uuid.Nil)
+	if err != nil {
+		return 0, err
+	}
+	var ids []uuid.UUID
+	for _, list := range deleted {
+		if list.DeletedAt().Before(before) {
+			ids = append(ids, list.ID())
+		}
+	}
+	var attachments []Attachment
+	if len(ids) > 0 {
+		items, err := svc.repo.GetItems(ctx, ids...)
+		if err != nil {
+			return 0, err
+		}
+		itemIDs := make([]uuid.UUID, len(items))
+		for i, item := range items {
+			itemIDs[i] = item.ID()
+		}
+		attachments, err = svc.repo.GetAttachments(ctx, itemIDs...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	purged, err := svc.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return purged, err
+	}
+	svc.deleteBlobs(ctx, attachments...)
+	if purged > 0 {
+		entry := auth.NewAuditEntry(auth.ActionPurgeDeleted, listType, uuid.Nil, nil, auth.PurgeSummary{Before: before, Purged: purged})
+		err = svc.dir.RecordAudit(ctx, entry)
+		if err != nil {
+			return purged, fmt.Errorf("cannot record purge: %w", err)
+		}
+	}
+	return purged, nil
+}
+
+// Access returns what the actor can do with the list.
+// Requests without an actor get no access, only the system actor background jobs run as is not restricted.
+// The owning user and the members of the owning team are owners, everyone else gets
+// the highest role among the shares made to them or to one of their teams.
+func (svc *BaseService) Access(ctx context.Context, list List) (Access, error) {
+	authz, err := svc.listAuthz(ctx)
+	if err != nil {
+		return AccessNone, err
+	}
+	return svc.access(ctx, authz, list)
+}
+
+func (svc *BaseService) access(ctx context.Context, authz *listAuthz, list List) (Access, error) {
+	switch {
+	case authz.system:
+		return AccessOwner, nil
+	case !authz.hasActor:
+		return AccessNone, nil
+	case list.OwnerUserID != uuid.Nil && list.OwnerUserID == authz.actorID:
+		return AccessOwner, nil
+	case list.OwnerTeamID != uuid.Nil && authz.teamIDs[list.OwnerTeamID]:
+		return AccessOwner, nil
+	}
+
+	shares, err := svc.repo.GetShares(ctx, list.ID())
+	if err != nil {
+		return AccessNone, err
+	}
+
+	access := AccessNone
+	for _, share := range shares {
+		granted := share.GranteeType == GranteeUser && share.GranteeID == authz.actorID ||
+			share.GranteeType == GranteeTeam && authz.teamIDs[share.GranteeID]
+		if granted && share.Access() > access {
+			access = share.Access()
+		}
+	}
+	return access, nil
+}
+
+// GetShares returns the shares of a list the actor can see.
+func (svc *BaseService) GetShares(ctx context.Context, listID uuid.UUID) ([]Share, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.repo.GetShares(ctx, listID)
+}
+
+// ShareList grants a user or a team access to the list, only owners can share.
+// Sharing again with the same grantee changes its role.
+func (svc *BaseService) ShareList(ctx context.Context, share Share) error {
+	if !share.Valid() {
+		return ErrInvalidShare
+	}
+
+	list, err := svc.Get(ctx, share.ListID)
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, list, AccessOwner)
+	if err != nil {
+		return err
+	}
+
+	if share.GranteeType == GranteeUser {
+		_, err = svc.dir.GetUser(ctx, share.GranteeID)
+	} else {
+		_, err = svc.dir.GetTeam(ctx, share.GranteeID)
+	}
+	if err != nil {
+		return err
+	}
+
+	share.CreatedBy, _ = am.ActorFromContext(ctx)
+	share.CreatedAt = time.Now()
+	return svc.repo.SaveShare(ctx, share)
+}
+
+// UnshareList revokes the access granted to a user or a team, only owners can unshare.
+func (svc *BaseService) UnshareList(ctx context.Context, listID uuid.UUID, granteeType string, granteeID uuid.UUID) error {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, list, AccessOwner)
+	if err != nil {
+		return err
+	}
+	return svc.repo.DeleteShare(ctx, listID, granteeType, granteeID)
+}
+
+// GetShareCandidates returns the users and the teams of the org selected for the request that lists can be shared with.
+func (svc *BaseService) GetShareCandidates(ctx context.Context) ([]auth.User, []auth.Team, error) {
+	users, err := svc.dir.GetUsers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgID, _ := am.OrgFromContext(ctx)
+	teams, err := svc.dir.GetAllTeams(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return users, teams, nil
+}
+
+// GetActorTeams returns the teams of the org the actor belongs to, the ones a new list can be owned by.
+func (svc *BaseService) GetActorTeams(ctx context.Context) ([]auth.Team, error) {
+	actorID, ok := am.ActorFromContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	orgID, _ := am.OrgFromContext(ctx)
+	teams, err := svc.dir.GetAllTeams(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamIDs, err := svc.userTeamIDs(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []auth.Team
+	for _, team := range teams {
+		if teamIDs[team.ID()] {
+			result = append(result, team)
+		}
+	}
+	return result, nil
+}
+
+// require fails with ErrForbidden unless the actor has the required access on the list and no policy denies it,
+// requests without an actor fail with ErrUnauthenticated.
+func (svc *BaseService) require(ctx context.Context, list List, required Access) error {
+	authz, err := svc.listAuthz(ctx)
+	if err != nil {
+		return err
+	}
+	if !authz.hasActor {
+		return ErrUnauthenticated
+	}
+
+	access, err := svc.access(ctx, authz, list)
+	if err != nil {
+		return err
+	}
+	if !authz.permits(list, access, required) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// listAuthz holds what deciding the access of the actor on lists needs: its teams and the policies to enforce.
+type listAuthz struct {
+	actorID  uuid.UUID
+	hasActor bool
+	system   bool
+	teamIDs  map[uuid.UUID]bool
+	enforcer *auth.Enforcer
+}
+
+type listAuthzContextKey struct{}
+
+// withListAuthz returns a context carrying the list authorization of the actor,
+// so that a request deciding on many lists resolves the actor, its teams and the policies only once.
+func (svc *BaseService) withListAuthz(ctx context.Context) (context.Context, *listAuthz, error) {
+	authz, err := svc.listAuthz(ctx)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return context.WithValue(ctx, listAuthzContextKey{}, authz), authz, nil
+}
+
+// listAuthz returns the list authorization of the actor, the one in the context when it was resolved for the same actor.
+func (svc *BaseService) listAuthz(ctx context.Context) (*listAuthz, error) {
+	actorID, ok := am.ActorFromContext(ctx)
+	if authz, found := ctx.Value(listAuthzContextKey{}).(*listAuthz); found && authz.hasActor == ok && authz.actorID == actorID {
+		return authz, nil
+	}
+
+	authz := &listAuthz{actorID: actorID, hasActor: ok, system: am.IsSystemActor(ctx)}
+	if !ok || authz.system {
+		return authz, nil
+	}
+
+	teamIDs, err := svc.userTeamIDs(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	enforcer, err := svc.dir.Enforcer(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	authz.teamIDs, authz.enforcer = teamIDs, enforcer
+	return authz, nil
+}
+
+// permits combines the access the actor has on the list with the policies.
+// A deny policy blocks the operation even for owners, an allow policy can grant the read or write access
+// ownership and shares do not, operations that need owner access still need it.
+func (a *listAuthz) permits(list List, access, required Access) bool {
+	granted := access >= required
+	if a.enforcer == nil {
+		return granted
+	}
+
+	action := policyActionWrite
+	if required <= AccessViewer {
+		action = policyActionRead
+	}
+	d := a.enforcer.Enforce(auth.AccessRequest{
+		Action:       action,
+		ResourceType: listType,
+		Resource:     listAttrs(list),
+	}, granted)
+
+	if required == AccessOwner {
+		return granted && d.Allowed
+	}
+	return d.Allowed
+}
+
+// listAttrs returns the attributes policies can check on a list.
+func listAttrs(list List) map[string]any {
+	attrs := map[string]any{
+		"id":       list.ID().String(),
+		"org_id":   list.OrgID.String(),
+		"name":     list.Name,
+		"archived": list.IsArchived(),
+		"template": list.Template,
+	}
+	if list.OwnerUserID != uuid.Nil {
+		attrs["owner_user_id"] = list.OwnerUserID.String()
+	}
+	if list.OwnerTeamID != uuid.Nil {
+		attrs["owner_team_id"] = list.OwnerTeamID.String()
+	}
+	return attrs
+}
+
+// userActor returns the actor of a request on data that belongs to a user, what names that data in the error.
+// Requests without an actor and the system actor, which is not a user, fail with ErrUnauthenticated.
+func userActor(ctx context.Context, what string) (uuid.UUID, error) {
+	userID, ok := am.ActorFromContext(ctx)
+	if !ok || am.IsSystemActor(ctx) {
+		return uuid.Nil, fmt.Errorf("%w: %s belong to a user", ErrUnauthenticated, what)
+	}
+	return userID, nil
+}
+
+// withTx runs fn inside a transaction of the repo. When the context already carries one, as in a bulk command,
+// fn joins it and committing or rolling back is left to whoever began it.
+func (svc *BaseService) withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := memTxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	ctx, tx, err := svc.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	err = fn(ctx)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (svc *BaseService) isTeamMember(ctx context.Context, userID, teamID uuid.UUID) (bool, error) {
+	teamIDs, err := svc.userTeamIDs(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return teamIDs[teamID], nil
+}
+
+// userTeamIDs returns the teams of the user, including the ones inherited through nested teams.
+func (svc *BaseService) userTeamIDs(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	ids, err := svc.dir.GetUserTeamIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	teamIDs := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		teamIDs[id] = true
+	}
+	return teamIDs, nil
 }
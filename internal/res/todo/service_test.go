@@ -0,0 +1,299 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/google/uuid"
+)
+
+// fakeDirectory resolves teams from a map and enforces the policies it holds like the auth service does.
+type fakeDirectory struct {
+	teams    map[uuid.UUID][]uuid.UUID
+	policies []auth.Policy
+}
+
+func (d *fakeDirectory) GetUsers(ctx context.Context) ([]auth.User, error) { return nil, nil }
+
+func (d *fakeDirectory) GetUser(ctx context.Context, id uuid.UUID) (auth.User, error) {
+	return auth.User{}, nil
+}
+
+func (d *fakeDirectory) GetAllTeams(ctx context.Context, orgID uuid.UUID) ([]auth.Team, error) {
+	return nil, nil
+}
+
+func (d *fakeDirectory) GetTeam(ctx context.Context, id uuid.UUID) (auth.Team, error) {
+	return auth.Team{}, nil
+}
+
+func (d *fakeDirectory) GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return d.teams[userID], nil
+}
+
+func (d *fakeDirectory) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]auth.TeamMember, error) {
+	return nil, nil
+}
+
+func (d *fakeDirectory) RecordAudit(ctx context.Context, entry auth.AuditEntry) error { return nil }
+
+func (d *fakeDirectory) Enforcer(ctx context.Context, userID uuid.UUID) (*auth.Enforcer, error) {
+	teamIDs := []string{}
+	for _, id := range d.teams[userID] {
+		teamIDs = append(teamIDs, id.String())
+	}
+	subject := map[string]any{"id": userID.String(), "team_ids": teamIDs}
+	return auth.NewEnforcer(subject, nil, d.policies), nil
+}
+
+func TestPolicyDeniesListUpdate(t *testing.T) {
+	dir := &fakeDirectory{}
+	svc := NewService(NewRepo(nil), dir, nil)
+	ctx := am.WithActor(context.Background(), uuid.New())
+
+	list := NewList("Groceries", "")
+	if err := svc.Create(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+
+	list.Name = "Shopping"
+	if err := svc.Update(ctx, list); err != nil {
+		t.Fatalf("expected the owner to update the list, got %v", err)
+	}
+
+	deny := auth.NewPolicy("freeze-lists", "", auth.EffectDeny)
+	deny.Actions = []string{policyActionWrite}
+	deny.Resources = []string{listType}
+	dir.policies = []auth.Policy{deny}
+
+	list, err := svc.Get(ctx, list.ID())
+	if err != nil {
+		t.Fatalf("expected the deny policy to leave reads alone, got %v", err)
+	}
+	list.Name = "Errands"
+	if err := svc.Update(ctx, list); !errors.Is(err, ErrForbidden) {
+		t.Errorf("expected the deny policy to block the update, got %v", err)
+	}
+
+	deny.Enabled = false
+	dir.policies = []auth.Policy{deny}
+	if err := svc.Update(ctx, list); err != nil {
+		t.Errorf("expected a disabled policy to be ignored, got %v", err)
+	}
+}
+
+func TestAccess(t *testing.T) {
+	ownerID, editorID, memberID, strangerID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	teamID := uuid.New()
+	dir := &fakeDirectory{teams: map[uuid.UUID][]uuid.UUID{memberID: {teamID}}}
+	repo := NewRepo(nil)
+	svc := NewService(repo, dir, nil)
+	ctx := context.Background()
+
+	list := NewList("Groceries", "")
+	if err := svc.Create(am.WithActor(ctx, ownerID), list); err != nil {
+		t.Fatal(err)
+	}
+	for _, share := range []Share{
+		NewShare(list.ID(), GranteeUser, editorID, RoleEditor),
+		NewShare(list.ID(), GranteeTeam, teamID, RoleViewer),
+	} {
+		if err := repo.SaveShare(ctx, share); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := repo.Get(ctx, list.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		access Access
+		view   error
+		edit   error
+		manage error
+	}{
+		{"owner", am.WithActor(ctx, ownerID), AccessOwner, nil, nil, nil},
+		{"editor", am.WithActor(ctx, editorID), AccessEditor, nil, nil, ErrForbidden},
+		{"viewer", am.WithActor(ctx, memberID), AccessViewer, nil, ErrForbidden, ErrForbidden},
+		{"stranger", am.WithActor(ctx, strangerID), AccessNone, ErrForbidden, ErrForbidden, ErrForbidden},
+		{"no actor", ctx, AccessNone, ErrUnauthenticated, ErrUnauthenticated, ErrUnauthenticated},
+		{"system", am.WithSystemActor(ctx), AccessOwner, nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			access, err := svc.Access(tt.ctx, list)
+			if err != nil || access != tt.access {
+				t.Errorf("expected access %d, got %d (%v)", tt.access, access, err)
+			}
+			for required, want := range map[Access]error{AccessViewer: tt.view, AccessEditor: tt.edit, AccessOwner: tt.manage} {
+				err := svc.require(tt.ctx, list, required)
+				if !errors.Is(err, want) || (want == nil && err != nil) {
+					t.Errorf("require %d: expected %v, got %v", required, want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRequiresActor(t *testing.T) {
+	svc := NewService(NewRepo(nil), &fakeDirectory{}, nil)
+	ctx := context.Background()
+
+	for name, ctx := range map[string]context.Context{"no actor": ctx, "system": am.WithSystemActor(ctx)} {
+		if err := svc.Create(ctx, NewList("Groceries", "")); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: expected %v, got %v", name, ErrUnauthenticated, err)
+		}
+	}
+}
+
+func TestTimeRequiresActor(t *testing.T) {
+	svc := NewService(NewRepo(nil), &fakeDirectory{}, nil)
+	listID, itemID := uuid.New(), uuid.New()
+
+	for name, ctx := range map[string]context.Context{"no actor": context.Background(), "system": am.WithSystemActor(context.Background())} {
+		calls := map[string]func() error{
+			"start timer": func() error { _, err := svc.StartTimer(ctx, listID, itemID, ""); return err },
+			"stop timer":  func() error { _, err := svc.StopTimer(ctx); return err },
+			"running":     func() error { _, err := svc.GetRunningTimer(ctx); return err },
+			"add entry":   func() error { _, err := svc.AddTimeEntry(ctx, TimeEntry{ListID: listID, ItemID: itemID}); return err },
+			"delete":      func() error { return svc.DeleteTimeEntry(ctx, listID, uuid.New()) },
+			"timesheet":   func() error { _, err := svc.GetTimesheet(ctx, time.Now()); return err },
+		}
+		for call, fn := range calls {
+			if err := fn(); !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("%s, %s: expected %v, got %v", name, call, ErrUnauthenticated, err)
+			}
+		}
+	}
+}
+
+func TestFeedTokenRequiresActor(t *testing.T) {
+	svc := NewService(NewRepo(nil), &fakeDirectory{}, nil)
+
+	for name, ctx := range map[string]context.Context{"no actor": context.Background(), "system": am.WithSystemActor(context.Background())} {
+		if _, err := svc.GetFeedToken(ctx); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: expected %v, got %v", name, ErrUnauthenticated, err)
+		}
+		if _, err := svc.RegenerateFeedToken(ctx); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: expected %v on regenerate, got %v", name, ErrUnauthenticated, err)
+		}
+	}
+}
+
+func TestAssignedItemsRequiresActor(t *testing.T) {
+	svc := NewService(NewRepo(nil), &fakeDirectory{}, nil)
+
+	for name, ctx := range map[string]context.Context{"no actor": context.Background(), "system": am.WithSystemActor(context.Background())} {
+		if _, err := svc.GetAssignedItems(ctx, AssignedToMe); !errors.Is(err, ErrUnauthenticated) {
+			t.Errorf("%s: expected %v, got %v", name, ErrUnauthenticated, err)
+		}
+	}
+}
+
+// failingEventsRepo fails to record events, the last write of most commands.
+type failingEventsRepo struct {
+	*BaseRepo
+}
+
+func (repo failingEventsRepo) AddEvent(ctx context.Context, event Event) error {
+	return errors.New("events unavailable")
+}
+
+func TestCommandsRollBack(t *testing.T) {
+	repo := NewRepo(nil)
+	ctx := am.WithActor(context.Background(), uuid.New())
+
+	list := NewList("Groceries", "")
+	if err := NewService(repo, &fakeDirectory{}, nil).Create(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	parent := NewItem(list.ID(), "Shop", "")
+	if err := NewService(repo, &fakeDirectory{}, nil).CreateItem(ctx, parent); err != nil {
+		t.Fatal(err)
+	}
+	child := NewItem(list.ID(), "Milk", "")
+	child.ParentID = parent.ID()
+	if err := NewService(repo, &fakeDirectory{}, nil).CreateItem(ctx, child); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(failingEventsRepo{repo}, &fakeDirectory{}, nil)
+	other := NewList("Errands", "")
+	if err := svc.Create(ctx, other); err == nil {
+		t.Fatal("expected the create to fail")
+	}
+	if _, err := repo.Get(ctx, other.ID()); !errors.Is(err, ErrListNotFound) {
+		t.Errorf("expected the list to be rolled back, got %v", err)
+	}
+
+	if err := svc.DeleteItem(ctx, list.ID(), parent.ID()); err == nil {
+		t.Fatal("expected the delete to fail")
+	}
+	items, err := repo.GetItems(ctx, list.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Errorf("expected the subtree delete to be rolled back, got %d items", len(items))
+	}
+	revisions, err := repo.GetRevisions(ctx, list.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 3 {
+		t.Errorf("expected only the revisions of the creates, got %d", len(revisions))
+	}
+}
+
+func TestReorderBlockedWritesNothing(t *testing.T) {
+	svc := NewService(NewRepo(nil), &fakeDirectory{}, nil)
+	ctx := am.WithActor(context.Background(), uuid.New())
+
+	list := NewList("Release", "")
+	if err := svc.Create(ctx, list); err != nil {
+		t.Fatal(err)
+	}
+	blocker := NewItem(list.ID(), "Review", "")
+	if err := svc.CreateItem(ctx, blocker); err != nil {
+		t.Fatal(err)
+	}
+	free := NewItem(list.ID(), "Changelog", "")
+	if err := svc.CreateItem(ctx, free); err != nil {
+		t.Fatal(err)
+	}
+	blocked := NewItem(list.ID(), "Ship", "")
+	blocked.BlockedBy = []uuid.UUID{blocker.ID()}
+	if err := svc.CreateItem(ctx, blocked); err != nil {
+		t.Fatal(err)
+	}
+	before, err := svc.GetRevisions(ctx, list.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = svc.ReorderItems(ctx, list.ID(), StatusDone, []uuid.UUID{free.ID(), blocked.ID()}, false)
+	if !errors.Is(err, ErrItemBlocked) {
+		t.Fatalf("expected the reorder to be refused, got %v", err)
+	}
+	item, err := svc.GetItem(ctx, list.ID(), free.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Done {
+		t.Error("expected the item before the blocked one to be left open")
+	}
+	after, err := svc.GetRevisions(ctx, list.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Errorf("expected no revisions, got %d more", len(after)-len(before))
+	}
+}
@@ -0,0 +1,273 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/google/uuid"
+)
+
+// SetAssignmentNotifier sets the notifier assignments are sent through, without one they are not notified.
+func (svc *BaseService) SetAssignmentNotifier(notifier AssignmentNotifier) {
+	svc.notifier = notifier
+}
+
+// AssignItem assigns an item to a user or a team, it requires editor access.
+// Users must be able to see the list, teams must own it or have it shared with them.
+// Assigning someone already assigned changes nothing.
+func (svc *BaseService) AssignItem(ctx context.Context, listID, id uuid.UUID, assignee Assignee) (Item, error) {
+	if !assignee.Valid() {
+		return Item{}, ErrInvalidAssignee
+	}
+	item, list, err := svc.assignableItem(ctx, listID, id)
+	if err != nil {
+		return Item{}, err
+	}
+	name, err := svc.checkAssignee(ctx, list, assignee)
+	if err != nil {
+		return Item{}, err
+	}
+	if item.IsAssignedTo(assignee) {
+		return item, nil
+	}
+
+	item.Assignees = append(item.Assignees, assignee)
+	err = svc.saveItem(ctx, item)
+	if err != nil {
+		return Item{}, err
+	}
+
+	err = svc.record(ctx, reassigned(listID, item, "", name))
+	if err != nil {
+		return Item{}, err
+	}
+	svc.notifyAssignment(ctx, item, list, assignee, name)
+	return item, nil
+}
+
+// UnassignItem removes a user or a team from the assignees of an item, it requires editor access.
+func (svc *BaseService) UnassignItem(ctx context.Context, listID, id uuid.UUID, assignee Assignee) (Item, error) {
+	item, _, err := svc.assignableItem(ctx, listID, id)
+	if err != nil {
+		return Item{}, err
+	}
+	if !item.IsAssignedTo(assignee) {
+		return Item{}, ErrAssigneeNotFound
+	}
+
+	assignees := make([]Assignee, 0, len(item.Assignees)-1)
+	for _, a := range item.Assignees {
+		if a != assignee {
+			assignees = append(assignees, a)
+		}
+	}
+	item.Assignees = assignees
+	err = svc.saveItem(ctx, item)
+	if err != nil {
+		return Item{}, err
+	}
+	return item, svc.record(ctx, reassigned(listID, item, svc.assigneeName(ctx, assignee), ""))
+}
+
+// GetAssignedItems returns the items of the lists the actor can see that are assigned to them, or to one of
+// their teams in the teams view. Open items come first, the ones due sooner before the others.
+func (svc *BaseService) GetAssignedItems(ctx context.Context, view string) ([]DueItem, error) {
+	actorID, err := userActor(ctx, "assignments")
+	if err != nil {
+		return nil, err
+	}
+
+	items, byID, err := svc.visibleItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, teamIDs := actorID, map[uuid.UUID]bool{}
+	if view == AssignedToTeams {
+		userID = uuid.Nil
+		teamIDs, err = svc.userTeamIDs(ctx, actorID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var assigned []DueItem
+	for _, item := range items {
+		if item.IsAssignedToAny(userID, teamIDs) {
+			assigned = append(assigned, DueItem{Item: item, List: byID[item.ListID]})
+		}
+	}
+
+	sort.SliceStable(assigned, func(i, j int) bool {
+		a, b := assigned[i], assigned[j]
+		if a.Done != b.Done {
+			return !a.Done
+		}
+		if a.HasDue() != b.HasDue() {
+			return a.HasDue()
+		}
+		return a.DueAt.Before(b.DueAt)
+	})
+	return assigned, nil
+}
+
+// GetAssigneeCandidates returns the users and the teams the items of the list can be assigned to.
+func (svc *BaseService) GetAssigneeCandidates(ctx context.Context, listID uuid.UUID) ([]auth.User, []auth.Team, error) {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users, teams, err := svc.GetShareCandidates(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var candidates []auth.User
+	for _, user := range users {
+		access, err := svc.Access(am.WithActor(ctx, user.ID()), list)
+		if err != nil {
+			return nil, nil, err
+		}
+		if access.CanView() {
+			candidates = append(candidates, user)
+		}
+	}
+
+	var teamCandidates []auth.Team
+	for _, team := range teams {
+		ok, err := svc.teamCanView(ctx, list, team.ID())
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			teamCandidates = append(teamCandidates, team)
+		}
+	}
+	return candidates, teamCandidates, nil
+}
+
+// assignableItem returns an item along with its list when the actor can edit the list.
+func (svc *BaseService) assignableItem(ctx context.Context, listID, id uuid.UUID) (Item, List, error) {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return Item{}, List{}, err
+	}
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Item{}, List{}, err
+	}
+	err = svc.require(ctx, list, AccessEditor)
+	if err != nil {
+		return Item{}, List{}, err
+	}
+	return item, list, nil
+}
+
+// checkAssignee validates an assignee against the sharing of the list and returns its name.
+func (svc *BaseService) checkAssignee(ctx context.Context, list List, assignee Assignee) (string, error) {
+	if assignee.IsTeam() {
+		team, err := svc.dir.GetTeam(ctx, assignee.ID)
+		if err != nil {
+			return "", fmt.Errorf("%w: unknown team", ErrInvalidAssignee)
+		}
+		ok, err := svc.teamCanView(ctx, list, team.ID())
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			return "", fmt.Errorf("%w: the list is not shared with %s", ErrInvalidAssignee, team.Name)
+		}
+		return team.Name, nil
+	}
+
+	user, err := svc.dir.GetUser(ctx, assignee.ID)
+	if err != nil {
+		return "", fmt.Errorf("%w: unknown user", ErrInvalidAssignee)
+	}
+	access, err := svc.Access(am.WithActor(ctx, user.ID()), list)
+	if err != nil {
+		return "", err
+	}
+	if !access.CanView() {
+		return "", fmt.Errorf("%w: %s cannot see the list", ErrInvalidAssignee, user.Username)
+	}
+	return user.Username, nil
+}
+
+// teamCanView reports whether the list is owned by the team or shared with it.
+// Lists without an owner are open to everyone.
+func (svc *BaseService) teamCanView(ctx context.Context, list List, teamID uuid.UUID) (bool, error) {
+	if !list.HasOwner() || list.OwnerTeamID == teamID {
+		return true, nil
+	}
+	shares, err := svc.repo.GetShares(ctx, list.ID())
+	if err != nil {
+		return false, err
+	}
+	for _, share := range shares {
+		if share.GranteeType == GranteeTeam && share.GranteeID == teamID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// assigneeName returns the user name or the team name of the assignee, its ID when it no longer exists.
+func (svc *BaseService) assigneeName(ctx context.Context, assignee Assignee) string {
+	if assignee.IsTeam() {
+		if team, err := svc.dir.GetTeam(ctx, assignee.ID); err == nil {
+			return team.Name
+		}
+	} else if user, err := svc.dir.GetUser(ctx, assignee.ID); err == nil {
+		return user.Username
+	}
+	return assignee.ID.String()
+}
+
+// notifyAssignment hands the assignment to the notifier, failures are logged and do not undo it.
+func (svc *BaseService) notifyAssignment(ctx context.Context, item Item, list List, assignee Assignee, name string) {
+	if svc.notifier == nil {
+		return
+	}
+
+	var recipients []auth.User
+	var err error
+	if assignee.IsTeam() {
+		recipients, err = svc.teamUsers(ctx, assignee.ID)
+	} else {
+		var user auth.User
+		user, err = svc.dir.GetUser(ctx, assignee.ID)
+		recipients = []auth.User{user}
+	}
+	if err != nil {
+		svc.Log().Errorf("cannot get recipients of assignment of item %s: %v", item.ID(), err)
+		return
+	}
+
+	actorID, _ := am.ActorFromContext(ctx)
+	assignment := Assignment{Item: item, List: list, Assignee: assignee, Name: name, AssignedBy: actorID}
+	for _, user := range recipients {
+		if user.ID() != actorID {
+			assignment.Recipients = append(assignment.Recipients, user)
+		}
+	}
+	if len(assignment.Recipients) == 0 {
+		return
+	}
+
+	err = svc.notifier.NotifyAssignment(ctx, assignment)
+	if err != nil {
+		svc.Log().Errorf("cannot notify assignment of item %s: %v", item.ID(), err)
+	}
+}
+
+func reassigned(listID uuid.UUID, item Item, from, to string) Event {
+	event := NewEvent(listID, item.ID(), EventReassigned, item.Title)
+	event.From = from
+	event.To = to
+	return event
+}
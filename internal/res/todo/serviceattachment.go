@@ -0,0 +1,153 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// AttachmentLimits returns the size and type limits uploads are checked against.
+func (svc *BaseService) AttachmentLimits() AttachmentLimits {
+	return NewAttachmentLimits(svc.Cfg())
+}
+
+// GetAttachments returns the attachments of an item, or of every item of the list when itemID is Nil.
+func (svc *BaseService) GetAttachments(ctx context.Context, listID, itemID uuid.UUID) ([]Attachment, error) {
+	if itemID != uuid.Nil {
+		_, err := svc.GetItem(ctx, listID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		return svc.repo.GetAttachments(ctx, itemID)
+	}
+
+	items, err := svc.GetItems(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID()
+	}
+	return svc.repo.GetAttachments(ctx, ids...)
+}
+
+// AddAttachment stores the content read from r as an attachment of the item, it requires editor access.
+// The content is spooled to a temporary file while its size is checked against the limit and
+// its type is detected, only then it is handed to the blob store.
+func (svc *BaseService) AddAttachment(ctx context.Context, listID, itemID uuid.UUID, name string, r io.Reader) (Attachment, error) {
+	_, err := svc.GetItem(ctx, listID, itemID)
+	if err != nil {
+		return Attachment{}, err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	limits := svc.AttachmentLimits()
+	tmp, err := os.CreateTemp("", "todo-attachment-*")
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(r, limits.MaxSize+1))
+	if err != nil {
+		return Attachment{}, err
+	}
+	if size > limits.MaxSize {
+		return Attachment{}, ErrAttachmentTooLarge
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Attachment{}, err
+	}
+	attachment := NewAttachment(listID, itemID, name)
+	attachment.ContentType = SniffContentType(head[:n])
+	if !limits.Allows(attachment.ContentType) {
+		return Attachment{}, ErrAttachmentType
+	}
+	attachment.Size = size
+	attachment.UploadedBy, _ = am.ActorFromContext(ctx)
+	attachment.CreatedAt = time.Now()
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return Attachment{}, err
+	}
+	err = svc.blobs.Put(ctx, attachment.Key, tmp, size, attachment.ContentType)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	err = svc.repo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		svc.deleteBlobs(ctx, attachment)
+		return Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment returns an attachment of an item the actor can see along with its content.
+// The caller closes the blob.
+func (svc *BaseService) OpenAttachment(ctx context.Context, listID, itemID, id uuid.UUID) (Attachment, Blob, error) {
+	_, err := svc.GetItem(ctx, listID, itemID)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+	attachment, err := svc.repo.GetAttachment(ctx, itemID, id)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+	blob, err := svc.blobs.Open(ctx, attachment.Key)
+	if errors.Is(err, ErrBlobNotFound) {
+		return Attachment{}, nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+	return attachment, blob, nil
+}
+
+// DeleteAttachment removes an attachment and its content, it requires editor access.
+func (svc *BaseService) DeleteAttachment(ctx context.Context, listID, itemID, id uuid.UUID) error {
+	_, err := svc.GetItem(ctx, listID, itemID)
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := svc.repo.GetAttachment(ctx, itemID, id)
+	if err != nil {
+		return err
+	}
+	err = svc.repo.DeleteAttachment(ctx, itemID, id)
+	if err != nil {
+		return err
+	}
+	svc.deleteBlobs(ctx, attachment)
+	return nil
+}
+
+// deleteBlobs removes the content of attachments whose records are gone.
+// Failures are only logged, a leftover blob is not reachable anymore.
+func (svc *BaseService) deleteBlobs(ctx context.Context, attachments ...Attachment) {
+	for _, a := range attachments {
+		err := svc.blobs.Delete(ctx, a.Key)
+		if err != nil {
+			svc.Log().Errorf("cannot delete attachment blob %s: %v", a.Key, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GetBoard returns the board of a list the actor can see.
+func (svc *BaseService) GetBoard(ctx context.Context, listID uuid.UUID) (Board, error) {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Board{}, err
+	}
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return Board{}, err
+	}
+	return BuildBoard(list, items), nil
+}
+
+// SetBoardColumns configures the board columns of a list, it requires editor access.
+func (svc *BaseService) SetBoardColumns(ctx context.Context, listID uuid.UUID, columns []BoardColumn) error {
+	columns, err := NormalizeColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return err
+	}
+	err = svc.require(ctx, list, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	list.Columns = columns
+	svc.StampUpdate(ctx, list)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.Update(ctx, list)
+		if err != nil {
+			return err
+		}
+		return svc.reviseList(ctx, RevisionUpdated, list)
+	})
+}
+
+// MoveItem places an item at the index of the column of the given status, the index is taken without the item.
+// Changing the status follows the item status rules, moving a blocked item to done needs force.
+// Blockers are checked before anything is written and the move, renumbering included, is a single transaction.
+func (svc *BaseService) MoveItem(ctx context.Context, listID, id uuid.UUID, status Status, index int, force bool) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, status)
+	}
+
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return err
+	}
+	if !force {
+		err = checkBlockers([]Item{item}, status, items)
+		if err != nil {
+			return err
+		}
+	}
+
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		column := columnItems(items, status, id)
+		position, ok := positionAt(column, index)
+		if !ok {
+			column, err = svc.renumber(ctx, column)
+			if err != nil {
+				return err
+			}
+			position, _ = positionAt(column, index)
+		}
+
+		item.Position = position
+		item.Status = status
+		return svc.saveStatus(ctx, item, force)
+	})
+}
+
+// ReorderItems sets the order of a board column, the items are moved to its status if needed.
+// Items of the column that are not given keep their relative order after the given ones.
+// Blockers are checked before anything is written and the items are saved in a single transaction.
+func (svc *BaseService) ReorderItems(ctx context.Context, listID uuid.UUID, status Status, ids []uuid.UUID, force bool) error {
+	if !status.Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidStatus, status)
+	}
+
+	err := svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return err
+	}
+	byID := itemsByID(items)
+
+	ordered := make([]Item, 0, len(ids))
+	given := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok || given[id] {
+			return ErrItemNotFound
+		}
+		given[id] = true
+		ordered = append(ordered, item)
+	}
+	for _, item := range columnItems(items, status, uuid.Nil) {
+		if !given[item.ID()] {
+			ordered = append(ordered, item)
+		}
+	}
+
+	if !force {
+		err = checkBlockers(ordered, status, items)
+		if err != nil {
+			return err
+		}
+	}
+
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		for i, item := range ordered {
+			position := float64(i+1) * positionGap
+			if item.Position == position && item.Status == status {
+				continue
+			}
+			// Completing an item can complete its parent too, the item is read again to save over the latest version.
+			item, err := svc.repo.GetItem(ctx, item.ID())
+			if err != nil {
+				return err
+			}
+			item.Position = position
+			item.Status = status
+			err = svc.saveStatus(ctx, item, force)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// renumber spreads the positions of a column evenly, it is needed when repeated inserts leave no room between items.
+func (svc *BaseService) renumber(ctx context.Context, column []Item) ([]Item, error) {
+	for i := range column {
+		column[i].Position = float64(i+1) * positionGap
+		err := svc.saveItem(ctx, column[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return column, nil
+}
+
+// lastPosition returns a position after every item of the list, new items go at the end of their column.
+func (svc *BaseService) lastPosition(ctx context.Context, listID uuid.UUID) (float64, error) {
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return 0, err
+	}
+	last := 0.0
+	for _, item := range items {
+		if item.Position > last {
+			last = item.Position
+		}
+	}
+	return last + positionGap, nil
+}
@@ -0,0 +1,75 @@
+package todo
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// BulkItems runs the command on each of its items in a single transaction, see am.RunBatch.
+// It requires editor access. Moved items go to the end of their column; deleting an item whose
+// parent is also selected succeeds, the item goes with its parent. Attachment blobs are only
+// deleted once the batch is applied.
+func (svc *BaseService) BulkItems(ctx context.Context, listID uuid.UUID, cmd BulkCommand) (am.Batch, error) {
+	err := cmd.Validate()
+	if err != nil {
+		return am.Batch{}, err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return am.Batch{}, err
+	}
+
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return am.Batch{}, err
+	}
+	byID := itemsByID(items)
+	deleted := map[uuid.UUID]bool{}
+	var attachments []Attachment
+
+	batch, err := am.RunBatch(ctx, svc.repo.BeginTx, cmd.Command, cmd.ItemIDs, func(ctx context.Context, id uuid.UUID) (string, error) {
+		title := byID[id].Title
+		switch cmd.Command {
+		case BulkComplete:
+			return title, svc.CompleteItem(ctx, listID, id, cmd.Force)
+		case BulkMove:
+			return title, svc.MoveItem(ctx, listID, id, cmd.Status, math.MaxInt, cmd.Force)
+		case BulkTag:
+			return title, svc.tagItem(ctx, listID, id, cmd.Tag)
+		}
+
+		if deleted[id] {
+			return title, nil
+		}
+		removed, err := svc.deleteItem(ctx, listID, id)
+		if err != nil {
+			return title, err
+		}
+		attachments = append(attachments, removed...)
+		for _, sub := range subtreeIDs(id, items) {
+			deleted[sub] = true
+		}
+		return title, nil
+	})
+	if batch.Applied {
+		svc.deleteBlobs(ctx, attachments...)
+	}
+	return batch, err
+}
+
+// tagItem adds the tag to the item unless it already has it.
+func (svc *BaseService) tagItem(ctx context.Context, listID, id uuid.UUID, tag string) error {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(item.Tags, tag) {
+		return nil
+	}
+	item.Tags = append(item.Tags, tag)
+	return svc.saveItem(ctx, item)
+}
@@ -0,0 +1,181 @@
+package todo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// GetComments returns the comments on the list, or on one of its items when itemID is not Nil, oldest first.
+func (svc *BaseService) GetComments(ctx context.Context, listID, itemID uuid.UUID) ([]Comment, error) {
+	if itemID != uuid.Nil {
+		_, err := svc.GetItem(ctx, listID, itemID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		_, err := svc.Get(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	comments, err := svc.repo.GetComments(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	var result []Comment
+	for _, c := range comments {
+		if c.ItemID == itemID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// GetComment returns a comment of a list the actor can see.
+func (svc *BaseService) GetComment(ctx context.Context, listID, id uuid.UUID) (Comment, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Comment{}, err
+	}
+	return svc.repo.GetComment(ctx, listID, id)
+}
+
+// AddComment adds a comment written by the actor, anyone who can see the list can comment on it.
+// Only users who can see the list are taken as mentioned.
+func (svc *BaseService) AddComment(ctx context.Context, comment Comment) (Comment, error) {
+	err := comment.Validate()
+	if err != nil {
+		return Comment{}, err
+	}
+
+	list, err := svc.Get(ctx, comment.ListID)
+	if err != nil {
+		return Comment{}, err
+	}
+	if comment.IsOnItem() {
+		_, err = svc.GetItem(ctx, comment.ListID, comment.ItemID)
+		if err != nil {
+			return Comment{}, err
+		}
+	}
+
+	comment.Mentions, err = svc.mentions(ctx, list, comment.Body)
+	if err != nil {
+		return Comment{}, err
+	}
+	comment.AuthorID, _ = am.ActorFromContext(ctx)
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	return comment, svc.repo.CreateComment(ctx, comment)
+}
+
+// UpdateComment changes the body of a comment, only its author can edit it.
+func (svc *BaseService) UpdateComment(ctx context.Context, listID, id uuid.UUID, body string) (Comment, error) {
+	comment, list, err := svc.authoredComment(ctx, listID, id)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	comment.Body = strings.TrimSpace(body)
+	err = comment.Validate()
+	if err != nil {
+		return Comment{}, err
+	}
+	comment.Mentions, err = svc.mentions(ctx, list, comment.Body)
+	if err != nil {
+		return Comment{}, err
+	}
+	comment.UpdatedAt = time.Now()
+	return comment, svc.repo.UpdateComment(ctx, comment)
+}
+
+// DeleteComment removes a comment, only its author can delete it.
+func (svc *BaseService) DeleteComment(ctx context.Context, listID, id uuid.UUID) error {
+	_, _, err := svc.authoredComment(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	return svc.repo.DeleteComment(ctx, listID, id)
+}
+
+// GetTimeline returns the comments and the changes of the list and its items, newest first.
+func (svc *BaseService) GetTimeline(ctx context.Context, listID uuid.UUID) ([]Activity, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := svc.repo.GetComments(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := svc.repo.GetEvents(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTimeline(comments, events), nil
+}
+
+// authoredComment returns a comment of a visible list if the actor wrote it.
+func (svc *BaseService) authoredComment(ctx context.Context, listID, id uuid.UUID) (Comment, List, error) {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Comment{}, List{}, err
+	}
+	comment, err := svc.repo.GetComment(ctx, listID, id)
+	if err != nil {
+		return Comment{}, List{}, err
+	}
+	if actorID, _ := am.ActorFromContext(ctx); comment.AuthorID != actorID {
+		return Comment{}, List{}, ErrForbidden
+	}
+	return comment, list, nil
+}
+
+// mentions returns the users named in the body that can see the list.
+func (svc *BaseService) mentions(ctx context.Context, list List, body string) ([]uuid.UUID, error) {
+	names := Mentions(body)
+	if len(names) == 0 {
+		return nil, nil
+	}
+	users, err := svc.dir.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uuid.UUID
+	for _, name := range names {
+		for _, user := range users {
+			if !strings.EqualFold(user.Username, name) {
+				continue
+			}
+			access, err := svc.Access(am.WithActor(ctx, user.ID()), list)
+			if err != nil {
+				return nil, err
+			}
+			if access.CanView() {
+				ids = append(ids, user.ID())
+			}
+			break
+		}
+	}
+	return ids, nil
+}
+
+// record adds an event made by the actor to the activity of its list.
+func (svc *BaseService) record(ctx context.Context, event Event) error {
+	event.ActorID, _ = am.ActorFromContext(ctx)
+	return svc.repo.AddEvent(ctx, event)
+}
+
+func renamed(listID, itemID uuid.UUID, from, to string) Event {
+	event := NewEvent(listID, itemID, EventRenamed, to)
+	event.From = from
+	event.To = to
+	return event
+}
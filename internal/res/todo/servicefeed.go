@@ -0,0 +1,66 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// GetFeedToken returns the calendar feed token of the actor, creating it on first use.
+func (svc *BaseService) GetFeedToken(ctx context.Context) (FeedToken, error) {
+	userID, err := userActor(ctx, "feeds")
+	if err != nil {
+		return FeedToken{}, err
+	}
+
+	token, err := svc.repo.GetFeedToken(ctx, userID)
+	if errors.Is(err, ErrFeedNotFound) {
+		return svc.RegenerateFeedToken(ctx)
+	}
+	return token, err
+}
+
+// RegenerateFeedToken replaces the feed token of the actor, calendars subscribed with the previous one stop working.
+func (svc *BaseService) RegenerateFeedToken(ctx context.Context) (FeedToken, error) {
+	userID, err := userActor(ctx, "feeds")
+	if err != nil {
+		return FeedToken{}, err
+	}
+
+	orgID, _ := am.OrgFromContext(ctx)
+	token, err := NewFeedToken(userID, orgID)
+	if err != nil {
+		return FeedToken{}, err
+	}
+	return token, svc.repo.SaveFeedToken(ctx, token)
+}
+
+// GetFeed returns the calendar of the user the token belongs to.
+// The request needs no actor, the token acts for its user.
+func (svc *BaseService) GetFeed(ctx context.Context, token string) (Feed, error) {
+	ft, err := svc.repo.FindFeedToken(ctx, token)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	ctx = am.WithActor(ctx, ft.UserID)
+	if ft.OrgID != uuid.Nil {
+		ctx = am.WithOrg(ctx, ft.OrgID)
+	}
+	items, lists, err := svc.visibleItems(ctx)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	feed := Feed{Token: ft}
+	for _, item := range items {
+		if item.HasDue() {
+			feed.Items = append(feed.Items, DueItem{Item: item, List: lists[item.ListID]})
+		}
+	}
+	sort.SliceStable(feed.Items, func(i, j int) bool { return feed.Items[i].DueAt.Before(feed.Items[j].DueAt) })
+	return feed, nil
+}
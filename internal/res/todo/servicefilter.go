@@ -0,0 +1,42 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter is returned when a saved filter has no name or selects every item.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// GetSavedFilters returns the saved filters of the actor.
+// Without an actor the filters are the ones saved without one, as in single user setups.
+func (svc *BaseService) GetSavedFilters(ctx context.Context) ([]SavedFilter, error) {
+	actorID, _ := am.ActorFromContext(ctx)
+	return svc.repo.GetSavedFilters(ctx, actorID)
+}
+
+// SaveFilter saves a named filter of the actor, it replaces the actor's filter of the same name if any.
+func (svc *BaseService) SaveFilter(ctx context.Context, name string, filter ItemFilter) (SavedFilter, error) {
+	actorID, _ := am.ActorFromContext(ctx)
+	saved := NewSavedFilter(actorID, name, filter)
+	if saved.Name == "" {
+		return SavedFilter{}, fmt.Errorf("%w: name is required", ErrInvalidFilter)
+	}
+	if filter.IsEmpty() {
+		return SavedFilter{}, fmt.Errorf("%w: it selects every item", ErrInvalidFilter)
+	}
+	saved.CreatedAt = time.Now()
+
+	return svc.repo.SaveFilter(ctx, saved)
+}
+
+// DeleteSavedFilter removes a saved filter of the actor.
+func (svc *BaseService) DeleteSavedFilter(ctx context.Context, id uuid.UUID) error {
+	actorID, _ := am.ActorFromContext(ctx)
+	return svc.repo.DeleteSavedFilter(ctx, actorID, id)
+}
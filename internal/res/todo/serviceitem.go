@@ -0,0 +1,633 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/google/uuid"
+)
+
+// Due views.
+const (
+	ViewToday    = "today"
+	ViewUpcoming = "upcoming"
+	ViewOverdue  = "overdue"
+)
+
+// DefaultUpcomingDays is how far ahead the upcoming view looks when no range is given.
+const DefaultUpcomingDays = 7
+
+// DueItem is an item shown along with its list, as in the due views and the filtered items view.
+type DueItem struct {
+	Item
+	List List
+}
+
+// GetItems returns the items of a list the actor can see.
+func (svc *BaseService) GetItems(ctx context.Context, listID uuid.UUID) ([]Item, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return svc.repo.GetItems(ctx, listID)
+}
+
+// GetItem returns an item of a list the actor can see.
+func (svc *BaseService) GetItem(ctx context.Context, listID, id uuid.UUID) (Item, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Item{}, err
+	}
+
+	item, err := svc.repo.GetItem(ctx, id)
+	if err != nil {
+		return Item{}, err
+	}
+	if item.ListID != listID {
+		return Item{}, ErrItemNotFound
+	}
+	return item, nil
+}
+
+// CreateItem adds an item to a list, it requires editor access.
+func (svc *BaseService) CreateItem(ctx context.Context, item Item) error {
+	err := item.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = svc.requireList(ctx, item.ListID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	err = svc.checkLinks(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	if item.IsRecurring() {
+		item.Occurrence = item.OccurrenceIndex()
+	}
+	item.Done = item.Status == StatusDone
+	if !item.Done {
+		item.CompletedAt = time.Time{}
+	} else if item.CompletedAt.IsZero() {
+		// Imported items can carry the time they were completed at.
+		item.CompletedAt = time.Now()
+	}
+	if item.Position == 0 {
+		item.Position, err = svc.lastPosition(ctx, item.ListID)
+		if err != nil {
+			return err
+		}
+	}
+
+	svc.StampCreate(ctx, item)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.CreateItem(ctx, item)
+		if err != nil {
+			return err
+		}
+		err = svc.reviseItem(ctx, RevisionCreated, item)
+		if err != nil {
+			return err
+		}
+		return svc.record(ctx, NewEvent(item.ListID, item.ID(), EventCreated, item.Title))
+	})
+}
+
+// UpdateItem requires editor access.
+// Changing the due time or the reminders makes reminders that have not been reached yet fire again.
+func (svc *BaseService) UpdateItem(ctx context.Context, item Item) error {
+	err := item.Validate()
+	if err != nil {
+		return err
+	}
+
+	current, err := svc.GetItem(ctx, item.ListID, item.ID())
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, item.ListID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	err = svc.checkLinks(ctx, item)
+	if err != nil {
+		return err
+	}
+
+	item.SeriesID = current.SeriesID
+	item.Occurrence = current.Occurrence
+	item.Assignees = current.Assignees
+	item.RemindedAt = current.RemindedAt
+	if !item.DueAt.Equal(current.DueAt) || FormatOffsets(item.Reminders) != FormatOffsets(current.Reminders) {
+		resetReminders(&item, time.Now())
+	}
+
+	item.Done = current.Done
+	item.CompletedAt = current.CompletedAt
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.saveStatus(ctx, item, false)
+		if err != nil || item.Title == current.Title {
+			return err
+		}
+		return svc.record(ctx, renamed(item.ListID, item.ID(), current.Title, item.Title))
+	})
+}
+
+// DeleteItem requires editor access.
+func (svc *BaseService) DeleteItem(ctx context.Context, listID, id uuid.UUID) error {
+	attachments, err := svc.deleteItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	svc.deleteBlobs(ctx, attachments...)
+	return nil
+}
+
+// deleteItem deletes the item and its subtasks and returns their attachments, whose blobs are left to the caller.
+func (svc *BaseService) deleteItem(ctx context.Context, listID, id uuid.UUID) ([]Attachment, error) {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return nil, err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	// Subtasks go with their parent, deepest first, and attachments with their item.
+	ids := subtreeIDs(id, items)
+	attachments, err := svc.repo.GetAttachments(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	byID := itemsByID(items)
+	err = svc.withTx(ctx, func(ctx context.Context) error {
+		for i := len(ids) - 1; i >= 0; i-- {
+			err := svc.repo.DeleteItem(ctx, ids[i])
+			if err != nil {
+				return err
+			}
+			err = svc.reviseItem(ctx, RevisionDeleted, byID[ids[i]])
+			if err != nil {
+				return err
+			}
+		}
+		return svc.record(ctx, NewEvent(listID, id, EventDeleted, item.Title))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// CompleteItem marks an item as done, it is refused with ErrItemBlocked while the item waits on open items unless forced.
+func (svc *BaseService) CompleteItem(ctx context.Context, listID, id uuid.UUID, force bool) error {
+	return svc.setItemDone(ctx, listID, id, true, force)
+}
+
+func (svc *BaseService) ReopenItem(ctx context.Context, listID, id uuid.UUID) error {
+	return svc.setItemDone(ctx, listID, id, false, false)
+}
+
+func (svc *BaseService) setItemDone(ctx context.Context, listID, id uuid.UUID, done, force bool) error {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	if item.Done == done {
+		return nil
+	}
+
+	items, err := svc.repo.GetItems(ctx, listID)
+	if err != nil {
+		return err
+	}
+	if done && !force && len(openBlockers(item, itemsByID(items))) > 0 {
+		return ErrItemBlocked
+	}
+
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		return svc.markDone(ctx, item, done)
+	})
+}
+
+// saveStatus saves an item whose Done flag is the stored one and whose status may have changed.
+// Moving the status to or from done completes or reopens the item, completing a blocked item needs force.
+func (svc *BaseService) saveStatus(ctx context.Context, item Item, force bool) error {
+	done := item.Status == StatusDone
+	if done == item.Done {
+		return svc.saveItem(ctx, item)
+	}
+	if done && !force {
+		items, err := svc.repo.GetItems(ctx, item.ListID)
+		if err != nil {
+			return err
+		}
+		if len(openBlockers(item, itemsByID(items))) > 0 {
+			return ErrItemBlocked
+		}
+	}
+	return svc.markDone(ctx, item, done)
+}
+
+// markDone completes or reopens an item and rolls the change up to its parent:
+// a parent is completed along with its last open subtask, unless it is blocked, and reopened with any of them.
+// Reopened items go back to todo unless they are given another open status.
+func (svc *BaseService) markDone(ctx context.Context, item Item, done bool) error {
+	item.Done = done
+	item.CompletedAt = time.Time{}
+	if done {
+		item.CompletedAt = time.Now()
+		item.Status = StatusDone
+	} else if item.Status == StatusDone {
+		item.Status = StatusTodo
+	}
+	if item.IsRecurring() {
+		item.SeriesID = item.SeriesKey()
+		item.Occurrence = item.OccurrenceIndex()
+	}
+
+	err := svc.saveItem(ctx, item)
+	if err != nil {
+		return err
+	}
+	kind := EventReopened
+	if done {
+		kind = EventCompleted
+	}
+	err = svc.record(ctx, NewEvent(item.ListID, item.ID(), kind, item.Title))
+	if err != nil {
+		return err
+	}
+
+	if done && item.IsRecurring() {
+		err = svc.createNextOccurrence(ctx, item)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !item.HasParent() {
+		return nil
+	}
+	items, err := svc.repo.GetItems(ctx, item.ListID)
+	if err != nil {
+		return err
+	}
+	byID := itemsByID(items)
+	parent, ok := byID[item.ParentID]
+	if !ok || parent.Done == done {
+		return nil
+	}
+	if done {
+		for _, other := range items {
+			if other.ParentID == parent.ID() && !other.Done {
+				return nil
+			}
+		}
+		if len(openBlockers(parent, byID)) > 0 {
+			return nil
+		}
+	}
+	return svc.markDone(ctx, parent, done)
+}
+
+// createNextOccurrence adds the occurrence that follows a completed one.
+// Nothing is created when the series has ended or a later occurrence already exists,
+// so reopening and completing an occurrence again does not duplicate it.
+func (svc *BaseService) createNextOccurrence(ctx context.Context, item Item) error {
+	due, ok := item.NextOccurrence()
+	if !ok {
+		return nil
+	}
+
+	items, err := svc.repo.GetItems(ctx, item.ListID)
+	if err != nil {
+		return err
+	}
+	for _, other := range items {
+		if other.SeriesKey() == item.SeriesKey() && other.OccurrenceIndex() > item.OccurrenceIndex() {
+			return nil
+		}
+	}
+
+	next := NewItem(item.ListID, item.Title, item.Notes)
+	next.TimeZone = item.TimeZone
+	next.Reminders = item.Reminders
+	next.RRule = item.RRule
+	next.SeriesID = item.SeriesKey()
+	next.Occurrence = item.OccurrenceIndex() + 1
+	next.Position = item.Position
+	next.ParentID = item.ParentID
+	next.BlockedBy = item.BlockedBy
+	next.Assignees = item.Assignees
+	next.StartAt = item.StartAt
+	next.DueAt = item.DueAt
+	next.Shift(due)
+	resetReminders(&next, time.Now())
+
+	svc.StampCreate(ctx, next)
+	err = svc.repo.CreateItem(ctx, next)
+	if err != nil {
+		return err
+	}
+	return svc.reviseItem(ctx, RevisionCreated, next)
+}
+
+// SkipOccurrence moves an open recurring item to its next occurrence without completing it.
+func (svc *BaseService) SkipOccurrence(ctx context.Context, listID, id uuid.UUID) error {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	if !item.IsRecurring() || item.Done {
+		return ErrNotRecurring
+	}
+	due, ok := item.NextOccurrence()
+	if !ok {
+		return ErrRecurrenceEnded
+	}
+	item.SeriesID = item.SeriesKey()
+	item.Occurrence = item.OccurrenceIndex() + 1
+	item.Shift(due)
+	resetReminders(&item, time.Now())
+
+	return svc.saveItem(ctx, item)
+}
+
+// EndRecurrence removes the recurrence rule of an item, it stays as the last occurrence of its series.
+func (svc *BaseService) EndRecurrence(ctx context.Context, listID, id uuid.UUID) error {
+	item, err := svc.GetItem(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return err
+	}
+
+	if !item.IsRecurring() {
+		return ErrNotRecurring
+	}
+	item.SeriesID = item.SeriesKey()
+	item.RRule = ""
+
+	return svc.saveItem(ctx, item)
+}
+
+// FindItems returns the items of all the lists the actor can see that pass the filter, along with their lists.
+// They are sorted by priority, items without one last, and keep their list order otherwise.
+func (svc *BaseService) FindItems(ctx context.Context, filter ItemFilter) ([]DueItem, error) {
+	items, byID, err := svc.visibleItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []DueItem
+	for _, item := range filter.Apply(items) {
+		found = append(found, DueItem{Item: item, List: byID[item.ListID]})
+	}
+
+	rank := func(p Priority) int {
+		if p == PriorityNone {
+			return int(Priority4) + 1
+		}
+		return int(p)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return rank(found[i].Priority) < rank(found[j].Priority) })
+	return found, nil
+}
+
+// GetTags returns the tags used in the lists the actor can see, in alphabetical order.
+func (svc *BaseService) GetTags(ctx context.Context) ([]string, error) {
+	items, _, err := svc.visibleItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return collectTags(items), nil
+}
+
+// visibleItems returns the items of the lists the actor can see along with those lists by ID.
+func (svc *BaseService) visibleItems(ctx context.Context) ([]Item, map[uuid.UUID]List, error) {
+	lists, err := svc.GetLists(ctx, FilterAll)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[uuid.UUID]List, len(lists))
+	ids := make([]uuid.UUID, len(lists))
+	for i, list := range lists {
+		byID[list.ID()] = list
+		ids[i] = list.ID()
+	}
+	if len(ids) == 0 {
+		return nil, byID, nil
+	}
+
+	items, err := svc.repo.GetItems(ctx, ids...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, byID, nil
+}
+
+// checkLinks validates the parent and the dependencies of an item against the items of its list.
+func (svc *BaseService) checkLinks(ctx context.Context, item Item) error {
+	if !item.HasParent() && len(item.BlockedBy) == 0 {
+		return nil
+	}
+	items, err := svc.repo.GetItems(ctx, item.ListID)
+	if err != nil {
+		return err
+	}
+	return checkItemLinks(item, items)
+}
+
+func itemsByID(items []Item) map[uuid.UUID]Item {
+	byID := make(map[uuid.UUID]Item, len(items))
+	for _, item := range items {
+		byID[item.ID()] = item
+	}
+	return byID
+}
+
+// subtreeIDs returns the ID of an item followed by the ones of all its subtasks, parents before their subtasks.
+func subtreeIDs(id uuid.UUID, items []Item) []uuid.UUID {
+	ids := []uuid.UUID{id}
+	for i := 0; i < len(ids); i++ {
+		for _, item := range items {
+			if item.ParentID == ids[i] {
+				ids = append(ids, item.ID())
+			}
+		}
+	}
+	return ids
+}
+
+// resetReminders marks the reminders already due at now as notified, so moving an item does not fire them late.
+func resetReminders(item *Item, now time.Time) {
+	item.RemindedAt = time.Time{}
+	if pending, ok := item.PendingReminder(now); ok {
+		item.RemindedAt = pending
+	}
+}
+
+// GetDueItems returns the open items of the lists the actor can see that fall in the view.
+// Today covers the current day in loc, upcoming the given number of days after it and
+// overdue everything past its due time. Items are sorted by due time.
+func (svc *BaseService) GetDueItems(ctx context.Context, view string, now time.Time, loc *time.Location, days int) ([]DueItem, error) {
+	items, byID, err := svc.visibleItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if days <= 0 {
+		days = DefaultUpcomingDays
+	}
+	local := now.In(loc)
+	startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+	endOfRange := endOfDay.AddDate(0, 0, days)
+
+	var due []DueItem
+	for _, item := range items {
+		if item.Done || !item.HasDue() {
+			continue
+		}
+
+		var in bool
+		switch view {
+		case ViewToday:
+			in = !item.DueAt.Before(startOfDay) && item.DueAt.Before(endOfDay)
+		case ViewUpcoming:
+			in = !item.DueAt.Before(endOfDay) && item.DueAt.Before(endOfRange)
+		case ViewOverdue:
+			in = item.IsOverdue(now)
+		}
+		if in {
+			due = append(due, DueItem{Item: item, List: byID[item.ListID]})
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].DueAt.Before(due[j].DueAt) })
+	return due, nil
+}
+
+// GetPendingReminders returns the reminders that are due and were not notified yet.
+// It looks at every list but archived ones and templates, the scheduler runs without an actor.
+func (svc *BaseService) GetPendingReminders(ctx context.Context, now time.Time) ([]Reminder, error) {
+	lists, err := svc.repo.GetAll(ctx, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]List, len(lists))
+	ids := make([]uuid.UUID, 0, len(lists))
+	for _, list := range lists {
+		if list.IsArchived() || list.Template {
+			continue
+		}
+		byID[list.ID()] = list
+		ids = append(ids, list.ID())
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	items, err := svc.repo.GetItems(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []Reminder
+	for _, item := range items {
+		at, ok := item.PendingReminder(now)
+		if !ok {
+			continue
+		}
+		reminders = append(reminders, Reminder{Item: item, List: byID[item.ListID], At: at})
+	}
+	return reminders, nil
+}
+
+// MarkReminded records that the reminders of the item up to the given time were notified.
+func (svc *BaseService) MarkReminded(ctx context.Context, reminder Reminder) error {
+	return svc.repo.SetItemRemindedAt(ctx, reminder.Item.ID(), reminder.At)
+}
+
+// GetReminderRecipients returns the users a reminder of the list goes to.
+// The owner of a list owned by a user, every member of the owning team otherwise.
+func (svc *BaseService) GetReminderRecipients(ctx context.Context, list List) ([]auth.User, error) {
+	if list.OwnerUserID != uuid.Nil {
+		user, err := svc.dir.GetUser(ctx, list.OwnerUserID)
+		if err != nil {
+			return nil, err
+		}
+		return []auth.User{user}, nil
+	}
+
+	if list.OwnerTeamID == uuid.Nil {
+		return nil, nil
+	}
+
+	return svc.teamUsers(ctx, list.OwnerTeamID)
+}
+
+// teamUsers returns the members of the team.
+func (svc *BaseService) teamUsers(ctx context.Context, teamID uuid.UUID) ([]auth.User, error) {
+	members, err := svc.dir.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	// Members are read again one by one, GetUser is the one that decrypts the email.
+	users := make([]auth.User, 0, len(members))
+	for _, m := range members {
+		user, err := svc.dir.GetUser(ctx, m.ID())
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// saveItem stores the changes of an item and records them as a revision, both or neither.
+func (svc *BaseService) saveItem(ctx context.Context, item Item) error {
+	svc.StampUpdate(ctx, item)
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.repo.UpdateItem(ctx, item)
+		if err != nil {
+			return err
+		}
+		return svc.reviseItem(ctx, RevisionUpdated, item)
+	})
+}
+
+func (svc *BaseService) requireList(ctx context.Context, listID uuid.UUID, required Access) error {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return err
+	}
+	return svc.require(ctx, list, required)
+}
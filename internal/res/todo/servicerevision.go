@@ -0,0 +1,197 @@
+package todo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// GetRevisions returns the revisions of a visible list and of its items, newest first.
+func (svc *BaseService) GetRevisions(ctx context.Context, listID uuid.UUID) ([]Revision, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := svc.repo.GetRevisions(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(revisions, func(i, j int) bool { return revisions[i].At.After(revisions[j].At) })
+	return revisions, nil
+}
+
+// GetRevision returns a revision of a visible list.
+func (svc *BaseService) GetRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Revision{}, err
+	}
+	return svc.repo.GetRevision(ctx, listID, id)
+}
+
+// RestoreRevision brings the list or the item back to the state of a revision, it requires editor access.
+// A deleted item is created again with its former ID, without its attachments, and links to items
+// that no longer exist are dropped. The restore is itself recorded as a new revision, which is returned.
+func (svc *BaseService) RestoreRevision(ctx context.Context, listID, id uuid.UUID) (Revision, error) {
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return Revision{}, err
+	}
+	err = svc.require(ctx, list, AccessEditor)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	revision, err := svc.repo.GetRevision(ctx, listID, id)
+	if err != nil {
+		return Revision{}, err
+	}
+	if revision.IsOnItem() {
+		return svc.restoreItem(ctx, revision)
+	}
+	return svc.restoreList(ctx, list, revision)
+}
+
+func (svc *BaseService) restoreList(ctx context.Context, list List, revision Revision) (Revision, error) {
+	var snapshot List
+	err := json.Unmarshal(revision.Snapshot, &snapshot)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	name := list.Name
+	list.Name = snapshot.Name
+	list.Description = snapshot.Description
+	list.Columns = snapshot.Columns
+	svc.StampUpdate(ctx, list)
+	err = svc.repo.Update(ctx, list)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	if list.Name != name {
+		err = svc.record(ctx, renamed(list.ID(), uuid.Nil, name, list.Name))
+		if err != nil {
+			return Revision{}, err
+		}
+	}
+	return svc.revise(ctx, RevisionRestored, list.ID(), uuid.Nil, list.Name, list, revision.ID)
+}
+
+func (svc *BaseService) restoreItem(ctx context.Context, revision Revision) (Revision, error) {
+	var snapshot Item
+	err := json.Unmarshal(revision.Snapshot, &snapshot)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	current, err := svc.repo.GetItem(ctx, revision.ItemID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, ErrItemNotFound) {
+		return Revision{}, err
+	}
+
+	item := snapshot
+	item.ListID = revision.ListID
+	if exists {
+		item.BaseModel = current.BaseModel
+	} else {
+		item.BaseModel = am.NewModel(am.WithID(revision.ItemID), am.WithType(itemType))
+	}
+
+	items, err := svc.repo.GetItems(ctx, revision.ListID)
+	if err != nil {
+		return Revision{}, err
+	}
+	pruneLinks(&item, items)
+	err = checkItemLinks(item, items)
+	if err != nil {
+		return Revision{}, err
+	}
+	item.Done = item.Status == StatusDone
+	err = item.Validate()
+	if err != nil {
+		return Revision{}, err
+	}
+	resetReminders(&item, time.Now())
+
+	if exists {
+		svc.StampUpdate(ctx, item)
+		err = svc.repo.UpdateItem(ctx, item)
+		if err == nil && item.Title != current.Title {
+			err = svc.record(ctx, renamed(item.ListID, item.ID(), current.Title, item.Title))
+		}
+	} else {
+		svc.StampCreate(ctx, item)
+		err = svc.repo.CreateItem(ctx, item)
+		if err == nil {
+			err = svc.record(ctx, NewEvent(item.ListID, item.ID(), EventCreated, item.Title))
+		}
+	}
+	if err != nil {
+		return Revision{}, err
+	}
+	return svc.revise(ctx, RevisionRestored, item.ListID, item.ID(), item.Title, item, revision.ID)
+}
+
+// reviseList records a revision of the list made by the actor.
+func (svc *BaseService) reviseList(ctx context.Context, action string, list List) error {
+	_, err := svc.revise(ctx, action, list.ID(), uuid.Nil, list.Name, list, uuid.Nil)
+	return err
+}
+
+// reviseItem records a revision of the item made by the actor.
+func (svc *BaseService) reviseItem(ctx context.Context, action string, item Item) error {
+	_, err := svc.revise(ctx, action, item.ListID, item.ID(), item.Title, item, uuid.Nil)
+	return err
+}
+
+// revise snapshots the list or the item and diffs it with its previous revision.
+// Updates that change nothing a user can see, such as moving a board neighbour, are not recorded.
+func (svc *BaseService) revise(ctx context.Context, action string, listID, itemID uuid.UUID, subject string, v any, restoredFrom uuid.UUID) (Revision, error) {
+	snapshot, err := json.Marshal(v)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	revisions, err := svc.repo.GetRevisions(ctx, listID)
+	if err != nil {
+		return Revision{}, err
+	}
+	var previous json.RawMessage
+	for i := len(revisions) - 1; i >= 0; i-- {
+		if revisions[i].IsOf(itemID) {
+			previous = revisions[i].Snapshot
+			break
+		}
+	}
+
+	revision := NewRevision(listID, itemID, action, subject, snapshot)
+	revision.Changes = DiffSnapshots(previous, snapshot)
+	revision.RestoredFrom = restoredFrom
+	if action == RevisionUpdated && len(revision.Changes) == 0 {
+		return revision, nil
+	}
+	revision.ActorID, _ = am.ActorFromContext(ctx)
+	return revision, svc.repo.AddRevision(ctx, revision)
+}
+
+// pruneLinks drops the parent and the dependencies of the item that are not among the items.
+func pruneLinks(item *Item, items []Item) {
+	byID := itemsByID(items)
+	if _, ok := byID[item.ParentID]; !ok || item.ParentID == item.ID() {
+		item.ParentID = uuid.Nil
+	}
+	var blockedBy []uuid.UUID
+	for _, id := range item.BlockedBy {
+		if _, ok := byID[id]; ok && id != item.ID() {
+			blockedBy = append(blockedBy, id)
+		}
+	}
+	item.BlockedBy = blockedBy
+}
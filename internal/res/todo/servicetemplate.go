@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SaveAsTemplate copies the list and its items into a new template owned by the actor.
+// Any list the actor can see can be saved as a template, it is named after the list when no name is given.
+func (svc *BaseService) SaveAsTemplate(ctx context.Context, id uuid.UUID, name string) (List, error) {
+	list, err := svc.Get(ctx, id)
+	if err != nil {
+		return List{}, err
+	}
+	items, err := svc.repo.GetItems(ctx, list.ID())
+	if err != nil {
+		return List{}, err
+	}
+
+	template := NewList(cmp.Or(strings.TrimSpace(name), list.Name), list.Description)
+	template.Template = true
+	template.Columns = slices.Clone(list.Columns)
+	err = svc.copyList(ctx, template, items, nil)
+	if err != nil {
+		return List{}, err
+	}
+	return template, nil
+}
+
+// CreateFromTemplate creates a list owned by the actor with the items of the template, placeholders filled with the values.
+func (svc *BaseService) CreateFromTemplate(ctx context.Context, id uuid.UUID, values TemplateValues) (List, error) {
+	template, err := svc.Get(ctx, id)
+	if err != nil {
+		return List{}, err
+	}
+	if !template.Template {
+		return List{}, ErrNotTemplate
+	}
+	items, err := svc.repo.GetItems(ctx, template.ID())
+	if err != nil {
+		return List{}, err
+	}
+
+	if values.Date.IsZero() {
+		values.Date = time.Now()
+	}
+	days := values.shiftDays(template)
+
+	name := cmp.Or(strings.TrimSpace(values.Name), template.Name)
+	list := NewList(values.Fill(name), values.Fill(template.Description))
+	list.Columns = slices.Clone(template.Columns)
+	err = svc.copyList(ctx, list, items, func(item *Item) {
+		item.Title = values.Fill(item.Title)
+		item.Notes = values.Fill(item.Notes)
+		if item.HasStart() {
+			item.StartAt = item.StartAt.AddDate(0, 0, days)
+		}
+		if item.HasDue() {
+			item.DueAt = item.DueAt.AddDate(0, 0, days)
+		}
+	})
+	if err != nil {
+		return List{}, err
+	}
+	return list, nil
+}
+
+// copyList creates the list along with copies of the items in a single transaction, see copyItems.
+func (svc *BaseService) copyList(ctx context.Context, list List, items []Item, edit func(item *Item)) error {
+	return svc.withTx(ctx, func(ctx context.Context) error {
+		err := svc.Create(ctx, list)
+		if err != nil {
+			return err
+		}
+		for _, item := range importOrder(copyItems(list.ID(), items, edit)) {
+			err = svc.CreateItem(ctx, item)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,211 @@
+package todo
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GetTimeEntries returns the time entries of the list, or of one of its items when itemID is not Nil, oldest first.
+func (svc *BaseService) GetTimeEntries(ctx context.Context, listID, itemID uuid.UUID) ([]TimeEntry, error) {
+	_, err := svc.Get(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := svc.repo.GetTimeEntries(ctx, listID)
+	if err != nil || itemID == uuid.Nil {
+		return entries, err
+	}
+
+	var result []TimeEntry
+	for _, e := range entries {
+		if e.ItemID == itemID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// StartTimer starts a timer of the actor on the item, it requires editor access.
+// It fails with ErrTimerRunning while another timer of the actor runs.
+func (svc *BaseService) StartTimer(ctx context.Context, listID, itemID uuid.UUID, notes string) (TimeEntry, error) {
+	userID, err := userActor(ctx, "time entries")
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	item, err := svc.GetItem(ctx, listID, itemID)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	err = svc.requireList(ctx, listID, AccessEditor)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	timer := NewTimer(listID, item.ID(), userID, notes)
+	return timer, svc.repo.CreateTimeEntry(ctx, timer)
+}
+
+// StopTimer stops the running timer of the actor, whatever its list.
+func (svc *BaseService) StopTimer(ctx context.Context) (TimeEntry, error) {
+	timer, err := svc.GetRunningTimer(ctx)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	timer.Stop(time.Now())
+	return timer, svc.repo.UpdateTimeEntry(ctx, timer)
+}
+
+// GetRunningTimer returns the running timer of the actor, ErrNoTimerRunning when there is none.
+func (svc *BaseService) GetRunningTimer(ctx context.Context) (TimeEntry, error) {
+	userID, err := userActor(ctx, "time entries")
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	return svc.repo.GetRunningTimer(ctx, userID)
+}
+
+// AddTimeEntry stores a manual entry of the actor on the item, it requires editor access.
+func (svc *BaseService) AddTimeEntry(ctx context.Context, entry TimeEntry) (TimeEntry, error) {
+	userID, err := userActor(ctx, "time entries")
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	err = entry.Validate()
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	_, err = svc.GetItem(ctx, entry.ListID, entry.ItemID)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+	err = svc.requireList(ctx, entry.ListID, AccessEditor)
+	if err != nil {
+		return TimeEntry{}, err
+	}
+
+	entry.UserID = userID
+	entry.Manual = true
+	return entry, svc.repo.CreateTimeEntry(ctx, entry)
+}
+
+// DeleteTimeEntry removes an entry of the actor, the owners of the list can remove the entries of anyone.
+func (svc *BaseService) DeleteTimeEntry(ctx context.Context, listID, id uuid.UUID) error {
+	actorID, err := userActor(ctx, "time entries")
+	if err != nil {
+		return err
+	}
+	list, err := svc.Get(ctx, listID)
+	if err != nil {
+		return err
+	}
+	entry, err := svc.repo.GetTimeEntry(ctx, listID, id)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != actorID {
+		err = svc.require(ctx, list, AccessOwner)
+		if err != nil {
+			return err
+		}
+	}
+	return svc.repo.DeleteTimeEntry(ctx, listID, id)
+}
+
+// GetTimesheet returns the time the actor tracked in the week of the given day, days start in the location of the day.
+func (svc *BaseService) GetTimesheet(ctx context.Context, day time.Time) (Timesheet, error) {
+	userID, err := userActor(ctx, "time entries")
+	if err != nil {
+		return Timesheet{}, err
+	}
+	start := WeekStart(day)
+	records, err := svc.timeRecords(ctx, TimeFilter{UserID: userID, From: start, To: start.AddDate(0, 0, 6)}, true)
+	if err != nil {
+		return Timesheet{}, err
+	}
+	return NewTimesheet(start, records), nil
+}
+
+// FindTimeEntries returns the entries that pass the filter in the lists the actor can see, archived ones included,
+// oldest first. Running timers are left out, their time is not known yet.
+func (svc *BaseService) FindTimeEntries(ctx context.Context, filter TimeFilter) ([]TimeRecord, error) {
+	return svc.timeRecords(ctx, filter, false)
+}
+
+// timeRecords finds the entries of FindTimeEntries, along with the running timers when running is set.
+func (svc *BaseService) timeRecords(ctx context.Context, filter TimeFilter, running bool) ([]TimeRecord, error) {
+	ctx, _, err := svc.withListAuthz(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := svc.GetLists(ctx, FilterAll)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := svc.GetLists(ctx, FilterArchived)
+	if err != nil {
+		return nil, err
+	}
+	lists = append(lists, archived...)
+	if len(lists) == 0 {
+		return nil, nil
+	}
+
+	names := make(map[uuid.UUID]string, len(lists))
+	ids := make([]uuid.UUID, len(lists))
+	for i, list := range lists {
+		names[list.ID()] = list.Name
+		ids[i] = list.ID()
+	}
+	items, err := svc.repo.GetItems(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		names[item.ID()] = item.Title
+	}
+	users, err := svc.dir.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		names[user.ID()] = user.Username
+	}
+
+	var members map[uuid.UUID]bool
+	if filter.TeamID != uuid.Nil {
+		teamMembers, err := svc.dir.GetTeamMembers(ctx, filter.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		members = make(map[uuid.UUID]bool, len(teamMembers))
+		for _, m := range teamMembers {
+			members[m.ID()] = true
+		}
+	}
+
+	entries, err := svc.repo.GetTimeEntries(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	var records []TimeRecord
+	for _, e := range entries {
+		switch {
+		case !filter.Match(e):
+			continue
+		case members != nil && !members[e.UserID]:
+			continue
+		case e.IsRunning() && !running:
+			continue
+		}
+		record := TimeRecord{TimeEntry: e, List: names[e.ListID], Item: names[e.ItemID], User: names[e.UserID]}
+		if record.Item == "" {
+			record.Item = "Deleted item"
+		}
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].StartedAt.Before(records[j].StartedAt) })
+	return records, nil
+}
@@ -0,0 +1,117 @@
+package todo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportLists returns the list along with its items ready to be written to a file,
+// or every list the actor can see when no list ID is given.
+func (svc *BaseService) ExportLists(ctx context.Context, listID uuid.UUID) ([]TransferList, error) {
+	var lists []List
+	if listID != uuid.Nil {
+		list, err := svc.Get(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+		lists = []List{list}
+	} else {
+		var err error
+		lists, err = svc.GetLists(ctx, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]TransferList, 0, len(lists))
+	for _, list := range lists {
+		items, err := svc.repo.GetItems(ctx, list.ID())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, NewTransferList(list, items))
+	}
+	return result, nil
+}
+
+// Import reads a file and creates its lists and items, or only reports what it would create on a dry run.
+// Nothing is created when the file has problems, the report lists them all so they can be fixed at once,
+// nor when creating any of its lists or items fails.
+// Importing into an existing list requires editor access to it.
+func (svc *BaseService) Import(ctx context.Context, opts ImportOptions, r io.Reader) (ImportReport, error) {
+	codec, ok := formats[opts.Format]
+	if !ok {
+		return ImportReport{}, fmt.Errorf("%w: %q", ErrInvalidFormat, opts.Format)
+	}
+	loc, err := time.LoadLocation(opts.TimeZone)
+	if err != nil {
+		return ImportReport{}, fmt.Errorf("%w: %s", ErrInvalidTimeZone, opts.TimeZone)
+	}
+
+	lists, problems := codec.decode(r, loc)
+	if opts.ListID != uuid.Nil {
+		list, err := svc.Get(ctx, opts.ListID)
+		if err != nil {
+			return ImportReport{}, err
+		}
+		err = svc.require(ctx, list, AccessEditor)
+		if err != nil {
+			return ImportReport{}, err
+		}
+		lists = mergeTransfer(list, lists)
+	}
+
+	report := ImportReport{Format: opts.Format, DryRun: opts.DryRun}
+	var more []ImportProblem
+	report.Lists, more = prepareImport(lists)
+	report.Problems = append(problems, more...)
+	if opts.ListID != uuid.Nil {
+		report.Lists[0].ListID = opts.ListID
+		report.Lists[0].New = false
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if !report.Valid() {
+		return report, ErrInvalidImport
+	}
+
+	err = svc.withTx(ctx, func(ctx context.Context) error {
+		for i := range report.Lists {
+			err := svc.importList(ctx, &report.Lists[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return report, err
+}
+
+// importList creates the list when it is new and then its items, parents and dependencies first.
+func (svc *BaseService) importList(ctx context.Context, il *ImportedList) error {
+	if il.New {
+		list := NewList(il.Name, il.Description)
+		err := svc.Create(ctx, list)
+		if err != nil {
+			return err
+		}
+		il.ListID = list.ID()
+	}
+
+	for i := range il.Items {
+		il.Items[i].ListID = il.ListID
+	}
+	for _, item := range importOrder(il.Items) {
+		err := svc.CreateItem(ctx, item)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
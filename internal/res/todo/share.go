@@ -0,0 +1,100 @@
+package todo
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Grantee types, a list can be shared with a single user or with every member of a team.
+const (
+	GranteeUser = "user"
+	GranteeTeam = "team"
+)
+
+// Share roles.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+)
+
+// Share grants a user or a team access to a list.
+type Share struct {
+	ListID      uuid.UUID `json:"list_id"`
+	GranteeType string    `json:"grantee_type"`
+	GranteeID   uuid.UUID `json:"grantee_id"`
+	Role        string    `json:"role"`
+	CreatedBy   uuid.UUID `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewShare creates a share of the list.
+func NewShare(listID uuid.UUID, granteeType string, granteeID uuid.UUID, role string) Share {
+	return Share{
+		ListID:      listID,
+		GranteeType: granteeType,
+		GranteeID:   granteeID,
+		Role:        role,
+	}
+}
+
+// Access returns the access level granted by the share.
+func (s Share) Access() Access {
+	if s.Role == RoleEditor {
+		return AccessEditor
+	}
+	return AccessViewer
+}
+
+// Valid reports whether the grantee type and the role are known.
+func (s Share) Valid() bool {
+	validType := s.GranteeType == GranteeUser || s.GranteeType == GranteeTeam
+	validRole := s.Role == RoleViewer || s.Role == RoleEditor
+	return validType && validRole && s.GranteeID != uuid.Nil
+}
+
+// Access is the level of access a user has on a list, higher levels include the lower ones.
+type Access int
+
+const (
+	AccessNone Access = iota
+	AccessViewer
+	AccessEditor
+	AccessOwner
+)
+
+// CanView reports whether the list can be read.
+func (a Access) CanView() bool {
+	return a >= AccessViewer
+}
+
+// CanEdit reports whether the list can be changed.
+func (a Access) CanEdit() bool {
+	return a >= AccessEditor
+}
+
+// CanManage reports whether the list can be deleted and shared.
+func (a Access) CanManage() bool {
+	return a >= AccessOwner
+}
+
+func (a Access) String() string {
+	switch a {
+	case AccessViewer:
+		return RoleViewer
+	case AccessEditor:
+		return RoleEditor
+	case AccessOwner:
+		return "owner"
+	}
+	return "none"
+}
+
+// List filters. Archived lists and templates are only listed by their own filters.
+const (
+	FilterAll       = ""
+	FilterOwned     = "owned"
+	FilterShared    = "shared"
+	FilterArchived  = "archived"
+	FilterTemplates = "templates"
+)
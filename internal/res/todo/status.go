@@ -0,0 +1,120 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPriority is returned when a priority is not one of P1 to P4.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+// ErrInvalidStatus is returned when a status is not one of the item statuses.
+var ErrInvalidStatus = errors.New("invalid status")
+
+// Priority ranks items from P1, the most urgent, to P4. The zero value means no priority.
+type Priority int
+
+// Priorities.
+const (
+	PriorityNone Priority = iota
+	Priority1
+	Priority2
+	Priority3
+	Priority4
+)
+
+// Priorities lists the priorities that can be set, most urgent first.
+var Priorities = []Priority{Priority1, Priority2, Priority3, Priority4}
+
+// ParsePriority parses "P1" to "P4", case insensitive and with an optional "P".
+// An empty string or "none" is no priority.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" || s == "NONE" {
+		return PriorityNone, nil
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(s, "P"))
+	if err != nil || n < int(Priority1) || n > int(Priority4) {
+		return PriorityNone, fmt.Errorf("%w: %s", ErrInvalidPriority, s)
+	}
+	return Priority(n), nil
+}
+
+func (p Priority) String() string {
+	if p == PriorityNone {
+		return ""
+	}
+	return "P" + strconv.Itoa(int(p))
+}
+
+// Valid reports whether the priority is no priority or one of P1 to P4.
+func (p Priority) Valid() bool {
+	return p >= PriorityNone && p <= Priority4
+}
+
+// MarshalText encodes the priority as "P1" to "P4", or an empty string.
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses a priority written as for ParsePriority.
+func (p *Priority) UnmarshalText(text []byte) error {
+	priority, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = priority
+	return nil
+}
+
+// Status is the stage of an item, done is the only closed one and it is kept in sync with the item Done flag.
+type Status string
+
+// Statuses.
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusWaiting    Status = "waiting"
+	StatusDone       Status = "done"
+)
+
+// Statuses lists the statuses in workflow order.
+var Statuses = []Status{StatusTodo, StatusInProgress, StatusWaiting, StatusDone}
+
+// ParseStatus parses a status, an empty string is todo.
+func ParseStatus(s string) (Status, error) {
+	status := Status(strings.ToLower(strings.TrimSpace(s)))
+	if status == "" {
+		return StatusTodo, nil
+	}
+	if !status.Valid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidStatus, s)
+	}
+	return status, nil
+}
+
+// Valid reports whether the status is one of the item statuses.
+func (s Status) Valid() bool {
+	for _, status := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the status as shown to users.
+func (s Status) Label() string {
+	switch s {
+	case StatusInProgress:
+		return "In progress"
+	case StatusWaiting:
+		return "Waiting"
+	case StatusDone:
+		return "Done"
+	default:
+		return "To do"
+	}
+}
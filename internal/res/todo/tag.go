@@ -0,0 +1,80 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxTagLen bounds the length of a tag once normalized.
+const maxTagLen = 40
+
+// ErrInvalidTag is returned when a tag is empty or too long.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// NormalizeTag returns the canonical form of a tag: lower case, without a leading "#"
+// and with inner spaces turned into dashes, so "Needs Review" and "#needs-review" are the same tag.
+func NormalizeTag(s string) (string, error) {
+	tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+	tag = strings.Join(strings.Fields(tag), "-")
+	if tag == "" || len(tag) > maxTagLen || strings.Contains(tag, ",") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTag, s)
+	}
+	return tag, nil
+}
+
+// NormalizeTags normalizes the tags and drops duplicates, keeping the first position of each one.
+func NormalizeTags(tags []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		tag, err := NormalizeTag(t)
+		if err != nil {
+			return nil, err
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+// ParseTags reads a comma separated list of tags, empty entries are ignored.
+func ParseTags(s string) ([]string, error) {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		if strings.TrimSpace(t) != "" {
+			tags = append(tags, t)
+		}
+	}
+	return NormalizeTags(tags)
+}
+
+// HasTag reports whether the item is tagged with the given normalized tag.
+func (i Item) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// collectTags returns the distinct tags of the items in alphabetical order.
+func collectTags(items []Item) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, item := range items {
+		for _, t := range item.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
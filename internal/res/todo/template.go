@@ -0,0 +1,96 @@
+package todo
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// ErrNotTemplate is returned when a list is created from a list that is not a template.
+var ErrNotTemplate = errors.New("list is not a template")
+
+// Template placeholders, written as {{date}} and {{project}} in the names, descriptions, titles and notes of a template.
+const (
+	PlaceholderDate    = "date"
+	PlaceholderProject = "project"
+)
+
+// Placeholders are the placeholders filled when a list is created from a template.
+var Placeholders = []string{PlaceholderDate, PlaceholderProject}
+
+var placeholderRe = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// TemplateValues are what a list created from a template is named and filled with.
+// Name is the name of the new list, the name of the template when empty; Date is the day it starts on, today when zero.
+// Placeholders without a value, like the project when none is given, are kept as they are to be filled by hand.
+type TemplateValues struct {
+	Name    string    `json:"name"`
+	Project string    `json:"project"`
+	Date    time.Time `json:"date"`
+}
+
+// Fill replaces the placeholders of the text with their values.
+func (v TemplateValues) Fill(s string) string {
+	values := map[string]string{
+		PlaceholderDate:    v.Date.Format(time.DateOnly),
+		PlaceholderProject: v.Project,
+	}
+	return placeholderRe.ReplaceAllStringFunc(s, func(match string) string {
+		value := values[placeholderRe.FindStringSubmatch(match)[1]]
+		if value == "" {
+			return match
+		}
+		return value
+	})
+}
+
+// shiftDays returns the number of days between the day the template was made and the date of the new list.
+// The dates of the items keep their distance to the day the template was made.
+func (v TemplateValues) shiftDays(template List) int {
+	from := template.CreatedAt().UTC().Truncate(24 * time.Hour)
+	to := v.Date.UTC().Truncate(24 * time.Hour)
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// copyItems returns new items of the list with the content of the given ones, linked to each other as they are.
+// Progress is not copied: the copies start as todo, unassigned and not reminded. edit, when given, changes each copy.
+func copyItems(listID uuid.UUID, items []Item, edit func(item *Item)) []Item {
+	ids := make(map[uuid.UUID]uuid.UUID, len(items))
+	copies := make([]Item, len(items))
+	for i, item := range items {
+		item.BaseModel = am.NewModel(am.WithType(itemType))
+		item.GenID()
+		ids[items[i].ID()] = item.ID()
+		copies[i] = item
+	}
+
+	for i := range copies {
+		item := &copies[i]
+		item.ListID = listID
+		item.ParentID = ids[item.ParentID]
+		var blockedBy []uuid.UUID
+		for _, id := range item.BlockedBy {
+			if copied, ok := ids[id]; ok {
+				blockedBy = append(blockedBy, copied)
+			}
+		}
+		item.BlockedBy = blockedBy
+		item.Tags = slices.Clone(item.Tags)
+		item.Reminders = slices.Clone(item.Reminders)
+		item.Status = StatusTodo
+		item.Done = false
+		item.CompletedAt = time.Time{}
+		item.RemindedAt = time.Time{}
+		item.SeriesID = uuid.Nil
+		item.Occurrence = 0
+		item.Assignees = nil
+		if edit != nil {
+			edit(item)
+		}
+	}
+	return copies
+}
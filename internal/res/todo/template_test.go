@@ -0,0 +1,87 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+func TestTemplateValuesFill(t *testing.T) {
+	values := TemplateValues{Project: "Acme", Date: time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Launch {{project}}", "Launch Acme"},
+		{"Kickoff on {{ date }}", "Kickoff on 2025-03-14"},
+		{"{{project}} {{project}}", "Acme Acme"},
+		{"Ask {{owner}}", "Ask {{owner}}"},
+		{"No placeholders", "No placeholders"},
+	}
+	for _, tt := range tests {
+		if got := values.Fill(tt.in); got != tt.want {
+			t.Errorf("Fill(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	values.Project = ""
+	if got := values.Fill("Launch {{project}}"); got != "Launch {{project}}" {
+		t.Errorf("expected the placeholder without a value to be kept, got %q", got)
+	}
+}
+
+func TestTemplateValuesShiftDays(t *testing.T) {
+	template := NewList("Release", "")
+	template.BaseModel = am.NewModel(am.WithCreatedAt(time.Date(2025, 3, 1, 23, 30, 0, 0, time.UTC)))
+
+	values := TemplateValues{Date: time.Date(2025, 3, 11, 0, 15, 0, 0, time.UTC)}
+	if days := values.shiftDays(template); days != 10 {
+		t.Errorf("expected a shift of 10 days, got %d", days)
+	}
+}
+
+func TestCopyItems(t *testing.T) {
+	listID := uuid.New()
+	parent := NewItem(uuid.New(), "Plan", "")
+	parent.GenID()
+	parent.Status = StatusDone
+	parent.Done = true
+	parent.CompletedAt = time.Now()
+	parent.Assignees = []Assignee{{Type: GranteeUser, ID: uuid.New()}}
+	child := NewItem(parent.ListID, "Ship", "")
+	child.GenID()
+	child.ParentID = parent.ID()
+	child.BlockedBy = []uuid.UUID{parent.ID(), uuid.New()}
+	child.Tags = []string{"release"}
+
+	copies := copyItems(listID, []Item{parent, child}, func(item *Item) {
+		item.Title += "!"
+	})
+
+	if len(copies) != 2 {
+		t.Fatalf("expected 2 copies, got %d", len(copies))
+	}
+	p, c := copies[0], copies[1]
+	if p.ID() == parent.ID() || c.ID() == child.ID() || p.ID() == uuid.Nil {
+		t.Errorf("expected the copies to get new IDs")
+	}
+	if p.ListID != listID || c.ListID != listID {
+		t.Errorf("expected the copies to belong to the new list")
+	}
+	if c.ParentID != p.ID() || len(c.BlockedBy) != 1 || c.BlockedBy[0] != p.ID() {
+		t.Errorf("expected the links to point to the copies, got parent %s and blockers %v", c.ParentID, c.BlockedBy)
+	}
+	if p.Done || p.Status != StatusTodo || !p.CompletedAt.IsZero() || len(p.Assignees) != 0 {
+		t.Errorf("expected the progress not to be copied, got %+v", p)
+	}
+	if p.Title != "Plan!" || c.Title != "Ship!" {
+		t.Errorf("expected the copies to be edited, got %q and %q", p.Title, c.Title)
+	}
+
+	c.Tags[0] = "changed"
+	if child.Tags[0] != "release" {
+		t.Errorf("expected the tags of the copy not to be shared with the original")
+	}
+}
@@ -0,0 +1,272 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// timesheetPath is the action of the weekly timesheet view.
+const timesheetPath = "timesheet"
+
+// maxTimeEntry is the longest time a manual entry can add.
+const maxTimeEntry = 24 * time.Hour
+
+var (
+	// ErrTimeEntryNotFound is returned when the list has no time entry with the given ID.
+	ErrTimeEntryNotFound = errors.New("time entry not found")
+	// ErrInvalidTimeEntry is returned when a manual time entry fails validation.
+	ErrInvalidTimeEntry = errors.New("invalid time entry")
+	// ErrTimerRunning is returned when a user starts a timer while another one of theirs is running.
+	ErrTimerRunning = errors.New("a timer is already running")
+	// ErrNoTimerRunning is returned when a user stops a timer and none of theirs is running.
+	ErrNoTimerRunning = errors.New("no timer is running")
+)
+
+// TimeEntry is time a user spent on an item, tracked with a timer or entered by hand.
+// A timer runs while EndedAt is zero and gets its Seconds when it is stopped, a user runs one timer at a time.
+// Manual entries start at the beginning of the day they are entered for.
+type TimeEntry struct {
+	ID        uuid.UUID `json:"id"`
+	ListID    uuid.UUID `json:"list_id"`
+	ItemID    uuid.UUID `json:"item_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Seconds   int64     `json:"seconds"`
+	Notes     string    `json:"notes"`
+	Manual    bool      `json:"manual"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTimer creates a running timer of the user on the item, started now.
+func NewTimer(listID, itemID, userID uuid.UUID, notes string) TimeEntry {
+	now := time.Now()
+	return TimeEntry{
+		ID:        uuid.New(),
+		ListID:    listID,
+		ItemID:    itemID,
+		UserID:    userID,
+		StartedAt: now,
+		Notes:     strings.TrimSpace(notes),
+		CreatedAt: now,
+	}
+}
+
+// NewManualEntry creates an entry of the time spent by the user on the item on the given day.
+func NewManualEntry(listID, itemID, userID uuid.UUID, day time.Time, spent time.Duration, notes string) TimeEntry {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return TimeEntry{
+		ID:        uuid.New(),
+		ListID:    listID,
+		ItemID:    itemID,
+		UserID:    userID,
+		StartedAt: day,
+		EndedAt:   day.Add(spent),
+		Seconds:   int64(spent / time.Second),
+		Notes:     strings.TrimSpace(notes),
+		Manual:    true,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsRunning reports whether the entry is a timer that has not been stopped.
+func (e TimeEntry) IsRunning() bool {
+	return e.EndedAt.IsZero()
+}
+
+// Stop ends the timer at the given time.
+func (e *TimeEntry) Stop(at time.Time) {
+	e.EndedAt = at
+	e.Seconds = max(int64(at.Sub(e.StartedAt).Round(time.Second)/time.Second), 0)
+}
+
+// Spent returns the time of the entry, up to now for a running timer.
+func (e TimeEntry) Spent() Spent {
+	if e.IsRunning() {
+		return Spent(time.Since(e.StartedAt).Truncate(time.Second))
+	}
+	return Spent(time.Duration(e.Seconds) * time.Second)
+}
+
+// Validate checks a manual entry.
+func (e TimeEntry) Validate() error {
+	if e.Seconds < 60 {
+		return fmt.Errorf("%w: at least a minute is required", ErrInvalidTimeEntry)
+	}
+	if time.Duration(e.Seconds)*time.Second > maxTimeEntry {
+		return fmt.Errorf("%w: more than %s", ErrInvalidTimeEntry, Spent(maxTimeEntry))
+	}
+	if e.StartedAt.IsZero() {
+		return fmt.Errorf("%w: date is required", ErrInvalidTimeEntry)
+	}
+	return nil
+}
+
+// Spent is an amount of tracked time, shown as hours and minutes.
+type Spent time.Duration
+
+// ParseSpent reads an amount of time written as "1:30", as hours like "1.5" or as a duration like "1h30m".
+func ParseSpent(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if h, m, ok := strings.Cut(s, ":"); ok {
+		hours, err := strconv.Atoi(h)
+		if err == nil && hours >= 0 {
+			minutes, err := strconv.Atoi(m)
+			if err == nil && minutes >= 0 && minutes < 60 && len(m) == 2 {
+				return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+			}
+		}
+	} else if hours, err := strconv.ParseFloat(s, 64); err == nil && hours >= 0 {
+		return time.Duration(hours * float64(time.Hour)).Round(time.Minute), nil
+	} else if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("%w: cannot read %q as time spent", ErrInvalidTimeEntry, s)
+}
+
+// String returns the time as hours and minutes, such as "1:05".
+func (s Spent) String() string {
+	minutes := int64(time.Duration(s) / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
+
+// MarshalText writes the time as its String form, so JSON shows it as hours and minutes.
+func (s Spent) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Hours returns the time in hours rounded to two decimals, as bills use it.
+func (s Spent) Hours() string {
+	return strconv.FormatFloat(time.Duration(s).Hours(), 'f', 2, 64)
+}
+
+// Time filter query params.
+const (
+	timeFilterUser = "user"
+	timeFilterTeam = "team"
+	timeFilterFrom = "from"
+	timeFilterTo   = "to"
+)
+
+// TimeFilter selects time entries by user, by the members of a team and by the day they started on.
+// From and To are days, both included; zero values match every entry.
+type TimeFilter struct {
+	UserID uuid.UUID `json:"user_id"`
+	TeamID uuid.UUID `json:"team_id"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// ParseTimeFilter reads a filter from query params such as "user=<id>&from=2025-03-01&to=2025-03-31",
+// the days are read in the given location.
+func ParseTimeFilter(values url.Values, loc *time.Location) (TimeFilter, error) {
+	var filter TimeFilter
+	var err error
+	if s := values.Get(timeFilterUser); s != "" {
+		if filter.UserID, err = uuid.Parse(s); err != nil {
+			return TimeFilter{}, fmt.Errorf("%w: invalid user", ErrInvalidTimeEntry)
+		}
+	}
+	if s := values.Get(timeFilterTeam); s != "" {
+		if filter.TeamID, err = uuid.Parse(s); err != nil {
+			return TimeFilter{}, fmt.Errorf("%w: invalid team", ErrInvalidTimeEntry)
+		}
+	}
+	if s := values.Get(timeFilterFrom); s != "" {
+		if filter.From, err = time.ParseInLocation(time.DateOnly, s, loc); err != nil {
+			return TimeFilter{}, fmt.Errorf("%w: invalid from date", ErrInvalidTimeEntry)
+		}
+	}
+	if s := values.Get(timeFilterTo); s != "" {
+		if filter.To, err = time.ParseInLocation(time.DateOnly, s, loc); err != nil {
+			return TimeFilter{}, fmt.Errorf("%w: invalid to date", ErrInvalidTimeEntry)
+		}
+	}
+	return filter, nil
+}
+
+// Match reports whether the entry is of the user and in the days of the filter, the team is resolved by the caller.
+func (f TimeFilter) Match(e TimeEntry) bool {
+	if f.UserID != uuid.Nil && e.UserID != f.UserID {
+		return false
+	}
+	if !f.From.IsZero() && e.StartedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.StartedAt.Before(f.To.AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
+// TimeRecord is a time entry along with the names of its list, item and user, as reports show it.
+type TimeRecord struct {
+	TimeEntry
+	List string `json:"list"`
+	Item string `json:"item"`
+	User string `json:"user"`
+}
+
+// Timesheet is the time a user tracked in a week, by item and by day. Days start on Monday.
+type Timesheet struct {
+	Start  time.Time      `json:"start"`
+	Days   []time.Time    `json:"days"`
+	Rows   []TimesheetRow `json:"rows"`
+	Totals []Spent        `json:"totals"`
+	Total  Spent          `json:"total"`
+}
+
+// TimesheetRow is the time tracked on an item in the days of a timesheet.
+type TimesheetRow struct {
+	ListID uuid.UUID `json:"list_id"`
+	ItemID uuid.UUID `json:"item_id"`
+	List   string    `json:"list"`
+	Item   string    `json:"item"`
+	Days   []Spent   `json:"days"`
+	Total  Spent     `json:"total"`
+}
+
+// WeekStart returns the beginning of the Monday of the week of t in its location.
+func WeekStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// NewTimesheet lays out the records in the week starting at start, running timers count up to now.
+// Rows keep the order the items first appear in.
+func NewTimesheet(start time.Time, records []TimeRecord) Timesheet {
+	sheet := Timesheet{Start: start, Totals: make([]Spent, 7)}
+	for i := range 7 {
+		sheet.Days = append(sheet.Days, start.AddDate(0, 0, i))
+	}
+	end := start.AddDate(0, 0, 7)
+
+	rows := make(map[uuid.UUID]int)
+	for _, r := range records {
+		if r.StartedAt.Before(start) || !r.StartedAt.Before(end) {
+			continue
+		}
+		i, ok := rows[r.ItemID]
+		if !ok {
+			i = len(sheet.Rows)
+			rows[r.ItemID] = i
+			sheet.Rows = append(sheet.Rows, TimesheetRow{ListID: r.ListID, ItemID: r.ItemID, List: r.List, Item: r.Item, Days: make([]Spent, 7)})
+		}
+		day := 6
+		for day > 0 && r.StartedAt.Before(sheet.Days[day]) {
+			day--
+		}
+		spent := r.Spent()
+		sheet.Rows[i].Days[day] += spent
+		sheet.Rows[i].Total += spent
+		sheet.Totals[day] += spent
+		sheet.Total += spent
+	}
+	return sheet
+}
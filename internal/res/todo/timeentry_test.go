@@ -0,0 +1,202 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseSpent(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"1:30", 90 * time.Minute, false},
+		{"0:05", 5 * time.Minute, false},
+		{"1.5", 90 * time.Minute, false},
+		{"2", 2 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{" 45m ", 45 * time.Minute, false},
+		{"1:5", 0, true},
+		{"1:75", 0, true},
+		{"-1", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpent(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSpent(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSpent(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSpentFormat(t *testing.T) {
+	s := Spent(2*time.Hour + 5*time.Minute + 40*time.Second)
+	if got := s.String(); got != "2:05" {
+		t.Errorf("String() = %q, want %q", got, "2:05")
+	}
+	if got := s.Hours(); got != "2.09" {
+		t.Errorf("Hours() = %q, want %q", got, "2.09")
+	}
+}
+
+func TestTimeEntryValidate(t *testing.T) {
+	day := time.Date(2025, 3, 12, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		spent   time.Duration
+		wantErr bool
+	}{
+		{30 * time.Second, true},
+		{time.Minute, false},
+		{8 * time.Hour, false},
+		{25 * time.Hour, true},
+	}
+	for _, tt := range tests {
+		entry := NewManualEntry(uuid.New(), uuid.New(), uuid.New(), day, tt.spent, "")
+		err := entry.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate() of %s error = %v, wantErr %v", tt.spent, err, tt.wantErr)
+		}
+	}
+
+	entry := NewManualEntry(uuid.New(), uuid.New(), uuid.New(), day, time.Hour, "")
+	if !entry.StartedAt.Equal(time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)) || entry.IsRunning() {
+		t.Errorf("expected a stopped entry at the start of the day, got %+v", entry)
+	}
+}
+
+func TestTimerStop(t *testing.T) {
+	timer := NewTimer(uuid.New(), uuid.New(), uuid.New(), " review ")
+	if !timer.IsRunning() || timer.Notes != "review" {
+		t.Fatalf("expected a running timer with trimmed notes, got %+v", timer)
+	}
+	timer.Stop(timer.StartedAt.Add(25 * time.Minute))
+	if timer.IsRunning() || timer.Spent() != Spent(25*time.Minute) {
+		t.Errorf("expected 25 minutes spent, got %s", timer.Spent())
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	loc := time.FixedZone("test", -3*3600)
+	tests := []struct {
+		in   time.Time
+		want time.Time
+	}{
+		{time.Date(2025, 3, 12, 18, 0, 0, 0, loc), time.Date(2025, 3, 10, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 3, 10, 0, 0, 0, 0, loc), time.Date(2025, 3, 10, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 3, 16, 23, 59, 0, 0, loc), time.Date(2025, 3, 10, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := WeekStart(tt.in); !got.Equal(tt.want) {
+			t.Errorf("WeekStart(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewTimesheet(t *testing.T) {
+	start := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	listID, first, second := uuid.New(), uuid.New(), uuid.New()
+	record := func(itemID uuid.UUID, day time.Time, spent time.Duration) TimeRecord {
+		return TimeRecord{TimeEntry: NewManualEntry(listID, itemID, uuid.New(), day, spent, ""), Item: itemID.String()}
+	}
+	records := []TimeRecord{
+		record(first, start, time.Hour),
+		record(second, start.AddDate(0, 0, 2), 30*time.Minute),
+		record(first, start.AddDate(0, 0, 6), 2*time.Hour),
+		record(first, start.AddDate(0, 0, 7), time.Hour),
+		record(first, start.AddDate(0, 0, -1), time.Hour),
+	}
+
+	sheet := NewTimesheet(start, records)
+
+	if len(sheet.Days) != 7 || len(sheet.Rows) != 2 {
+		t.Fatalf("expected 7 days and 2 rows, got %d and %d", len(sheet.Days), len(sheet.Rows))
+	}
+	if sheet.Rows[0].ItemID != first || sheet.Rows[1].ItemID != second {
+		t.Errorf("expected the rows in the order the items first appear")
+	}
+	if sheet.Rows[0].Days[0] != Spent(time.Hour) || sheet.Rows[0].Days[6] != Spent(2*time.Hour) || sheet.Rows[0].Total != Spent(3*time.Hour) {
+		t.Errorf("unexpected days of the first row: %v", sheet.Rows[0].Days)
+	}
+	if sheet.Totals[2] != Spent(30*time.Minute) || sheet.Total != Spent(3*time.Hour+30*time.Minute) {
+		t.Errorf("unexpected totals: %v, %s", sheet.Totals, sheet.Total)
+	}
+}
+
+func TestTimeFilterMatch(t *testing.T) {
+	userID := uuid.New()
+	values := url.Values{}
+	values.Set(timeFilterUser, userID.String())
+	values.Set(timeFilterFrom, "2025-03-10")
+	values.Set(timeFilterTo, "2025-03-16")
+	filter, err := ParseTimeFilter(values, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entry := func(user uuid.UUID, day time.Time) TimeEntry {
+		return NewManualEntry(uuid.New(), uuid.New(), user, day, time.Hour, "")
+	}
+	tests := []struct {
+		name  string
+		entry TimeEntry
+		want  bool
+	}{
+		{"first day", entry(userID, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)), true},
+		{"last day", entry(userID, time.Date(2025, 3, 16, 23, 0, 0, 0, time.UTC)), true},
+		{"before", entry(userID, time.Date(2025, 3, 9, 23, 0, 0, 0, time.UTC)), false},
+		{"after", entry(userID, time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)), false},
+		{"other user", entry(uuid.New(), time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)), false},
+	}
+	for _, tt := range tests {
+		if got := filter.Match(tt.entry); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	values.Set(timeFilterTo, "next week")
+	if _, err := ParseTimeFilter(values, time.UTC); !errors.Is(err, ErrInvalidTimeEntry) {
+		t.Errorf("expected ErrInvalidTimeEntry, got %v", err)
+	}
+}
+
+func TestRepoOneRunningTimer(t *testing.T) {
+	repo := NewRepo(nil)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	timer := NewTimer(uuid.New(), uuid.New(), userID, "")
+	if err := repo.CreateTimeEntry(ctx, timer); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.CreateTimeEntry(ctx, NewTimer(uuid.New(), uuid.New(), userID, "")); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("expected ErrTimerRunning, got %v", err)
+	}
+	if err := repo.CreateTimeEntry(ctx, NewTimer(uuid.New(), uuid.New(), uuid.New(), "")); err != nil {
+		t.Errorf("expected another user to start a timer, got %v", err)
+	}
+
+	running, err := repo.GetRunningTimer(ctx, userID)
+	if err != nil || running.ID != timer.ID {
+		t.Fatalf("expected the running timer, got %v", err)
+	}
+	running.Stop(time.Now())
+	if err := repo.UpdateTimeEntry(ctx, running); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.GetRunningTimer(ctx, userID); !errors.Is(err, ErrNoTimerRunning) {
+		t.Errorf("expected ErrNoTimerRunning, got %v", err)
+	}
+	if err := repo.CreateTimeEntry(ctx, NewTimer(uuid.New(), uuid.New(), userID, "")); err != nil {
+		t.Errorf("expected a new timer once the first one stopped, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// TimeFormats are the formats time entries are exported in.
+var TimeFormats = []string{FormatCSV, FormatJSON}
+
+var timeCSVColumns = []string{"date", "user", "list", "item", "started_at", "ended_at", "duration", "hours", "manual", "notes"}
+
+// EncodeTimeRecords writes the records in the format, times in the given location.
+func EncodeTimeRecords(w io.Writer, f string, records []TimeRecord, loc *time.Location) error {
+	switch f {
+	case FormatCSV:
+		return encodeTimeCSV(w, records, loc)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if records == nil {
+			records = []TimeRecord{}
+		}
+		return enc.Encode(records)
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidFormat, f)
+}
+
+// TimeExportFile returns the records written in the format along with the content type and the file name to download them as.
+func TimeExportFile(f string, records []TimeRecord, loc *time.Location) (content []byte, contentType, filename string, err error) {
+	var buf bytes.Buffer
+	err = EncodeTimeRecords(&buf, f, records, loc)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), formats[f].contentType, "time." + formats[f].ext, nil
+}
+
+func encodeTimeCSV(w io.Writer, records []TimeRecord, loc *time.Location) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write(timeCSVColumns)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		spent := r.Spent()
+		err = cw.Write([]string{
+			r.StartedAt.In(loc).Format(time.DateOnly),
+			r.User,
+			r.List,
+			r.Item,
+			csvTime(r.StartedAt, loc),
+			csvTime(r.EndedAt, loc),
+			spent.String(),
+			spent.Hours(),
+			strconv.FormatBool(r.Manual),
+			r.Notes,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,392 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/google/uuid"
+)
+
+// Import and export formats.
+const (
+	FormatTodoTxt  = "todotxt"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the import and export formats in the order they are offered.
+var Formats = []string{FormatTodoTxt, FormatCSV, FormatJSON, FormatMarkdown}
+
+const (
+	// maxImportSize bounds the size of an import file.
+	maxImportSize = 5 << 20
+	// defaultImportList names the list of the imported items that do not say which list they belong to.
+	defaultImportList = "Imported"
+)
+
+var (
+	// ErrInvalidFormat is returned for an import or export format that is not one of Formats.
+	ErrInvalidFormat = errors.New("unknown import/export format")
+	// ErrInvalidImport is returned when an import is not a dry run and its report has problems, nothing is imported then.
+	ErrInvalidImport = errors.New("import file has problems")
+)
+
+// format writes and reads lists in a file format, decode reports the problems of the file instead of stopping at the first one.
+// Dates without a time zone are read in the given location.
+type format struct {
+	label       string
+	ext         string
+	contentType string
+	encode      func(w io.Writer, lists []TransferList) error
+	decode      func(r io.Reader, loc *time.Location) ([]TransferList, []ImportProblem)
+}
+
+var formats = map[string]format{
+	FormatTodoTxt:  {"todo.txt", "txt", "text/plain; charset=utf-8", encodeTodoTxt, decodeTodoTxt},
+	FormatCSV:      {"CSV", "csv", "text/csv; charset=utf-8", encodeCSV, decodeCSV},
+	FormatJSON:     {"JSON", "json", "application/json", encodeJSON, decodeJSON},
+	FormatMarkdown: {"Markdown", "md", "text/markdown; charset=utf-8", encodeMarkdown, decodeMarkdown},
+}
+
+// ParseFormat returns the format with the given name, "todo.txt" and "md" are accepted as well.
+func ParseFormat(s string) (string, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "todo.txt", "txt":
+		return FormatTodoTxt, nil
+	case "md":
+		return FormatMarkdown, nil
+	}
+	if _, ok := formats[s]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidFormat, s)
+	}
+	return s, nil
+}
+
+// FormatOf returns the format of a file by its extension, empty when it is not known.
+func FormatOf(filename string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".txt":
+		return FormatTodoTxt
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".md", ".markdown":
+		return FormatMarkdown
+	}
+	return ""
+}
+
+// FormatLabel returns the name of the format as shown to users.
+func FormatLabel(f string) string {
+	return formats[f].label
+}
+
+// EncodeLists writes the lists in the format.
+func EncodeLists(w io.Writer, f string, lists []TransferList) error {
+	codec, ok := formats[f]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, f)
+	}
+	return codec.encode(w, lists)
+}
+
+// ExportFile returns the lists written in the format along with the content type and the file name to download them as.
+func ExportFile(f string, lists []TransferList) (content []byte, contentType, filename string, err error) {
+	var buf bytes.Buffer
+	err = EncodeLists(&buf, f, lists)
+	if err != nil {
+		return nil, "", "", err
+	}
+	name := "todo"
+	if len(lists) == 1 {
+		name = exportName(lists[0].Name)
+	}
+	return buf.Bytes(), formats[f].contentType, name + "." + formats[f].ext, nil
+}
+
+// exportName turns a list name into a file name, keeping letters, digits and dashes only.
+func exportName(s string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, strings.TrimSpace(s))
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "todo"
+	}
+	return name
+}
+
+// TransferList is a list along with its items as written to or read from an import/export file.
+type TransferList struct {
+	Name        string
+	Description string
+	Items       []TransferItem
+}
+
+// TransferItem is an item of a TransferList.
+// Items refer to each other through refs, the item IDs on export and whatever the file uses on import.
+// Line is the line of the file the item was read from, Depth is its nesting on export.
+type TransferItem struct {
+	Item
+	Depth         int
+	Line          int
+	Ref           string
+	ParentRef     string
+	BlockedByRefs []string
+}
+
+// NewTransferList prepares a list for export, subtasks follow their parents.
+func NewTransferList(list List, items []Item) TransferList {
+	tl := TransferList{Name: list.Name, Description: list.Description}
+	for _, node := range BuildItemTree(items) {
+		ti := TransferItem{Item: node.Item, Depth: node.Depth, Ref: node.ID().String()}
+		if node.HasParent() {
+			ti.ParentRef = node.ParentID.String()
+		}
+		for _, id := range node.BlockedBy {
+			ti.BlockedByRefs = append(ti.BlockedByRefs, id.String())
+		}
+		tl.Items = append(tl.Items, ti)
+	}
+	return tl
+}
+
+// ImportOptions selects how a file is imported.
+// Without a list ID each list of the file becomes a new list, otherwise all the items go to that list.
+// TimeZone is the one dates without a zone are read in, UTC when empty.
+type ImportOptions struct {
+	Format   string
+	ListID   uuid.UUID
+	TimeZone string
+	DryRun   bool
+}
+
+// ImportReport tells what an import creates, or would create on a dry run, and the problems found in the file.
+type ImportReport struct {
+	Format   string          `json:"format"`
+	DryRun   bool            `json:"dry_run"`
+	Lists    []ImportedList  `json:"lists"`
+	Problems []ImportProblem `json:"problems"`
+}
+
+// Valid reports whether the file can be imported.
+func (r ImportReport) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// ItemCount returns the number of items of the import.
+func (r ImportReport) ItemCount() int {
+	n := 0
+	for _, l := range r.Lists {
+		n += len(l.Items)
+	}
+	return n
+}
+
+// ImportedList is a list of an import, New is set when the import creates it.
+type ImportedList struct {
+	ListID      uuid.UUID `json:"list_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	New         bool      `json:"new"`
+	Items       []Item    `json:"items"`
+}
+
+// Nodes returns the items of the list as a tree, as the preview shows them.
+func (l ImportedList) Nodes() []ItemNode {
+	return BuildItemTree(l.Items)
+}
+
+// ImportProblem is something wrong in an import file, Line is zero when the format has no lines.
+type ImportProblem struct {
+	Line    int    `json:"line,omitempty"`
+	Item    string `json:"item,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p ImportProblem) String() string {
+	switch {
+	case p.Line > 0:
+		return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+	case p.Item != "":
+		return fmt.Sprintf("%q: %s", p.Item, p.Message)
+	}
+	return p.Message
+}
+
+func itemProblem(ti TransferItem, err error) ImportProblem {
+	return ImportProblem{Line: ti.Line, Item: ti.Title, Message: err.Error()}
+}
+
+// mergeTransfer puts the items of all the lists in the one given, as importing into an existing list does.
+func mergeTransfer(list List, lists []TransferList) []TransferList {
+	merged := TransferList{Name: list.Name, Description: list.Description}
+	for _, l := range lists {
+		merged.Items = append(merged.Items, l.Items...)
+	}
+	return []TransferList{merged}
+}
+
+// prepareImport gives the items of the lists new IDs, links them through their refs and validates them.
+// Refs are resolved within each list, items can only be linked to items of their own list.
+func prepareImport(lists []TransferList) ([]ImportedList, []ImportProblem) {
+	var imported []ImportedList
+	var problems []ImportProblem
+	count := 0
+
+	for _, tl := range lists {
+		il := ImportedList{Name: strings.TrimSpace(tl.Name), Description: tl.Description, New: true}
+		if il.Name == "" {
+			il.Name = defaultImportList
+		}
+
+		ids := make(map[string]uuid.UUID, len(tl.Items))
+		items := make([]Item, len(tl.Items))
+		for i, ti := range tl.Items {
+			item := ti.Item
+			item.BaseModel = am.NewModel(am.WithType(itemType))
+			item.GenID()
+			item.ParentID, item.BlockedBy = uuid.Nil, nil
+			items[i] = item
+			if ti.Ref == "" {
+				continue
+			}
+			if _, ok := ids[ti.Ref]; ok {
+				problems = append(problems, itemProblem(ti, fmt.Errorf("id %q is used twice", ti.Ref)))
+				continue
+			}
+			ids[ti.Ref] = item.ID()
+		}
+
+		for i, ti := range tl.Items {
+			if ti.ParentRef != "" {
+				id, ok := ids[ti.ParentRef]
+				if !ok {
+					problems = append(problems, itemProblem(ti, fmt.Errorf("%w: unknown parent %q", ErrInvalidParent, ti.ParentRef)))
+				}
+				items[i].ParentID = id
+			}
+			for _, ref := range ti.BlockedByRefs {
+				id, ok := ids[ref]
+				if !ok {
+					problems = append(problems, itemProblem(ti, fmt.Errorf("%w: unknown item %q", ErrInvalidDependency, ref)))
+					continue
+				}
+				items[i].BlockedBy = append(items[i].BlockedBy, id)
+			}
+		}
+
+		for i, ti := range tl.Items {
+			err := items[i].Validate()
+			if err == nil {
+				err = checkItemLinks(items[i], items)
+			}
+			if err != nil {
+				problems = append(problems, itemProblem(ti, err))
+			}
+		}
+
+		count += len(items)
+		il.Items = items
+		imported = append(imported, il)
+	}
+
+	if count == 0 {
+		problems = append(problems, ImportProblem{Message: "the file has no items"})
+	}
+	return imported, problems
+}
+
+// splitRefs reads a comma separated list of refs.
+func splitRefs(s string) []string {
+	var refs []string
+	for _, ref := range strings.Split(s, ",") {
+		if ref = strings.TrimSpace(ref); ref != "" {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// importOrder returns the items with their parents and dependencies ahead of them, creating an item requires its links to exist.
+func importOrder(items []Item) []Item {
+	byID := itemsByID(items)
+	visited := make(map[uuid.UUID]bool, len(items))
+	ordered := make([]Item, 0, len(items))
+
+	var visit func(item Item)
+	visit = func(item Item) {
+		if visited[item.ID()] {
+			return
+		}
+		visited[item.ID()] = true
+		if parent, ok := byID[item.ParentID]; ok {
+			visit(parent)
+		}
+		for _, id := range item.BlockedBy {
+			if blocker, ok := byID[id]; ok {
+				visit(blocker)
+			}
+		}
+		ordered = append(ordered, item)
+	}
+
+	for _, item := range items {
+		visit(item)
+	}
+	return ordered
+}
+
+// Layouts accepted for the dates of import files, the ones without a zone are read in the import time zone.
+var transferTimeLayouts = []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02"}
+
+// parseTransferTime reads an RFC 3339 instant or a date with an optional time.
+func parseTransferTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range transferTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid date %q", ErrInvalidItem, s)
+}
+
+// formatTransferDate writes a time in the location as a date, along with the time of day when it is not midnight.
+func formatTransferDate(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02T15:04")
+}
+
+// parseTransferPriority reads a priority as "P1" to "P4" or as the todo.txt letters "A" to "D".
+func parseTransferPriority(s string) (Priority, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) == 1 && s[0] >= 'A' && s[0] <= 'D' {
+		return Priority(s[0]-'A') + Priority1, nil
+	}
+	return ParsePriority(s)
+}
+
+// priorityLetter returns the todo.txt letter of a priority, "A" for P1.
+func priorityLetter(p Priority) string {
+	return string(rune('A' + int(p-Priority1)))
+}
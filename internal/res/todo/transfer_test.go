@@ -0,0 +1,114 @@
+package todo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTransferRoundTrip(t *testing.T) {
+	list := NewList("Release plan", "Things to ship")
+	list.GenID()
+
+	a := newTestItem(list.ID(), "Write notes")
+	a.Notes = "Cover the API"
+	a.Tags = []string{"docs"}
+	a.Priority = Priority2
+	a.TimeZone = "Europe/Madrid"
+	a.DueAt = time.Date(2025, 3, 10, 17, 30, 0, 0, time.UTC)
+	b := newTestItem(list.ID(), "Proofread")
+	b.ParentID = a.ID()
+	b.Status = StatusDone
+	b.Done = true
+	c := newTestItem(list.ID(), "Publish")
+	c.BlockedBy = []uuid.UUID{a.ID()}
+	c.Status = StatusInProgress
+
+	exported := []TransferList{NewTransferList(list, []Item{a, b, c})}
+
+	for _, f := range Formats {
+		var buf bytes.Buffer
+		if err := EncodeLists(&buf, f, exported); err != nil {
+			t.Fatalf("%s: encode: %v", f, err)
+		}
+		lists, problems := formats[f].decode(&buf, time.UTC)
+		imported, more := prepareImport(lists)
+		problems = append(problems, more...)
+		if len(problems) > 0 {
+			t.Fatalf("%s: unexpected problems %v", f, problems)
+		}
+		if len(imported) != 1 || len(imported[0].Items) != 3 {
+			t.Fatalf("%s: expected a list with 3 items, got %+v", f, imported)
+		}
+
+		got := map[string]Item{}
+		for _, item := range imported[0].Items {
+			got[item.Title] = item
+		}
+		na, nb, nc := got["Write notes"], got["Proofread"], got["Publish"]
+		switch {
+		case !na.HasTag("docs") || na.Priority != Priority2 || !na.DueAt.Equal(a.DueAt) || na.TimeZone != "Europe/Madrid":
+			t.Errorf("%s: attributes not kept: %+v", f, na)
+		case nb.ParentID != na.ID() || nb.Status != StatusDone:
+			t.Errorf("%s: subtask not kept: %+v", f, nb)
+		case len(nc.BlockedBy) != 1 || nc.BlockedBy[0] != na.ID() || nc.Status != StatusInProgress:
+			t.Errorf("%s: dependency not kept: %+v", f, nc)
+		}
+		if f != FormatTodoTxt && na.Notes != a.Notes {
+			t.Errorf("%s: expected notes %q, got %q", f, a.Notes, na.Notes)
+		}
+		if f == FormatMarkdown && imported[0].Name != list.Name {
+			t.Errorf("%s: expected list %q, got %q", f, list.Name, imported[0].Name)
+		}
+	}
+}
+
+func TestImportProblems(t *testing.T) {
+	input := strings.Join([]string{
+		"# Groceries",
+		"- [ ] Milk due:tomorrow",
+		"- [ ] #urgent",
+		"Stray text",
+		"- [x] Bread blocked:9",
+	}, "\n")
+
+	lists, problems := decodeMarkdown(strings.NewReader(input), time.UTC)
+	_, more := prepareImport(lists)
+	problems = append(problems, more...)
+
+	lines := map[int]bool{}
+	for _, p := range problems {
+		lines[p.Line] = true
+	}
+	for _, line := range []int{2, 3, 4, 5} {
+		if !lines[line] {
+			t.Errorf("expected a problem on line %d, got %v", line, problems)
+		}
+	}
+}
+
+func TestDecodeTodoTxt(t *testing.T) {
+	input := "x 2025-01-02 2025-01-01 Pay rent +Home @bills pri:B\n(A) Call mom +Home +Family due:2025-02-01\nLoose item\n"
+
+	lists, problems := decodeTodoTxt(strings.NewReader(input), time.UTC)
+	if len(problems) > 0 {
+		t.Fatalf("unexpected problems %v", problems)
+	}
+	if len(lists) != 2 || lists[0].Name != "Home" || lists[1].Name != "" {
+		t.Fatalf("expected lists Home and a default one, got %+v", lists)
+	}
+
+	rent, call := lists[0].Items[0], lists[0].Items[1]
+	if rent.Title != "Pay rent" || rent.Status != StatusDone || rent.Priority != Priority2 || !rent.HasTag("bills") {
+		t.Errorf("unexpected done item: %+v", rent)
+	}
+	if rent.CompletedAt.Format("2006-01-02") != "2025-01-02" {
+		t.Errorf("expected completion on 2025-01-02, got %v", rent.CompletedAt)
+	}
+	if call.Priority != Priority1 || !call.HasTag("family") || call.DueAt.Format("2006-01-02") != "2025-02-01" {
+		t.Errorf("unexpected open item: %+v", call)
+	}
+}
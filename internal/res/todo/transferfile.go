@@ -0,0 +1,260 @@
+package todo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jsonList is a list as exported to JSON, its items have the API shape.
+type jsonList struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Items       []Item `json:"items"`
+}
+
+// jsonImportList reads what jsonList writes.
+// Items are read as the API item payload along with their ID, which the links of the other items refer to, and their completion.
+type jsonImportList struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Items       []jsonImportItem `json:"items"`
+}
+
+type jsonImportItem struct {
+	ID uuid.UUID `json:"id"`
+	itemPayload
+	Done        bool      `json:"done"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+func encodeJSON(w io.Writer, lists []TransferList) error {
+	out := make([]jsonList, len(lists))
+	for i, tl := range lists {
+		out[i] = jsonList{Name: tl.Name, Description: tl.Description, Items: make([]Item, len(tl.Items))}
+		for j, ti := range tl.Items {
+			out[i].Items[j] = ti.Item
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// decodeJSON reads an array of lists, or a single list.
+func decodeJSON(r io.Reader, loc *time.Location) ([]TransferList, []ImportProblem) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, []ImportProblem{{Message: err.Error()}}
+	}
+
+	var in []jsonImportList
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		in = make([]jsonImportList, 1)
+		err = json.Unmarshal(data, &in[0])
+	} else {
+		err = json.Unmarshal(data, &in)
+	}
+	if err != nil {
+		return nil, []ImportProblem{{Message: err.Error()}}
+	}
+
+	var lists []TransferList
+	var problems []ImportProblem
+	for _, l := range in {
+		tl := TransferList{Name: l.Name, Description: l.Description}
+		for _, p := range l.Items {
+			if p.Status == "" && p.Done {
+				p.Status = StatusDone
+			}
+			zone := p.TimeZone
+			item, err := p.apply(NewItem(uuid.Nil, "", ""))
+			if err != nil {
+				problems = append(problems, ImportProblem{Item: p.Title, Message: err.Error()})
+				continue
+			}
+			if zone == "" {
+				item.TimeZone = loc.String()
+			}
+			item.CompletedAt = p.CompletedAt.UTC()
+
+			ti := TransferItem{Item: item}
+			if p.ID != uuid.Nil {
+				ti.Ref = p.ID.String()
+			}
+			if p.ParentID != uuid.Nil {
+				ti.ParentRef = p.ParentID.String()
+			}
+			for _, id := range p.BlockedBy {
+				ti.BlockedByRefs = append(ti.BlockedByRefs, id.String())
+			}
+			tl.Items = append(tl.Items, ti)
+		}
+		lists = append(lists, tl)
+	}
+	return lists, problems
+}
+
+// csvColumns are the columns of a CSV export, imports find them by name in the header and only require the title.
+// Times are written in the time zone of the item, tags and dependencies are comma separated.
+var csvColumns = []string{
+	"list", "id", "title", "notes", "status", "priority", "tags", "start_at", "due_at",
+	"time_zone", "rrule", "parent_id", "blocked_by", "completed_at",
+}
+
+func encodeCSV(w io.Writer, lists []TransferList) error {
+	cw := csv.NewWriter(w)
+	err := cw.Write(csvColumns)
+	if err != nil {
+		return err
+	}
+	for _, tl := range lists {
+		for _, ti := range tl.Items {
+			loc := ti.Location()
+			err = cw.Write([]string{
+				tl.Name,
+				ti.Ref,
+				ti.Title,
+				ti.Notes,
+				string(ti.Status),
+				ti.Priority.String(),
+				strings.Join(ti.Tags, ","),
+				csvTime(ti.StartAt, loc),
+				csvTime(ti.DueAt, loc),
+				ti.TimeZone,
+				ti.RRule,
+				ti.ParentRef,
+				strings.Join(ti.BlockedByRefs, ","),
+				csvTime(ti.CompletedAt, loc),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvTime(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// decodeCSV reads a header row and an item per row, items are grouped in lists by the list column.
+// A done column with "x", "yes", "true" or "1" is taken for spreadsheets that have no status.
+func decodeCSV(r io.Reader, loc *time.Location) ([]TransferList, []ImportProblem) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, []ImportProblem{{Line: 1, Message: "a header row is required"}}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, []ImportProblem{{Line: 1, Message: "the title column is required"}}
+	}
+
+	var lists []TransferList
+	index := map[string]int{}
+	var problems []ImportProblem
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			problems = append(problems, ImportProblem{Line: line, Message: err.Error()})
+			continue
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		ti, err := csvItem(field, loc)
+		ti.Line = line
+		if err != nil {
+			problems = append(problems, itemProblem(ti, err))
+			continue
+		}
+
+		name := field("list")
+		i, ok := index[name]
+		if !ok {
+			i = len(lists)
+			index[name] = i
+			lists = append(lists, TransferList{Name: name})
+		}
+		lists[i].Items = append(lists[i].Items, ti)
+	}
+	return lists, problems
+}
+
+func csvItem(field func(string) string, loc *time.Location) (TransferItem, error) {
+	item := NewItem(uuid.Nil, field("title"), field("notes"))
+	ti := TransferItem{Item: item, Ref: field("id"), ParentRef: field("parent_id")}
+
+	if zone := field("time_zone"); zone != "" {
+		l, err := time.LoadLocation(zone)
+		if err != nil {
+			return ti, fmt.Errorf("%w: %s", ErrInvalidTimeZone, zone)
+		}
+		loc = l
+	}
+	ti.TimeZone = loc.String()
+
+	var err error
+	ti.Status, err = ParseStatus(field("status"))
+	if err != nil {
+		return ti, err
+	}
+	if field("status") == "" {
+		switch strings.ToLower(field("done")) {
+		case "x", "yes", "true", "1":
+			ti.Status = StatusDone
+		}
+	}
+	ti.Priority, err = parseTransferPriority(field("priority"))
+	if err != nil {
+		return ti, err
+	}
+	ti.Tags, err = ParseTags(field("tags"))
+	if err != nil {
+		return ti, err
+	}
+	times := []struct {
+		column string
+		t      *time.Time
+	}{{"start_at", &ti.StartAt}, {"due_at", &ti.DueAt}, {"completed_at", &ti.CompletedAt}}
+	for _, c := range times {
+		if v := field(c.column); v != "" {
+			*c.t, err = parseTransferTime(v, loc)
+			if err != nil {
+				return ti, err
+			}
+		}
+	}
+	ti.RRule = field("rrule")
+	ti.BlockedByRefs = splitRefs(field("blocked_by"))
+	return ti, nil
+}
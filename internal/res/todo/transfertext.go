@@ -0,0 +1,367 @@
+package todo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// todo.txt and Markdown items are a line of words.
+// Attributes are key:value words: due, t (start), tz, rrule, status, id, parent, blocked (comma separated ids) and pri.
+// Tags are words with a mark, "@" in todo.txt and "#" in Markdown, and in todo.txt a "+project" word names the list.
+// Ids are only written for the items others refer to, numbered from 1 on each export.
+const (
+	todoTxtTag  = "@"
+	markdownTag = "#"
+)
+
+var (
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	todoTxtPriority = regexp.MustCompile(`^\(([A-D])\)$`)
+	markdownItem    = regexp.MustCompile(`^(\s*)[-*+] \[([ xX])\] ?(.*)$`)
+	markdownList    = regexp.MustCompile(`^# +(.+)$`)
+)
+
+// textRefs numbers the items other items refer to, through dependencies and, when parents is set, as parents.
+func textRefs(lists []TransferList, parents bool) map[string]string {
+	refs := map[string]string{}
+	add := func(ref string) {
+		if _, ok := refs[ref]; !ok {
+			refs[ref] = strconv.Itoa(len(refs) + 1)
+		}
+	}
+	for _, tl := range lists {
+		for _, ti := range tl.Items {
+			if parents && ti.ParentRef != "" {
+				add(ti.ParentRef)
+			}
+			for _, ref := range ti.BlockedByRefs {
+				add(ref)
+			}
+		}
+	}
+	return refs
+}
+
+// itemWords returns the attribute and tag words of an item.
+func itemWords(ti TransferItem, tag string, refs map[string]string, parent bool) []string {
+	var words []string
+	for _, t := range ti.Tags {
+		words = append(words, tag+t)
+	}
+	loc := ti.Location()
+	if ti.HasStart() {
+		words = append(words, "t:"+formatTransferDate(ti.StartAt, loc))
+	}
+	if ti.HasDue() {
+		words = append(words, "due:"+formatTransferDate(ti.DueAt, loc))
+	}
+	if (ti.HasStart() || ti.HasDue()) && ti.TimeZone != "" && ti.TimeZone != "UTC" {
+		words = append(words, "tz:"+ti.TimeZone)
+	}
+	if ti.RRule != "" {
+		words = append(words, "rrule:"+ti.RRule)
+	}
+	if ti.Status == StatusInProgress || ti.Status == StatusWaiting {
+		words = append(words, "status:"+string(ti.Status))
+	}
+	if id, ok := refs[ti.Ref]; ok {
+		words = append(words, "id:"+id)
+	}
+	if parent && ti.ParentRef != "" {
+		words = append(words, "parent:"+refs[ti.ParentRef])
+	}
+	if len(ti.BlockedByRefs) > 0 {
+		blocked := make([]string, len(ti.BlockedByRefs))
+		for i, ref := range ti.BlockedByRefs {
+			blocked[i] = refs[ref]
+		}
+		words = append(words, "blocked:"+strings.Join(blocked, ","))
+	}
+	return words
+}
+
+// parseItemWords sets the attributes and tags given as words on the item, the other words make its title.
+// In todo.txt the "+project" words are returned apart.
+func parseItemWords(ti *TransferItem, words []string, tag string, loc *time.Location) (projects []string, err error) {
+	attrs := map[string]string{}
+	var title, tags []string
+	for _, w := range words {
+		key, value, ok := strings.Cut(w, ":")
+		switch {
+		case len(w) > 1 && strings.HasPrefix(w, tag):
+			tags = append(tags, w[1:])
+		case tag == todoTxtTag && len(w) > 1 && strings.HasPrefix(w, "+"):
+			projects = append(projects, w[1:])
+		case ok && value != "" && isTextAttr(key):
+			attrs[key] = value
+		default:
+			title = append(title, w)
+		}
+	}
+	ti.Title = strings.Join(title, " ")
+
+	if zone, ok := attrs["tz"]; ok {
+		loc, err = time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTimeZone, zone)
+		}
+	}
+	ti.TimeZone = loc.String()
+
+	ti.Tags, err = NormalizeTags(append(ti.Tags, tags...))
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := attrs["t"]; ok {
+		ti.StartAt, err = parseTransferTime(v, loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := attrs["due"]; ok {
+		ti.DueAt, err = parseTransferTime(v, loc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := attrs["status"]; ok && ti.Status != StatusDone {
+		ti.Status, err = ParseStatus(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v, ok := attrs["pri"]; ok {
+		ti.Priority, err = parseTransferPriority(v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ti.RRule = attrs["rrule"]
+	ti.Ref = attrs["id"]
+	ti.ParentRef = attrs["parent"]
+	ti.BlockedByRefs = splitRefs(attrs["blocked"])
+	return projects, nil
+}
+
+func isTextAttr(key string) bool {
+	switch key {
+	case "due", "t", "tz", "rrule", "status", "id", "parent", "blocked", "pri":
+		return true
+	}
+	return false
+}
+
+// encodeTodoTxt writes an item per line, the list is given as a project.
+// Done items start with "x" and their completion date and keep their priority as a pri attribute.
+// Notes do not fit in the format and are left out.
+func encodeTodoTxt(w io.Writer, lists []TransferList) error {
+	refs := textRefs(lists, true)
+	bw := bufio.NewWriter(w)
+	for _, tl := range lists {
+		project := "+" + strings.Join(strings.Fields(tl.Name), "-")
+		for _, ti := range tl.Items {
+			var words []string
+			loc := ti.Location()
+			if ti.Done {
+				words = append(words, "x")
+				if !ti.CompletedAt.IsZero() {
+					words = append(words, ti.CompletedAt.In(loc).Format("2006-01-02"))
+				}
+			} else if ti.Priority != PriorityNone {
+				words = append(words, "("+priorityLetter(ti.Priority)+")")
+			}
+			words = append(words, ti.Title)
+			if project != "+" {
+				words = append(words, project)
+			}
+			words = append(words, itemWords(ti, todoTxtTag, refs, true)...)
+			if ti.Done && ti.Priority != PriorityNone {
+				words = append(words, "pri:"+priorityLetter(ti.Priority))
+			}
+			fmt.Fprintln(bw, strings.Join(words, " "))
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeTodoTxt reads an item per line, the first project of an item names its list and any other one becomes a tag.
+// Creation dates are accepted and ignored.
+func decodeTodoTxt(r io.Reader, loc *time.Location) ([]TransferList, []ImportProblem) {
+	var lists []TransferList
+	index := map[string]int{}
+	var problems []ImportProblem
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+
+		ti := TransferItem{Item: NewItem(uuid.Nil, "", ""), Line: line}
+		if words[0] == "x" {
+			ti.Status = StatusDone
+			words = words[1:]
+			if len(words) > 0 && todoTxtDate.MatchString(words[0]) {
+				ti.CompletedAt, _ = parseTransferTime(words[0], loc)
+				words = words[1:]
+			}
+		} else if len(words) > 0 {
+			if m := todoTxtPriority.FindStringSubmatch(words[0]); m != nil {
+				ti.Priority, _ = parseTransferPriority(m[1])
+				words = words[1:]
+			}
+		}
+		if len(words) > 0 && todoTxtDate.MatchString(words[0]) {
+			words = words[1:]
+		}
+
+		projects, err := parseItemWords(&ti, words, todoTxtTag, loc)
+		if err == nil && len(projects) > 1 {
+			ti.Tags, err = NormalizeTags(append(ti.Tags, projects[1:]...))
+		}
+		if err != nil {
+			problems = append(problems, itemProblem(ti, err))
+			continue
+		}
+
+		name := ""
+		if len(projects) > 0 {
+			name = projects[0]
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(lists)
+			index[name] = i
+			lists = append(lists, TransferList{Name: name})
+		}
+		lists[i].Items = append(lists[i].Items, ti)
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, ImportProblem{Message: err.Error()})
+	}
+	return lists, problems
+}
+
+// encodeMarkdown writes a heading per list followed by its description and a checklist of its items.
+// Subtasks are nested under their parents and notes are indented below their item.
+func encodeMarkdown(w io.Writer, lists []TransferList) error {
+	refs := textRefs(lists, false)
+	bw := bufio.NewWriter(w)
+	for i, tl := range lists {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		fmt.Fprintf(bw, "# %s\n\n", tl.Name)
+		if desc := strings.TrimSpace(tl.Description); desc != "" {
+			fmt.Fprintf(bw, "%s\n\n", desc)
+		}
+		for _, ti := range tl.Items {
+			indent := strings.Repeat("  ", ti.Depth)
+			check := " "
+			if ti.Done {
+				check = "x"
+			}
+			var words []string
+			if ti.Priority != PriorityNone {
+				words = append(words, "("+priorityLetter(ti.Priority)+")")
+			}
+			words = append(words, ti.Title)
+			words = append(words, itemWords(ti, markdownTag, refs, false)...)
+			fmt.Fprintf(bw, "%s- [%s] %s\n", indent, check, strings.Join(words, " "))
+			for _, note := range strings.Split(strings.TrimSpace(ti.Notes), "\n") {
+				if note = strings.TrimRight(note, " \r"); note != "" {
+					fmt.Fprintf(bw, "%s  %s\n", indent, note)
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// decodeMarkdown reads a list per "# " heading and its checklist items, items before any heading go to a default list.
+// Nesting makes subtasks, lines indented below an item are its notes and the text before the first item is the description.
+func decodeMarkdown(r io.Reader, loc *time.Location) ([]TransferList, []ImportProblem) {
+	type open struct {
+		indent int
+		ref    string
+	}
+	var lists []TransferList
+	var problems []ImportProblem
+	var stack []open
+	last := -1
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.ReplaceAll(scanner.Text(), "\t", "    ")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if m := markdownList.FindStringSubmatch(text); m != nil {
+			lists = append(lists, TransferList{Name: strings.TrimSpace(m[1])})
+			stack, last = nil, -1
+			continue
+		}
+		if len(lists) == 0 {
+			lists = append(lists, TransferList{})
+		}
+		tl := &lists[len(lists)-1]
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+
+		m := markdownItem.FindStringSubmatch(text)
+		if m == nil {
+			switch {
+			case last >= 0 && indent > stack[len(stack)-1].indent:
+				prev := &tl.Items[last]
+				prev.Notes = strings.TrimPrefix(prev.Notes+"\n"+strings.TrimSpace(text), "\n")
+			case len(tl.Items) == 0:
+				tl.Description = strings.TrimPrefix(tl.Description+"\n"+strings.TrimSpace(text), "\n")
+			default:
+				problems = append(problems, ImportProblem{Line: line, Message: "not a checklist item"})
+			}
+			continue
+		}
+
+		ti := TransferItem{Item: NewItem(uuid.Nil, "", ""), Line: line}
+		if m[2] != " " {
+			ti.Status = StatusDone
+		}
+		words := strings.Fields(m[3])
+		if len(words) > 0 {
+			if p := todoTxtPriority.FindStringSubmatch(words[0]); p != nil {
+				ti.Priority, _ = parseTransferPriority(p[1])
+				words = words[1:]
+			}
+		}
+		_, err := parseItemWords(&ti, words, markdownTag, loc)
+		if err != nil {
+			problems = append(problems, itemProblem(ti, err))
+			continue
+		}
+		if ti.Ref == "" {
+			ti.Ref = "line " + strconv.Itoa(line)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			ti.ParentRef = stack[len(stack)-1].ref
+		}
+		stack = append(stack, open{indent: indent, ref: ti.Ref})
+		tl.Items = append(tl.Items, ti)
+		last = len(tl.Items) - 1
+	}
+	if err := scanner.Err(); err != nil {
+		problems = append(problems, ImportProblem{Message: err.Error()})
+	}
+	return lists, problems
+}
@@ -2,10 +2,14 @@ package todo
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
@@ -16,6 +20,45 @@ var (
 	method      = am.HTTPMethod
 )
 
+// listRow is a list along with what the actor can do with it and the name of its owner.
+type listRow struct {
+	List
+	Access Access
+	Owner  string
+}
+
+// listPage is the data of the list page.
+type listPage struct {
+	Filter string
+	Rows   []listRow
+}
+
+// showPage is the data of the list show page, items are in tree order and Item holds the defaults of the new item form.
+// When the filter hides the parent of an item the item is shown at the top level.
+type showPage struct {
+	List
+	Access     Access
+	FilterForm FilterForm
+	Items      []ItemNode
+	Item       ItemForm
+	Now        time.Time
+	// Timeline holds the comments and the changes of the list and its items, newest first.
+	Timeline []activityRow
+	// Attachments are keyed by item.
+	Attachments map[uuid.UUID][]Attachment
+	// Names are the user and team names of the assignees.
+	Names map[uuid.UUID]string
+	// Tracked is the time tracked on each item, Timer the running timer of the actor.
+	Tracked map[uuid.UUID]Spent
+	Timer   TimeEntry
+}
+
+// newListPage is the data of the new list page, the teams are the ones the list can be owned by.
+type newListPage struct {
+	List
+	Teams []auth.Team
+}
+
 type WebHandler struct {
 	*am.Handler
 	service Service
@@ -35,17 +78,44 @@ func (h *WebHandler) List(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("List todos")
 	ctx := r.Context()
 
-	lists, err := h.service.GetLists(ctx)
+	filter := r.URL.Query().Get("filter")
+	lists, err := h.service.GetLists(ctx, filter)
 	if err != nil {
 		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
 		return
 	}
 
-	page := am.NewPage(r, lists)
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	rows := make([]listRow, 0, len(lists))
+	for _, list := range lists {
+		access, err := h.service.Access(ctx, list)
+		if err != nil {
+			http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		rows = append(rows, listRow{List: list, Access: access, Owner: names[list.OwnerID()]})
+	}
 
-	menu := am.NewMenu(todoResPath)
+	page := am.NewPage(r, listPage{Filter: filter, Rows: rows})
+
+	menu := page.NewMenu(todoResPath)
 
 	menu.AddResNewItem("todo")
+	menu.AddResGenericItem(ViewToday, "", "Today")
+	menu.AddResGenericItem(ViewUpcoming, "", "Upcoming")
+	menu.AddResGenericItem(ViewOverdue, "", "Overdue")
+	menu.AddResGenericItem(itemsPath, "", "Items")
+	menu.AddResGenericItem(assignedPath, "", "Assigned to me")
+	menu.AddResGenericItem(timesheetPath, "", "Timesheet")
+	menu.AddResGenericItem("import", "", "Import/Export")
+	menu.AddResGenericItem(feedPath, "", "Calendar feed")
+	menu.AddResTrashItem()
+	addSavedFilterItems(menu, h.savedFilters(ctx))
 
 	tmpl, err := h.tm.Get("todo", "list")
 	if err != nil {
@@ -69,7 +139,13 @@ func (h *WebHandler) List(w http.ResponseWriter, r *http.Request) {
 func (h *WebHandler) New(w http.ResponseWriter, r *http.Request) {
 	h.Log().Info("New todo form")
 
-	page := am.NewPage(r, List{})
+	teams, err := h.service.GetActorTeams(r.Context())
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, newListPage{List: List{}, Teams: teams})
 	page.SetFormAction(todoResPath)
 	page.SetFormMethod(method.POST)
 	page.SetFormButtonText("Create")
@@ -100,10 +176,18 @@ func (h *WebHandler) Create(w http.ResponseWriter, r *http.Request) {
 	name := r.FormValue("name")
 	description := r.FormValue("description")
 	list := NewList(name, description)
+	if teamID := r.FormValue("owner_team_id"); teamID != "" {
+		ownerTeamID, err := uuid.Parse(teamID)
+		if err != nil {
+			http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+		list.OwnerTeamID = ownerTeamID
+	}
 
 	err := h.service.Create(ctx, list)
 	if err != nil {
-		http.Error(w, am.ErrCannotCreateResource, http.StatusInternalServerError)
+		h.listErr(w, err, am.ErrCannotCreateResource)
 		return
 	}
 
@@ -123,17 +207,92 @@ func (h *WebHandler) Show(w http.ResponseWriter, r *http.Request) {
 
 	list, err := h.service.Get(ctx, listID)
 	if err != nil {
-		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+		h.listErr(w, err, am.ErrCannotGetResources)
 		return
 	}
 
-	page := am.NewPage(r, list)
+	access, err := h.service.Access(ctx, list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
 
-	menu := am.NewMenu(todoResPath)
+	filter, err := ParseItemFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.GetItems(ctx, listID)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	timeline, err := h.timelineRows(ctx, listID, items)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(ctx, listID, uuid.Nil)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := h.service.GetTimeEntries(ctx, listID, uuid.Nil)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	tracked := make(map[uuid.UUID]Spent)
+	for _, e := range entries {
+		tracked[e.ItemID] += e.Spent()
+	}
+
+	timer, err := h.service.GetRunningTimer(ctx)
+	if err != nil && !errors.Is(err, ErrNoTimerRunning) {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, showPage{
+		List:        list,
+		Access:      access,
+		FilterForm:  FilterForm{Action: todoResPath + "/" + id, Filter: filter, Tags: collectTags(items)},
+		Items:       BuildItemTree(filter.Apply(items)),
+		Item:        ItemForm{TimeZone: "UTC", Candidates: items},
+		Now:         time.Now(),
+		Timeline:    timeline,
+		Attachments: byItem(attachments),
+		Names:       names,
+		Tracked:     tracked,
+		Timer:       timer,
+	})
+
+	menu := page.NewMenu(todoResPath)
 
 	menu.AddResListItem(list)
-	menu.AddResEditItem(list)
-	menu.AddResDeleteItem(list)
+	menu.AddResGenericItem("board", list.ID().String(), "Board")
+	menu.AddResGenericItem("import", list.ID().String(), "Import/Export")
+	menu.AddResGenericItem(historyPath, list.ID().String(), "History")
+	if list.Template {
+		menu.AddResGenericItem(usePath, list.ID().String(), "Use template")
+	}
+	if access.CanEdit() {
+		menu.AddResEditItem(list)
+	}
+	if access.CanManage() {
+		menu.AddResGenericItem("shares", list.ID().String(), "Shares")
+		menu.AddResDeleteItem(list)
+	}
 
 	tmpl, err := h.tm.Get("todo", "show")
 	if err != nil {
@@ -167,7 +326,17 @@ func (h *WebHandler) Edit(w http.ResponseWriter, r *http.Request) {
 
 	list, err := h.service.Get(ctx, listID)
 	if err != nil {
-		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	access, err := h.service.Access(ctx, list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	if !access.CanEdit() {
+		http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
 		return
 	}
 
@@ -208,7 +377,7 @@ func (h *WebHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	list, err := h.service.Get(ctx, listID)
 	if err != nil {
-		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+		h.listErr(w, err, am.ErrCannotGetResources)
 		return
 	}
 
@@ -216,10 +385,17 @@ func (h *WebHandler) Update(w http.ResponseWriter, r *http.Request) {
 	description := r.FormValue("description")
 	list.Name = name
 	list.Description = description
+	if version, ok := am.FormVersion(r); ok {
+		list.SetVersion(version)
+	}
 
 	err = h.service.Update(ctx, list)
+	if am.IsConflict(err) {
+		http.Error(w, am.ErrVersionConflict, http.StatusConflict)
+		return
+	}
 	if err != nil {
-		http.Error(w, am.ErrCannotUpdateResource, http.StatusInternalServerError)
+		h.listErr(w, err, am.ErrCannotUpdateResource)
 		return
 	}
 
@@ -239,9 +415,115 @@ func (h *WebHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.Delete(ctx, listID)
 	if err != nil {
-		http.Error(w, am.ErrCannotDeleteResource, http.StatusInternalServerError)
+		h.listErr(w, err, am.ErrCannotDeleteResource)
 		return
 	}
 
 	http.Redirect(w, r, todoResPath, http.StatusSeeOther)
 }
+
+func (h *WebHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("List deleted todos")
+	ctx := r.Context()
+
+	lists, err := h.service.GetDeleted(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, lists)
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(List{})
+
+	tmpl, err := h.tm.Get("todo", "trash")
+	if err != nil {
+		http.Error(w, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		http.Error(w, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		http.Error(w, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
+
+func (h *WebHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Restore todo ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.Restore(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotRestoreResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/trash", http.StatusSeeOther)
+}
+
+// render executes the named todo template and writes it to the response.
+func (h *WebHandler) render(w http.ResponseWriter, name string, page *am.Page) {
+	tmpl, err := h.tm.Get("todo", name)
+	if err != nil {
+		http.Error(w, am.ErrTemplateNotFound, http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, page)
+	if err != nil {
+		http.Error(w, am.ErrCannotRenderTemplate, http.StatusInternalServerError)
+		return
+	}
+
+	_, err = buf.WriteTo(w)
+	if err != nil {
+		http.Error(w, am.ErrCannotWriteResponse, http.StatusInternalServerError)
+	}
+}
+
+// names maps the IDs of the users and teams lists can be owned by and shared with to their names.
+func (h *WebHandler) names(ctx context.Context) (map[uuid.UUID]string, error) {
+	users, teams, err := h.service.GetShareCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uuid.UUID]string, len(users)+len(teams))
+	for _, user := range users {
+		names[user.ID()] = user.Username
+	}
+	for _, team := range teams {
+		names[team.ID()] = team.Name
+	}
+	return names, nil
+}
+
+func (h *WebHandler) listErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrListNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrUnauthenticated):
+		http.Error(w, ErrUnauthenticated.Error(), http.StatusUnauthorized)
+	case errors.Is(err, ErrForbidden):
+		http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,143 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/google/uuid"
+)
+
+const assignedPath = "assigned"
+
+// assignedPage is the data of the assigned to me and assigned to my teams pages.
+type assignedPage struct {
+	View  string
+	Items []DueItem
+	Names map[uuid.UUID]string
+	Now   time.Time
+}
+
+// assigneeRow is an assignee of an item with its name resolved.
+type assigneeRow struct {
+	Assignee
+	Name string
+}
+
+// Assigned shows the items assigned to the actor, or to their teams when the view query param is teams.
+func (h *WebHandler) Assigned(w http.ResponseWriter, r *http.Request) {
+	view := AssignedToMe
+	if r.URL.Query().Get("view") == AssignedToTeams {
+		view = AssignedToTeams
+	}
+	h.Log().Info("List todo items assigned to ", view)
+	ctx := r.Context()
+
+	items, err := h.service.GetAssignedItems(ctx, view)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, assignedPage{View: view, Items: items, Names: names, Now: time.Now()})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(List{})
+	menu.AddResGenericItem(assignedPath, "", "Assigned to me")
+	menu.AddResQueryItem(assignedPath, map[string]string{"view": AssignedToTeams}, "Assigned to my teams")
+
+	h.render(w, "assigned", page)
+}
+
+// AssignItem assigns the item to the assignee in the form, written as type:id, e.g. "team:<id>".
+func (h *WebHandler) AssignItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Assign todo item ", itemID)
+
+	assignee, err := ParseAssignee(r.FormValue("assignee"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.AssignItem(r.Context(), listID, itemID, assignee)
+	if err != nil {
+		h.assigneeErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, itemPath(listID, itemID)+"/edit"), http.StatusSeeOther)
+}
+
+func (h *WebHandler) UnassignItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Unassign todo item ", itemID)
+
+	assignee, err := ParseAssignee(r.FormValue("assignee"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.UnassignItem(r.Context(), listID, itemID, assignee)
+	if err != nil {
+		h.assigneeErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, itemPath(listID, itemID)+"/edit"), http.StatusSeeOther)
+}
+
+// assigneeRows resolves the names of the assignees of the item along with the users and teams it can be assigned to.
+func (h *WebHandler) assigneeRows(ctx context.Context, item Item) ([]assigneeRow, []auth.User, []auth.Team, error) {
+	users, teams, err := h.service.GetAssigneeCandidates(ctx, item.ListID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	names, err := h.names(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	rows := make([]assigneeRow, len(item.Assignees))
+	for i, a := range item.Assignees {
+		rows[i] = assigneeRow{Assignee: a, Name: names[a.ID]}
+		if rows[i].Name == "" {
+			rows[i].Name = a.ID.String()
+		}
+	}
+	return rows, users, teams, nil
+}
+
+func (h *WebHandler) assigneeErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidAssignee):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrAssigneeNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
+
+func assigneesPath(listID, itemID uuid.UUID) string {
+	return itemPath(listID, itemID) + "/assignees"
+}
@@ -0,0 +1,140 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// multipartOverhead is the room left for the form fields and boundaries of an upload.
+const multipartOverhead = 1 << 20
+
+// UploadAttachment attaches the file of a multipart form to the item.
+func (h *WebHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Upload todo attachment ", itemID)
+
+	limits := h.service.AttachmentLimits()
+	r.Body = http.MaxBytesReader(w, r.Body, limits.MaxSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	_, err = h.service.AddAttachment(r.Context(), listID, itemID, header.Filename, file)
+	if err != nil {
+		h.attachmentErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, itemPath(listID, itemID)+"/edit", http.StatusSeeOther)
+}
+
+// DownloadAttachment sends the content of an attachment, in ranges when asked.
+func (h *WebHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Download todo attachment ", attachmentID)
+
+	err = serveAttachment(w, r, h.service, listID, itemID, attachmentID)
+	if err != nil {
+		h.attachmentErr(w, err, am.ErrCannotGetResources)
+	}
+}
+
+func (h *WebHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, attachmentID, err := attachmentIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Delete todo attachment ", attachmentID)
+
+	err = h.service.DeleteAttachment(r.Context(), listID, itemID, attachmentID)
+	if err != nil {
+		h.attachmentErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, itemPath(listID, itemID)+"/edit"), http.StatusSeeOther)
+}
+
+func (h *WebHandler) attachmentErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrAttachmentNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, ErrAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
+
+// serveAttachment writes an attachment the actor can see, http.ServeContent answers range and conditional requests.
+// Attachments are immutable so their ID is their entity tag. Nothing but images and PDFs is shown inline,
+// and the browser is told neither to guess the type nor to run anything in the file.
+// Errors are returned before anything is written.
+func serveAttachment(w http.ResponseWriter, r *http.Request, service Service, listID, itemID, id uuid.UUID) error {
+	attachment, blob, err := service.OpenAttachment(r.Context(), listID, itemID, id)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	disposition := "attachment"
+	if attachment.IsInline() {
+		disposition = "inline"
+	}
+	header := w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Name}))
+	header.Set("ETag", fmt.Sprintf("%q", attachment.ID.String()))
+	header.Set("Cache-Control", "private, max-age=3600")
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "sandbox; default-src 'none'")
+	http.ServeContent(w, r, attachment.Name, attachment.CreatedAt, blob)
+	return nil
+}
+
+func attachmentIDs(r *http.Request) (listID, itemID, attachmentID uuid.UUID, err error) {
+	listID, itemID, err = itemIDs(r)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, err
+	}
+	attachmentID, err = uuid.Parse(chi.URLParam(r, "attachmentID"))
+	return listID, itemID, attachmentID, err
+}
+
+func attachmentsPath(listID, itemID uuid.UUID) string {
+	return itemPath(listID, itemID) + "/attachments"
+}
+
+// byItem groups attachments by the item they belong to.
+func byItem(attachments []Attachment) map[uuid.UUID][]Attachment {
+	grouped := make(map[uuid.UUID][]Attachment)
+	for _, a := range attachments {
+		grouped[a.ItemID] = append(grouped[a.ItemID], a)
+	}
+	return grouped
+}
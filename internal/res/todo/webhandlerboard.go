@@ -0,0 +1,187 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// boardPage is the data of the board page, Columns holds a row for each status in the columns form.
+type boardPage struct {
+	List    List
+	Access  Access
+	Board   Board
+	Columns []columnRow
+}
+
+// columnRow is a status in the board columns form, Order is its place among the shown ones.
+type columnRow struct {
+	Status Status
+	Title  string
+	Shown  bool
+	Order  int
+}
+
+func (h *WebHandler) Board(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Show todo board ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	access, err := h.service.Access(ctx, list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	board, err := h.service.GetBoard(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	page := am.NewPage(r, boardPage{List: list, Access: access, Board: board, Columns: columnRows(list)})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResShowItem(list, "Back")
+
+	h.render(w, "board", page)
+}
+
+// MoveItem moves an item on the board, the form gives the target status and the index in its column.
+// It is used by the move buttons and by drag and drop alike.
+func (h *WebHandler) MoveItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Move todo item ", itemID)
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	status, err := ParseStatus(r.PostForm.Get("status"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	index, err := strconv.Atoi(r.PostForm.Get("index"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+	force := r.PostForm.Get("force") == "true"
+
+	err = h.service.MoveItem(r.Context(), listID, itemID, status, index, force)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, boardPath(listID)), http.StatusSeeOther)
+}
+
+// UpdateBoardColumns reads a shown flag, a title and an order for each status.
+func (h *WebHandler) UpdateBoardColumns(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Update todo board columns ", id)
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.SetBoardColumns(r.Context(), listID, formColumns(r.PostForm))
+	if am.IsConflict(err) {
+		http.Error(w, am.ErrVersionConflict, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.boardErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, boardPath(listID), http.StatusSeeOther)
+}
+
+// columnRows returns a row for each status, the shown ones first in board order.
+func columnRows(list List) []columnRow {
+	var rows []columnRow
+	shown := map[Status]bool{}
+	for i, c := range list.BoardColumns() {
+		rows = append(rows, columnRow{Status: c.Status, Title: c.Title, Shown: true, Order: i + 1})
+		shown[c.Status] = true
+	}
+	for _, s := range Statuses {
+		if !shown[s] {
+			rows = append(rows, columnRow{Status: s, Title: s.Label(), Order: len(rows) + 1})
+		}
+	}
+	return rows
+}
+
+// formColumns reads the columns form, the shown statuses sorted by their order.
+func formColumns(values url.Values) []BoardColumn {
+	type entry struct {
+		column BoardColumn
+		order  int
+	}
+	var entries []entry
+	for _, s := range Statuses {
+		if values.Get("shown_"+string(s)) == "" {
+			continue
+		}
+		order, _ := strconv.Atoi(values.Get("order_" + string(s)))
+		entries = append(entries, entry{BoardColumn{Status: s, Title: values.Get("title_" + string(s))}, order})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].order < entries[j].order })
+
+	columns := make([]BoardColumn, len(entries))
+	for i, e := range entries {
+		columns[i] = e.column
+	}
+	return columns
+}
+
+func (h *WebHandler) boardErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidColumns):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.listErr(w, err, msg)
+	}
+}
+
+func boardPath(listID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/board", todoResPath, listID)
+}
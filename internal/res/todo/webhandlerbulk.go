@@ -0,0 +1,78 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// bulkPage is the data of the result page of a bulk command.
+type bulkPage struct {
+	List  List
+	Batch am.Batch
+}
+
+// BulkItems runs the bulk command of the form on the selected items and shows the result of each one.
+func (h *WebHandler) BulkItems(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	cmd := BulkCommand{
+		Command: r.PostForm.Get("command"),
+		Status:  Status(r.PostForm.Get("status")),
+		Tag:     r.PostForm.Get("tag"),
+		Force:   r.PostForm.Get("force") == "true",
+	}
+	for _, v := range r.PostForm["item_ids"] {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+		cmd.ItemIDs = append(cmd.ItemIDs, id)
+	}
+	h.Log().Info("Bulk ", cmd.Command, " of ", len(cmd.ItemIDs), " todo items")
+	ctx := r.Context()
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	batch, err := h.service.BulkItems(ctx, listID, cmd)
+	if err != nil {
+		h.bulkErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	page := am.NewPage(r, bulkPage{List: list, Batch: batch})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(list)
+	menu.AddResShowItem(list)
+
+	h.render(w, "bulk", page)
+}
+
+func (h *WebHandler) bulkErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidBulk):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
@@ -0,0 +1,213 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// activityRow is an entry of the timeline as shown on the list page.
+// Item is the title of the item a comment is about, CanEdit tells whether the actor wrote the comment.
+type activityRow struct {
+	Activity
+	Actor   string
+	Item    string
+	HTML    template.HTML
+	CanEdit bool
+}
+
+// commentPage is the data of the edit comment page.
+type commentPage struct {
+	List    List
+	Comment Comment
+	Item    string
+}
+
+// CreateComment adds a comment to the list, or to the item chosen in the form.
+func (h *WebHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Create todo comment")
+
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	itemID := uuid.Nil
+	if id := r.FormValue("item_id"); id != "" {
+		itemID, err = uuid.Parse(id)
+		if err != nil {
+			http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+	}
+
+	_, err = h.service.AddComment(r.Context(), NewComment(listID, itemID, r.FormValue("body")))
+	if err != nil {
+		h.commentErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, activityPath(listID), http.StatusSeeOther)
+}
+
+// EditComment shows the form to change a comment, to its author only.
+func (h *WebHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	listID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Edit todo comment ", commentID)
+	ctx := r.Context()
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+	comment, err := h.service.GetComment(ctx, listID, commentID)
+	if err != nil {
+		h.commentErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+	if actorID, ok := am.ActorFromContext(ctx); ok && comment.AuthorID != actorID {
+		http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	data := commentPage{List: list, Comment: comment}
+	if comment.IsOnItem() {
+		item, err := h.service.GetItem(ctx, listID, comment.ItemID)
+		if err != nil {
+			h.itemErr(w, err, am.ErrResourceNotFound)
+			return
+		}
+		data.Item = item.Title
+	}
+
+	page := am.NewPage(r, data)
+	page.SetFormAction(commentPath(listID, commentID))
+
+	menu := page.NewMenu(todoResPath)
+	menu.AddResShowItem(list, "Back")
+
+	h.render(w, "edit-comment", page)
+}
+
+func (h *WebHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	listID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Update todo comment ", commentID)
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.UpdateComment(r.Context(), listID, commentID, r.FormValue("body"))
+	if err != nil {
+		h.commentErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, activityPath(listID), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	listID, commentID, err := commentIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Delete todo comment ", commentID)
+
+	err = h.service.DeleteComment(r.Context(), listID, commentID)
+	if err != nil {
+		h.commentErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, activityPath(listID), http.StatusSeeOther)
+}
+
+// timelineRows returns the timeline of the list with names resolved and comments rendered.
+func (h *WebHandler) timelineRows(ctx context.Context, listID uuid.UUID, items []Item) ([]activityRow, error) {
+	timeline, err := h.service.GetTimeline(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := h.names(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	titles := make(map[uuid.UUID]string, len(items))
+	for _, item := range items {
+		titles[item.ID()] = item.Title
+	}
+	actorID, hasActor := am.ActorFromContext(ctx)
+
+	rows := make([]activityRow, len(timeline))
+	for i, a := range timeline {
+		row := activityRow{Activity: a, Actor: names[a.ActorID()]}
+		if row.Actor == "" {
+			row.Actor = "Someone"
+		}
+		if c := a.Comment; c != nil {
+			row.HTML = c.HTML(names)
+			row.CanEdit = !hasActor || c.AuthorID == actorID
+			if c.IsOnItem() {
+				row.Item = titles[c.ItemID]
+				if row.Item == "" {
+					row.Item = "a deleted item"
+				}
+			}
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+func (h *WebHandler) commentErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrCommentNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrInvalidComment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
+
+func commentIDs(r *http.Request) (listID, commentID uuid.UUID, err error) {
+	listID, err = uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	commentID, err = uuid.Parse(chi.URLParam(r, "commentID"))
+	return listID, commentID, err
+}
+
+func commentPath(listID, commentID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/comments/%s", todoResPath, listID, commentID)
+}
+
+func activityPath(listID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s#activity", todoResPath, listID)
+}
@@ -0,0 +1,120 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+)
+
+// feedPage is the data of the calendar feed page, the URLs are absolute so they can be pasted in a calendar client.
+type feedPage struct {
+	Token     FeedToken
+	TodosURL  string
+	EventsURL string
+	// Webcal URLs open the subscription dialog of the calendar client.
+	TodosWebcal  string
+	EventsWebcal string
+}
+
+// FeedPage shows the calendar URLs of the actor.
+func (h *WebHandler) FeedPage(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show todo calendar feed")
+
+	token, err := h.service.GetFeedToken(r.Context())
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	base := baseURL(r)
+	data := feedPage{
+		Token:     token,
+		TodosURL:  base + token.Path(FeedTodos),
+		EventsURL: base + token.Path(FeedEvents),
+	}
+	data.TodosWebcal = webcalURL(data.TodosURL)
+	data.EventsWebcal = webcalURL(data.EventsURL)
+
+	page := am.NewPage(r, data)
+	page.SetFormAction(todoResPath + "/" + feedPath + "/token")
+
+	menu := page.NewMenu(todoResPath)
+	menu.AddResListItem(List{})
+
+	h.render(w, "feed", page)
+}
+
+// RegenerateFeedToken replaces the feed token of the actor.
+func (h *WebHandler) RegenerateFeedToken(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Regenerate todo calendar feed token")
+
+	_, err := h.service.RegenerateFeedToken(r.Context())
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+feedPath, http.StatusSeeOther)
+}
+
+// Feed serves the calendar of a feed token, the token authenticates the request.
+func (h *WebHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Serve todo calendar feed")
+
+	kind := FeedTodos
+	if r.URL.Query().Get("kind") == FeedEvents {
+		kind = FeedEvents
+	}
+
+	feed, err := h.service.GetFeed(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		if errors.Is(err, ErrFeedNotFound) {
+			http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+			return
+		}
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	etag := feed.ETag(kind)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	err = WriteCalendar(w, feed, kind)
+	if err != nil {
+		h.Log().Errorf("cannot write calendar feed: %v", err)
+	}
+}
+
+// etagMatch reports whether an If-None-Match header matches the entity tag, weakly as the header asks for.
+func etagMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// baseURL returns the scheme and host the request was made to, behind a proxy as it forwarded them.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func webcalURL(url string) string {
+	_, rest, _ := strings.Cut(url, "://")
+	return "webcal://" + rest
+}
@@ -0,0 +1,154 @@
+package todo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// FilterForm is the data of the item filter form, Tags are the ones offered to pick from.
+type FilterForm struct {
+	Action string
+	Filter ItemFilter
+	Tags   []string
+}
+
+// PriorityOptions lists the priorities the form offers.
+func (f FilterForm) PriorityOptions() []Priority {
+	return Priorities
+}
+
+// StatusOptions lists the statuses the form offers.
+func (f FilterForm) StatusOptions() []Status {
+	return Statuses
+}
+
+// itemsPage is the data of the filtered items page.
+type itemsPage struct {
+	FilterForm FilterForm
+	Items      []DueItem
+	Saved      []SavedFilter
+	Now        time.Time
+}
+
+// Items lists the items of all the visible lists that pass the filter in the query.
+func (h *WebHandler) Items(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Find todo items")
+	ctx := r.Context()
+
+	filter, err := ParseItemFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.FindItems(ctx, filter)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	tags, err := h.service.GetTags(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := h.service.GetSavedFilters(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, itemsPage{
+		FilterForm: FilterForm{Action: todoResPath + "/" + itemsPath, Filter: filter, Tags: tags},
+		Items:      items,
+		Saved:      saved,
+		Now:        time.Now(),
+	})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(List{})
+	addSavedFilterItems(menu, saved)
+
+	h.render(w, "items", page)
+}
+
+// SaveFilter saves the filter in the form under the given name and shows its items.
+func (h *WebHandler) SaveFilter(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Save todo filter")
+
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	filter, err := ParseItemFilter(r.PostForm)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.service.SaveFilter(r.Context(), r.PostForm.Get("name"), filter)
+	if err != nil {
+		h.filterErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, saved.Path(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteSavedFilter(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "filterID")
+	h.Log().Info("Delete todo filter ", id)
+
+	filterID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.DeleteSavedFilter(r.Context(), filterID)
+	if err != nil {
+		h.filterErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+itemsPath, http.StatusSeeOther)
+}
+
+// savedFilters returns the saved filters of the actor to show in the menu.
+// They are a convenience, failing to read them does not fail the page.
+func (h *WebHandler) savedFilters(ctx context.Context) []SavedFilter {
+	saved, err := h.service.GetSavedFilters(ctx)
+	if err != nil {
+		h.Log().Errorf("cannot get saved filters: %v", err)
+		return nil
+	}
+	return saved
+}
+
+// addSavedFilterItems adds a menu item for each saved filter.
+func addSavedFilterItems(menu *am.Menu, saved []SavedFilter) {
+	for _, f := range saved {
+		menu.AddResQueryItem(itemsPath, f.Filter.Params(), f.Name)
+	}
+}
+
+func (h *WebHandler) filterErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidFilter):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrFilterNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	default:
+		http.Error(w, msg, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,460 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// previewSize is the number of occurrences shown in the preview of recurring items.
+	previewSize = 5
+	// maxOccurrences bounds the occurrences an API preview can ask for.
+	maxOccurrences = 100
+)
+
+// itemFormPage is the data of the new and edit item pages.
+// Preview holds the due times of the first occurrences when the item recurs.
+// Attachments and assignees are only shown on the edit page, where AttachmentsPath and AssigneesPath are set.
+type itemFormPage struct {
+	List            List
+	Item            Item
+	Form            ItemForm
+	Preview         []time.Time
+	Attachments     []Attachment
+	AttachmentsPath string
+	Limits          AttachmentLimits
+	Assignees       []assigneeRow
+	AssigneesPath   string
+	AssigneeUsers   []auth.User
+	AssigneeTeams   []auth.Team
+}
+
+// duePage is the data of the today, upcoming and overdue pages.
+type duePage struct {
+	View     string
+	TimeZone string
+	Days     int
+	Items    []DueItem
+	Now      time.Time
+}
+
+func (h *WebHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Create todo item ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	form := NewItemForm(r.PostForm)
+	item, err := FormToItem(form, NewItem(listID, "", ""))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if isPreview(r) {
+		h.previewItem(w, r, item, form, true)
+		return
+	}
+
+	err = h.service.CreateItem(ctx, item)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+id, http.StatusSeeOther)
+}
+
+func (h *WebHandler) NewItem(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("New todo item ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	item := NewItem(listID, "", "")
+	if parentID, err := uuid.Parse(r.URL.Query().Get("parent")); err == nil {
+		item.ParentID = parentID
+	}
+	h.itemForm(w, r, list, item, ItemToForm(item), true)
+}
+
+func (h *WebHandler) EditItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Edit todo item ", itemID)
+	ctx := r.Context()
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	item, err := h.service.GetItem(ctx, listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	h.itemForm(w, r, list, item, ItemToForm(item), false)
+}
+
+// previewItem shows the item form again with the upcoming occurrences of the submitted values, nothing is saved.
+func (h *WebHandler) previewItem(w http.ResponseWriter, r *http.Request, item Item, form ItemForm, isNew bool) {
+	err := item.Validate()
+	if err != nil {
+		h.itemErr(w, err, am.ErrInvalidFormData)
+		return
+	}
+
+	list, err := h.service.Get(r.Context(), item.ListID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	h.itemForm(w, r, list, item, form, isNew)
+}
+
+// itemForm renders the new or edit item page, it requires editor access to the list.
+func (h *WebHandler) itemForm(w http.ResponseWriter, r *http.Request, list List, item Item, form ItemForm, isNew bool) {
+	access, err := h.service.Access(r.Context(), list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	if !access.CanEdit() {
+		http.Error(w, ErrForbidden.Error(), http.StatusForbidden)
+		return
+	}
+
+	items, err := h.service.GetItems(r.Context(), list.ID())
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	form.Candidates = linkCandidates(item, items)
+
+	var preview []time.Time
+	if item.IsRecurring() {
+		preview = append([]time.Time{item.LocalDue()}, item.UpcomingOccurrences(previewSize-1)...)
+	}
+
+	data := itemFormPage{List: list, Item: item, Form: form, Preview: preview}
+	if !isNew {
+		data.Attachments, err = h.service.GetAttachments(r.Context(), list.ID(), item.ID())
+		if err != nil {
+			http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		data.AttachmentsPath = attachmentsPath(list.ID(), item.ID())
+		data.Limits = h.service.AttachmentLimits()
+
+		data.Assignees, data.AssigneeUsers, data.AssigneeTeams, err = h.assigneeRows(r.Context(), item)
+		if err != nil {
+			http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		data.AssigneesPath = assigneesPath(list.ID(), item.ID())
+	}
+
+	page := am.NewPage(r, data)
+	if isNew {
+		page.SetFormAction(fmt.Sprintf("%s/%s/items", todoResPath, list.ID()))
+		page.SetFormButtonText("Create")
+	} else {
+		page.SetFormAction(itemPath(list.ID(), item.ID()))
+		page.SetFormMethod(method.PUT)
+		page.SetFormButtonText("Update")
+	}
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResShowItem(list, "Back")
+
+	h.render(w, "edit-item", page)
+}
+
+func (h *WebHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Update todo item ", itemID)
+	ctx := r.Context()
+
+	err = r.ParseForm()
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.GetItem(ctx, listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	form := NewItemForm(r.PostForm)
+	item, err = FormToItem(form, item)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if version, ok := am.FormVersion(r); ok {
+		item.SetVersion(version)
+	}
+
+	if isPreview(r) {
+		h.previewItem(w, r, item, form, false)
+		return
+	}
+
+	err = h.service.UpdateItem(ctx, item)
+	if am.IsConflict(err) {
+		http.Error(w, am.ErrVersionConflict, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+listID.String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Delete todo item ", itemID)
+
+	err = h.service.DeleteItem(r.Context(), listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+listID.String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) CompleteItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Complete todo item ", itemID)
+
+	force := r.FormValue("force") == "true"
+	err = h.service.CompleteItem(r.Context(), listID, itemID, force)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath+"/"+listID.String()), http.StatusSeeOther)
+}
+
+func (h *WebHandler) ReopenItem(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Reopen todo item ", itemID)
+
+	err = h.service.ReopenItem(r.Context(), listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+listID.String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) SkipOccurrence(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Skip todo item occurrence ", itemID)
+
+	err = h.service.SkipOccurrence(r.Context(), listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath+"/"+listID.String()), http.StatusSeeOther)
+}
+
+func (h *WebHandler) EndRecurrence(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("End todo item recurrence ", itemID)
+
+	err = h.service.EndRecurrence(r.Context(), listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+listID.String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) Today(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewToday)
+}
+
+func (h *WebHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewUpcoming)
+}
+
+func (h *WebHandler) Overdue(w http.ResponseWriter, r *http.Request) {
+	h.due(w, r, ViewOverdue)
+}
+
+// due renders a due view, days are taken in the time zone given by the tz query param.
+func (h *WebHandler) due(w http.ResponseWriter, r *http.Request, view string) {
+	h.Log().Info("List todo items ", view)
+	ctx := r.Context()
+
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days <= 0 {
+		days = DefaultUpcomingDays
+	}
+
+	now := time.Now()
+	items, err := h.service.GetDueItems(ctx, view, now, loc, days)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, duePage{View: view, TimeZone: loc.String(), Days: days, Items: items, Now: now})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(List{})
+	menu.AddResGenericItem(ViewToday, "", "Today")
+	menu.AddResGenericItem(ViewUpcoming, "", "Upcoming")
+	menu.AddResGenericItem(ViewOverdue, "", "Overdue")
+
+	h.render(w, "due", page)
+}
+
+func (h *WebHandler) itemErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrItemNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrInvalidTimeZone), errors.Is(err, ErrInvalidOffset), errors.Is(err, ErrInvalidItem),
+		errors.Is(err, ErrInvalidRRule), errors.Is(err, ErrNotRecurring),
+		errors.Is(err, ErrInvalidParent), errors.Is(err, ErrInvalidDependency), errors.Is(err, ErrDependencyCycle),
+		errors.Is(err, ErrInvalidTag), errors.Is(err, ErrInvalidPriority), errors.Is(err, ErrInvalidStatus):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrRecurrenceEnded), errors.Is(err, ErrItemBlocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.listErr(w, err, msg)
+	}
+}
+
+func itemIDs(r *http.Request) (listID, itemID uuid.UUID, err error) {
+	listID, err = uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	itemID, err = uuid.Parse(chi.URLParam(r, "itemID"))
+	return listID, itemID, err
+}
+
+func itemPath(listID, itemID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s/items/%s", todoResPath, listID, itemID)
+}
+
+// linkCandidates returns the items the item can be nested under or depend on, all of them but the item itself.
+// The service rejects the choices that would make a cycle.
+func linkCandidates(item Item, items []Item) []Item {
+	candidates := make([]Item, 0, len(items))
+	for _, other := range items {
+		if other.ID() != item.ID() {
+			candidates = append(candidates, other)
+		}
+	}
+	return candidates
+}
+
+// isPreview reports whether the form was sent with the preview button.
+func isPreview(r *http.Request) bool {
+	return r.PostFormValue("action") == "preview"
+}
+
+// returnPath returns the local path in the return form value, the default otherwise.
+// It lets the due views complete items and come back to themselves.
+func returnPath(r *http.Request, def string) string {
+	ret := r.FormValue("return")
+	if strings.HasPrefix(ret, todoResPath+"/") {
+		return ret
+	}
+	return def
+}
+
+// viewLocation returns the time zone of the tz query param, the local one if not given.
+func viewLocation(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
@@ -0,0 +1,132 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const historyPath = "history"
+
+// historyPage is the data of the history page of a list.
+type historyPage struct {
+	List      List
+	Item      uuid.UUID
+	CanEdit   bool
+	Revisions []revisionRow
+}
+
+// revisionRow is a revision with the name of its actor resolved.
+type revisionRow struct {
+	Revision
+	Actor string
+}
+
+// History shows the revisions of a list and of its items, newest first.
+// The item query param narrows them to the revisions of a single item.
+func (h *WebHandler) History(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Show history of todo ", listID)
+	ctx := r.Context()
+
+	var itemID uuid.UUID
+	if id := r.URL.Query().Get("item"); id != "" {
+		itemID, err = uuid.Parse(id)
+		if err != nil {
+			http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	access, err := h.service.Access(ctx, list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	rows := make([]revisionRow, 0, len(revisions))
+	for _, revision := range revisions {
+		if itemID != uuid.Nil && !revision.IsOf(itemID) {
+			continue
+		}
+		row := revisionRow{Revision: revision, Actor: names[revision.ActorID]}
+		if row.Actor == "" {
+			row.Actor = "Someone"
+		}
+		rows = append(rows, row)
+	}
+
+	page := am.NewPage(r, historyPage{List: list, Item: itemID, CanEdit: access.CanEdit(), Revisions: rows})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(list)
+	menu.AddResShowItem(list)
+	if itemID != uuid.Nil {
+		menu.AddResGenericItem(historyPath, list.ID().String(), "Whole history")
+	}
+
+	h.render(w, "history", page)
+}
+
+// RestoreRevision brings the list or the item back to the state of a revision.
+func (h *WebHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	listID, revisionID, err := revisionIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Restore todo revision ", revisionID)
+
+	_, err = h.service.RestoreRevision(r.Context(), listID, revisionID)
+	if err != nil {
+		h.revisionErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath+"/"+listID.String()+"/"+historyPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) revisionErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrRevisionNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
+
+// revisionIDs returns the list and the revision IDs of the route.
+func revisionIDs(r *http.Request) (listID, revisionID uuid.UUID, err error) {
+	listID, err = uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	revisionID, err = uuid.Parse(chi.URLParam(r, "revisionID"))
+	return listID, revisionID, err
+}
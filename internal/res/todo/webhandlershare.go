@@ -0,0 +1,131 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// sharesPage is the data of the list shares page.
+type sharesPage struct {
+	List   List
+	Shares []Share
+	Users  []auth.User
+	Teams  []auth.Team
+	Names  map[uuid.UUID]string
+}
+
+func (h *WebHandler) Shares(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("List todo shares ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrResourceNotFound)
+		return
+	}
+
+	shares, err := h.service.GetShares(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	users, teams, err := h.service.GetShareCandidates(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, sharesPage{List: list, Shares: shares, Users: users, Teams: teams, Names: names})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResShowItem(list, "Back")
+
+	h.render(w, "shares", page)
+}
+
+// Share grants access to the list, the grantee field holds the type and the ID, e.g. "team:<id>".
+func (h *WebHandler) Share(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Share todo ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	granteeType, grantee, _ := strings.Cut(r.FormValue("grantee"), ":")
+	granteeID, err := uuid.Parse(grantee)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	share := NewShare(listID, granteeType, granteeID, r.FormValue("role"))
+	err = h.service.ShareList(ctx, share)
+	if err != nil {
+		h.shareErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+id+"/shares", http.StatusSeeOther)
+}
+
+func (h *WebHandler) Unshare(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	h.Log().Info("Unshare todo ", id)
+	ctx := r.Context()
+
+	listID, err := uuid.Parse(id)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	granteeID, err := uuid.Parse(r.FormValue("grantee_id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.UnshareList(ctx, listID, r.FormValue("grantee_type"), granteeID)
+	if err != nil {
+		h.shareErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+id+"/shares", http.StatusSeeOther)
+}
+
+func (h *WebHandler) shareErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidShare):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrShareNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	default:
+		h.listErr(w, err, msg)
+	}
+}
@@ -0,0 +1,141 @@
+package todo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const usePath = "use"
+
+// useTemplatePage is the data of the page that creates a list from a template, Date is the default day.
+type useTemplatePage struct {
+	Template     List
+	Placeholders []string
+	Date         string
+}
+
+func (h *WebHandler) Archive(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Archive todo ", listID)
+
+	err = h.service.Archive(r.Context(), listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath), http.StatusSeeOther)
+}
+
+func (h *WebHandler) Unarchive(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Unarchive todo ", listID)
+
+	err = h.service.Unarchive(r.Context(), listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath+"/"+listID.String()), http.StatusSeeOther)
+}
+
+// SaveAsTemplate copies the list into a new template and shows the template.
+func (h *WebHandler) SaveAsTemplate(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Save todo as template ", listID)
+
+	template, err := h.service.SaveAsTemplate(r.Context(), listID, r.FormValue("name"))
+	if err != nil {
+		h.templateErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+template.ID().String(), http.StatusSeeOther)
+}
+
+// UseTemplatePage shows the form that creates a list from a template.
+func (h *WebHandler) UseTemplatePage(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Use todo template form ", listID)
+
+	template, err := h.service.Get(r.Context(), listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+	if !template.Template {
+		http.Error(w, ErrNotTemplate.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page := am.NewPage(r, useTemplatePage{
+		Template:     template,
+		Placeholders: Placeholders,
+		Date:         time.Now().Format(time.DateOnly),
+	})
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(template)
+	menu.AddResShowItem(template, "Template")
+
+	h.render(w, "use-template", page)
+}
+
+// UseTemplate creates a list from the template and shows the list.
+func (h *WebHandler) UseTemplate(w http.ResponseWriter, r *http.Request) {
+	listID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Use todo template ", listID)
+
+	values := TemplateValues{Name: r.FormValue("name"), Project: r.FormValue("project")}
+	if date := r.FormValue("date"); date != "" {
+		values.Date, err = time.Parse(time.DateOnly, date)
+		if err != nil {
+			http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := h.service.CreateFromTemplate(r.Context(), listID, values)
+	if err != nil {
+		h.templateErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, todoResPath+"/"+list.ID().String(), http.StatusSeeOther)
+}
+
+func (h *WebHandler) templateErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrNotTemplate):
+		http.Error(w, ErrNotTemplate.Error(), http.StatusBadRequest)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
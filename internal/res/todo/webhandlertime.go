@@ -0,0 +1,315 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/aquamarinepk/todo/internal/feat/auth"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// timesheetPage is the data of the weekly timesheet, Prev and Next are days of the neighbour weeks.
+// Timer is the running timer of the actor and TimerItem the title of its item.
+// Users and Teams are the ones the export can be filtered by.
+type timesheetPage struct {
+	Sheet     Timesheet
+	Prev      string
+	Next      string
+	TimeZone  string
+	Timer     TimeEntry
+	TimerItem string
+	Users     []auth.User
+	Teams     []auth.Team
+	Formats   []formatOption
+}
+
+// itemTimePage is the data of the time page of an item, Today is the default day of a manual entry.
+type itemTimePage struct {
+	List    List
+	Item    Item
+	CanEdit bool
+	Entries []timeRow
+	Total   Spent
+	Timer   TimeEntry
+	Today   string
+}
+
+// timeRow is a time entry with the name of its user resolved.
+type timeRow struct {
+	TimeEntry
+	User string
+}
+
+// Timesheet shows the time the actor tracked in a week, by item and day.
+// The week query param is a day of the week to show, the current one when empty; tz is the zone days start in.
+func (h *WebHandler) Timesheet(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show timesheet")
+	ctx := r.Context()
+
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	day := time.Now().In(loc)
+	if week := r.URL.Query().Get("week"); week != "" {
+		day, err = time.ParseInLocation(time.DateOnly, week, loc)
+		if err != nil {
+			http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+			return
+		}
+	}
+
+	sheet, err := h.service.GetTimesheet(ctx, day)
+	if err != nil {
+		h.timeErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	data := timesheetPage{
+		Sheet:    sheet,
+		Prev:     sheet.Start.AddDate(0, 0, -7).Format(time.DateOnly),
+		Next:     sheet.Start.AddDate(0, 0, 7).Format(time.DateOnly),
+		TimeZone: r.URL.Query().Get("tz"),
+	}
+	for _, f := range TimeFormats {
+		data.Formats = append(data.Formats, formatOption{Value: f, Label: FormatLabel(f)})
+	}
+
+	data.Timer, err = h.service.GetRunningTimer(ctx)
+	if err != nil && !errors.Is(err, ErrNoTimerRunning) {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	if data.Timer.ID != uuid.Nil {
+		data.TimerItem = "Deleted item"
+		if item, err := h.service.GetItem(ctx, data.Timer.ListID, data.Timer.ItemID); err == nil {
+			data.TimerItem = item.Title
+		}
+	}
+
+	data.Users, data.Teams, err = h.service.GetShareCandidates(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	page := am.NewPage(r, data)
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(List{})
+
+	h.render(w, "timesheet", page)
+}
+
+// ExportTime sends the time entries that pass the filter of the query params as a CSV or JSON download.
+func (h *WebHandler) ExportTime(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Export time entries")
+
+	loc, err := viewLocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, err := ParseTimeFilter(r.URL.Query(), loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f, err := ParseFormat(r.URL.Query().Get("format"))
+	if err != nil || !slices.Contains(TimeFormats, f) {
+		http.Error(w, ErrInvalidFormat.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.service.FindTimeEntries(r.Context(), filter)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	writeTimeExport(w, f, records, loc)
+}
+
+// StopTimer stops the running timer of the actor.
+func (h *WebHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Stop timer")
+
+	_, err := h.service.StopTimer(r.Context())
+	if err != nil {
+		h.timeErr(w, err, am.ErrCannotUpdateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, todoResPath+"/"+timesheetPath), http.StatusSeeOther)
+}
+
+// ItemTime shows the time tracked on an item, along with the forms to track more.
+func (h *WebHandler) ItemTime(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Show todo item time ", itemID)
+	ctx := r.Context()
+
+	list, err := h.service.Get(ctx, listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	item, err := h.service.GetItem(ctx, listID, itemID)
+	if err != nil {
+		h.itemErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+
+	access, err := h.service.Access(ctx, list)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := h.service.GetTimeEntries(ctx, listID, itemID)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	timer, err := h.service.GetRunningTimer(ctx)
+	if err != nil && !errors.Is(err, ErrNoTimerRunning) {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	names, err := h.names(ctx)
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+
+	data := itemTimePage{List: list, Item: item, CanEdit: access.CanEdit(), Timer: timer, Today: time.Now().Format(time.DateOnly)}
+	for _, e := range entries {
+		row := timeRow{TimeEntry: e, User: names[e.UserID]}
+		if row.User == "" {
+			row.User = "Someone"
+		}
+		data.Entries = append(data.Entries, row)
+		data.Total += e.Spent()
+	}
+
+	page := am.NewPage(r, data)
+
+	menu := page.NewMenu(todoResPath)
+
+	menu.AddResListItem(list)
+	menu.AddResShowItem(list)
+	menu.AddResGenericItem(timesheetPath, "", "Timesheet")
+
+	h.render(w, "item-time", page)
+}
+
+// StartTimer starts a timer of the actor on the item.
+func (h *WebHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Start timer on todo item ", itemID)
+
+	_, err = h.service.StartTimer(r.Context(), listID, itemID, r.FormValue("notes"))
+	if err != nil {
+		h.timeErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, returnPath(r, timePath(listID, itemID)), http.StatusSeeOther)
+}
+
+// AddTimeEntry stores a manual entry of the time the actor spent on the item on a day.
+func (h *WebHandler) AddTimeEntry(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Add time entry to todo item ", itemID)
+
+	day, err := time.ParseInLocation(time.DateOnly, r.FormValue("date"), time.Local)
+	if err != nil {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+	spent, err := ParseSpent(r.FormValue("spent"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.service.AddTimeEntry(r.Context(), NewManualEntry(listID, itemID, uuid.Nil, day, spent, r.FormValue("notes")))
+	if err != nil {
+		h.timeErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+
+	http.Redirect(w, r, timePath(listID, itemID), http.StatusSeeOther)
+}
+
+func (h *WebHandler) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	listID, itemID, err := itemIDs(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	entryID, err := uuid.Parse(chi.URLParam(r, "entryID"))
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	h.Log().Info("Delete time entry ", entryID)
+
+	err = h.service.DeleteTimeEntry(r.Context(), listID, entryID)
+	if err != nil {
+		h.timeErr(w, err, am.ErrCannotDeleteResource)
+		return
+	}
+
+	http.Redirect(w, r, timePath(listID, itemID), http.StatusSeeOther)
+}
+
+func (h *WebHandler) timeErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrTimeEntryNotFound):
+		http.Error(w, am.ErrResourceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrInvalidTimeEntry):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrTimerRunning), errors.Is(err, ErrNoTimerRunning):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		h.itemErr(w, err, msg)
+	}
+}
+
+// writeTimeExport sends the time records as a file download.
+func writeTimeExport(w http.ResponseWriter, f string, records []TimeRecord, loc *time.Location) {
+	content, contentType, filename, err := TimeExportFile(f, records, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(content)
+}
+
+func timePath(listID, itemID uuid.UUID) string {
+	return itemPath(listID, itemID) + "/time"
+}
@@ -0,0 +1,232 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aquamarinepk/todo/internal/am"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// importPage is the data of the import and export page.
+// Lists are the ones the actor can import into, Report is set after a preview or a failed import.
+type importPage struct {
+	List    List
+	Lists   []List
+	Formats []formatOption
+	Form    ImportForm
+	Report  *ImportReport
+}
+
+// formatOption is a format as offered in the import form and the export links.
+type formatOption struct {
+	Value string
+	Label string
+}
+
+// ImportForm is the import form, Content holds the file once read so a preview can be imported without uploading it again.
+type ImportForm struct {
+	Format   string
+	ListID   uuid.UUID
+	TimeZone string
+	Content  string
+}
+
+// IsList reports whether the form imports into the list.
+func (f ImportForm) IsList(id uuid.UUID) bool {
+	return f.ListID == id
+}
+
+func (h *WebHandler) ImportPage(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Show todo import")
+
+	listID, err := optionalListID(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	h.renderImport(w, r, ImportForm{Format: FormatTodoTxt, ListID: listID, TimeZone: "UTC"}, nil)
+}
+
+// Import previews or imports a file, uploaded or pasted in the form.
+// The format is taken from the file extension when the form does not give it.
+func (h *WebHandler) Import(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Import todo lists")
+
+	urlListID, err := optionalListID(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+1<<20)
+	err = r.ParseMultipartForm(maxImportSize)
+	if err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+		return
+	}
+
+	form := ImportForm{
+		Format:   r.FormValue("format"),
+		ListID:   urlListID,
+		TimeZone: strings.TrimSpace(r.FormValue("time_zone")),
+		Content:  r.FormValue("content"),
+	}
+	if form.ListID == uuid.Nil && r.FormValue("list") != "" {
+		form.ListID, err = uuid.Parse(r.FormValue("list"))
+		if err != nil {
+			http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+			return
+		}
+	}
+
+	file, header, err := r.FormFile("file")
+	if err == nil {
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+		if err != nil {
+			http.Error(w, am.ErrInvalidFormData, http.StatusBadRequest)
+			return
+		}
+		form.Content = string(data)
+		if form.Format == "" {
+			form.Format = FormatOf(header.Filename)
+		}
+	}
+
+	form.Format, err = ParseFormat(form.Format)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := ImportOptions{Format: form.Format, ListID: form.ListID, TimeZone: form.TimeZone, DryRun: isPreview(r)}
+	report, err := h.service.Import(r.Context(), opts, strings.NewReader(form.Content))
+	if err != nil && !errors.Is(err, ErrInvalidImport) {
+		h.transferErr(w, err, am.ErrCannotCreateResource)
+		return
+	}
+	if opts.DryRun || err != nil {
+		h.renderImport(w, r, form, &report)
+		return
+	}
+
+	if form.ListID != uuid.Nil {
+		http.Redirect(w, r, fmt.Sprintf("%s/%s", todoResPath, form.ListID), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, todoResPath, http.StatusSeeOther)
+}
+
+// Export downloads the list, or every visible list, in the format of the query.
+func (h *WebHandler) Export(w http.ResponseWriter, r *http.Request) {
+	h.Log().Info("Export todo lists")
+
+	listID, err := optionalListID(r)
+	if err != nil {
+		http.Error(w, am.ErrInvalidID, http.StatusBadRequest)
+		return
+	}
+	f, err := ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	lists, err := h.service.ExportLists(r.Context(), listID)
+	if err != nil {
+		h.listErr(w, err, am.ErrCannotGetResources)
+		return
+	}
+	writeExport(w, f, lists)
+}
+
+func (h *WebHandler) renderImport(w http.ResponseWriter, r *http.Request, form ImportForm, report *ImportReport) {
+	ctx := r.Context()
+
+	lists, err := h.service.GetLists(ctx, "")
+	if err != nil {
+		http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+		return
+	}
+	data := importPage{Formats: formatOptions(), Form: form, Report: report}
+	for _, list := range lists {
+		access, err := h.service.Access(ctx, list)
+		if err != nil {
+			http.Error(w, am.ErrCannotGetResources, http.StatusInternalServerError)
+			return
+		}
+		if access.CanEdit() {
+			data.Lists = append(data.Lists, list)
+		}
+		if list.ID() == form.ListID {
+			data.List = list
+		}
+	}
+
+	page := am.NewPage(r, data)
+	page.SetFormAction(importPath(form.ListID))
+
+	menu := page.NewMenu(todoResPath)
+
+	if data.List.BaseModel != nil {
+		menu.AddResShowItem(data.List, "Back")
+	} else {
+		menu.AddResListItem(List{})
+	}
+
+	if report != nil && !report.Valid() && !report.DryRun {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	h.render(w, "import", page)
+}
+
+func (h *WebHandler) transferErr(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, ErrInvalidFormat), errors.Is(err, ErrInvalidTimeZone):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.listErr(w, err, msg)
+	}
+}
+
+// writeExport sends the lists as a file download.
+func writeExport(w http.ResponseWriter, f string, lists []TransferList) {
+	content, contentType, filename, err := ExportFile(f, lists)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Write(content)
+}
+
+// optionalListID returns the list of the URL, nil on the routes that work on every list.
+func optionalListID(r *http.Request) (uuid.UUID, error) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(id)
+}
+
+func formatOptions() []formatOption {
+	options := make([]formatOption, len(Formats))
+	for i, f := range Formats {
+		options[i] = formatOption{Value: f, Label: FormatLabel(f)}
+	}
+	return options
+}
+
+func importPath(listID uuid.UUID) string {
+	if listID == uuid.Nil {
+		return todoResPath + "/import"
+	}
+	return fmt.Sprintf("%s/%s/import", todoResPath, listID)
+}
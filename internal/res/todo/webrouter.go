@@ -13,11 +13,68 @@ func NewWebRouter(handler *WebHandler, opts ...am.Option) *am.Router {
 
 	r.Get("/", handler.List)
 	r.Get("/new", handler.New)
+	r.Get("/trash", handler.Trash)
+	r.Get("/today", handler.Today)
+	r.Get("/upcoming", handler.Upcoming)
+	r.Get("/overdue", handler.Overdue)
+	r.Get("/items", handler.Items)
+	r.Get("/assigned", handler.Assigned)
+	r.Post("/filters", handler.SaveFilter)
+	r.Delete("/filters/{filterID}", handler.DeleteSavedFilter)
+	r.Get("/import", handler.ImportPage)
+	r.Post("/import", handler.Import)
+	r.Get("/export", handler.Export)
+	r.Get("/feed", handler.FeedPage)
+	r.Post("/feed/token", handler.RegenerateFeedToken)
+	r.Get("/feed/{token}.ics", handler.Feed)
+	r.Get("/timesheet", handler.Timesheet)
+	r.Get("/time/export", handler.ExportTime)
+	r.Post("/timer/stop", handler.StopTimer)
 	r.Post("/", handler.Create)
 	r.Get("/{id}", handler.Show)
 	r.Get("/{id}/edit", handler.Edit)
 	r.Put("/{id}", handler.Update)
 	r.Delete("/{id}", handler.Delete)
+	r.Post("/{id}/restore", handler.Restore)
+	r.Post("/{id}/archive", handler.Archive)
+	r.Post("/{id}/unarchive", handler.Unarchive)
+	r.Post("/{id}/template", handler.SaveAsTemplate)
+	r.Get("/{id}/use", handler.UseTemplatePage)
+	r.Post("/{id}/use", handler.UseTemplate)
+	r.Get("/{id}/history", handler.History)
+	r.Post("/{id}/revisions/{revisionID}/restore", handler.RestoreRevision)
+	r.Get("/{id}/board", handler.Board)
+	r.Put("/{id}/board/columns", handler.UpdateBoardColumns)
+	r.Get("/{id}/import", handler.ImportPage)
+	r.Post("/{id}/import", handler.Import)
+	r.Get("/{id}/export", handler.Export)
+	r.Get("/{id}/shares", handler.Shares)
+	r.Post("/{id}/shares", handler.Share)
+	r.Delete("/{id}/shares", handler.Unshare)
+	r.Post("/{id}/comments", handler.CreateComment)
+	r.Get("/{id}/comments/{commentID}/edit", handler.EditComment)
+	r.Put("/{id}/comments/{commentID}", handler.UpdateComment)
+	r.Delete("/{id}/comments/{commentID}", handler.DeleteComment)
+	r.Post("/{id}/items", handler.CreateItem)
+	r.Post("/{id}/items/bulk", handler.BulkItems)
+	r.Get("/{id}/items/new", handler.NewItem)
+	r.Get("/{id}/items/{itemID}/edit", handler.EditItem)
+	r.Put("/{id}/items/{itemID}", handler.UpdateItem)
+	r.Delete("/{id}/items/{itemID}", handler.DeleteItem)
+	r.Post("/{id}/items/{itemID}/complete", handler.CompleteItem)
+	r.Post("/{id}/items/{itemID}/reopen", handler.ReopenItem)
+	r.Post("/{id}/items/{itemID}/move", handler.MoveItem)
+	r.Post("/{id}/items/{itemID}/skip", handler.SkipOccurrence)
+	r.Post("/{id}/items/{itemID}/end-recurrence", handler.EndRecurrence)
+	r.Post("/{id}/items/{itemID}/assignees", handler.AssignItem)
+	r.Delete("/{id}/items/{itemID}/assignees", handler.UnassignItem)
+	r.Post("/{id}/items/{itemID}/attachments", handler.UploadAttachment)
+	r.Get("/{id}/items/{itemID}/attachments/{attachmentID}", handler.DownloadAttachment)
+	r.Delete("/{id}/items/{itemID}/attachments/{attachmentID}", handler.DeleteAttachment)
+	r.Get("/{id}/items/{itemID}/time", handler.ItemTime)
+	r.Post("/{id}/items/{itemID}/time", handler.AddTimeEntry)
+	r.Delete("/{id}/items/{itemID}/time/{entryID}", handler.DeleteTimeEntry)
+	r.Post("/{id}/items/{itemID}/timer", handler.StartTimer)
 
 	return r
 }
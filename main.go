@@ -53,27 +53,58 @@ func main() {
 
 	// Auth feature
 	authRepo := sqlite.NewAuthRepo(queryManager)
-	authService := auth.NewService(authRepo)
+	policyFiles := auth.NewPolicyFiles(assetsFS)
+	authService := auth.NewService(authRepo, policyFiles)
 	authWebHandler := auth.NewWebHandler(templateManager, flashManager, authService)
-	authWebRouter := auth.NewWebRouter(authWebHandler)
+	orgMw := am.WithMiddleware(auth.OrgMw(authService))
+	authWebRouter := auth.NewWebRouter(authWebHandler, orgMw)
 	authAPIHandler := auth.NewAPIHandler(authService)
-	authAPIRouter := auth.NewAPIRouter(authAPIHandler)
+	authAPIRouter := auth.NewAPIRouter(authAPIHandler, orgMw)
 	authSeeder := auth.NewSeeder(assetsFS, engine, authRepo)
 
 	app.MountWeb("/auth", authWebRouter)
 	app.MountAPI(version, "/auth", authAPIRouter)
 
+	// Attachment storage
+	fsBlobStore := todo.NewFSBlobStore()
+	s3BlobStore := todo.NewS3BlobStore()
+	blobStore := todo.NewBlobSwitch(map[string]todo.BlobStore{
+		todo.BlobStoreFS: fsBlobStore,
+		todo.BlobStoreS3: s3BlobStore,
+	})
+
 	// Todo resource
 	todoRepo := todo.NewRepo(queryManager)
-	todoService := todo.NewService(todoRepo)
+	todoService := todo.NewService(todoRepo, authService, blobStore)
 	todoWebHandler := todo.NewWebHandler(templateManager, todoService)
-	todoWebRouter := todo.NewWebRouter(todoWebHandler)
+	todoWebRouter := todo.NewWebRouter(todoWebHandler, orgMw)
 	todoAPIHandler := todo.NewAPIHandler(todoService)
-	todoAPIRouter := todo.NewAPIRouter(todoAPIHandler)
+	todoAPIRouter := todo.NewAPIRouter(todoAPIHandler, orgMw)
 
 	app.MountResWeb("/todo", todoWebRouter)
 	app.MountResAPI(version, "/todo", todoAPIRouter)
 
+	// Purger
+	purger := am.NewPurger([]am.Purgeable{authService, todoService})
+
+	// Reminders
+	logNotifier := todo.NewLogNotifier()
+	outboxNotifier := todo.NewOutboxNotifier(todoService)
+	webhookNotifier := todo.NewWebhookNotifier()
+	reminderScheduler := todo.NewReminderScheduler(todoService, map[string]todo.Notifier{
+		todo.NotifierLog:     logNotifier,
+		todo.NotifierOutbox:  outboxNotifier,
+		todo.NotifierWebhook: webhookNotifier,
+	})
+
+	// Assignments
+	assignmentNotifier := todo.NewAssignmentSwitch(map[string]todo.AssignmentNotifier{
+		todo.NotifierLog:     logNotifier,
+		todo.NotifierOutbox:  outboxNotifier,
+		todo.NotifierWebhook: webhookNotifier,
+	})
+	todoService.SetAssignmentNotifier(assignmentNotifier)
+
 	// Add deps
 	app.Add(migrator)
 	app.Add(seeder)
@@ -82,17 +113,27 @@ func main() {
 	app.Add(queryManager)
 	app.Add(templateManager)
 	app.Add(authRepo)
+	app.Add(policyFiles)
 	app.Add(authService)
 	app.Add(authWebHandler)
 	app.Add(authAPIHandler)
 	app.Add(authWebRouter)
 	app.Add(authAPIRouter)
+	app.Add(fsBlobStore)
+	app.Add(s3BlobStore)
+	app.Add(blobStore)
 	app.Add(todoRepo)
 	app.Add(todoService)
 	app.Add(todoWebHandler)
 	app.Add(todoAPIHandler)
 	app.Add(todoWebRouter)
 	app.Add(todoAPIRouter)
+	app.Add(purger)
+	app.Add(logNotifier)
+	app.Add(outboxNotifier)
+	app.Add(webhookNotifier)
+	app.Add(reminderScheduler)
+	app.Add(assignmentNotifier)
 	app.Add(authSeeder)
 
 	err := app.Setup(ctx)